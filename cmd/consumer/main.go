@@ -39,8 +39,11 @@ func main() {
 	}
 
 	dbCfg := pg.PoolConfig{
-		MinConns:          cfg.DBMinConns,
-		MaxConns:          cfg.DBMaxConns,
+		MinConns: cfg.DBMinConns,
+		// Runs only borrow a connection for their queries and lease renewals, so one per
+		// worker is enough. The run request listener holds a connection for good, and the
+		// orphan reaper and approval expiry loops hold one each while they run.
+		MaxConns:          max(cfg.DBMaxConns, int32(cfg.ConsumerWorkers)+3),
 		MaxConnLifetime:   cfg.DBMaxConnLifetime,
 		MaxConnIdleTime:   cfg.DBMaxConnIdleTime,
 		HealthCheckPeriod: cfg.DBHealthCheck,
@@ -51,7 +54,7 @@ func main() {
 	}
 	defer pool.Close()
 
-	consumer.Process(procSpawnFn, appCtx, srv, pool, rmqClient)
+	consumer.Process(procSpawnFn, appCtx, srv, pool, rmqClient, cfg.ConsumerWorkers, cfg.ConsumerLeaseTTL)
 
 	dbComp := component.NewDBChecker(pool)
 	rmqConn := component.NewRabbitMQChecker(rmqClient.Connection())
@@ -2,6 +2,8 @@ package main
 
 import (
 	"fmt"
+	// Schedules can name any IANA time zone, also where the image has no zoneinfo.
+	_ "time/tzdata"
 
 	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/config"
 	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/healthcheck"
@@ -54,7 +56,7 @@ func main() {
 
 	configStore, configReader := processloader.Process(procSpawnFn, appCtx, cfg.ProcessCfgDir, pool)
 
-	producer.Process(procSpawnFn, appCtx, srv, rmqClient, configReader, configStore)
+	producer.Process(procSpawnFn, appCtx, srv, rmqClient, configReader, configStore, pool, cfg.ProcessStatusURL, cfg.IdempotencyKeyTTL)
 
 	dbComp := component.NewDBChecker(pool)
 	rmqConn := component.NewRabbitMQChecker(rmqClient.Connection())
@@ -0,0 +1,230 @@
+// Package condition parses and evaluates the `when:` expressions of tasks.
+//
+// An expression combines comparisons and function calls with `&&`, `||`, `!` and
+// parentheses, e.g. `failure() && status('deploy') == 'timed_out'` or `prod == "prod"`.
+// Operands are quoted strings, bare words or calls of:
+//
+//	success()            all dependencies succeeded
+//	failure()            at least one dependency failed or timed out
+//	always()             true
+//	status('task')       the status of an upstream task
+//	output('task', 'k')  an output captured from an upstream task
+package condition
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/model"
+)
+
+// Env is what an expression can observe while it is evaluated for a task.
+type Env interface {
+	// Dependencies returns the statuses of the tasks the evaluated task waits for.
+	Dependencies() []model.TaskStatus
+	Status(task string) (model.TaskStatus, bool)
+	Output(task, key string) (string, bool)
+}
+
+// Reference points to an upstream task, and optionally one of its outputs, used in an expression.
+type Reference struct {
+	Task   string
+	Output string
+}
+
+// Expr is a parsed condition.
+type Expr struct {
+	src  string
+	root node
+	refs []Reference
+}
+
+// Parse parses and type-checks the expression, which must evaluate to a boolean.
+func Parse(src string) (*Expr, error) {
+	tokens, err := tokenize(src)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, fmt.Errorf("unexpected %q at position %d", tok.text, tok.pos)
+	}
+	if root.kind() != kindBool {
+		return nil, errors.New("condition must evaluate to a boolean")
+	}
+
+	return &Expr{src: src, root: root, refs: p.refs}, nil
+}
+
+func (e *Expr) String() string {
+	return e.src
+}
+
+// References returns the upstream tasks and outputs the expression reads.
+func (e *Expr) References() []Reference {
+	return slices.Clone(e.refs)
+}
+
+// Eval evaluates the expression.
+func (e *Expr) Eval(env Env) (bool, error) {
+	v, err := e.root.eval(env)
+	if err != nil {
+		return false, err
+	}
+	return v.b, nil
+}
+
+type kind int
+
+const (
+	kindString kind = iota
+	kindBool
+)
+
+type value struct {
+	b bool
+	s string
+}
+
+type node interface {
+	kind() kind
+	eval(Env) (value, error)
+}
+
+type literal struct {
+	s string
+}
+
+func (literal) kind() kind { return kindString }
+
+func (l literal) eval(Env) (value, error) {
+	return value{s: l.s}, nil
+}
+
+type not struct {
+	x node
+}
+
+func (not) kind() kind { return kindBool }
+
+func (n not) eval(env Env) (value, error) {
+	v, err := n.x.eval(env)
+	if err != nil {
+		return value{}, err
+	}
+	return value{b: !v.b}, nil
+}
+
+type logical struct {
+	and  bool
+	l, r node
+}
+
+func (logical) kind() kind { return kindBool }
+
+func (n logical) eval(env Env) (value, error) {
+	l, err := n.l.eval(env)
+	if err != nil {
+		return value{}, err
+	}
+	if n.and != l.b {
+		// Short-circuit: false && x, true || x.
+		return l, nil
+	}
+	return n.r.eval(env)
+}
+
+type comparison struct {
+	equal bool
+	l, r  node
+}
+
+func (comparison) kind() kind { return kindBool }
+
+func (n comparison) eval(env Env) (value, error) {
+	l, err := n.l.eval(env)
+	if err != nil {
+		return value{}, err
+	}
+	r, err := n.r.eval(env)
+	if err != nil {
+		return value{}, err
+	}
+	return value{b: (l == r) == n.equal}, nil
+}
+
+type call struct {
+	fn   function
+	args []string
+}
+
+func (c call) kind() kind { return c.fn.result }
+
+func (c call) eval(env Env) (value, error) {
+	return c.fn.eval(env, c.args)
+}
+
+type function struct {
+	arity  int
+	result kind
+	eval   func(env Env, args []string) (value, error)
+}
+
+var functions = map[string]function{
+	"success": {
+		result: kindBool,
+		eval: func(env Env, _ []string) (value, error) {
+			for _, status := range env.Dependencies() {
+				if status != model.TaskStatusSucceeded {
+					return value{b: false}, nil
+				}
+			}
+			return value{b: true}, nil
+		},
+	},
+	"failure": {
+		result: kindBool,
+		eval: func(env Env, _ []string) (value, error) {
+			for _, status := range env.Dependencies() {
+				if status == model.TaskStatusFailed || status == model.TaskStatusTimedOut {
+					return value{b: true}, nil
+				}
+			}
+			return value{b: false}, nil
+		},
+	},
+	"always": {
+		result: kindBool,
+		eval: func(Env, []string) (value, error) {
+			return value{b: true}, nil
+		},
+	},
+	"status": {
+		arity:  1,
+		result: kindString,
+		eval: func(env Env, args []string) (value, error) {
+			status, ok := env.Status(args[0])
+			if !ok {
+				return value{}, fmt.Errorf("unknown task '%s'", args[0])
+			}
+			return value{s: string(status)}, nil
+		},
+	},
+	"output": {
+		arity:  2,
+		result: kindString,
+		eval: func(env Env, args []string) (value, error) {
+			output, ok := env.Output(args[0], args[1])
+			if !ok {
+				return value{}, fmt.Errorf("task '%s' has no output '%s'", args[0], args[1])
+			}
+			return value{s: output}, nil
+		},
+	},
+}
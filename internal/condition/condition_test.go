@@ -0,0 +1,102 @@
+package condition_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/condition"
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/model"
+)
+
+type fakeEnv struct {
+	statuses map[string]model.TaskStatus
+	outputs  map[string]map[string]string
+	deps     []string
+}
+
+func (e fakeEnv) Dependencies() []model.TaskStatus {
+	var statuses []model.TaskStatus
+	for _, dep := range e.deps {
+		statuses = append(statuses, e.statuses[dep])
+	}
+	return statuses
+}
+
+func (e fakeEnv) Status(task string) (model.TaskStatus, bool) {
+	status, ok := e.statuses[task]
+	return status, ok
+}
+
+func (e fakeEnv) Output(task, key string) (string, bool) {
+	value, ok := e.outputs[task][key]
+	return value, ok
+}
+
+var _ = Describe("Condition", func() {
+	var env fakeEnv
+
+	BeforeEach(func() {
+		env = fakeEnv{
+			statuses: map[string]model.TaskStatus{
+				"build":  model.TaskStatusSucceeded,
+				"deploy": model.TaskStatusFailed,
+			},
+			outputs: map[string]map[string]string{
+				"build": {"version": "1.2.3"},
+			},
+			deps: []string{"build", "deploy"},
+		}
+	})
+
+	DescribeTable("Eval",
+		func(src string, expected bool) {
+			expr, err := condition.Parse(src)
+			Expect(err).NotTo(HaveOccurred())
+
+			result, err := expr.Eval(env)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(expected))
+		},
+		Entry("success", "success()", false),
+		Entry("failure", "failure()", true),
+		Entry("always", "always()", true),
+		Entry("negation", "!success()", true),
+		Entry("bare word comparison", `prod == "prod"`, true),
+		Entry("inequality", "prod != 'prod'", false),
+		Entry("status", "status('deploy') == 'failed'", true),
+		Entry("output", "output(build, version) == '1.2.3'", true),
+		Entry("precedence", "always() || failure() && success()", true),
+		Entry("parentheses", "(always() || failure()) && success()", false),
+	)
+
+	DescribeTable("Parse errors",
+		func(src, message string) {
+			_, err := condition.Parse(src)
+			Expect(err).To(MatchError(message))
+		},
+		Entry("unknown function", "later()", "unknown function 'later'"),
+		Entry("wrong arity", "status()", "function 'status' expects 1 argument(s), got 0"),
+		Entry("non-boolean result", "status('deploy')", "condition must evaluate to a boolean"),
+		Entry("non-boolean operand", "success() && prod", "'&&' and '||' require boolean operands"),
+		Entry("missing operand", "success() ||", "unexpected end of condition"),
+		Entry("unterminated string", "prod == 'prod", "unterminated string at position 8"),
+		Entry("trailing tokens", "success() failure()", `unexpected "failure" at position 10`),
+	)
+
+	It("returns the referenced tasks", func() {
+		expr, err := condition.Parse("status(deploy) == 'failed' || output('build', 'version') == '1'")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(expr.References()).To(Equal([]condition.Reference{
+			{Task: "deploy"},
+			{Task: "build", Output: "version"},
+		}))
+	})
+
+	It("returns an error for unknown outputs", func() {
+		expr, err := condition.Parse("output('build', 'missing') == ''")
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = expr.Eval(env)
+		Expect(err).To(MatchError("task 'build' has no output 'missing'"))
+	})
+})
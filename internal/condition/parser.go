@@ -0,0 +1,248 @@
+package condition
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenWord
+	tokenString
+	tokenLParen
+	tokenRParen
+	tokenComma
+	tokenNot
+	tokenAnd
+	tokenOr
+	tokenEqual
+	tokenNotEqual
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+var operators = []struct {
+	text string
+	kind tokenKind
+}{
+	{"&&", tokenAnd},
+	{"||", tokenOr},
+	{"==", tokenEqual},
+	{"!=", tokenNotEqual},
+	{"!", tokenNot},
+	{"(", tokenLParen},
+	{")", tokenRParen},
+	{",", tokenComma},
+}
+
+func tokenize(src string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(src); {
+		c := src[i]
+		if unicode.IsSpace(rune(c)) {
+			i++
+			continue
+		}
+
+		if c == '\'' || c == '"' {
+			end := strings.IndexByte(src[i+1:], c)
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated string at position %d", i)
+			}
+			tokens = append(tokens, token{kind: tokenString, text: src[i+1 : i+1+end], pos: i})
+			i += end + 2
+			continue
+		}
+
+		matched := false
+		for _, op := range operators {
+			if strings.HasPrefix(src[i:], op.text) {
+				tokens = append(tokens, token{kind: op.kind, text: op.text, pos: i})
+				i += len(op.text)
+				matched = true
+				break
+			}
+		}
+		if matched {
+			continue
+		}
+
+		start := i
+		for i < len(src) && !unicode.IsSpace(rune(src[i])) && !strings.ContainsRune("()!,=&|'\"", rune(src[i])) {
+			i++
+		}
+		if start == i {
+			return nil, fmt.Errorf("unexpected %q at position %d", src[i], i)
+		}
+		tokens = append(tokens, token{kind: tokenWord, text: src[start:i], pos: start})
+	}
+
+	return append(tokens, token{kind: tokenEOF, pos: len(src)}), nil
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+	refs   []Reference
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *parser) parseOr() (node, error) {
+	l, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokenOr {
+		p.next()
+		r, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		if l, err = newLogical(false, l, r); err != nil {
+			return nil, err
+		}
+	}
+	return l, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	l, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokenAnd {
+		p.next()
+		r, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		if l, err = newLogical(true, l, r); err != nil {
+			return nil, err
+		}
+	}
+	return l, nil
+}
+
+func newLogical(and bool, l, r node) (node, error) {
+	if l.kind() != kindBool || r.kind() != kindBool {
+		return nil, errors.New("'&&' and '||' require boolean operands")
+	}
+	return logical{and: and, l: l, r: r}, nil
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if p.peek().kind == tokenNot {
+		p.next()
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		if x.kind() != kindBool {
+			return nil, errors.New("'!' requires a boolean operand")
+		}
+		return not{x: x}, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (node, error) {
+	l, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+
+	op := p.peek().kind
+	if op != tokenEqual && op != tokenNotEqual {
+		return l, nil
+	}
+	p.next()
+
+	r, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	if l.kind() != r.kind() {
+		return nil, errors.New("cannot compare a boolean with a string")
+	}
+	return comparison{equal: op == tokenEqual, l: l, r: r}, nil
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokenLParen:
+		x, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokenRParen {
+			return nil, fmt.Errorf("expected ')' at position %d", closing.pos)
+		}
+		return x, nil
+	case tokenString:
+		return literal{s: tok.text}, nil
+	case tokenWord:
+		if p.peek().kind == tokenLParen {
+			return p.parseCall(tok)
+		}
+		return literal{s: tok.text}, nil
+	case tokenEOF:
+		return nil, errors.New("unexpected end of condition")
+	default:
+		return nil, fmt.Errorf("unexpected %q at position %d", tok.text, tok.pos)
+	}
+}
+
+func (p *parser) parseCall(name token) (node, error) {
+	fn, ok := functions[name.text]
+	if !ok {
+		return nil, fmt.Errorf("unknown function '%s'", name.text)
+	}
+	p.next() // (
+
+	var args []string
+	for p.peek().kind != tokenRParen {
+		if len(args) > 0 {
+			if comma := p.next(); comma.kind != tokenComma {
+				return nil, fmt.Errorf("expected ',' at position %d", comma.pos)
+			}
+		}
+		arg := p.next()
+		if arg.kind != tokenString && arg.kind != tokenWord {
+			return nil, fmt.Errorf("function '%s' expects string arguments", name.text)
+		}
+		args = append(args, arg.text)
+	}
+	p.next() // )
+
+	if len(args) != fn.arity {
+		return nil, fmt.Errorf("function '%s' expects %d argument(s), got %d", name.text, fn.arity, len(args))
+	}
+
+	switch name.text {
+	case "status":
+		p.refs = append(p.refs, Reference{Task: args[0]})
+	case "output":
+		p.refs = append(p.refs, Reference{Task: args[0], Output: args[1]})
+	}
+	return call{fn: fn, args: args}, nil
+}
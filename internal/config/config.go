@@ -28,6 +28,15 @@ type Config struct {
 	RabbitMQCAFile   string
 	RabbitMQCertFile string
 	RabbitMQKeyFile  string
+
+	// ConsumerWorkers is the number of messages a consumer processes concurrently.
+	ConsumerWorkers int
+	// ConsumerLeaseTTL is how long a consumer's claim on a message lasts without a heartbeat.
+	ConsumerLeaseTTL time.Duration
+	// ProcessStatusURL is the base URL of the consumer API, used to point callers at the status of their runs.
+	ProcessStatusURL string
+	// IdempotencyKeyTTL is how long the producer remembers the Idempotency-Key of a request.
+	IdempotencyKeyTTL time.Duration
 }
 
 func Load() (*Config, error) {
@@ -48,6 +57,10 @@ func Load() (*Config, error) {
 		RabbitMQCAFile:    getEnv("RABBITMQ_CA_FILE", ""),
 		RabbitMQCertFile:  getEnv("RABBITMQ_CERT_FILE", ""),
 		RabbitMQKeyFile:   getEnv("RABBITMQ_KEY_FILE", ""),
+		ConsumerWorkers:   getInt("CONSUMER_WORKERS", 4),
+		ConsumerLeaseTTL:  getDuration("CONSUMER_LEASE_TTL", 30*time.Second),
+		ProcessStatusURL:  getEnv("PROCESS_STATUS_URL", ""),
+		IdempotencyKeyTTL: getDuration("IDEMPOTENCY_KEY_TTL", 24*time.Hour),
 	}, nil
 }
 
@@ -77,3 +90,13 @@ func getInt32(key string, fallback int32) int32 {
 	}
 	return fallback
 }
+
+func getInt(key string, fallback int) int {
+	if val := os.Getenv(key); val != "" {
+		i, err := strconv.Atoi(val)
+		if err == nil {
+			return i
+		}
+	}
+	return fallback
+}
@@ -0,0 +1,79 @@
+package cron_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/cron"
+)
+
+var _ = Describe("Cron", func() {
+	// 2024-03-15 is a Friday.
+	from := time.Date(2024, 3, 15, 10, 17, 30, 0, time.UTC)
+
+	DescribeTable("Next",
+		func(expr string, expected time.Time) {
+			schedule, err := cron.Parse(expr)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(schedule.Next(from)).To(Equal(expected))
+		},
+		Entry("every minute", "* * * * *", time.Date(2024, 3, 15, 10, 18, 0, 0, time.UTC)),
+		Entry("every 15 minutes", "*/15 * * * *", time.Date(2024, 3, 15, 10, 30, 0, 0, time.UTC)),
+		Entry("a fixed time later today", "30 14 * * *", time.Date(2024, 3, 15, 14, 30, 0, 0, time.UTC)),
+		Entry("a fixed time that passed today", "0 9 * * *", time.Date(2024, 3, 16, 9, 0, 0, 0, time.UTC)),
+		Entry("a list of hours", "0 8,12,18 * * *", time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)),
+		Entry("a range with a step", "0 8-18/4 * * *", time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)),
+		Entry("a value with a step", "5/20 * * * *", time.Date(2024, 3, 15, 10, 25, 0, 0, time.UTC)),
+		Entry("weekdays by name", "0 6 * * MON-FRI", time.Date(2024, 3, 18, 6, 0, 0, 0, time.UTC)),
+		Entry("sunday as 7", "0 6 * * 7", time.Date(2024, 3, 17, 6, 0, 0, 0, time.UTC)),
+		Entry("a month by name", "0 0 1 jun *", time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)),
+		Entry("day of month or day of week", "0 0 1 * 1", time.Date(2024, 3, 18, 0, 0, 0, 0, time.UTC)),
+		Entry("a leap day", "0 0 29 2 *", time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)),
+		Entry("@hourly", "@hourly", time.Date(2024, 3, 15, 11, 0, 0, 0, time.UTC)),
+		Entry("@daily", "@daily", time.Date(2024, 3, 16, 0, 0, 0, 0, time.UTC)),
+		Entry("@weekly", "@weekly", time.Date(2024, 3, 17, 0, 0, 0, 0, time.UTC)),
+		Entry("@monthly", "@monthly", time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)),
+		Entry("@yearly", "@yearly", time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)),
+		Entry("a day that never comes", "0 0 30 2 *", time.Time{}),
+	)
+
+	It("fires in the location of the given time", func() {
+		berlin, err := time.LoadLocation("Europe/Berlin")
+		Expect(err).NotTo(HaveOccurred())
+
+		schedule, err := cron.Parse("0 9 * * *")
+		Expect(err).NotTo(HaveOccurred())
+
+		next := schedule.Next(from.In(berlin))
+		Expect(next).To(Equal(time.Date(2024, 3, 16, 9, 0, 0, 0, berlin)))
+		Expect(next.UTC()).To(Equal(time.Date(2024, 3, 16, 8, 0, 0, 0, time.UTC)))
+	})
+
+	It("skips times that do not exist because of daylight saving time", func() {
+		berlin, err := time.LoadLocation("Europe/Berlin")
+		Expect(err).NotTo(HaveOccurred())
+
+		schedule, err := cron.Parse("30 2 * * *")
+		Expect(err).NotTo(HaveOccurred())
+
+		// Clocks were moved from 02:00 to 03:00 on 2024-03-31.
+		next := schedule.Next(time.Date(2024, 3, 31, 0, 0, 0, 0, berlin))
+		Expect(next).To(Equal(time.Date(2024, 4, 1, 2, 30, 0, 0, berlin)))
+	})
+
+	DescribeTable("Parse errors",
+		func(expr, expected string) {
+			_, err := cron.Parse(expr)
+			Expect(err).To(MatchError(expected))
+		},
+		Entry("too few fields", "* * * *", "expected 5 fields, got 4"),
+		Entry("an unknown descriptor", "@sometimes", "unknown descriptor '@sometimes'"),
+		Entry("a value out of range", "60 * * * *", "minute 60 out of range 0-59"),
+		Entry("an invalid value", "* x * * *", "invalid hour 'x'"),
+		Entry("an unknown month name", "* * * foo *", "invalid month 'foo'"),
+		Entry("a reversed range", "* * * * 5-1", "invalid day of week range '5-1'"),
+		Entry("an invalid step", "*/0 * * * *", "invalid minute step '0'"),
+	)
+})
@@ -2,22 +2,117 @@ package model
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	ErrProcessNotRunning   = errors.New("process is not running")
+	ErrProcessNotRetryable = errors.New("process is not in a retryable state")
+	ErrLeaseLost           = errors.New("lease on the message was lost")
+	ErrScheduleNotFound    = errors.New("schedule not found")
+	ErrProcessNotScheduled = errors.New("process is not scheduled")
+	// ErrAwaitingApproval is returned by executors of tasks that wait for a manual decision.
+	ErrAwaitingApproval           = errors.New("task is awaiting approval")
+	ErrProcessNotAwaitingApproval = errors.New("process is not awaiting approval")
+	ErrTaskNotAwaitingApproval    = errors.New("task is not awaiting approval")
+	ErrProcessNotPaused           = errors.New("process is not paused")
+	// ErrProcessNotParked is returned when continuing a run that neither awaits approval nor is paused.
+	ErrProcessNotParked = errors.New("process is not parked")
+	// ErrOwnershipLost is returned when recording a run attempt that is no longer running,
+	// because it was abandoned or another attempt was started.
+	ErrOwnershipLost = errors.New("ownership of the process run was lost")
 )
 
 type Message struct {
 	UUID              uuid.UUID         `json:"uuid"`
 	ProcessDefinition ProcessDefinition `json:"process_definition"`
+	// RetryOf is the run to resume instead of starting a new one.
+	RetryOf *uuid.UUID `json:"retry_of,omitempty"`
+	// ContinueOf is a parked run to continue, e.g. once its approvals were decided or it was resumed.
+	ContinueOf *uuid.UUID `json:"continue_of,omitempty"`
+	// Priority orders the message in the queue, higher first.
+	Priority int `json:"priority,omitempty"`
 }
 
+// MaxPriority is the highest priority of a run. The queue is declared with it as x-max-priority.
+const MaxPriority = 9
+
 type ProcessDefinition struct {
 	Name   string  `yaml:"name" json:"name"`
 	Params []Param `yaml:"params" json:"params"`
 	Tasks  []Task  `yaml:"tasks" json:"tasks"`
+	// Timeout bounds the whole run. Zero means no limit.
+	Timeout Duration `yaml:"timeout,omitempty" json:"timeout,omitempty"`
+	// MaxParallel limits how many tasks of a run execute at the same time. Zero means no limit.
+	MaxParallel int `yaml:"maxParallel,omitempty" json:"maxParallel,omitempty"`
+	// Finally are cleanup tasks that always run once the tasks are done, even if
+	// the run failed, timed out or was stopped.
+	Finally []Task `yaml:"finally,omitempty" json:"finally,omitempty"`
+	// OnWorkerLoss decides what happens to a run whose consumer stopped heartbeating.
+	// Empty means WorkerLossFail.
+	OnWorkerLoss WorkerLossPolicy `yaml:"onWorkerLoss,omitempty" json:"onWorkerLoss,omitempty"`
+	// Schedules start runs of the process periodically.
+	Schedules []Schedule `yaml:"schedules,omitempty" json:"schedules,omitempty"`
+	// Priority is the default priority of the runs of the process, from 0 to MaxPriority.
+	Priority int `yaml:"priority,omitempty" json:"priority,omitempty"`
+}
+
+// Schedule starts a run of a process with the given parameters whenever its cron expression fires.
+type Schedule struct {
+	Name string `yaml:"name" json:"name"`
+	Cron string `yaml:"cron" json:"cron"`
+	// Timezone is the IANA name of the time zone the cron expression is evaluated in. Empty means UTC.
+	Timezone   string            `yaml:"timezone,omitempty" json:"timezone,omitempty"`
+	Parameters map[string]string `yaml:"parameters,omitempty" json:"parameters,omitempty"`
+}
+
+// Location returns the time zone of the schedule.
+func (s Schedule) Location() (*time.Location, error) {
+	if s.Timezone == "" {
+		return time.UTC, nil
+	}
+	return time.LoadLocation(s.Timezone)
+}
+
+// ScheduleState is a schedule of a process along with when it fires.
+type ScheduleState struct {
+	ProcessName string `json:"process_name"`
+	Schedule
+	Paused      bool       `json:"paused"`
+	NextFireAt  time.Time  `json:"next_fire_at"`
+	LastFiredAt *time.Time `json:"last_fired_at,omitempty"`
+	LastRunID   *uuid.UUID `json:"last_run_id,omitempty"`
+}
+
+type WorkerLossPolicy string
+
+const (
+	// WorkerLossFail marks runs of lost consumers as abandoned.
+	WorkerLossFail WorkerLossPolicy = "fail"
+	// WorkerLossRetry marks runs of lost consumers as abandoned and resumes them like /retryProcess.
+	WorkerLossRetry WorkerLossPolicy = "retry"
+)
+
+// IdempotencyRecord is what is kept about a request made with an Idempotency-Key.
+// StatusCode and Response are only set once the request succeeded.
+type IdempotencyRecord struct {
+	Key         string          `json:"key"`
+	RequestHash string          `json:"request_hash"`
+	ProcessID   *uuid.UUID      `json:"process_id,omitempty"`
+	StatusCode  int             `json:"status_code,omitempty"`
+	Response    json.RawMessage `json:"response,omitempty"`
+}
+
+// ClaimedMessage is a message along with the consumer that claimed it.
+type ClaimedMessage struct {
+	Message Message
+	Owner   string
 }
 
 type Param struct {
@@ -32,6 +127,118 @@ type Task struct {
 	Class      ClassType         `yaml:"class" json:"class"`
 	Parameters map[string]string `yaml:"parameters" json:"parameters"`
 	WaitFor    []string          `yaml:"waitfor,omitempty" json:"waitfor,omitempty"`
+	Retry      *RetryPolicy      `yaml:"retry,omitempty" json:"retry,omitempty"`
+	// Timeout bounds every single attempt of the task. Zero means no limit.
+	Timeout Duration `yaml:"timeout,omitempty" json:"timeout,omitempty"`
+	// Outputs are captured from stdout once the task succeeded and can be referenced
+	// by downstream tasks as {{.tasks.<name>.outputs.<key>}}.
+	Outputs []OutputCapture `yaml:"outputs,omitempty" json:"outputs,omitempty"`
+	// When is a condition deciding whether the task runs once its dependencies
+	// are done, e.g. "failure()". By default a task only runs if all of them succeeded.
+	When string `yaml:"when,omitempty" json:"when,omitempty"`
+	// ContinueOnError lets the run and the dependents of the task carry on as if it
+	// succeeded when it fails. The run then ends as completed_with_errors.
+	ContinueOnError bool `yaml:"continueOnError,omitempty" json:"continueOnError,omitempty"`
+	// Compensate undoes the task. It runs if the task succeeded but the run failed later on.
+	Compensate *Task `yaml:"compensate,omitempty" json:"compensate,omitempty"`
+	// ForEach expands the task into an instance per item of a list, given as a comma separated
+	// list or a JSON array. It is rendered with the request inputs, e.g. "{{.hosts}}".
+	// The instances are named "<task>_<index>" and render {{.item}} and {{.index}}.
+	// Waiting for the task waits for all of its instances.
+	ForEach string `yaml:"forEach,omitempty" json:"forEach,omitempty"`
+	// Rolling runs the instances of a task with a forEach in batches instead of all at once.
+	Rolling *RollingPolicy `yaml:"rolling,omitempty" json:"rolling,omitempty"`
+	// Group and Item are set on the instances of a task with a forEach: the name of the
+	// task and the item the instance runs for.
+	Group string `yaml:"-" json:"group,omitempty"`
+	Item  string `yaml:"-" json:"item,omitempty"`
+	// Subprocess is the definition a processCmd task runs. It is resolved from the
+	// index when the run is started and rendered with the task parameters before execution.
+	Subprocess *ProcessDefinition `yaml:"-" json:"subprocess,omitempty"`
+}
+
+// Compensation returns the task compensating t. It is named "compensate-<task>" unless it declares a name.
+func (t Task) Compensation() (Task, bool) {
+	if t.Compensate == nil {
+		return Task{}, false
+	}
+	compensation := *t.Compensate
+	if compensation.Name == "" {
+		compensation.Name = "compensate-" + t.Name
+	}
+	return compensation, true
+}
+
+// OutputCapture declares a named value extracted from the stdout of a task.
+// Exactly one of Regex, JSONPath and LastLine must be set.
+type OutputCapture struct {
+	Name string `yaml:"name" json:"name"`
+	// Regex captures its first group, or the whole match when it has none.
+	Regex string `yaml:"regex,omitempty" json:"regex,omitempty"`
+	// JSONPath selects a value from stdout parsed as JSON, e.g. "$.build.id".
+	JSONPath string `yaml:"jsonPath,omitempty" json:"jsonPath,omitempty"`
+	LastLine bool   `yaml:"lastLine,omitempty" json:"lastLine,omitempty"`
+}
+
+type BackoffType string
+
+const (
+	BackoffFixed       BackoffType = "fixed"
+	BackoffExponential BackoffType = "exponential"
+)
+
+// RetryPolicy controls how often a failing task is re-executed before it is considered failed.
+type RetryPolicy struct {
+	// Attempts is the total number of executions, including the first one.
+	Attempts     int         `yaml:"attempts" json:"attempts"`
+	Backoff      BackoffType `yaml:"backoff,omitempty" json:"backoff,omitempty"`
+	InitialDelay Duration    `yaml:"initialDelay,omitempty" json:"initialDelay,omitempty"`
+	MaxDelay     Duration    `yaml:"maxDelay,omitempty" json:"maxDelay,omitempty"`
+	// RetryableExitCodes restricts retries to these exit codes. Failures without an
+	// exit code, e.g. connection errors, are always retryable.
+	RetryableExitCodes []int `yaml:"retryableExitCodes,omitempty" json:"retryableExitCodes,omitempty"`
+}
+
+// RollingPolicy rolls a task out over its forEach items, e.g. hosts, a batch at a time. A batch
+// starts once the previous one is done. Failures of up to MaxFailures instances are tolerated
+// like those of tasks that continue on error. Beyond that the rollout is aborted: the instances
+// left are cancelled and the run fails.
+type RollingPolicy struct {
+	BatchSize           int      `yaml:"batchSize" json:"batchSize"`
+	MaxFailures         int      `yaml:"maxFailures" json:"maxFailures"`
+	PauseBetweenBatches Duration `yaml:"pauseBetweenBatches,omitempty" json:"pauseBetweenBatches,omitempty"`
+}
+
+// Duration is a time.Duration written as a string ("500ms", "1m30s") in YAML and JSON.
+type Duration time.Duration
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var raw string
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("invalid duration: %w", err)
+	}
+	return d.parse(raw)
+}
+
+func (d *Duration) UnmarshalYAML(value *yaml.Node) error {
+	var raw string
+	if err := value.Decode(&raw); err != nil {
+		return fmt.Errorf("invalid duration: %w", err)
+	}
+	return d.parse(raw)
+}
+
+func (d *Duration) parse(raw string) error {
+	parsed, err := time.ParseDuration(raw)
+	if err != nil {
+		return fmt.Errorf("invalid duration %q: %w", raw, err)
+	}
+	*d = Duration(parsed)
+	return nil
 }
 
 type ClassType string
@@ -40,12 +247,27 @@ const (
 	LocalCmd ClassType = "localCmd"
 	SshCmd   ClassType = "sshCmd"
 	ScpCmd   ClassType = "scpCmd"
+	// ApprovalCmd tasks wait until they are approved or rejected via the API. Their
+	// timeout is how long the approval can be given.
+	ApprovalCmd ClassType = "approvalCmd"
+	// ProcessCmd tasks run another indexed process definition as a child run. Its
+	// name is given by the "process" parameter, all other parameters are its inputs.
+	ProcessCmd ClassType = "processCmd"
 )
 
+// ProcessParam is the parameter of a processCmd task naming the definition to run.
+const ProcessParam = "process"
+
+// MaxProcessDepth is how deeply processCmd tasks can nest definitions, counting the
+// definition that was started.
+const MaxProcessDepth = 5
+
 var validClassTypes = map[string]ClassType{
-	"localcmd": LocalCmd,
-	"sshcmd":   SshCmd,
-	"scpcmd":   ScpCmd,
+	"localcmd":    LocalCmd,
+	"sshcmd":      SshCmd,
+	"scpcmd":      ScpCmd,
+	"approvalcmd": ApprovalCmd,
+	"processcmd":  ProcessCmd,
 }
 
 func (c *ClassType) UnmarshalJSON(data []byte) error {
@@ -67,23 +289,157 @@ func (c *ClassType) UnmarshalJSON(data []byte) error {
 type ProcessStatus string
 
 const (
+	// StatusScheduled marks a run that is held until its run_at time.
+	StatusScheduled ProcessStatus = "scheduled"
+	// StatusCancelled marks a scheduled run that was cancelled before it was queued.
+	StatusCancelled ProcessStatus = "cancelled"
+	// StatusQueued marks a run that was requested but not picked up by a consumer yet.
+	StatusQueued    ProcessStatus = "queued"
 	StatusRunning   ProcessStatus = "running"
 	StatusCompleted ProcessStatus = "completed"
 	StatusFailed    ProcessStatus = "failed"
 	StatusStopped   ProcessStatus = "stopped"
+	StatusTimedOut  ProcessStatus = "timed_out"
+	// StatusCompletedWithErrors marks a run whose tasks succeeded apart from tolerated
+	// (continueOnError) failures and failed finally tasks.
+	StatusCompletedWithErrors ProcessStatus = "completed_with_errors"
+	// StatusAbandoned marks a run whose consumer stopped heartbeating before finishing it.
+	StatusAbandoned ProcessStatus = "abandoned"
+	// StatusAwaitingApproval marks a run that is parked until its approval tasks are decided.
+	StatusAwaitingApproval ProcessStatus = "awaiting_approval"
+	// StatusPaused marks a run that is parked until it is resumed via the API.
+	StatusPaused ProcessStatus = "paused"
+)
+
+// RetryableStatuses are the final statuses of runs that can be resumed.
+var RetryableStatuses = []ProcessStatus{StatusFailed, StatusStopped, StatusTimedOut, StatusCompletedWithErrors, StatusAbandoned}
+
+type TaskStatus string
+
+const (
+	TaskStatusPending   TaskStatus = "pending"
+	TaskStatusRunning   TaskStatus = "running"
+	TaskStatusSucceeded TaskStatus = "succeeded"
+	TaskStatusFailed    TaskStatus = "failed"
+	TaskStatusSkipped   TaskStatus = "skipped"
+	TaskStatusCancelled TaskStatus = "cancelled"
+	TaskStatusTimedOut  TaskStatus = "timed_out"
+	// TaskStatusAwaitingApproval marks an approval task that waits for a decision.
+	TaskStatusAwaitingApproval TaskStatus = "awaiting_approval"
 )
 
 type ProcessRun struct {
-	ID         uuid.UUID         `json:"id"`
-	Definition ProcessDefinition `json:"definition"`
-	Status     ProcessStatus     `json:"status"`
-	StartedAt  time.Time         `json:"started_at"`
-	EndedAt    *time.Time        `json:"ended_at,omitempty"`
+	ID              uuid.UUID         `json:"id"`
+	Definition      ProcessDefinition `json:"definition"`
+	Status          ProcessStatus     `json:"status"`
+	Attempt         int               `json:"attempt"`
+	Priority        int               `json:"priority"`
+	StartedAt       time.Time         `json:"started_at"`
+	EndedAt         *time.Time        `json:"ended_at,omitempty"`
+	RunAt           *time.Time        `json:"run_at,omitempty"`
+	StopReason      string            `json:"stop_reason,omitempty"`
+	StopRequestedAt *time.Time        `json:"stop_requested_at,omitempty"`
+	Tasks           []TaskRun         `json:"tasks,omitempty"`
+	// Attempts are the earlier attempts of the run, which was resumed with /retryProcess.
+	Attempts []ProcessRunAttempt `json:"attempts,omitempty"`
+	// PauseRequestedAt is set once a pause was requested, until the run continues.
+	PauseRequestedAt *time.Time `json:"pause_requested_at,omitempty"`
+	// ParentID is the run whose processCmd task ParentTask started this run.
+	ParentID   *uuid.UUID `json:"parent_id,omitempty"`
+	ParentTask string     `json:"parent_task,omitempty"`
+	// Rollouts summarize the tasks of the run with a rolling policy.
+	Rollouts []Rollout `json:"rollouts,omitempty"`
 }
 
+// Rollout is the progress of a rolling task, item by item.
+type Rollout struct {
+	Task        string `json:"task"`
+	BatchSize   int    `json:"batch_size"`
+	MaxFailures int    `json:"max_failures"`
+	Batches     int    `json:"batches"`
+	// Failures counts the instances that failed or timed out.
+	Failures int `json:"failures"`
+	// Aborted is set once the failures exceeded MaxFailures.
+	Aborted  bool               `json:"aborted"`
+	Statuses map[TaskStatus]int `json:"statuses"`
+	Items    []RolloutItem      `json:"items"`
+}
+
+// RolloutItem is the latest result of the instance of a rolling task for a single item.
+type RolloutItem struct {
+	Item   string     `json:"item"`
+	Task   string     `json:"task"`
+	Batch  int        `json:"batch"`
+	Status TaskStatus `json:"status"`
+	Error  string     `json:"error,omitempty"`
+}
+
+// ProcessRunAttempt is the outcome of a finished attempt of a run.
+type ProcessRunAttempt struct {
+	Attempt   int           `json:"attempt"`
+	Status    ProcessStatus `json:"status"`
+	StartedAt time.Time     `json:"started_at"`
+	EndedAt   *time.Time    `json:"ended_at,omitempty"`
+}
+
+type TaskRun struct {
+	ID        int               `json:"id"`
+	ProcessID uuid.UUID         `json:"process_id"`
+	Name      string            `json:"name"`
+	Class     ClassType         `json:"class"`
+	Status    TaskStatus        `json:"status"`
+	Attempt   int               `json:"attempt"`
+	ExitCode  *int              `json:"exit_code,omitempty"`
+	Stdout    string            `json:"stdout,omitempty"`
+	Stderr    string            `json:"stderr,omitempty"`
+	Error     string            `json:"error,omitempty"`
+	Outputs   map[string]string `json:"outputs,omitempty"`
+	StartedAt *time.Time        `json:"started_at,omitempty"`
+	EndedAt   *time.Time        `json:"ended_at,omitempty"`
+	// ApprovalExpiresAt, DecidedBy and DecidedAt are only set for approval tasks.
+	ApprovalExpiresAt *time.Time `json:"approval_expires_at,omitempty"`
+	DecidedBy         string     `json:"decided_by,omitempty"`
+	DecidedAt         *time.Time `json:"decided_at,omitempty"`
+}
+
+// LatestTaskRuns keeps the latest attempt of every task.
+func LatestTaskRuns(taskRuns []TaskRun) map[string]TaskRun {
+	latest := make(map[string]TaskRun, len(taskRuns))
+	for _, taskRun := range taskRuns {
+		if prev, ok := latest[taskRun.Name]; !ok || taskRun.Attempt > prev.Attempt {
+			latest[taskRun.Name] = taskRun
+		}
+	}
+	return latest
+}
+
+// ApprovalDecision is the decision on a task awaiting approval.
+type ApprovalDecision struct {
+	Approved bool
+	// By is who made the decision.
+	By      string
+	Comment string
+}
+
+// TaskOutput is what an executor reports back about a finished task.
+type TaskOutput struct {
+	ExitCode *int
+	Stdout   string
+	Stderr   string
+}
+
+type LogStream string
+
+const (
+	StreamStdout LogStream = "stdout"
+	StreamStderr LogStream = "stderr"
+)
+
 type ProcessLog struct {
 	ID        int       `json:"id"`
 	ProcessID uuid.UUID `json:"process_id"`
+	TaskName  string    `json:"task_name,omitempty"`
+	Stream    LogStream `json:"stream,omitempty"`
 	Log       string    `json:"log"`
 	CreatedAt time.Time `json:"created_at"`
 }
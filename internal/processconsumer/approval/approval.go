@@ -0,0 +1,82 @@
+package approval
+
+import (
+	"context"
+	"time"
+
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/logger"
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/model"
+	"github.com/google/uuid"
+)
+
+//counterfeiter:generate . Store
+type Store interface {
+	DecideApproval(context.Context, uuid.UUID, string, model.ApprovalDecision, func(context.Context, model.ProcessRun) error) error
+	ExpireApprovals(context.Context, time.Time, func(context.Context, model.ProcessRun) error) (int, error)
+}
+
+//counterfeiter:generate . Publisher
+type Publisher interface {
+	Publish(context.Context, model.Message) error
+}
+
+// Approvals records the decisions on approval tasks. Every decision, and every approval
+// that expires, requests the continuation of its run from the consumers. The request is
+// published before the decision is committed, so a run is never left parked with a
+// decision nobody acts on.
+type Approvals struct {
+	store     Store
+	publisher Publisher
+}
+
+func NewApprovals(store Store, publisher Publisher) *Approvals {
+	return &Approvals{
+		store:     store,
+		publisher: publisher,
+	}
+}
+
+// Decide approves or rejects a task of a run that is awaiting approval.
+func (a *Approvals) Decide(ctx context.Context, id uuid.UUID, task string, decision model.ApprovalDecision) error {
+	return a.store.DecideApproval(ctx, id, task, decision, a.requestContinue)
+}
+
+// Run expires the approvals that were not given in time every interval until the
+// context is done.
+func (a *Approvals) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := a.Expire(ctx); err != nil && ctx.Err() == nil {
+			logger.GetLogger().Errorf("failed to expire approvals: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Expire times out the approvals whose timeout passed, failing their runs.
+func (a *Approvals) Expire(ctx context.Context) error {
+	resumed, err := a.store.ExpireApprovals(ctx, time.Now(), a.requestContinue)
+	if err != nil {
+		return err
+	}
+	if resumed > 0 {
+		logger.GetLogger().Infof("Expired the approvals of %d runs", resumed)
+	}
+	return nil
+}
+
+func (a *Approvals) requestContinue(ctx context.Context, run model.ProcessRun) error {
+	return a.publisher.Publish(ctx, model.Message{
+		UUID:              uuid.New(),
+		ProcessDefinition: run.Definition,
+		ContinueOf:        &run.ID,
+		Priority:          run.Priority,
+	})
+}
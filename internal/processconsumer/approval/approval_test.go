@@ -0,0 +1,134 @@
+package approval_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/model"
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/processconsumer/approval"
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/processconsumer/approval/approvalfakes"
+)
+
+var (
+	ErrDb      = errors.New("db error")
+	ErrPublish = errors.New("publish error")
+)
+
+var _ = Describe("Approvals", func() {
+	var (
+		ctx       context.Context
+		store     *approvalfakes.FakeStore
+		publisher *approvalfakes.FakePublisher
+		approvals *approval.Approvals
+		run       model.ProcessRun
+		errAction error
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		store = &approvalfakes.FakeStore{}
+		publisher = &approvalfakes.FakePublisher{}
+		approvals = approval.NewApprovals(store, publisher)
+
+		run = model.ProcessRun{
+			ID:         uuid.New(),
+			Definition: model.ProcessDefinition{Name: "release"},
+			Status:     model.StatusAwaitingApproval,
+			Priority:   5,
+		}
+	})
+
+	Describe("Decide", func() {
+		var decision model.ApprovalDecision
+
+		BeforeEach(func() {
+			decision = model.ApprovalDecision{Approved: true, By: "alice"}
+			store.DecideApprovalStub = func(ctx context.Context, _ uuid.UUID, _ string, _ model.ApprovalDecision, resume func(context.Context, model.ProcessRun) error) error {
+				return resume(ctx, run)
+			}
+		})
+
+		JustBeforeEach(func() {
+			errAction = approvals.Decide(ctx, run.ID, "approve", decision)
+		})
+
+		It("records the decision", func() {
+			Expect(errAction).NotTo(HaveOccurred())
+			_, id, task, recorded, _ := store.DecideApprovalArgsForCall(0)
+			Expect(id).To(Equal(run.ID))
+			Expect(task).To(Equal("approve"))
+			Expect(recorded).To(Equal(decision))
+		})
+
+		It("requests the continuation of the run", func() {
+			Expect(publisher.PublishCallCount()).To(Equal(1))
+			_, msg := publisher.PublishArgsForCall(0)
+			Expect(msg.UUID).NotTo(Equal(run.ID))
+			Expect(msg.ContinueOf).To(Equal(&run.ID))
+			Expect(msg.ProcessDefinition).To(Equal(run.Definition))
+			Expect(msg.Priority).To(Equal(5))
+		})
+
+		When("requesting the continuation fails", func() {
+			BeforeEach(func() {
+				publisher.PublishReturns(ErrPublish)
+			})
+
+			It("returns an error", func() {
+				Expect(errAction).To(MatchError(ErrPublish))
+			})
+		})
+
+		When("the task is not awaiting approval", func() {
+			BeforeEach(func() {
+				store.DecideApprovalStub = nil
+				store.DecideApprovalReturns(model.ErrTaskNotAwaitingApproval)
+			})
+
+			It("returns an error", func() {
+				Expect(errAction).To(MatchError(model.ErrTaskNotAwaitingApproval))
+				Expect(publisher.PublishCallCount()).To(Equal(0))
+			})
+		})
+	})
+
+	Describe("Expire", func() {
+		BeforeEach(func() {
+			store.ExpireApprovalsStub = func(ctx context.Context, _ time.Time, resume func(context.Context, model.ProcessRun) error) (int, error) {
+				return 1, resume(ctx, run)
+			}
+		})
+
+		JustBeforeEach(func() {
+			errAction = approvals.Expire(ctx)
+		})
+
+		It("expires the approvals that are due", func() {
+			Expect(errAction).NotTo(HaveOccurred())
+			_, now, _ := store.ExpireApprovalsArgsForCall(0)
+			Expect(now).To(BeTemporally("~", time.Now(), time.Second))
+		})
+
+		It("requests the continuation of their runs", func() {
+			Expect(publisher.PublishCallCount()).To(Equal(1))
+			_, msg := publisher.PublishArgsForCall(0)
+			Expect(msg.ContinueOf).To(Equal(&run.ID))
+		})
+
+		When("expiring fails", func() {
+			BeforeEach(func() {
+				store.ExpireApprovalsStub = nil
+				store.ExpireApprovalsReturns(0, ErrDb)
+			})
+
+			It("returns an error", func() {
+				Expect(errAction).To(MatchError(ErrDb))
+			})
+		})
+	})
+})
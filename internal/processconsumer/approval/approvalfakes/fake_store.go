@@ -0,0 +1,206 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package approvalfakes
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/model"
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/processconsumer/approval"
+	"github.com/google/uuid"
+)
+
+type FakeStore struct {
+	DecideApprovalStub        func(context.Context, uuid.UUID, string, model.ApprovalDecision, func(context.Context, model.ProcessRun) error) error
+	decideApprovalMutex       sync.RWMutex
+	decideApprovalArgsForCall []struct {
+		arg1 context.Context
+		arg2 uuid.UUID
+		arg3 string
+		arg4 model.ApprovalDecision
+		arg5 func(context.Context, model.ProcessRun) error
+	}
+	decideApprovalReturns struct {
+		result1 error
+	}
+	decideApprovalReturnsOnCall map[int]struct {
+		result1 error
+	}
+	ExpireApprovalsStub        func(context.Context, time.Time, func(context.Context, model.ProcessRun) error) (int, error)
+	expireApprovalsMutex       sync.RWMutex
+	expireApprovalsArgsForCall []struct {
+		arg1 context.Context
+		arg2 time.Time
+		arg3 func(context.Context, model.ProcessRun) error
+	}
+	expireApprovalsReturns struct {
+		result1 int
+		result2 error
+	}
+	expireApprovalsReturnsOnCall map[int]struct {
+		result1 int
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeStore) DecideApproval(arg1 context.Context, arg2 uuid.UUID, arg3 string, arg4 model.ApprovalDecision, arg5 func(context.Context, model.ProcessRun) error) error {
+	fake.decideApprovalMutex.Lock()
+	ret, specificReturn := fake.decideApprovalReturnsOnCall[len(fake.decideApprovalArgsForCall)]
+	fake.decideApprovalArgsForCall = append(fake.decideApprovalArgsForCall, struct {
+		arg1 context.Context
+		arg2 uuid.UUID
+		arg3 string
+		arg4 model.ApprovalDecision
+		arg5 func(context.Context, model.ProcessRun) error
+	}{arg1, arg2, arg3, arg4, arg5})
+	stub := fake.DecideApprovalStub
+	fakeReturns := fake.decideApprovalReturns
+	fake.recordInvocation("DecideApproval", []interface{}{arg1, arg2, arg3, arg4, arg5})
+	fake.decideApprovalMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4, arg5)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeStore) DecideApprovalCallCount() int {
+	fake.decideApprovalMutex.RLock()
+	defer fake.decideApprovalMutex.RUnlock()
+	return len(fake.decideApprovalArgsForCall)
+}
+
+func (fake *FakeStore) DecideApprovalCalls(stub func(context.Context, uuid.UUID, string, model.ApprovalDecision, func(context.Context, model.ProcessRun) error) error) {
+	fake.decideApprovalMutex.Lock()
+	defer fake.decideApprovalMutex.Unlock()
+	fake.DecideApprovalStub = stub
+}
+
+func (fake *FakeStore) DecideApprovalArgsForCall(i int) (context.Context, uuid.UUID, string, model.ApprovalDecision, func(context.Context, model.ProcessRun) error) {
+	fake.decideApprovalMutex.RLock()
+	defer fake.decideApprovalMutex.RUnlock()
+	argsForCall := fake.decideApprovalArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5
+}
+
+func (fake *FakeStore) DecideApprovalReturns(result1 error) {
+	fake.decideApprovalMutex.Lock()
+	defer fake.decideApprovalMutex.Unlock()
+	fake.DecideApprovalStub = nil
+	fake.decideApprovalReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeStore) DecideApprovalReturnsOnCall(i int, result1 error) {
+	fake.decideApprovalMutex.Lock()
+	defer fake.decideApprovalMutex.Unlock()
+	fake.DecideApprovalStub = nil
+	if fake.decideApprovalReturnsOnCall == nil {
+		fake.decideApprovalReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.decideApprovalReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeStore) ExpireApprovals(arg1 context.Context, arg2 time.Time, arg3 func(context.Context, model.ProcessRun) error) (int, error) {
+	fake.expireApprovalsMutex.Lock()
+	ret, specificReturn := fake.expireApprovalsReturnsOnCall[len(fake.expireApprovalsArgsForCall)]
+	fake.expireApprovalsArgsForCall = append(fake.expireApprovalsArgsForCall, struct {
+		arg1 context.Context
+		arg2 time.Time
+		arg3 func(context.Context, model.ProcessRun) error
+	}{arg1, arg2, arg3})
+	stub := fake.ExpireApprovalsStub
+	fakeReturns := fake.expireApprovalsReturns
+	fake.recordInvocation("ExpireApprovals", []interface{}{arg1, arg2, arg3})
+	fake.expireApprovalsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeStore) ExpireApprovalsCallCount() int {
+	fake.expireApprovalsMutex.RLock()
+	defer fake.expireApprovalsMutex.RUnlock()
+	return len(fake.expireApprovalsArgsForCall)
+}
+
+func (fake *FakeStore) ExpireApprovalsCalls(stub func(context.Context, time.Time, func(context.Context, model.ProcessRun) error) (int, error)) {
+	fake.expireApprovalsMutex.Lock()
+	defer fake.expireApprovalsMutex.Unlock()
+	fake.ExpireApprovalsStub = stub
+}
+
+func (fake *FakeStore) ExpireApprovalsArgsForCall(i int) (context.Context, time.Time, func(context.Context, model.ProcessRun) error) {
+	fake.expireApprovalsMutex.RLock()
+	defer fake.expireApprovalsMutex.RUnlock()
+	argsForCall := fake.expireApprovalsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeStore) ExpireApprovalsReturns(result1 int, result2 error) {
+	fake.expireApprovalsMutex.Lock()
+	defer fake.expireApprovalsMutex.Unlock()
+	fake.ExpireApprovalsStub = nil
+	fake.expireApprovalsReturns = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStore) ExpireApprovalsReturnsOnCall(i int, result1 int, result2 error) {
+	fake.expireApprovalsMutex.Lock()
+	defer fake.expireApprovalsMutex.Unlock()
+	fake.ExpireApprovalsStub = nil
+	if fake.expireApprovalsReturnsOnCall == nil {
+		fake.expireApprovalsReturnsOnCall = make(map[int]struct {
+			result1 int
+			result2 error
+		})
+	}
+	fake.expireApprovalsReturnsOnCall[i] = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStore) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.decideApprovalMutex.RLock()
+	defer fake.decideApprovalMutex.RUnlock()
+	fake.expireApprovalsMutex.RLock()
+	defer fake.expireApprovalsMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeStore) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ approval.Store = new(FakeStore)
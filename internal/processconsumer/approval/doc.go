@@ -0,0 +1,4 @@
+// Package approval decides the approval tasks of parked runs and continues the runs.
+package approval
+
+//go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 -generate
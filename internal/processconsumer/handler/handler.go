@@ -2,8 +2,11 @@ package handler
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
+	"slices"
+	"strings"
 
 	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/logger"
 	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/model"
@@ -11,20 +14,57 @@ import (
 	"github.com/labstack/echo/v4"
 )
 
+const (
+	defaultStopReason   = "stop requested via API"
+	defaultCancelReason = "cancelled via API"
+)
+
+type StopProcessRequest struct {
+	Reason string `json:"reason"`
+}
+
+// ApprovalRequest is the decision on a task awaiting approval.
+type ApprovalRequest struct {
+	// By is who decided, it is mandatory.
+	By      string `json:"by"`
+	Comment string `json:"comment"`
+}
+
 //counterfeiter:generate . ProcessStore
 type ProcessStore interface {
 	ListRunningProcesses(context.Context) ([]model.ProcessRun, error)
 	GetProcessByID(context.Context, uuid.UUID) (model.ProcessRun, error)
-	UpdateProcessStatus(context.Context, uuid.UUID, model.ProcessStatus) error
+	RequestStop(context.Context, uuid.UUID, string) error
+	RequestPause(context.Context, uuid.UUID) error
+	ResumeProcess(context.Context, uuid.UUID, func(context.Context, model.ProcessRun) error) error
+	CancelScheduledProcess(context.Context, uuid.UUID, string) error
 	GetProcessLogs(context.Context, uuid.UUID) ([]model.ProcessLog, error)
+	GetTaskRuns(context.Context, uuid.UUID) ([]model.TaskRun, error)
+	GetRunAttempts(context.Context, uuid.UUID) ([]model.ProcessRunAttempt, error)
 }
 
-func RegisterHandlers(ctx context.Context, srv *echo.Echo, store ProcessStore) {
+//counterfeiter:generate . Publisher
+type Publisher interface {
+	Publish(context.Context, model.Message) error
+}
+
+//counterfeiter:generate . Approvals
+type Approvals interface {
+	Decide(context.Context, uuid.UUID, string, model.ApprovalDecision) error
+}
+
+func RegisterHandlers(ctx context.Context, srv *echo.Echo, store ProcessStore, publisher Publisher, approvals Approvals) {
 	if srv != nil {
 		srv.GET("/listProcesses", handleListProcesses(ctx, store))
 		srv.GET("/listProcess/:id", handleGetProcess(ctx, store))
 		srv.POST("/stopProcess/:id", handleStopProcess(ctx, store))
+		srv.POST("/pauseProcess/:id", handlePauseProcess(ctx, store))
+		srv.POST("/resumeProcess/:id", handleResumeProcess(ctx, store, publisher))
+		srv.POST("/cancelProcess/:id", handleCancelProcess(ctx, store))
+		srv.POST("/retryProcess/:id", handleRetryProcess(ctx, store, publisher))
 		srv.GET("/processlog/:id", handleGetProcessLogs(ctx, store))
+		srv.POST("/processes/:id/tasks/:task/approve", handleDecideApproval(ctx, approvals, true))
+		srv.POST("/processes/:id/tasks/:task/reject", handleDecideApproval(ctx, approvals, false))
 	} else {
 		logger.GetLogger().Warn("Running routes without a webapi server, did NOT register routes.")
 	}
@@ -51,6 +91,17 @@ func handleGetProcess(ctx context.Context, store ProcessStore) echo.HandlerFunc
 		if err != nil {
 			return echo.NewHTTPError(http.StatusNotFound, "Process not found")
 		}
+
+		process.Tasks, err = store.GetTaskRuns(ctx, id)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get process tasks")
+		}
+		process.Rollouts = summarizeRollouts(process.Definition, process.Tasks)
+
+		process.Attempts, err = store.GetRunAttempts(ctx, id)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get process attempts")
+		}
 		return c.JSON(http.StatusOK, process)
 	}
 }
@@ -62,12 +113,174 @@ func handleStopProcess(ctx context.Context, store ProcessStore) echo.HandlerFunc
 			return echo.NewHTTPError(http.StatusBadRequest, "Invalid process ID")
 		}
 
-		// Updates process status without checking if the process is currently running. For simplicity.
-		if err := store.UpdateProcessStatus(ctx, id, model.StatusStopped); err != nil {
+		var req StopProcessRequest
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Invalid stop request")
+		}
+		reason := strings.TrimSpace(req.Reason)
+		if reason == "" {
+			reason = defaultStopReason
+		}
+
+		// The owning consumer is notified and records the final "stopped" status once its tasks are aborted.
+		// Parked runs are stopped right away.
+		if err := store.RequestStop(ctx, id, reason); err != nil {
+			if errors.Is(err, model.ErrProcessNotRunning) {
+				return echo.NewHTTPError(http.StatusConflict, "Process is not running")
+			}
 			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to stop process")
 		}
+		return c.JSON(http.StatusAccepted, map[string]interface{}{
+			"message": fmt.Sprintf("stop of process with id - %s - successfully requested!", id.String()),
+		})
+	}
+}
+
+func handlePauseProcess(ctx context.Context, store ProcessStore) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		id, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Invalid process ID")
+		}
+
+		// The owning consumer is notified and records the "paused" status once its executing tasks are done.
+		if err := store.RequestPause(ctx, id); err != nil {
+			if errors.Is(err, model.ErrProcessNotRunning) {
+				return echo.NewHTTPError(http.StatusConflict, "Process is not running")
+			}
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to pause process")
+		}
+		return c.JSON(http.StatusAccepted, map[string]interface{}{
+			"message": fmt.Sprintf("pause of process with id - %s - successfully requested!", id.String()),
+		})
+	}
+}
+
+func handleResumeProcess(ctx context.Context, store ProcessStore, publisher Publisher) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		id, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Invalid process ID")
+		}
+
+		// Any consumer continues the run where it was paused.
+		err = store.ResumeProcess(ctx, id, func(ctx context.Context, run model.ProcessRun) error {
+			return publisher.Publish(ctx, model.Message{
+				UUID:              uuid.New(),
+				ProcessDefinition: run.Definition,
+				ContinueOf:        &run.ID,
+				Priority:          run.Priority,
+			})
+		})
+		if err != nil {
+			if errors.Is(err, model.ErrProcessNotPaused) {
+				return echo.NewHTTPError(http.StatusConflict, "Process is not paused")
+			}
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to resume process")
+		}
+		return c.JSON(http.StatusAccepted, map[string]interface{}{
+			"message": fmt.Sprintf("resume of process with id - %s - successfully requested!", id.String()),
+		})
+	}
+}
+
+func handleCancelProcess(ctx context.Context, store ProcessStore) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		id, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Invalid process ID")
+		}
+
+		var req StopProcessRequest
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Invalid cancel request")
+		}
+		reason := strings.TrimSpace(req.Reason)
+		if reason == "" {
+			reason = defaultCancelReason
+		}
+
+		if err := store.CancelScheduledProcess(ctx, id, reason); err != nil {
+			if errors.Is(err, model.ErrProcessNotScheduled) {
+				return echo.NewHTTPError(http.StatusConflict, "Process is not scheduled")
+			}
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to cancel process")
+		}
+		return c.JSON(http.StatusOK, map[string]interface{}{
+			"message": fmt.Sprintf("process with id - %s - successfully cancelled!", id.String()),
+		})
+	}
+}
+
+func handleRetryProcess(ctx context.Context, store ProcessStore, publisher Publisher) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		id, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Invalid process ID")
+		}
+
+		process, err := store.GetProcessByID(ctx, id)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusNotFound, "Process not found")
+		}
+		if !slices.Contains(model.RetryableStatuses, process.Status) {
+			return echo.NewHTTPError(http.StatusConflict, "Process is not in a retryable state")
+		}
+
+		// The consuming service starts the next attempt, re-executing only the tasks that did not succeed.
+		message := model.Message{
+			UUID:              uuid.New(),
+			ProcessDefinition: process.Definition,
+			RetryOf:           &id,
+			Priority:          process.Priority,
+		}
+		if err := publisher.Publish(ctx, message); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to request process retry")
+		}
+		return c.JSON(http.StatusAccepted, map[string]interface{}{
+			"message": fmt.Sprintf("retry of process with id - %s - successfully requested!", id.String()),
+		})
+	}
+}
+
+func handleDecideApproval(ctx context.Context, approvals Approvals, approved bool) echo.HandlerFunc {
+	verdict := "approved"
+	if !approved {
+		verdict = "rejected"
+	}
+
+	return func(c echo.Context) error {
+		id, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Invalid process ID")
+		}
+		task := c.Param("task")
+
+		var req ApprovalRequest
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Invalid approval request")
+		}
+		decision := model.ApprovalDecision{
+			Approved: approved,
+			By:       strings.TrimSpace(req.By),
+			Comment:  strings.TrimSpace(req.Comment),
+		}
+		if decision.By == "" {
+			return echo.NewHTTPError(http.StatusBadRequest, "Missing who decided (by)")
+		}
+
+		// The run is continued by a consumer once the decision is recorded.
+		if err := approvals.Decide(ctx, id, task, decision); err != nil {
+			switch {
+			case errors.Is(err, model.ErrProcessNotAwaitingApproval):
+				return echo.NewHTTPError(http.StatusConflict, "Process is not awaiting approval")
+			case errors.Is(err, model.ErrTaskNotAwaitingApproval):
+				return echo.NewHTTPError(http.StatusConflict, "Task is not awaiting approval")
+			}
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to record decision")
+		}
 		return c.JSON(http.StatusOK, map[string]interface{}{
-			"message": fmt.Sprintf("process with id - %s - successfully stopped!", id.String()),
+			"message": fmt.Sprintf("task %s of process with id - %s - %s by %s!", task, id.String(), verdict, decision.By),
 		})
 	}
 }
@@ -3,9 +3,11 @@ package handler_test
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"time"
 
 	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/model"
@@ -23,6 +25,8 @@ var _ = Describe("Process Handlers", func() {
 		ctx    context.Context
 		rec    *httptest.ResponseRecorder
 		fakePS *handlerfakes.FakeProcessStore
+		fakePB *handlerfakes.FakePublisher
+		fakeAP *handlerfakes.FakeApprovals
 		id     uuid.UUID
 		req    *http.Request
 	)
@@ -32,7 +36,9 @@ var _ = Describe("Process Handlers", func() {
 		ctx = context.Background()
 		rec = httptest.NewRecorder()
 		fakePS = &handlerfakes.FakeProcessStore{}
-		handler.RegisterHandlers(ctx, e, fakePS)
+		fakePB = &handlerfakes.FakePublisher{}
+		fakeAP = &handlerfakes.FakeApprovals{}
+		handler.RegisterHandlers(ctx, e, fakePS, fakePB, fakeAP)
 		id = uuid.New()
 	})
 
@@ -63,6 +69,10 @@ var _ = Describe("Process Handlers", func() {
 		BeforeEach(func() {
 			expected := model.ProcessRun{ID: id, Definition: model.ProcessDefinition{Name: "test"}, Status: model.StatusRunning}
 			fakePS.GetProcessByIDReturns(expected, nil)
+			fakePS.GetTaskRunsReturns([]model.TaskRun{
+				{ProcessID: id, Name: "build", Class: model.LocalCmd, Status: model.TaskStatusSucceeded, Attempt: 1},
+			}, nil)
+			fakePS.GetRunAttemptsReturns([]model.ProcessRunAttempt{{Attempt: 1, Status: model.StatusFailed}}, nil)
 
 			req = httptest.NewRequest(http.MethodGet, fmt.Sprintf("/listProcess/%s", id), nil)
 		})
@@ -73,21 +83,369 @@ var _ = Describe("Process Handlers", func() {
 			Expect(json.Unmarshal(rec.Body.Bytes(), &result)).To(Succeed())
 			Expect(result.ID).To(Equal(id))
 		})
+
+		It("includes the task runs", func() {
+			var result model.ProcessRun
+			Expect(json.Unmarshal(rec.Body.Bytes(), &result)).To(Succeed())
+			Expect(result.Tasks).To(HaveLen(1))
+			Expect(result.Tasks[0].Name).To(Equal("build"))
+			Expect(result.Tasks[0].Status).To(Equal(model.TaskStatusSucceeded))
+		})
+
+		It("includes the earlier attempts", func() {
+			var result model.ProcessRun
+			Expect(json.Unmarshal(rec.Body.Bytes(), &result)).To(Succeed())
+			Expect(result.Attempts).To(HaveLen(1))
+			Expect(result.Attempts[0].Status).To(Equal(model.StatusFailed))
+		})
+
+		It("has no rollouts without rolling tasks", func() {
+			var result model.ProcessRun
+			Expect(json.Unmarshal(rec.Body.Bytes(), &result)).To(Succeed())
+			Expect(result.Rollouts).To(BeEmpty())
+		})
+
+		When("the run has a rolling task", func() {
+			BeforeEach(func() {
+				policy := &model.RollingPolicy{BatchSize: 2, MaxFailures: 1}
+				fakePS.GetProcessByIDReturns(model.ProcessRun{
+					ID: id,
+					Definition: model.ProcessDefinition{Name: "test", Tasks: []model.Task{
+						{Name: "upgrade_0", Class: model.SshCmd, Rolling: policy, Group: "upgrade", Item: "web1"},
+						{Name: "upgrade_1", Class: model.SshCmd, Rolling: policy, Group: "upgrade", Item: "web2"},
+						{Name: "upgrade_2", Class: model.SshCmd, Rolling: policy, Group: "upgrade", Item: "web3"},
+						{Name: "notify", Class: model.LocalCmd, WaitFor: []string{"upgrade_0", "upgrade_1", "upgrade_2"}},
+					}},
+					Status: model.StatusFailed,
+				}, nil)
+				fakePS.GetTaskRunsReturns([]model.TaskRun{
+					{Name: "upgrade_0", Class: model.SshCmd, Status: model.TaskStatusSucceeded, Attempt: 1},
+					{Name: "upgrade_0", Class: model.SshCmd, Status: model.TaskStatusFailed, Attempt: 2, Error: "exit status 1"},
+					{Name: "upgrade_1", Class: model.SshCmd, Status: model.TaskStatusFailed, Attempt: 2, Error: "exit status 2"},
+					{Name: "upgrade_2", Class: model.SshCmd, Status: model.TaskStatusCancelled, Attempt: 2, Error: "aborted"},
+				}, nil)
+			})
+
+			It("summarizes the latest results item by item", func() {
+				var result model.ProcessRun
+				Expect(json.Unmarshal(rec.Body.Bytes(), &result)).To(Succeed())
+				Expect(result.Rollouts).To(Equal([]model.Rollout{{
+					Task:        "upgrade",
+					BatchSize:   2,
+					MaxFailures: 1,
+					Batches:     2,
+					Failures:    2,
+					Aborted:     true,
+					Statuses: map[model.TaskStatus]int{
+						model.TaskStatusFailed:    2,
+						model.TaskStatusCancelled: 1,
+					},
+					Items: []model.RolloutItem{
+						{Item: "web1", Task: "upgrade_0", Batch: 1, Status: model.TaskStatusFailed, Error: "exit status 1"},
+						{Item: "web2", Task: "upgrade_1", Batch: 1, Status: model.TaskStatusFailed, Error: "exit status 2"},
+						{Item: "web3", Task: "upgrade_2", Batch: 2, Status: model.TaskStatusCancelled, Error: "aborted"},
+					},
+				}}))
+			})
+		})
+
+		When("fetching the task runs fails", func() {
+			BeforeEach(func() {
+				fakePS.GetTaskRunsReturns(nil, errors.New("db error"))
+			})
+
+			It("returns 500", func() {
+				Expect(rec.Code).To(Equal(http.StatusInternalServerError))
+			})
+		})
 	})
 
 	Describe("POST /stopProcess/:id", func() {
 		BeforeEach(func() {
-			fakePS.UpdateProcessStatusReturns(nil)
+			fakePS.RequestStopReturns(nil)
 
 			req = httptest.NewRequest(http.MethodPost, fmt.Sprintf("/stopProcess/%s", id), nil)
 		})
 
-		It("updates process status to stopped", func() {
+		It("requests the process stop", func() {
+			Expect(rec.Code).To(Equal(http.StatusAccepted))
+			Expect(fakePS.RequestStopCallCount()).To(Equal(1))
+			_, calledID, reason := fakePS.RequestStopArgsForCall(0)
+			Expect(calledID).To(Equal(id))
+			Expect(reason).To(Equal("stop requested via API"))
+		})
+
+		When("a reason is provided", func() {
+			BeforeEach(func() {
+				req = httptest.NewRequest(http.MethodPost, fmt.Sprintf("/stopProcess/%s", id), strings.NewReader(`{"reason":"maintenance"}`))
+				req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			})
+
+			It("passes it to the store", func() {
+				Expect(rec.Code).To(Equal(http.StatusAccepted))
+				_, _, reason := fakePS.RequestStopArgsForCall(0)
+				Expect(reason).To(Equal("maintenance"))
+			})
+		})
+
+		When("the process is not running", func() {
+			BeforeEach(func() {
+				fakePS.RequestStopReturns(model.ErrProcessNotRunning)
+			})
+
+			It("returns 409", func() {
+				Expect(rec.Code).To(Equal(http.StatusConflict))
+			})
+		})
+
+		When("the store fails", func() {
+			BeforeEach(func() {
+				fakePS.RequestStopReturns(errors.New("db error"))
+			})
+
+			It("returns 500", func() {
+				Expect(rec.Code).To(Equal(http.StatusInternalServerError))
+			})
+		})
+	})
+
+	Describe("POST /pauseProcess/:id", func() {
+		BeforeEach(func() {
+			req = httptest.NewRequest(http.MethodPost, fmt.Sprintf("/pauseProcess/%s", id), nil)
+		})
+
+		It("requests the process pause", func() {
+			Expect(rec.Code).To(Equal(http.StatusAccepted))
+			Expect(fakePS.RequestPauseCallCount()).To(Equal(1))
+			_, calledID := fakePS.RequestPauseArgsForCall(0)
+			Expect(calledID).To(Equal(id))
+		})
+
+		When("the process is not running", func() {
+			BeforeEach(func() {
+				fakePS.RequestPauseReturns(model.ErrProcessNotRunning)
+			})
+
+			It("returns 409", func() {
+				Expect(rec.Code).To(Equal(http.StatusConflict))
+			})
+		})
+
+		When("the store fails", func() {
+			BeforeEach(func() {
+				fakePS.RequestPauseReturns(errors.New("db error"))
+			})
+
+			It("returns 500", func() {
+				Expect(rec.Code).To(Equal(http.StatusInternalServerError))
+			})
+		})
+	})
+
+	Describe("POST /resumeProcess/:id", func() {
+		var run model.ProcessRun
+
+		BeforeEach(func() {
+			run = model.ProcessRun{ID: id, Definition: model.ProcessDefinition{Name: "test"}, Status: model.StatusPaused, Priority: 3}
+			fakePS.ResumeProcessStub = func(ctx context.Context, _ uuid.UUID, resume func(context.Context, model.ProcessRun) error) error {
+				return resume(ctx, run)
+			}
+			req = httptest.NewRequest(http.MethodPost, fmt.Sprintf("/resumeProcess/%s", id), nil)
+		})
+
+		It("requests the continuation of the run", func() {
+			Expect(rec.Code).To(Equal(http.StatusAccepted))
+			_, calledID, _ := fakePS.ResumeProcessArgsForCall(0)
+			Expect(calledID).To(Equal(id))
+
+			Expect(fakePB.PublishCallCount()).To(Equal(1))
+			_, msg := fakePB.PublishArgsForCall(0)
+			Expect(msg.UUID).NotTo(Equal(id))
+			Expect(msg.ContinueOf).To(Equal(&id))
+			Expect(msg.ProcessDefinition).To(Equal(run.Definition))
+			Expect(msg.Priority).To(Equal(3))
+		})
+
+		When("publishing fails", func() {
+			BeforeEach(func() {
+				fakePB.PublishReturns(errors.New("publish error"))
+			})
+
+			It("returns 500", func() {
+				Expect(rec.Code).To(Equal(http.StatusInternalServerError))
+			})
+		})
+
+		When("the process is not paused", func() {
+			BeforeEach(func() {
+				fakePS.ResumeProcessStub = nil
+				fakePS.ResumeProcessReturns(model.ErrProcessNotPaused)
+			})
+
+			It("returns 409", func() {
+				Expect(rec.Code).To(Equal(http.StatusConflict))
+				Expect(fakePB.PublishCallCount()).To(Equal(0))
+			})
+		})
+	})
+
+	Describe("POST /cancelProcess/:id", func() {
+		BeforeEach(func() {
+			req = httptest.NewRequest(http.MethodPost, fmt.Sprintf("/cancelProcess/%s", id), nil)
+		})
+
+		It("cancels the scheduled process", func() {
+			Expect(rec.Code).To(Equal(http.StatusOK))
+			Expect(fakePS.CancelScheduledProcessCallCount()).To(Equal(1))
+			_, calledID, reason := fakePS.CancelScheduledProcessArgsForCall(0)
+			Expect(calledID).To(Equal(id))
+			Expect(reason).To(Equal("cancelled via API"))
+		})
+
+		When("the process is not scheduled", func() {
+			BeforeEach(func() {
+				fakePS.CancelScheduledProcessReturns(model.ErrProcessNotScheduled)
+			})
+
+			It("returns 409", func() {
+				Expect(rec.Code).To(Equal(http.StatusConflict))
+			})
+		})
+
+		When("the store fails", func() {
+			BeforeEach(func() {
+				fakePS.CancelScheduledProcessReturns(errors.New("db error"))
+			})
+
+			It("returns 500", func() {
+				Expect(rec.Code).To(Equal(http.StatusInternalServerError))
+			})
+		})
+	})
+
+	Describe("POST /processes/:id/tasks/:task/approve|reject", func() {
+		decide := func(action, body string) *http.Request {
+			r := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/processes/%s/tasks/approve-release/%s", id, action), strings.NewReader(body))
+			r.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			return r
+		}
+
+		BeforeEach(func() {
+			req = decide("approve", `{"by":" alice ","comment":"looks good"}`)
+		})
+
+		It("records the approval", func() {
 			Expect(rec.Code).To(Equal(http.StatusOK))
-			Expect(fakePS.UpdateProcessStatusCallCount()).To(Equal(1))
-			_, calledID, status := fakePS.UpdateProcessStatusArgsForCall(0)
+			Expect(fakeAP.DecideCallCount()).To(Equal(1))
+			_, calledID, task, decision := fakeAP.DecideArgsForCall(0)
 			Expect(calledID).To(Equal(id))
-			Expect(status).To(Equal(model.StatusStopped))
+			Expect(task).To(Equal("approve-release"))
+			Expect(decision).To(Equal(model.ApprovalDecision{Approved: true, By: "alice", Comment: "looks good"}))
+		})
+
+		When("the task is rejected", func() {
+			BeforeEach(func() {
+				req = decide("reject", `{"by":"bob"}`)
+			})
+
+			It("records the rejection", func() {
+				Expect(rec.Code).To(Equal(http.StatusOK))
+				_, _, _, decision := fakeAP.DecideArgsForCall(0)
+				Expect(decision).To(Equal(model.ApprovalDecision{Approved: false, By: "bob"}))
+			})
+		})
+
+		When("it is not said who decided", func() {
+			BeforeEach(func() {
+				req = decide("approve", `{"comment":"looks good"}`)
+			})
+
+			It("returns 400", func() {
+				Expect(rec.Code).To(Equal(http.StatusBadRequest))
+				Expect(fakeAP.DecideCallCount()).To(Equal(0))
+			})
+		})
+
+		When("the process is not awaiting approval", func() {
+			BeforeEach(func() {
+				fakeAP.DecideReturns(model.ErrProcessNotAwaitingApproval)
+			})
+
+			It("returns 409", func() {
+				Expect(rec.Code).To(Equal(http.StatusConflict))
+			})
+		})
+
+		When("the task is not awaiting approval", func() {
+			BeforeEach(func() {
+				fakeAP.DecideReturns(model.ErrTaskNotAwaitingApproval)
+			})
+
+			It("returns 409", func() {
+				Expect(rec.Code).To(Equal(http.StatusConflict))
+				Expect(rec.Body.String()).To(ContainSubstring("Task is not awaiting approval"))
+			})
+		})
+
+		When("recording the decision fails", func() {
+			BeforeEach(func() {
+				fakeAP.DecideReturns(errors.New("db error"))
+			})
+
+			It("returns 500", func() {
+				Expect(rec.Code).To(Equal(http.StatusInternalServerError))
+			})
+		})
+	})
+
+	Describe("POST /retryProcess/:id", func() {
+		var definition model.ProcessDefinition
+
+		BeforeEach(func() {
+			definition = model.ProcessDefinition{Name: "test"}
+			fakePS.GetProcessByIDReturns(model.ProcessRun{ID: id, Definition: definition, Status: model.StatusFailed, Priority: 7}, nil)
+
+			req = httptest.NewRequest(http.MethodPost, fmt.Sprintf("/retryProcess/%s", id), nil)
+		})
+
+		It("publishes a retry of the run", func() {
+			Expect(rec.Code).To(Equal(http.StatusAccepted))
+			Expect(fakePB.PublishCallCount()).To(Equal(1))
+			_, message := fakePB.PublishArgsForCall(0)
+			Expect(message.RetryOf).To(HaveValue(Equal(id)))
+			Expect(message.ProcessDefinition).To(Equal(definition))
+			Expect(message.Priority).To(Equal(7))
+		})
+
+		When("the process does not exist", func() {
+			BeforeEach(func() {
+				fakePS.GetProcessByIDReturns(model.ProcessRun{}, errors.New("no rows"))
+			})
+
+			It("returns 404", func() {
+				Expect(rec.Code).To(Equal(http.StatusNotFound))
+			})
+		})
+
+		When("the process completed", func() {
+			BeforeEach(func() {
+				fakePS.GetProcessByIDReturns(model.ProcessRun{ID: id, Status: model.StatusCompleted}, nil)
+			})
+
+			It("returns 409", func() {
+				Expect(rec.Code).To(Equal(http.StatusConflict))
+				Expect(fakePB.PublishCallCount()).To(Equal(0))
+			})
+		})
+
+		When("publishing fails", func() {
+			BeforeEach(func() {
+				fakePB.PublishReturns(errors.New("broker down"))
+			})
+
+			It("returns 500", func() {
+				Expect(rec.Code).To(Equal(http.StatusInternalServerError))
+			})
 		})
 	})
 
@@ -0,0 +1,120 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package handlerfakes
+
+import (
+	"context"
+	"sync"
+
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/model"
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/processconsumer/handler"
+	"github.com/google/uuid"
+)
+
+type FakeApprovals struct {
+	DecideStub        func(context.Context, uuid.UUID, string, model.ApprovalDecision) error
+	decideMutex       sync.RWMutex
+	decideArgsForCall []struct {
+		arg1 context.Context
+		arg2 uuid.UUID
+		arg3 string
+		arg4 model.ApprovalDecision
+	}
+	decideReturns struct {
+		result1 error
+	}
+	decideReturnsOnCall map[int]struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeApprovals) Decide(arg1 context.Context, arg2 uuid.UUID, arg3 string, arg4 model.ApprovalDecision) error {
+	fake.decideMutex.Lock()
+	ret, specificReturn := fake.decideReturnsOnCall[len(fake.decideArgsForCall)]
+	fake.decideArgsForCall = append(fake.decideArgsForCall, struct {
+		arg1 context.Context
+		arg2 uuid.UUID
+		arg3 string
+		arg4 model.ApprovalDecision
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.DecideStub
+	fakeReturns := fake.decideReturns
+	fake.recordInvocation("Decide", []interface{}{arg1, arg2, arg3, arg4})
+	fake.decideMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeApprovals) DecideCallCount() int {
+	fake.decideMutex.RLock()
+	defer fake.decideMutex.RUnlock()
+	return len(fake.decideArgsForCall)
+}
+
+func (fake *FakeApprovals) DecideCalls(stub func(context.Context, uuid.UUID, string, model.ApprovalDecision) error) {
+	fake.decideMutex.Lock()
+	defer fake.decideMutex.Unlock()
+	fake.DecideStub = stub
+}
+
+func (fake *FakeApprovals) DecideArgsForCall(i int) (context.Context, uuid.UUID, string, model.ApprovalDecision) {
+	fake.decideMutex.RLock()
+	defer fake.decideMutex.RUnlock()
+	argsForCall := fake.decideArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeApprovals) DecideReturns(result1 error) {
+	fake.decideMutex.Lock()
+	defer fake.decideMutex.Unlock()
+	fake.DecideStub = nil
+	fake.decideReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeApprovals) DecideReturnsOnCall(i int, result1 error) {
+	fake.decideMutex.Lock()
+	defer fake.decideMutex.Unlock()
+	fake.DecideStub = nil
+	if fake.decideReturnsOnCall == nil {
+		fake.decideReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.decideReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeApprovals) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.decideMutex.RLock()
+	defer fake.decideMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeApprovals) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ handler.Approvals = new(FakeApprovals)
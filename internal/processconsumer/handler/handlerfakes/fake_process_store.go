@@ -11,6 +11,19 @@ import (
 )
 
 type FakeProcessStore struct {
+	CancelScheduledProcessStub        func(context.Context, uuid.UUID, string) error
+	cancelScheduledProcessMutex       sync.RWMutex
+	cancelScheduledProcessArgsForCall []struct {
+		arg1 context.Context
+		arg2 uuid.UUID
+		arg3 string
+	}
+	cancelScheduledProcessReturns struct {
+		result1 error
+	}
+	cancelScheduledProcessReturnsOnCall map[int]struct {
+		result1 error
+	}
 	GetProcessByIDStub        func(context.Context, uuid.UUID) (model.ProcessRun, error)
 	getProcessByIDMutex       sync.RWMutex
 	getProcessByIDArgsForCall []struct {
@@ -39,6 +52,34 @@ type FakeProcessStore struct {
 		result1 []model.ProcessLog
 		result2 error
 	}
+	GetRunAttemptsStub        func(context.Context, uuid.UUID) ([]model.ProcessRunAttempt, error)
+	getRunAttemptsMutex       sync.RWMutex
+	getRunAttemptsArgsForCall []struct {
+		arg1 context.Context
+		arg2 uuid.UUID
+	}
+	getRunAttemptsReturns struct {
+		result1 []model.ProcessRunAttempt
+		result2 error
+	}
+	getRunAttemptsReturnsOnCall map[int]struct {
+		result1 []model.ProcessRunAttempt
+		result2 error
+	}
+	GetTaskRunsStub        func(context.Context, uuid.UUID) ([]model.TaskRun, error)
+	getTaskRunsMutex       sync.RWMutex
+	getTaskRunsArgsForCall []struct {
+		arg1 context.Context
+		arg2 uuid.UUID
+	}
+	getTaskRunsReturns struct {
+		result1 []model.TaskRun
+		result2 error
+	}
+	getTaskRunsReturnsOnCall map[int]struct {
+		result1 []model.TaskRun
+		result2 error
+	}
 	ListRunningProcessesStub        func(context.Context) ([]model.ProcessRun, error)
 	listRunningProcessesMutex       sync.RWMutex
 	listRunningProcessesArgsForCall []struct {
@@ -52,23 +93,111 @@ type FakeProcessStore struct {
 		result1 []model.ProcessRun
 		result2 error
 	}
-	UpdateProcessStatusStub        func(context.Context, uuid.UUID, model.ProcessStatus) error
-	updateProcessStatusMutex       sync.RWMutex
-	updateProcessStatusArgsForCall []struct {
+	RequestPauseStub        func(context.Context, uuid.UUID) error
+	requestPauseMutex       sync.RWMutex
+	requestPauseArgsForCall []struct {
 		arg1 context.Context
 		arg2 uuid.UUID
-		arg3 model.ProcessStatus
 	}
-	updateProcessStatusReturns struct {
+	requestPauseReturns struct {
 		result1 error
 	}
-	updateProcessStatusReturnsOnCall map[int]struct {
+	requestPauseReturnsOnCall map[int]struct {
+		result1 error
+	}
+	RequestStopStub        func(context.Context, uuid.UUID, string) error
+	requestStopMutex       sync.RWMutex
+	requestStopArgsForCall []struct {
+		arg1 context.Context
+		arg2 uuid.UUID
+		arg3 string
+	}
+	requestStopReturns struct {
+		result1 error
+	}
+	requestStopReturnsOnCall map[int]struct {
+		result1 error
+	}
+	ResumeProcessStub        func(context.Context, uuid.UUID, func(context.Context, model.ProcessRun) error) error
+	resumeProcessMutex       sync.RWMutex
+	resumeProcessArgsForCall []struct {
+		arg1 context.Context
+		arg2 uuid.UUID
+		arg3 func(context.Context, model.ProcessRun) error
+	}
+	resumeProcessReturns struct {
+		result1 error
+	}
+	resumeProcessReturnsOnCall map[int]struct {
 		result1 error
 	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
 
+func (fake *FakeProcessStore) CancelScheduledProcess(arg1 context.Context, arg2 uuid.UUID, arg3 string) error {
+	fake.cancelScheduledProcessMutex.Lock()
+	ret, specificReturn := fake.cancelScheduledProcessReturnsOnCall[len(fake.cancelScheduledProcessArgsForCall)]
+	fake.cancelScheduledProcessArgsForCall = append(fake.cancelScheduledProcessArgsForCall, struct {
+		arg1 context.Context
+		arg2 uuid.UUID
+		arg3 string
+	}{arg1, arg2, arg3})
+	stub := fake.CancelScheduledProcessStub
+	fakeReturns := fake.cancelScheduledProcessReturns
+	fake.recordInvocation("CancelScheduledProcess", []interface{}{arg1, arg2, arg3})
+	fake.cancelScheduledProcessMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeProcessStore) CancelScheduledProcessCallCount() int {
+	fake.cancelScheduledProcessMutex.RLock()
+	defer fake.cancelScheduledProcessMutex.RUnlock()
+	return len(fake.cancelScheduledProcessArgsForCall)
+}
+
+func (fake *FakeProcessStore) CancelScheduledProcessCalls(stub func(context.Context, uuid.UUID, string) error) {
+	fake.cancelScheduledProcessMutex.Lock()
+	defer fake.cancelScheduledProcessMutex.Unlock()
+	fake.CancelScheduledProcessStub = stub
+}
+
+func (fake *FakeProcessStore) CancelScheduledProcessArgsForCall(i int) (context.Context, uuid.UUID, string) {
+	fake.cancelScheduledProcessMutex.RLock()
+	defer fake.cancelScheduledProcessMutex.RUnlock()
+	argsForCall := fake.cancelScheduledProcessArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeProcessStore) CancelScheduledProcessReturns(result1 error) {
+	fake.cancelScheduledProcessMutex.Lock()
+	defer fake.cancelScheduledProcessMutex.Unlock()
+	fake.CancelScheduledProcessStub = nil
+	fake.cancelScheduledProcessReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeProcessStore) CancelScheduledProcessReturnsOnCall(i int, result1 error) {
+	fake.cancelScheduledProcessMutex.Lock()
+	defer fake.cancelScheduledProcessMutex.Unlock()
+	fake.CancelScheduledProcessStub = nil
+	if fake.cancelScheduledProcessReturnsOnCall == nil {
+		fake.cancelScheduledProcessReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.cancelScheduledProcessReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeProcessStore) GetProcessByID(arg1 context.Context, arg2 uuid.UUID) (model.ProcessRun, error) {
 	fake.getProcessByIDMutex.Lock()
 	ret, specificReturn := fake.getProcessByIDReturnsOnCall[len(fake.getProcessByIDArgsForCall)]
@@ -199,6 +328,136 @@ func (fake *FakeProcessStore) GetProcessLogsReturnsOnCall(i int, result1 []model
 	}{result1, result2}
 }
 
+func (fake *FakeProcessStore) GetRunAttempts(arg1 context.Context, arg2 uuid.UUID) ([]model.ProcessRunAttempt, error) {
+	fake.getRunAttemptsMutex.Lock()
+	ret, specificReturn := fake.getRunAttemptsReturnsOnCall[len(fake.getRunAttemptsArgsForCall)]
+	fake.getRunAttemptsArgsForCall = append(fake.getRunAttemptsArgsForCall, struct {
+		arg1 context.Context
+		arg2 uuid.UUID
+	}{arg1, arg2})
+	stub := fake.GetRunAttemptsStub
+	fakeReturns := fake.getRunAttemptsReturns
+	fake.recordInvocation("GetRunAttempts", []interface{}{arg1, arg2})
+	fake.getRunAttemptsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeProcessStore) GetRunAttemptsCallCount() int {
+	fake.getRunAttemptsMutex.RLock()
+	defer fake.getRunAttemptsMutex.RUnlock()
+	return len(fake.getRunAttemptsArgsForCall)
+}
+
+func (fake *FakeProcessStore) GetRunAttemptsCalls(stub func(context.Context, uuid.UUID) ([]model.ProcessRunAttempt, error)) {
+	fake.getRunAttemptsMutex.Lock()
+	defer fake.getRunAttemptsMutex.Unlock()
+	fake.GetRunAttemptsStub = stub
+}
+
+func (fake *FakeProcessStore) GetRunAttemptsArgsForCall(i int) (context.Context, uuid.UUID) {
+	fake.getRunAttemptsMutex.RLock()
+	defer fake.getRunAttemptsMutex.RUnlock()
+	argsForCall := fake.getRunAttemptsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeProcessStore) GetRunAttemptsReturns(result1 []model.ProcessRunAttempt, result2 error) {
+	fake.getRunAttemptsMutex.Lock()
+	defer fake.getRunAttemptsMutex.Unlock()
+	fake.GetRunAttemptsStub = nil
+	fake.getRunAttemptsReturns = struct {
+		result1 []model.ProcessRunAttempt
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeProcessStore) GetRunAttemptsReturnsOnCall(i int, result1 []model.ProcessRunAttempt, result2 error) {
+	fake.getRunAttemptsMutex.Lock()
+	defer fake.getRunAttemptsMutex.Unlock()
+	fake.GetRunAttemptsStub = nil
+	if fake.getRunAttemptsReturnsOnCall == nil {
+		fake.getRunAttemptsReturnsOnCall = make(map[int]struct {
+			result1 []model.ProcessRunAttempt
+			result2 error
+		})
+	}
+	fake.getRunAttemptsReturnsOnCall[i] = struct {
+		result1 []model.ProcessRunAttempt
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeProcessStore) GetTaskRuns(arg1 context.Context, arg2 uuid.UUID) ([]model.TaskRun, error) {
+	fake.getTaskRunsMutex.Lock()
+	ret, specificReturn := fake.getTaskRunsReturnsOnCall[len(fake.getTaskRunsArgsForCall)]
+	fake.getTaskRunsArgsForCall = append(fake.getTaskRunsArgsForCall, struct {
+		arg1 context.Context
+		arg2 uuid.UUID
+	}{arg1, arg2})
+	stub := fake.GetTaskRunsStub
+	fakeReturns := fake.getTaskRunsReturns
+	fake.recordInvocation("GetTaskRuns", []interface{}{arg1, arg2})
+	fake.getTaskRunsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeProcessStore) GetTaskRunsCallCount() int {
+	fake.getTaskRunsMutex.RLock()
+	defer fake.getTaskRunsMutex.RUnlock()
+	return len(fake.getTaskRunsArgsForCall)
+}
+
+func (fake *FakeProcessStore) GetTaskRunsCalls(stub func(context.Context, uuid.UUID) ([]model.TaskRun, error)) {
+	fake.getTaskRunsMutex.Lock()
+	defer fake.getTaskRunsMutex.Unlock()
+	fake.GetTaskRunsStub = stub
+}
+
+func (fake *FakeProcessStore) GetTaskRunsArgsForCall(i int) (context.Context, uuid.UUID) {
+	fake.getTaskRunsMutex.RLock()
+	defer fake.getTaskRunsMutex.RUnlock()
+	argsForCall := fake.getTaskRunsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeProcessStore) GetTaskRunsReturns(result1 []model.TaskRun, result2 error) {
+	fake.getTaskRunsMutex.Lock()
+	defer fake.getTaskRunsMutex.Unlock()
+	fake.GetTaskRunsStub = nil
+	fake.getTaskRunsReturns = struct {
+		result1 []model.TaskRun
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeProcessStore) GetTaskRunsReturnsOnCall(i int, result1 []model.TaskRun, result2 error) {
+	fake.getTaskRunsMutex.Lock()
+	defer fake.getTaskRunsMutex.Unlock()
+	fake.GetTaskRunsStub = nil
+	if fake.getTaskRunsReturnsOnCall == nil {
+		fake.getTaskRunsReturnsOnCall = make(map[int]struct {
+			result1 []model.TaskRun
+			result2 error
+		})
+	}
+	fake.getTaskRunsReturnsOnCall[i] = struct {
+		result1 []model.TaskRun
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeProcessStore) ListRunningProcesses(arg1 context.Context) ([]model.ProcessRun, error) {
 	fake.listRunningProcessesMutex.Lock()
 	ret, specificReturn := fake.listRunningProcessesReturnsOnCall[len(fake.listRunningProcessesArgsForCall)]
@@ -263,18 +522,143 @@ func (fake *FakeProcessStore) ListRunningProcessesReturnsOnCall(i int, result1 [
 	}{result1, result2}
 }
 
-func (fake *FakeProcessStore) UpdateProcessStatus(arg1 context.Context, arg2 uuid.UUID, arg3 model.ProcessStatus) error {
-	fake.updateProcessStatusMutex.Lock()
-	ret, specificReturn := fake.updateProcessStatusReturnsOnCall[len(fake.updateProcessStatusArgsForCall)]
-	fake.updateProcessStatusArgsForCall = append(fake.updateProcessStatusArgsForCall, struct {
+func (fake *FakeProcessStore) RequestPause(arg1 context.Context, arg2 uuid.UUID) error {
+	fake.requestPauseMutex.Lock()
+	ret, specificReturn := fake.requestPauseReturnsOnCall[len(fake.requestPauseArgsForCall)]
+	fake.requestPauseArgsForCall = append(fake.requestPauseArgsForCall, struct {
+		arg1 context.Context
+		arg2 uuid.UUID
+	}{arg1, arg2})
+	stub := fake.RequestPauseStub
+	fakeReturns := fake.requestPauseReturns
+	fake.recordInvocation("RequestPause", []interface{}{arg1, arg2})
+	fake.requestPauseMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeProcessStore) RequestPauseCallCount() int {
+	fake.requestPauseMutex.RLock()
+	defer fake.requestPauseMutex.RUnlock()
+	return len(fake.requestPauseArgsForCall)
+}
+
+func (fake *FakeProcessStore) RequestPauseCalls(stub func(context.Context, uuid.UUID) error) {
+	fake.requestPauseMutex.Lock()
+	defer fake.requestPauseMutex.Unlock()
+	fake.RequestPauseStub = stub
+}
+
+func (fake *FakeProcessStore) RequestPauseArgsForCall(i int) (context.Context, uuid.UUID) {
+	fake.requestPauseMutex.RLock()
+	defer fake.requestPauseMutex.RUnlock()
+	argsForCall := fake.requestPauseArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeProcessStore) RequestPauseReturns(result1 error) {
+	fake.requestPauseMutex.Lock()
+	defer fake.requestPauseMutex.Unlock()
+	fake.RequestPauseStub = nil
+	fake.requestPauseReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeProcessStore) RequestPauseReturnsOnCall(i int, result1 error) {
+	fake.requestPauseMutex.Lock()
+	defer fake.requestPauseMutex.Unlock()
+	fake.RequestPauseStub = nil
+	if fake.requestPauseReturnsOnCall == nil {
+		fake.requestPauseReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.requestPauseReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeProcessStore) RequestStop(arg1 context.Context, arg2 uuid.UUID, arg3 string) error {
+	fake.requestStopMutex.Lock()
+	ret, specificReturn := fake.requestStopReturnsOnCall[len(fake.requestStopArgsForCall)]
+	fake.requestStopArgsForCall = append(fake.requestStopArgsForCall, struct {
+		arg1 context.Context
+		arg2 uuid.UUID
+		arg3 string
+	}{arg1, arg2, arg3})
+	stub := fake.RequestStopStub
+	fakeReturns := fake.requestStopReturns
+	fake.recordInvocation("RequestStop", []interface{}{arg1, arg2, arg3})
+	fake.requestStopMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeProcessStore) RequestStopCallCount() int {
+	fake.requestStopMutex.RLock()
+	defer fake.requestStopMutex.RUnlock()
+	return len(fake.requestStopArgsForCall)
+}
+
+func (fake *FakeProcessStore) RequestStopCalls(stub func(context.Context, uuid.UUID, string) error) {
+	fake.requestStopMutex.Lock()
+	defer fake.requestStopMutex.Unlock()
+	fake.RequestStopStub = stub
+}
+
+func (fake *FakeProcessStore) RequestStopArgsForCall(i int) (context.Context, uuid.UUID, string) {
+	fake.requestStopMutex.RLock()
+	defer fake.requestStopMutex.RUnlock()
+	argsForCall := fake.requestStopArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeProcessStore) RequestStopReturns(result1 error) {
+	fake.requestStopMutex.Lock()
+	defer fake.requestStopMutex.Unlock()
+	fake.RequestStopStub = nil
+	fake.requestStopReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeProcessStore) RequestStopReturnsOnCall(i int, result1 error) {
+	fake.requestStopMutex.Lock()
+	defer fake.requestStopMutex.Unlock()
+	fake.RequestStopStub = nil
+	if fake.requestStopReturnsOnCall == nil {
+		fake.requestStopReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.requestStopReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeProcessStore) ResumeProcess(arg1 context.Context, arg2 uuid.UUID, arg3 func(context.Context, model.ProcessRun) error) error {
+	fake.resumeProcessMutex.Lock()
+	ret, specificReturn := fake.resumeProcessReturnsOnCall[len(fake.resumeProcessArgsForCall)]
+	fake.resumeProcessArgsForCall = append(fake.resumeProcessArgsForCall, struct {
 		arg1 context.Context
 		arg2 uuid.UUID
-		arg3 model.ProcessStatus
+		arg3 func(context.Context, model.ProcessRun) error
 	}{arg1, arg2, arg3})
-	stub := fake.UpdateProcessStatusStub
-	fakeReturns := fake.updateProcessStatusReturns
-	fake.recordInvocation("UpdateProcessStatus", []interface{}{arg1, arg2, arg3})
-	fake.updateProcessStatusMutex.Unlock()
+	stub := fake.ResumeProcessStub
+	fakeReturns := fake.resumeProcessReturns
+	fake.recordInvocation("ResumeProcess", []interface{}{arg1, arg2, arg3})
+	fake.resumeProcessMutex.Unlock()
 	if stub != nil {
 		return stub(arg1, arg2, arg3)
 	}
@@ -284,44 +668,44 @@ func (fake *FakeProcessStore) UpdateProcessStatus(arg1 context.Context, arg2 uui
 	return fakeReturns.result1
 }
 
-func (fake *FakeProcessStore) UpdateProcessStatusCallCount() int {
-	fake.updateProcessStatusMutex.RLock()
-	defer fake.updateProcessStatusMutex.RUnlock()
-	return len(fake.updateProcessStatusArgsForCall)
+func (fake *FakeProcessStore) ResumeProcessCallCount() int {
+	fake.resumeProcessMutex.RLock()
+	defer fake.resumeProcessMutex.RUnlock()
+	return len(fake.resumeProcessArgsForCall)
 }
 
-func (fake *FakeProcessStore) UpdateProcessStatusCalls(stub func(context.Context, uuid.UUID, model.ProcessStatus) error) {
-	fake.updateProcessStatusMutex.Lock()
-	defer fake.updateProcessStatusMutex.Unlock()
-	fake.UpdateProcessStatusStub = stub
+func (fake *FakeProcessStore) ResumeProcessCalls(stub func(context.Context, uuid.UUID, func(context.Context, model.ProcessRun) error) error) {
+	fake.resumeProcessMutex.Lock()
+	defer fake.resumeProcessMutex.Unlock()
+	fake.ResumeProcessStub = stub
 }
 
-func (fake *FakeProcessStore) UpdateProcessStatusArgsForCall(i int) (context.Context, uuid.UUID, model.ProcessStatus) {
-	fake.updateProcessStatusMutex.RLock()
-	defer fake.updateProcessStatusMutex.RUnlock()
-	argsForCall := fake.updateProcessStatusArgsForCall[i]
+func (fake *FakeProcessStore) ResumeProcessArgsForCall(i int) (context.Context, uuid.UUID, func(context.Context, model.ProcessRun) error) {
+	fake.resumeProcessMutex.RLock()
+	defer fake.resumeProcessMutex.RUnlock()
+	argsForCall := fake.resumeProcessArgsForCall[i]
 	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
-func (fake *FakeProcessStore) UpdateProcessStatusReturns(result1 error) {
-	fake.updateProcessStatusMutex.Lock()
-	defer fake.updateProcessStatusMutex.Unlock()
-	fake.UpdateProcessStatusStub = nil
-	fake.updateProcessStatusReturns = struct {
+func (fake *FakeProcessStore) ResumeProcessReturns(result1 error) {
+	fake.resumeProcessMutex.Lock()
+	defer fake.resumeProcessMutex.Unlock()
+	fake.ResumeProcessStub = nil
+	fake.resumeProcessReturns = struct {
 		result1 error
 	}{result1}
 }
 
-func (fake *FakeProcessStore) UpdateProcessStatusReturnsOnCall(i int, result1 error) {
-	fake.updateProcessStatusMutex.Lock()
-	defer fake.updateProcessStatusMutex.Unlock()
-	fake.UpdateProcessStatusStub = nil
-	if fake.updateProcessStatusReturnsOnCall == nil {
-		fake.updateProcessStatusReturnsOnCall = make(map[int]struct {
+func (fake *FakeProcessStore) ResumeProcessReturnsOnCall(i int, result1 error) {
+	fake.resumeProcessMutex.Lock()
+	defer fake.resumeProcessMutex.Unlock()
+	fake.ResumeProcessStub = nil
+	if fake.resumeProcessReturnsOnCall == nil {
+		fake.resumeProcessReturnsOnCall = make(map[int]struct {
 			result1 error
 		})
 	}
-	fake.updateProcessStatusReturnsOnCall[i] = struct {
+	fake.resumeProcessReturnsOnCall[i] = struct {
 		result1 error
 	}{result1}
 }
@@ -329,14 +713,24 @@ func (fake *FakeProcessStore) UpdateProcessStatusReturnsOnCall(i int, result1 er
 func (fake *FakeProcessStore) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.cancelScheduledProcessMutex.RLock()
+	defer fake.cancelScheduledProcessMutex.RUnlock()
 	fake.getProcessByIDMutex.RLock()
 	defer fake.getProcessByIDMutex.RUnlock()
 	fake.getProcessLogsMutex.RLock()
 	defer fake.getProcessLogsMutex.RUnlock()
+	fake.getRunAttemptsMutex.RLock()
+	defer fake.getRunAttemptsMutex.RUnlock()
+	fake.getTaskRunsMutex.RLock()
+	defer fake.getTaskRunsMutex.RUnlock()
 	fake.listRunningProcessesMutex.RLock()
 	defer fake.listRunningProcessesMutex.RUnlock()
-	fake.updateProcessStatusMutex.RLock()
-	defer fake.updateProcessStatusMutex.RUnlock()
+	fake.requestPauseMutex.RLock()
+	defer fake.requestPauseMutex.RUnlock()
+	fake.requestStopMutex.RLock()
+	defer fake.requestStopMutex.RUnlock()
+	fake.resumeProcessMutex.RLock()
+	defer fake.resumeProcessMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
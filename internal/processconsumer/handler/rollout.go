@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"slices"
+
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/model"
+)
+
+// summarizeRollouts reports the latest results of the instances of the rolling tasks of a run.
+func summarizeRollouts(def model.ProcessDefinition, taskRuns []model.TaskRun) []model.Rollout {
+	latest := model.LatestTaskRuns(taskRuns)
+
+	var rollouts []model.Rollout
+	index := make(map[string]int)
+	for _, task := range slices.Concat(def.Tasks, def.Finally) {
+		if task.Rolling == nil || task.Group == "" {
+			continue
+		}
+		i, ok := index[task.Group]
+		if !ok {
+			i = len(rollouts)
+			index[task.Group] = i
+			rollouts = append(rollouts, model.Rollout{
+				Task:        task.Group,
+				BatchSize:   max(task.Rolling.BatchSize, 1),
+				MaxFailures: task.Rolling.MaxFailures,
+				Statuses:    make(map[model.TaskStatus]int),
+			})
+		}
+		r := &rollouts[i]
+
+		status := model.TaskStatusPending
+		taskRun, ran := latest[task.Name]
+		if ran {
+			status = taskRun.Status
+		}
+		r.Items = append(r.Items, model.RolloutItem{
+			Item:   task.Item,
+			Task:   task.Name,
+			Batch:  len(r.Items)/r.BatchSize + 1,
+			Status: status,
+			Error:  taskRun.Error,
+		})
+		r.Statuses[status]++
+		if status == model.TaskStatusFailed || status == model.TaskStatusTimedOut {
+			r.Failures++
+		}
+	}
+
+	for i := range rollouts {
+		r := &rollouts[i]
+		r.Batches = (len(r.Items) + r.BatchSize - 1) / r.BatchSize
+		r.Aborted = r.Failures > r.MaxFailures
+	}
+	return rollouts
+}
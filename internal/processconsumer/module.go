@@ -3,20 +3,30 @@ package consumer
 import (
 	"context"
 	"fmt"
+	"os"
+	"time"
 
 	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/lifecycle"
 	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/logger"
 	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/model"
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/processconsumer/approval"
 	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/processconsumer/handler"
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/processconsumer/reaper"
 	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/processconsumer/service"
 	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/processconsumer/service/executor"
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/processconsumer/service/registry"
 	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/processconsumer/store"
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/labstack/echo/v4"
 )
 
+// approvalExpiryInterval is how often approvals are checked for their timeout.
+const approvalExpiryInterval = 10 * time.Second
+
 type Consumer interface {
-	Consume(context.Context, func(context.Context, model.Message) error) error
+	Consume(context.Context, int, func(context.Context, model.Message) error) error
+	Publish(context.Context, model.Message) error
 	Close() error
 }
 
@@ -26,21 +36,46 @@ func Process(
 	srv *echo.Echo,
 	pool *pgxpool.Pool,
 	consumer Consumer,
+	workers int,
+	leaseTTL time.Duration,
 ) {
+	processStore := store.NewProcessDBStore(pool)
+	messageStore := store.NewStore(pool)
+	runRegistry := registry.NewRegistry()
+	lease := service.Lease{Owner: consumerID(), TTL: leaseTTL}
+	approvals := approval.NewApprovals(processStore, consumer)
+
+	procSpawnFn(func(ctx context.Context) error {
+		if err := processStore.ListenRunRequests(ctx, runRegistry.Cancel, runRegistry.Pause); err != nil {
+			return fmt.Errorf("run request listener failed: %w", err)
+		}
+		return nil
+	}, "Run Request Listener")
+
+	procSpawnFn(func(ctx context.Context) error {
+		// Claims are checked once per lease, so runs of lost consumers are found within two leases.
+		reaper.NewReaper(messageStore, processStore, consumer, lease.Owner, lease.TTL).Run(ctx, lease.TTL)
+		return nil
+	}, "Orphan Reaper")
+
 	procSpawnFn(func(ctx context.Context) error {
-		processStore := store.NewProcessDBStore(pool)
-		messageStore := store.NewStore(pool)
+		approvals.Run(ctx, approvalExpiryInterval)
+		return nil
+	}, "Approval Expiry")
 
-		handler.RegisterHandlers(ctx, srv, processStore)
+	procSpawnFn(func(ctx context.Context) error {
+		handler.RegisterHandlers(ctx, srv, processStore, consumer, approvals)
 
 		taskHandlers := map[model.ClassType]service.Executor{
 			model.LocalCmd: executor.NewLocalCmdService(),
 			model.SshCmd:   executor.NewSSHCmdExecutor(),
 			model.ScpCmd:   executor.NewSCPCmdExecutor(),
+
+			model.ApprovalCmd: executor.NewApprovalExecutor(),
 		}
 
-		processHandlerSvc := service.NewService(messageStore, processStore, taskHandlers)
-		err := consumer.Consume(ctx, processHandlerSvc.Run)
+		processHandlerSvc := service.NewService(messageStore, processStore, taskHandlers, runRegistry, lease)
+		err := consumer.Consume(ctx, workers, processHandlerSvc.Run)
 		if err != nil {
 			return fmt.Errorf("consume failed: %w", err)
 		}
@@ -55,3 +90,12 @@ func Process(
 		return nil
 	}, "Consumer")
 }
+
+// consumerID identifies this consumer instance in the claims it takes on messages.
+func consumerID() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "consumer"
+	}
+	return hostname + "-" + uuid.NewString()[:8]
+}
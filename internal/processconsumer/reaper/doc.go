@@ -0,0 +1,4 @@
+// Package reaper recovers the runs of consumers that stopped heartbeating.
+package reaper
+
+//go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 -generate
@@ -0,0 +1,165 @@
+package reaper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/logger"
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/model"
+	"github.com/google/uuid"
+)
+
+//counterfeiter:generate . MessageStore
+type MessageStore interface {
+	ClaimExpiredMessages(context.Context, string, time.Duration) ([]model.ClaimedMessage, error)
+	CompleteMessage(context.Context, uuid.UUID, string) error
+	ReleaseMessage(context.Context, uuid.UUID, string) error
+}
+
+//counterfeiter:generate . ProcessStore
+type ProcessStore interface {
+	GetProcessByID(context.Context, uuid.UUID) (model.ProcessRun, error)
+	AbandonProcess(context.Context, uuid.UUID, int, string) error
+	AppendProcessLog(context.Context, uuid.UUID, string) error
+}
+
+//counterfeiter:generate . Publisher
+type Publisher interface {
+	Publish(context.Context, model.Message) error
+}
+
+// Reaper periodically takes over the messages whose claim expired and deals with their
+// runs according to the onWorkerLoss policy of their definition.
+type Reaper struct {
+	store        MessageStore
+	processStore ProcessStore
+	publisher    Publisher
+	owner        string
+	ttl          time.Duration
+}
+
+// NewReaper creates a reaper that claims expired messages as owner for ttl.
+func NewReaper(store MessageStore, processStore ProcessStore, publisher Publisher, owner string, ttl time.Duration) *Reaper {
+	return &Reaper{
+		store:        store,
+		processStore: processStore,
+		publisher:    publisher,
+		owner:        owner,
+		ttl:          ttl,
+	}
+}
+
+// Run reaps expired claims every interval until the context is done.
+func (r *Reaper) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := r.Reap(ctx); err != nil && ctx.Err() == nil {
+			logger.GetLogger().Errorf("failed to reap orphaned runs: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Reap takes over all expired claims and recovers their runs.
+func (r *Reaper) Reap(ctx context.Context) error {
+	orphans, err := r.store.ClaimExpiredMessages(ctx, r.owner, r.ttl)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, orphan := range orphans {
+		if err := r.recover(ctx, orphan); err != nil {
+			errs = append(errs, fmt.Errorf("message %s: %w", orphan.Message.UUID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (r *Reaper) recover(ctx context.Context, orphan model.ClaimedMessage) error {
+	msg := orphan.Message
+	runID := msg.UUID
+	if msg.RetryOf != nil {
+		runID = *msg.RetryOf
+	}
+	if msg.ContinueOf != nil {
+		runID = *msg.ContinueOf
+	}
+
+	run, err := r.processStore.GetProcessByID(ctx, runID)
+	if err != nil {
+		return fmt.Errorf("failed to get process: %w", err)
+	}
+
+	notStarted := run.Status == model.StatusQueued ||
+		(msg.RetryOf != nil && slices.Contains(model.RetryableStatuses, run.Status)) ||
+		(msg.ContinueOf != nil && (run.Status == model.StatusAwaitingApproval || run.Status == model.StatusPaused))
+	if notStarted {
+		// The consumer was lost before it started the run, so it is safe to hand the
+		// message to another consumer.
+		return r.requeue(ctx, orphan, runID)
+	}
+
+	if run.Status == model.StatusRunning {
+		reason := fmt.Sprintf("consumer %s stopped heartbeating", orphan.Owner)
+		err := r.processStore.AbandonProcess(ctx, runID, run.Attempt, reason)
+		if errors.Is(err, model.ErrProcessNotRunning) {
+			// The attempt ended after it was read, so there is nothing left to recover.
+			logger.GetLogger().Infof("Completing message %s of consumer %s, attempt %d of its process %s already ended", msg.UUID, orphan.Owner, run.Attempt, runID)
+			return r.store.CompleteMessage(ctx, msg.UUID, r.owner)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to abandon process: %w", err)
+		}
+		r.appendLog(ctx, runID, fmt.Sprintf("Process abandoned: %s", reason))
+		logger.GetLogger().Warnf("Abandoned process %s: %s", runID, reason)
+		run.Status = model.StatusAbandoned
+	}
+
+	// An abandoned run is only found here again if requesting its retry failed before.
+	if run.Status == model.StatusAbandoned && run.Definition.OnWorkerLoss == model.WorkerLossRetry {
+		retry := model.Message{
+			UUID:              uuid.New(),
+			ProcessDefinition: run.Definition,
+			RetryOf:           &runID,
+			Priority:          run.Priority,
+		}
+		if err := r.publisher.Publish(ctx, retry); err != nil {
+			return fmt.Errorf("failed to request retry of process: %w", err)
+		}
+		r.appendLog(ctx, runID, "Retry requested after losing the consumer")
+		logger.GetLogger().Infof("Requested retry of abandoned process %s", runID)
+	} else if run.Status != model.StatusAbandoned {
+		logger.GetLogger().Infof("Completing message %s of consumer %s, its process %s already ended as %s", msg.UUID, orphan.Owner, runID, run.Status)
+	}
+
+	return r.store.CompleteMessage(ctx, msg.UUID, r.owner)
+}
+
+func (r *Reaper) appendLog(ctx context.Context, runID uuid.UUID, msg string) {
+	if err := r.processStore.AppendProcessLog(ctx, runID, msg); err != nil {
+		logger.GetLogger().Warnf("failed to append to log of process %s: %v", runID, err)
+	}
+}
+
+// requeue releases the message and publishes it again.
+func (r *Reaper) requeue(ctx context.Context, orphan model.ClaimedMessage, runID uuid.UUID) error {
+	if err := r.store.ReleaseMessage(ctx, orphan.Message.UUID, r.owner); err != nil {
+		return fmt.Errorf("failed to release message: %w", err)
+	}
+	if err := r.publisher.Publish(ctx, orphan.Message); err != nil {
+		return fmt.Errorf("failed to requeue message: %w", err)
+	}
+	logger.GetLogger().Infof("Requeued message %s of consumer %s, its process %s had not started", orphan.Message.UUID, orphan.Owner, runID)
+	return nil
+}
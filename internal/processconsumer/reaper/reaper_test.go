@@ -0,0 +1,238 @@
+package reaper_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/model"
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/processconsumer/reaper"
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/processconsumer/reaper/reaperfakes"
+)
+
+var (
+	ErrDb      = errors.New("db error")
+	ErrPublish = errors.New("publish error")
+)
+
+var _ = Describe("Reaper", func() {
+	var (
+		ctx          context.Context
+		store        *reaperfakes.FakeMessageStore
+		processStore *reaperfakes.FakeProcessStore
+		publisher    *reaperfakes.FakePublisher
+		r            *reaper.Reaper
+		msg          model.Message
+		run          model.ProcessRun
+		errAction    error
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		store = &reaperfakes.FakeMessageStore{}
+		processStore = &reaperfakes.FakeProcessStore{}
+		publisher = &reaperfakes.FakePublisher{}
+		r = reaper.NewReaper(store, processStore, publisher, "consumer-2", time.Minute)
+
+		msg = model.Message{
+			UUID:              uuid.New(),
+			ProcessDefinition: model.ProcessDefinition{Name: "deploy"},
+		}
+		run = model.ProcessRun{
+			ID:         msg.UUID,
+			Definition: msg.ProcessDefinition,
+			Status:     model.StatusRunning,
+			Attempt:    2,
+		}
+
+		store.ClaimExpiredMessagesReturns([]model.ClaimedMessage{{Message: msg, Owner: "consumer-1"}}, nil)
+		processStore.GetProcessByIDStub = func(context.Context, uuid.UUID) (model.ProcessRun, error) {
+			return run, nil
+		}
+	})
+
+	JustBeforeEach(func() {
+		errAction = r.Reap(ctx)
+	})
+
+	It("claims expired messages as the reaper", func() {
+		Expect(errAction).NotTo(HaveOccurred())
+		_, owner, ttl := store.ClaimExpiredMessagesArgsForCall(0)
+		Expect(owner).To(Equal("consumer-2"))
+		Expect(ttl).To(Equal(time.Minute))
+	})
+
+	It("abandons the running attempt of the process", func() {
+		Expect(processStore.AbandonProcessCallCount()).To(Equal(1))
+		_, id, attempt, reason := processStore.AbandonProcessArgsForCall(0)
+		Expect(id).To(Equal(run.ID))
+		Expect(attempt).To(Equal(2))
+		Expect(reason).To(Equal("consumer consumer-1 stopped heartbeating"))
+	})
+
+	It("logs it in the process log", func() {
+		Expect(processStore.AppendProcessLogCallCount()).To(Equal(1))
+		_, _, log := processStore.AppendProcessLogArgsForCall(0)
+		Expect(log).To(Equal("Process abandoned: consumer consumer-1 stopped heartbeating"))
+	})
+
+	It("completes the message without retrying the process", func() {
+		Expect(publisher.PublishCallCount()).To(Equal(0))
+		Expect(store.CompleteMessageCallCount()).To(Equal(1))
+		_, id, owner := store.CompleteMessageArgsForCall(0)
+		Expect(id).To(Equal(msg.UUID))
+		Expect(owner).To(Equal("consumer-2"))
+	})
+
+	When("the definition retries on worker loss", func() {
+		BeforeEach(func() {
+			run.Definition.OnWorkerLoss = model.WorkerLossRetry
+		})
+
+		It("requests a retry of the process", func() {
+			Expect(errAction).NotTo(HaveOccurred())
+			Expect(publisher.PublishCallCount()).To(Equal(1))
+			_, retry := publisher.PublishArgsForCall(0)
+			Expect(retry.UUID).NotTo(Equal(msg.UUID))
+			Expect(retry.RetryOf).To(Equal(&run.ID))
+			Expect(retry.ProcessDefinition).To(Equal(run.Definition))
+			Expect(store.CompleteMessageCallCount()).To(Equal(1))
+		})
+
+		When("requesting the retry fails", func() {
+			BeforeEach(func() {
+				publisher.PublishReturns(ErrPublish)
+			})
+
+			It("keeps the message claimed", func() {
+				Expect(errAction).To(MatchError(ErrPublish))
+				Expect(store.CompleteMessageCallCount()).To(Equal(0))
+			})
+		})
+
+		When("the process was already abandoned", func() {
+			BeforeEach(func() {
+				run.Status = model.StatusAbandoned
+			})
+
+			It("only requests the retry", func() {
+				Expect(processStore.AbandonProcessCallCount()).To(Equal(0))
+				Expect(publisher.PublishCallCount()).To(Equal(1))
+				Expect(store.CompleteMessageCallCount()).To(Equal(1))
+			})
+		})
+	})
+
+	When("the process was not started yet", func() {
+		BeforeEach(func() {
+			run.Status = model.StatusQueued
+		})
+
+		It("releases and requeues the message", func() {
+			Expect(errAction).NotTo(HaveOccurred())
+			Expect(processStore.AbandonProcessCallCount()).To(Equal(0))
+			Expect(store.ReleaseMessageCallCount()).To(Equal(1))
+			Expect(publisher.PublishCallCount()).To(Equal(1))
+			_, requeued := publisher.PublishArgsForCall(0)
+			Expect(requeued).To(Equal(msg))
+			Expect(store.CompleteMessageCallCount()).To(Equal(0))
+		})
+	})
+
+	When("the message is a retry that was not started yet", func() {
+		BeforeEach(func() {
+			msg.RetryOf = &run.ID
+			msg.UUID = uuid.New()
+			run.Status = model.StatusFailed
+			store.ClaimExpiredMessagesReturns([]model.ClaimedMessage{{Message: msg, Owner: "consumer-1"}}, nil)
+		})
+
+		It("requeues the message", func() {
+			_, id := processStore.GetProcessByIDArgsForCall(0)
+			Expect(id).To(Equal(run.ID))
+			Expect(store.ReleaseMessageCallCount()).To(Equal(1))
+			Expect(publisher.PublishCallCount()).To(Equal(1))
+		})
+	})
+
+	When("the message continues a parked run that was not started yet", func() {
+		BeforeEach(func() {
+			msg.ContinueOf = &run.ID
+			msg.UUID = uuid.New()
+			run.Status = model.StatusAwaitingApproval
+			store.ClaimExpiredMessagesReturns([]model.ClaimedMessage{{Message: msg, Owner: "consumer-1"}}, nil)
+		})
+
+		It("requeues the message", func() {
+			_, id := processStore.GetProcessByIDArgsForCall(0)
+			Expect(id).To(Equal(run.ID))
+			Expect(processStore.AbandonProcessCallCount()).To(Equal(0))
+			Expect(store.ReleaseMessageCallCount()).To(Equal(1))
+			Expect(publisher.PublishCallCount()).To(Equal(1))
+		})
+	})
+
+	When("the message resumes a paused run that was not started yet", func() {
+		BeforeEach(func() {
+			msg.ContinueOf = &run.ID
+			msg.UUID = uuid.New()
+			run.Status = model.StatusPaused
+			store.ClaimExpiredMessagesReturns([]model.ClaimedMessage{{Message: msg, Owner: "consumer-1"}}, nil)
+		})
+
+		It("requeues the message", func() {
+			Expect(store.ReleaseMessageCallCount()).To(Equal(1))
+			Expect(publisher.PublishCallCount()).To(Equal(1))
+		})
+	})
+
+	When("the process already ended", func() {
+		BeforeEach(func() {
+			run.Status = model.StatusCompleted
+		})
+
+		It("only completes the message", func() {
+			Expect(errAction).NotTo(HaveOccurred())
+			Expect(processStore.AbandonProcessCallCount()).To(Equal(0))
+			Expect(publisher.PublishCallCount()).To(Equal(0))
+			Expect(store.CompleteMessageCallCount()).To(Equal(1))
+		})
+	})
+
+	When("claiming expired messages fails", func() {
+		BeforeEach(func() {
+			store.ClaimExpiredMessagesReturns(nil, ErrDb)
+		})
+
+		It("returns an error", func() {
+			Expect(errAction).To(MatchError(ErrDb))
+		})
+	})
+
+	When("the attempt ends before it is abandoned", func() {
+		BeforeEach(func() {
+			processStore.AbandonProcessReturns(model.ErrProcessNotRunning)
+		})
+
+		It("completes the message without retrying the process", func() {
+			Expect(errAction).NotTo(HaveOccurred())
+			Expect(publisher.PublishCallCount()).To(Equal(0))
+			Expect(store.CompleteMessageCallCount()).To(Equal(1))
+		})
+	})
+
+	When("abandoning the process fails", func() {
+		BeforeEach(func() {
+			processStore.AbandonProcessReturns(ErrDb)
+		})
+
+		It("returns an error and keeps the message claimed", func() {
+			Expect(errAction).To(MatchError(ErrDb))
+			Expect(store.CompleteMessageCallCount()).To(Equal(0))
+		})
+	})
+})
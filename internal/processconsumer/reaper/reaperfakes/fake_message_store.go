@@ -0,0 +1,280 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package reaperfakes
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/model"
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/processconsumer/reaper"
+	"github.com/google/uuid"
+)
+
+type FakeMessageStore struct {
+	ClaimExpiredMessagesStub        func(context.Context, string, time.Duration) ([]model.ClaimedMessage, error)
+	claimExpiredMessagesMutex       sync.RWMutex
+	claimExpiredMessagesArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 time.Duration
+	}
+	claimExpiredMessagesReturns struct {
+		result1 []model.ClaimedMessage
+		result2 error
+	}
+	claimExpiredMessagesReturnsOnCall map[int]struct {
+		result1 []model.ClaimedMessage
+		result2 error
+	}
+	CompleteMessageStub        func(context.Context, uuid.UUID, string) error
+	completeMessageMutex       sync.RWMutex
+	completeMessageArgsForCall []struct {
+		arg1 context.Context
+		arg2 uuid.UUID
+		arg3 string
+	}
+	completeMessageReturns struct {
+		result1 error
+	}
+	completeMessageReturnsOnCall map[int]struct {
+		result1 error
+	}
+	ReleaseMessageStub        func(context.Context, uuid.UUID, string) error
+	releaseMessageMutex       sync.RWMutex
+	releaseMessageArgsForCall []struct {
+		arg1 context.Context
+		arg2 uuid.UUID
+		arg3 string
+	}
+	releaseMessageReturns struct {
+		result1 error
+	}
+	releaseMessageReturnsOnCall map[int]struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeMessageStore) ClaimExpiredMessages(arg1 context.Context, arg2 string, arg3 time.Duration) ([]model.ClaimedMessage, error) {
+	fake.claimExpiredMessagesMutex.Lock()
+	ret, specificReturn := fake.claimExpiredMessagesReturnsOnCall[len(fake.claimExpiredMessagesArgsForCall)]
+	fake.claimExpiredMessagesArgsForCall = append(fake.claimExpiredMessagesArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 time.Duration
+	}{arg1, arg2, arg3})
+	stub := fake.ClaimExpiredMessagesStub
+	fakeReturns := fake.claimExpiredMessagesReturns
+	fake.recordInvocation("ClaimExpiredMessages", []interface{}{arg1, arg2, arg3})
+	fake.claimExpiredMessagesMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeMessageStore) ClaimExpiredMessagesCallCount() int {
+	fake.claimExpiredMessagesMutex.RLock()
+	defer fake.claimExpiredMessagesMutex.RUnlock()
+	return len(fake.claimExpiredMessagesArgsForCall)
+}
+
+func (fake *FakeMessageStore) ClaimExpiredMessagesCalls(stub func(context.Context, string, time.Duration) ([]model.ClaimedMessage, error)) {
+	fake.claimExpiredMessagesMutex.Lock()
+	defer fake.claimExpiredMessagesMutex.Unlock()
+	fake.ClaimExpiredMessagesStub = stub
+}
+
+func (fake *FakeMessageStore) ClaimExpiredMessagesArgsForCall(i int) (context.Context, string, time.Duration) {
+	fake.claimExpiredMessagesMutex.RLock()
+	defer fake.claimExpiredMessagesMutex.RUnlock()
+	argsForCall := fake.claimExpiredMessagesArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeMessageStore) ClaimExpiredMessagesReturns(result1 []model.ClaimedMessage, result2 error) {
+	fake.claimExpiredMessagesMutex.Lock()
+	defer fake.claimExpiredMessagesMutex.Unlock()
+	fake.ClaimExpiredMessagesStub = nil
+	fake.claimExpiredMessagesReturns = struct {
+		result1 []model.ClaimedMessage
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeMessageStore) ClaimExpiredMessagesReturnsOnCall(i int, result1 []model.ClaimedMessage, result2 error) {
+	fake.claimExpiredMessagesMutex.Lock()
+	defer fake.claimExpiredMessagesMutex.Unlock()
+	fake.ClaimExpiredMessagesStub = nil
+	if fake.claimExpiredMessagesReturnsOnCall == nil {
+		fake.claimExpiredMessagesReturnsOnCall = make(map[int]struct {
+			result1 []model.ClaimedMessage
+			result2 error
+		})
+	}
+	fake.claimExpiredMessagesReturnsOnCall[i] = struct {
+		result1 []model.ClaimedMessage
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeMessageStore) CompleteMessage(arg1 context.Context, arg2 uuid.UUID, arg3 string) error {
+	fake.completeMessageMutex.Lock()
+	ret, specificReturn := fake.completeMessageReturnsOnCall[len(fake.completeMessageArgsForCall)]
+	fake.completeMessageArgsForCall = append(fake.completeMessageArgsForCall, struct {
+		arg1 context.Context
+		arg2 uuid.UUID
+		arg3 string
+	}{arg1, arg2, arg3})
+	stub := fake.CompleteMessageStub
+	fakeReturns := fake.completeMessageReturns
+	fake.recordInvocation("CompleteMessage", []interface{}{arg1, arg2, arg3})
+	fake.completeMessageMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeMessageStore) CompleteMessageCallCount() int {
+	fake.completeMessageMutex.RLock()
+	defer fake.completeMessageMutex.RUnlock()
+	return len(fake.completeMessageArgsForCall)
+}
+
+func (fake *FakeMessageStore) CompleteMessageCalls(stub func(context.Context, uuid.UUID, string) error) {
+	fake.completeMessageMutex.Lock()
+	defer fake.completeMessageMutex.Unlock()
+	fake.CompleteMessageStub = stub
+}
+
+func (fake *FakeMessageStore) CompleteMessageArgsForCall(i int) (context.Context, uuid.UUID, string) {
+	fake.completeMessageMutex.RLock()
+	defer fake.completeMessageMutex.RUnlock()
+	argsForCall := fake.completeMessageArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeMessageStore) CompleteMessageReturns(result1 error) {
+	fake.completeMessageMutex.Lock()
+	defer fake.completeMessageMutex.Unlock()
+	fake.CompleteMessageStub = nil
+	fake.completeMessageReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeMessageStore) CompleteMessageReturnsOnCall(i int, result1 error) {
+	fake.completeMessageMutex.Lock()
+	defer fake.completeMessageMutex.Unlock()
+	fake.CompleteMessageStub = nil
+	if fake.completeMessageReturnsOnCall == nil {
+		fake.completeMessageReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.completeMessageReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeMessageStore) ReleaseMessage(arg1 context.Context, arg2 uuid.UUID, arg3 string) error {
+	fake.releaseMessageMutex.Lock()
+	ret, specificReturn := fake.releaseMessageReturnsOnCall[len(fake.releaseMessageArgsForCall)]
+	fake.releaseMessageArgsForCall = append(fake.releaseMessageArgsForCall, struct {
+		arg1 context.Context
+		arg2 uuid.UUID
+		arg3 string
+	}{arg1, arg2, arg3})
+	stub := fake.ReleaseMessageStub
+	fakeReturns := fake.releaseMessageReturns
+	fake.recordInvocation("ReleaseMessage", []interface{}{arg1, arg2, arg3})
+	fake.releaseMessageMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeMessageStore) ReleaseMessageCallCount() int {
+	fake.releaseMessageMutex.RLock()
+	defer fake.releaseMessageMutex.RUnlock()
+	return len(fake.releaseMessageArgsForCall)
+}
+
+func (fake *FakeMessageStore) ReleaseMessageCalls(stub func(context.Context, uuid.UUID, string) error) {
+	fake.releaseMessageMutex.Lock()
+	defer fake.releaseMessageMutex.Unlock()
+	fake.ReleaseMessageStub = stub
+}
+
+func (fake *FakeMessageStore) ReleaseMessageArgsForCall(i int) (context.Context, uuid.UUID, string) {
+	fake.releaseMessageMutex.RLock()
+	defer fake.releaseMessageMutex.RUnlock()
+	argsForCall := fake.releaseMessageArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeMessageStore) ReleaseMessageReturns(result1 error) {
+	fake.releaseMessageMutex.Lock()
+	defer fake.releaseMessageMutex.Unlock()
+	fake.ReleaseMessageStub = nil
+	fake.releaseMessageReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeMessageStore) ReleaseMessageReturnsOnCall(i int, result1 error) {
+	fake.releaseMessageMutex.Lock()
+	defer fake.releaseMessageMutex.Unlock()
+	fake.ReleaseMessageStub = nil
+	if fake.releaseMessageReturnsOnCall == nil {
+		fake.releaseMessageReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.releaseMessageReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeMessageStore) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.claimExpiredMessagesMutex.RLock()
+	defer fake.claimExpiredMessagesMutex.RUnlock()
+	fake.completeMessageMutex.RLock()
+	defer fake.completeMessageMutex.RUnlock()
+	fake.releaseMessageMutex.RLock()
+	defer fake.releaseMessageMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeMessageStore) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ reaper.MessageStore = new(FakeMessageStore)
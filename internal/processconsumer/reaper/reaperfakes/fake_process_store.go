@@ -0,0 +1,279 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package reaperfakes
+
+import (
+	"context"
+	"sync"
+
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/model"
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/processconsumer/reaper"
+	"github.com/google/uuid"
+)
+
+type FakeProcessStore struct {
+	AbandonProcessStub        func(context.Context, uuid.UUID, int, string) error
+	abandonProcessMutex       sync.RWMutex
+	abandonProcessArgsForCall []struct {
+		arg1 context.Context
+		arg2 uuid.UUID
+		arg3 int
+		arg4 string
+	}
+	abandonProcessReturns struct {
+		result1 error
+	}
+	abandonProcessReturnsOnCall map[int]struct {
+		result1 error
+	}
+	AppendProcessLogStub        func(context.Context, uuid.UUID, string) error
+	appendProcessLogMutex       sync.RWMutex
+	appendProcessLogArgsForCall []struct {
+		arg1 context.Context
+		arg2 uuid.UUID
+		arg3 string
+	}
+	appendProcessLogReturns struct {
+		result1 error
+	}
+	appendProcessLogReturnsOnCall map[int]struct {
+		result1 error
+	}
+	GetProcessByIDStub        func(context.Context, uuid.UUID) (model.ProcessRun, error)
+	getProcessByIDMutex       sync.RWMutex
+	getProcessByIDArgsForCall []struct {
+		arg1 context.Context
+		arg2 uuid.UUID
+	}
+	getProcessByIDReturns struct {
+		result1 model.ProcessRun
+		result2 error
+	}
+	getProcessByIDReturnsOnCall map[int]struct {
+		result1 model.ProcessRun
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeProcessStore) AbandonProcess(arg1 context.Context, arg2 uuid.UUID, arg3 int, arg4 string) error {
+	fake.abandonProcessMutex.Lock()
+	ret, specificReturn := fake.abandonProcessReturnsOnCall[len(fake.abandonProcessArgsForCall)]
+	fake.abandonProcessArgsForCall = append(fake.abandonProcessArgsForCall, struct {
+		arg1 context.Context
+		arg2 uuid.UUID
+		arg3 int
+		arg4 string
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.AbandonProcessStub
+	fakeReturns := fake.abandonProcessReturns
+	fake.recordInvocation("AbandonProcess", []interface{}{arg1, arg2, arg3, arg4})
+	fake.abandonProcessMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeProcessStore) AbandonProcessCallCount() int {
+	fake.abandonProcessMutex.RLock()
+	defer fake.abandonProcessMutex.RUnlock()
+	return len(fake.abandonProcessArgsForCall)
+}
+
+func (fake *FakeProcessStore) AbandonProcessCalls(stub func(context.Context, uuid.UUID, int, string) error) {
+	fake.abandonProcessMutex.Lock()
+	defer fake.abandonProcessMutex.Unlock()
+	fake.AbandonProcessStub = stub
+}
+
+func (fake *FakeProcessStore) AbandonProcessArgsForCall(i int) (context.Context, uuid.UUID, int, string) {
+	fake.abandonProcessMutex.RLock()
+	defer fake.abandonProcessMutex.RUnlock()
+	argsForCall := fake.abandonProcessArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeProcessStore) AbandonProcessReturns(result1 error) {
+	fake.abandonProcessMutex.Lock()
+	defer fake.abandonProcessMutex.Unlock()
+	fake.AbandonProcessStub = nil
+	fake.abandonProcessReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeProcessStore) AbandonProcessReturnsOnCall(i int, result1 error) {
+	fake.abandonProcessMutex.Lock()
+	defer fake.abandonProcessMutex.Unlock()
+	fake.AbandonProcessStub = nil
+	if fake.abandonProcessReturnsOnCall == nil {
+		fake.abandonProcessReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.abandonProcessReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeProcessStore) AppendProcessLog(arg1 context.Context, arg2 uuid.UUID, arg3 string) error {
+	fake.appendProcessLogMutex.Lock()
+	ret, specificReturn := fake.appendProcessLogReturnsOnCall[len(fake.appendProcessLogArgsForCall)]
+	fake.appendProcessLogArgsForCall = append(fake.appendProcessLogArgsForCall, struct {
+		arg1 context.Context
+		arg2 uuid.UUID
+		arg3 string
+	}{arg1, arg2, arg3})
+	stub := fake.AppendProcessLogStub
+	fakeReturns := fake.appendProcessLogReturns
+	fake.recordInvocation("AppendProcessLog", []interface{}{arg1, arg2, arg3})
+	fake.appendProcessLogMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeProcessStore) AppendProcessLogCallCount() int {
+	fake.appendProcessLogMutex.RLock()
+	defer fake.appendProcessLogMutex.RUnlock()
+	return len(fake.appendProcessLogArgsForCall)
+}
+
+func (fake *FakeProcessStore) AppendProcessLogCalls(stub func(context.Context, uuid.UUID, string) error) {
+	fake.appendProcessLogMutex.Lock()
+	defer fake.appendProcessLogMutex.Unlock()
+	fake.AppendProcessLogStub = stub
+}
+
+func (fake *FakeProcessStore) AppendProcessLogArgsForCall(i int) (context.Context, uuid.UUID, string) {
+	fake.appendProcessLogMutex.RLock()
+	defer fake.appendProcessLogMutex.RUnlock()
+	argsForCall := fake.appendProcessLogArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeProcessStore) AppendProcessLogReturns(result1 error) {
+	fake.appendProcessLogMutex.Lock()
+	defer fake.appendProcessLogMutex.Unlock()
+	fake.AppendProcessLogStub = nil
+	fake.appendProcessLogReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeProcessStore) AppendProcessLogReturnsOnCall(i int, result1 error) {
+	fake.appendProcessLogMutex.Lock()
+	defer fake.appendProcessLogMutex.Unlock()
+	fake.AppendProcessLogStub = nil
+	if fake.appendProcessLogReturnsOnCall == nil {
+		fake.appendProcessLogReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.appendProcessLogReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeProcessStore) GetProcessByID(arg1 context.Context, arg2 uuid.UUID) (model.ProcessRun, error) {
+	fake.getProcessByIDMutex.Lock()
+	ret, specificReturn := fake.getProcessByIDReturnsOnCall[len(fake.getProcessByIDArgsForCall)]
+	fake.getProcessByIDArgsForCall = append(fake.getProcessByIDArgsForCall, struct {
+		arg1 context.Context
+		arg2 uuid.UUID
+	}{arg1, arg2})
+	stub := fake.GetProcessByIDStub
+	fakeReturns := fake.getProcessByIDReturns
+	fake.recordInvocation("GetProcessByID", []interface{}{arg1, arg2})
+	fake.getProcessByIDMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeProcessStore) GetProcessByIDCallCount() int {
+	fake.getProcessByIDMutex.RLock()
+	defer fake.getProcessByIDMutex.RUnlock()
+	return len(fake.getProcessByIDArgsForCall)
+}
+
+func (fake *FakeProcessStore) GetProcessByIDCalls(stub func(context.Context, uuid.UUID) (model.ProcessRun, error)) {
+	fake.getProcessByIDMutex.Lock()
+	defer fake.getProcessByIDMutex.Unlock()
+	fake.GetProcessByIDStub = stub
+}
+
+func (fake *FakeProcessStore) GetProcessByIDArgsForCall(i int) (context.Context, uuid.UUID) {
+	fake.getProcessByIDMutex.RLock()
+	defer fake.getProcessByIDMutex.RUnlock()
+	argsForCall := fake.getProcessByIDArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeProcessStore) GetProcessByIDReturns(result1 model.ProcessRun, result2 error) {
+	fake.getProcessByIDMutex.Lock()
+	defer fake.getProcessByIDMutex.Unlock()
+	fake.GetProcessByIDStub = nil
+	fake.getProcessByIDReturns = struct {
+		result1 model.ProcessRun
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeProcessStore) GetProcessByIDReturnsOnCall(i int, result1 model.ProcessRun, result2 error) {
+	fake.getProcessByIDMutex.Lock()
+	defer fake.getProcessByIDMutex.Unlock()
+	fake.GetProcessByIDStub = nil
+	if fake.getProcessByIDReturnsOnCall == nil {
+		fake.getProcessByIDReturnsOnCall = make(map[int]struct {
+			result1 model.ProcessRun
+			result2 error
+		})
+	}
+	fake.getProcessByIDReturnsOnCall[i] = struct {
+		result1 model.ProcessRun
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeProcessStore) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.abandonProcessMutex.RLock()
+	defer fake.abandonProcessMutex.RUnlock()
+	fake.appendProcessLogMutex.RLock()
+	defer fake.appendProcessLogMutex.RUnlock()
+	fake.getProcessByIDMutex.RLock()
+	defer fake.getProcessByIDMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeProcessStore) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ reaper.ProcessStore = new(FakeProcessStore)
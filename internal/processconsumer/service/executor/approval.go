@@ -0,0 +1,28 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/model"
+)
+
+// ApprovalExecutor parks approval tasks. The run is resumed once the task was approved
+// or rejected via the API, so nothing waits for the decision here.
+type ApprovalExecutor struct {
+}
+
+func NewApprovalExecutor() *ApprovalExecutor {
+	return &ApprovalExecutor{}
+}
+
+func (ae *ApprovalExecutor) Run(ctx context.Context, task model.Task, stdout, stderr io.Writer) (model.TaskOutput, error) {
+	msg := fmt.Sprintf("Waiting for approval of task %q", task.Name)
+	if message := task.Parameters["message"]; message != "" {
+		msg = fmt.Sprintf("%s: %s", msg, message)
+	}
+	_, _ = fmt.Fprintln(stdout, msg)
+
+	return model.TaskOutput{Stdout: msg + "\n"}, model.ErrAwaitingApproval
+}
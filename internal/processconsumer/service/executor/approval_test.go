@@ -0,0 +1,56 @@
+package executor_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/model"
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/processconsumer/service/executor"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ApprovalExecutor", func() {
+	var (
+		executorSvc *executor.ApprovalExecutor
+		task        model.Task
+		output      model.TaskOutput
+		stdout      *bytes.Buffer
+		errAction   error
+	)
+
+	JustBeforeEach(func() {
+		output, errAction = executorSvc.Run(context.Background(), task, stdout, io.Discard)
+	})
+
+	BeforeEach(func() {
+		executorSvc = executor.NewApprovalExecutor()
+		stdout = &bytes.Buffer{}
+
+		task = model.Task{
+			Name:       "approve-release",
+			Parameters: map[string]string{"message": "Release 1.2 to production?"},
+		}
+	})
+
+	It("parks the task", func() {
+		Expect(errAction).To(MatchError(model.ErrAwaitingApproval))
+	})
+
+	It("writes the message to stdout", func() {
+		expected := "Waiting for approval of task \"approve-release\": Release 1.2 to production?\n"
+		Expect(stdout.String()).To(Equal(expected))
+		Expect(output.Stdout).To(Equal(expected))
+	})
+
+	When("the task has no message", func() {
+		BeforeEach(func() {
+			task.Parameters = nil
+		})
+
+		It("only names the task", func() {
+			Expect(stdout.String()).To(Equal("Waiting for approval of task \"approve-release\"\n"))
+		})
+	})
+})
@@ -3,15 +3,22 @@ package executor
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os/exec"
 	"runtime"
 	"strings"
+	"time"
 
 	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/logger"
 	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/model"
 )
 
+// localWaitDelay bounds how long a cancelled command may keep its output open, e.g.
+// through processes it left behind.
+const localWaitDelay = 5 * time.Second
+
 type LocalCmdExecutor struct {
 }
 
@@ -19,10 +26,10 @@ func NewLocalCmdService() *LocalCmdExecutor {
 	return &LocalCmdExecutor{}
 }
 
-func (le *LocalCmdExecutor) Run(ctx context.Context, task model.Task) error {
+func (le *LocalCmdExecutor) Run(ctx context.Context, task model.Task, stdout, stderr io.Writer) (model.TaskOutput, error) {
 	cmdStr, ok := task.Parameters["command"]
 	if !ok || strings.TrimSpace(cmdStr) == "" {
-		return fmt.Errorf("missing 'command' parameter in task %q", task.Name)
+		return model.TaskOutput{}, fmt.Errorf("missing 'command' parameter in task %q", task.Name)
 	}
 
 	logger.GetLogger().Infof("Executing local command: %q", cmdStr)
@@ -33,19 +40,32 @@ func (le *LocalCmdExecutor) Run(ctx context.Context, task model.Task) error {
 	} else {
 		cmd = exec.CommandContext(ctx, "sh", "-c", cmdStr)
 	}
+	killProcessGroup(cmd)
+	cmd.WaitDelay = localWaitDelay
 
 	var stdoutBuf, stderrBuf bytes.Buffer
-	cmd.Stdout = &stdoutBuf
-	cmd.Stderr = &stderrBuf
+	cmd.Stdout = io.MultiWriter(&stdoutBuf, stdout)
+	cmd.Stderr = io.MultiWriter(&stderrBuf, stderr)
 
 	err := cmd.Run()
-	output := stdoutBuf.String()
-	errOutput := stderrBuf.String()
+	output := model.TaskOutput{
+		Stdout: stdoutBuf.String(),
+		Stderr: stderrBuf.String(),
+	}
 
 	if err != nil {
-		return fmt.Errorf("local command execution failed for task %q: %w. Error output: %s", task.Name, err, errOutput)
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			output.ExitCode = exitCode(exitErr.ExitCode())
+		}
+		return output, fmt.Errorf("local command execution failed for task %q: %w. Error output: %s", task.Name, err, output.Stderr)
 	}
 
-	logger.GetLogger().Infof("Local command for task %q executed successfully. Output: %s.", task.Name, output)
-	return nil
+	output.ExitCode = exitCode(0)
+	logger.GetLogger().Infof("Local command for task %q executed successfully. Output: %s.", task.Name, output.Stdout)
+	return output, nil
+}
+
+func exitCode(code int) *int {
+	return &code
 }
@@ -1,7 +1,10 @@
 package executor_test
 
 import (
+	"bytes"
 	"context"
+	"io"
+	"time"
 
 	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/model"
 	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/processconsumer/service/executor"
@@ -14,16 +17,19 @@ var _ = Describe("LocalCmdExecutor", func() {
 		executorSvc *executor.LocalCmdExecutor
 		ctx         context.Context
 		task        model.Task
+		output      model.TaskOutput
+		stdout      *bytes.Buffer
 		errAction   error
 	)
 
 	JustBeforeEach(func() {
-		errAction = executorSvc.Run(ctx, task)
+		output, errAction = executorSvc.Run(ctx, task, stdout, io.Discard)
 	})
 
 	BeforeEach(func() {
 		executorSvc = executor.NewLocalCmdService()
 		ctx = context.Background()
+		stdout = &bytes.Buffer{}
 
 		task = model.Task{
 			Name: "echo test",
@@ -37,6 +43,15 @@ var _ = Describe("LocalCmdExecutor", func() {
 		Expect(errAction).ToNot(HaveOccurred())
 	})
 
+	It("captures the output and exit code", func() {
+		Expect(output.Stdout).To(Equal("123\n"))
+		Expect(output.ExitCode).To(HaveValue(Equal(0)))
+	})
+
+	It("streams the output", func() {
+		Expect(stdout.String()).To(Equal("123\n"))
+	})
+
 	Context("with a missing command parameter", func() {
 		BeforeEach(func() {
 			task = model.Task{
@@ -63,5 +78,34 @@ var _ = Describe("LocalCmdExecutor", func() {
 		It("should return an error", func() {
 			Expect(errAction.Error()).To(ContainSubstring("local command execution failed"))
 		})
+
+		It("reports the exit code", func() {
+			Expect(output.ExitCode).To(HaveValue(Equal(127)))
+		})
+	})
+
+	Context("when the context is cancelled", func() {
+		var (
+			cancel  context.CancelFunc
+			started time.Time
+		)
+
+		BeforeEach(func() {
+			ctx, cancel = context.WithTimeout(context.Background(), 100*time.Millisecond)
+			DeferCleanup(func() { cancel() })
+			started = time.Now()
+
+			task = model.Task{
+				Name: "background",
+				Parameters: map[string]string{
+					"command": "sleep 30 & sleep 30",
+				},
+			}
+		})
+
+		It("kills the processes started by the command as well", func() {
+			Expect(errAction).To(HaveOccurred())
+			Expect(time.Since(started)).To(BeNumerically("<", 3*time.Second))
+		})
 	})
 })
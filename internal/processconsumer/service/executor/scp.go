@@ -19,22 +19,22 @@ func NewSCPCmdExecutor() *SCPCmdExecutor {
 	return &SCPCmdExecutor{}
 }
 
-func (e *SCPCmdExecutor) Run(ctx context.Context, task model.Task) error {
+func (e *SCPCmdExecutor) Run(ctx context.Context, task model.Task, stdout, _ io.Writer) (model.TaskOutput, error) {
 	local := task.Parameters["localPath"]
 	remote := task.Parameters["remotePath"]
 	if local == "" || remote == "" {
-		return fmt.Errorf("missing 'localPath' or 'remotePath' in task %q", task.Name)
+		return model.TaskOutput{}, fmt.Errorf("missing 'localPath' or 'remotePath' in task %q", task.Name)
 	}
 
 	connCfg, err := sshutil.BuildConnectionConfig(task.Parameters)
 	if err != nil {
-		return err
+		return model.TaskOutput{}, err
 	}
 
 	addr := fmt.Sprintf("%s:%s", connCfg.Host, connCfg.Port)
-	client, err := ssh.Dial("tcp", addr, connCfg.ClientConfig)
+	client, err := sshutil.Dial(ctx, connCfg)
 	if err != nil {
-		return fmt.Errorf("failed to dial SSH: %w", err)
+		return model.TaskOutput{}, err
 	}
 	defer func() {
 		if err := client.Close(); err != nil {
@@ -45,11 +45,19 @@ func (e *SCPCmdExecutor) Run(ctx context.Context, task model.Task) error {
 	logger.GetLogger().Infof("About to transfer file to %s...", addr)
 
 	if err := transferFile(client, local, remote, connCfg.Host, connCfg.Port); err != nil {
-		return err
+		if ctx.Err() != nil {
+			return model.TaskOutput{}, fmt.Errorf("file transfer for task %q aborted: %w", task.Name, context.Cause(ctx))
+		}
+		return model.TaskOutput{}, err
 	}
 
 	logger.GetLogger().Infof("Remote file copy for task %q succeeded", task.Name)
-	return nil
+
+	summary := fmt.Sprintf("copied %s to %s:%s\n", local, addr, remote)
+	if _, err := io.WriteString(stdout, summary); err != nil {
+		logger.GetLogger().Warnf("failed to write output of task %q: %v", task.Name, err)
+	}
+	return model.TaskOutput{Stdout: summary}, nil
 }
 
 func transferFile(client *ssh.Client, localPath, remotePath, host, port string) error {
@@ -83,13 +91,8 @@ func transferFile(client *ssh.Client, localPath, remotePath, host, port string)
 		}
 	}()
 
-	content, err := io.ReadAll(localFile)
-	if err != nil {
-		return fmt.Errorf("failed to read local file: %w", err)
-	}
-
-	if _, err := remoteFile.Write(content); err != nil {
-		return fmt.Errorf("failed to write to remote file: %w", err)
+	if _, err := io.Copy(remoteFile, localFile); err != nil {
+		return fmt.Errorf("failed to copy to remote file: %w", err)
 	}
 
 	logger.GetLogger().Infof("File successfully copied to %s:%s as %s", host, port, remotePath)
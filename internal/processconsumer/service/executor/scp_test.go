@@ -2,6 +2,7 @@ package executor_test
 
 import (
 	"context"
+	"io"
 
 	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/model"
 	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/processconsumer/service/executor"
@@ -23,7 +24,7 @@ var _ = Describe("SCPCmdExecutor", func() {
 	})
 
 	JustBeforeEach(func() {
-		errAction = exec.Run(ctx, task)
+		_, errAction = exec.Run(ctx, task, io.Discard, io.Discard)
 	})
 
 	// TODO: Add happy path test coverage.
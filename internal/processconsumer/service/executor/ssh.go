@@ -3,7 +3,9 @@ package executor
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"io"
 
 	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/logger"
 	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/model"
@@ -18,21 +20,21 @@ func NewSSHCmdExecutor() *SSHCmdExecutor {
 	return &SSHCmdExecutor{}
 }
 
-func (se *SSHCmdExecutor) Run(ctx context.Context, task model.Task) error {
+func (se *SSHCmdExecutor) Run(ctx context.Context, task model.Task, stdout, stderr io.Writer) (model.TaskOutput, error) {
 	command := task.Parameters["command"]
 	if command == "" {
-		return fmt.Errorf("missing 'command' parameter in task %q", task.Name)
+		return model.TaskOutput{}, fmt.Errorf("missing 'command' parameter in task %q", task.Name)
 	}
 
 	connCfg, err := sshutil.BuildConnectionConfig(task.Parameters)
 	if err != nil {
-		return fmt.Errorf("failed to build SSH config: %w", err)
+		return model.TaskOutput{}, fmt.Errorf("failed to build SSH config: %w", err)
 	}
 
 	addr := fmt.Sprintf("%s:%s", connCfg.Host, connCfg.Port)
-	client, err := ssh.Dial("tcp", addr, connCfg.ClientConfig)
+	client, err := sshutil.Dial(ctx, connCfg)
 	if err != nil {
-		return fmt.Errorf("failed to dial SSH: %w", err)
+		return model.TaskOutput{}, err
 	}
 	defer func() {
 		if err := client.Close(); err != nil {
@@ -42,28 +44,35 @@ func (se *SSHCmdExecutor) Run(ctx context.Context, task model.Task) error {
 
 	logger.GetLogger().Infof("Executing SSH command on %s: %q", addr, command)
 
-	if err := runCommand(ctx, client, task.Name, command); err != nil {
-		return err
+	output, err := runCommand(ctx, client, task.Name, command, stdout, stderr)
+	if err != nil {
+		return output, err
 	}
 
 	logger.GetLogger().Infof("SSH command for task %q succeeded", task.Name)
-	return nil
+	return output, nil
 }
 
-func runCommand(ctx context.Context, client *ssh.Client, taskName, command string) error {
+func runCommand(
+	ctx context.Context,
+	client *ssh.Client,
+	taskName, command string,
+	stdout, stderr io.Writer,
+) (model.TaskOutput, error) {
 	session, err := client.NewSession()
 	if err != nil {
-		return fmt.Errorf("failed to create SSH session: %w", err)
+		return model.TaskOutput{}, fmt.Errorf("failed to create SSH session: %w", err)
 	}
 	defer func() {
-		if err := session.Close(); err != nil {
+		// A session closed on cancellation reports io.EOF when closed again.
+		if err := session.Close(); err != nil && !errors.Is(err, io.EOF) {
 			logger.GetLogger().Warnf("failed to close session: %v", err)
 		}
 	}()
 
 	var stdoutBuf, stderrBuf bytes.Buffer
-	session.Stdout = &stdoutBuf
-	session.Stderr = &stderrBuf
+	session.Stdout = io.MultiWriter(&stdoutBuf, stdout)
+	session.Stderr = io.MultiWriter(&stderrBuf, stderr)
 
 	done := make(chan error, 1)
 	go func() {
@@ -73,19 +82,30 @@ func runCommand(ctx context.Context, client *ssh.Client, taskName, command strin
 	select {
 	case <-ctx.Done():
 		_ = session.Signal(ssh.SIGKILL)
-		return ctx.Err()
+		// Closing the session ends Run, which only returns once the output was copied,
+		// so nothing is written to stdout and stderr after this function returned.
+		_ = session.Close()
+		<-done
+		return model.TaskOutput{}, ctx.Err()
 	case err = <-done:
 	}
 
-	stdout := stdoutBuf.String()
-	stderr := stderrBuf.String()
+	output := model.TaskOutput{
+		Stdout: stdoutBuf.String(),
+		Stderr: stderrBuf.String(),
+	}
 
 	if err != nil {
-		return fmt.Errorf("SSH command failed for task %q: %w. Stderr: %s", taskName, err, stderr)
+		var exitErr *ssh.ExitError
+		if errors.As(err, &exitErr) {
+			output.ExitCode = exitCode(exitErr.ExitStatus())
+		}
+		return output, fmt.Errorf("SSH command failed for task %q: %w. Stderr: %s", taskName, err, output.Stderr)
 	}
 
-	if stdout != "" {
-		logger.GetLogger().Infof("Output for task %q:\n%s", taskName, stdout)
+	output.ExitCode = exitCode(0)
+	if output.Stdout != "" {
+		logger.GetLogger().Infof("Output for task %q:\n%s", taskName, output.Stdout)
 	}
-	return nil
+	return output, nil
 }
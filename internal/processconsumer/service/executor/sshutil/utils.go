@@ -1,7 +1,9 @@
 package sshutil
 
 import (
+	"context"
 	"fmt"
+	"net"
 	"os"
 	"time"
 
@@ -47,6 +49,37 @@ func BuildConnectionConfig(params map[string]string) (*SSHConnectionConfig, erro
 	}, nil
 }
 
+// Dial opens an SSH connection which is torn down as soon as the context is done,
+// aborting any session or file transfer running over it.
+func Dial(ctx context.Context, cfg *SSHConnectionConfig) (*ssh.Client, error) {
+	addr := net.JoinHostPort(cfg.Host, cfg.Port)
+
+	dialer := net.Dialer{Timeout: cfg.ClientConfig.Timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial SSH: %w", err)
+	}
+
+	stop := context.AfterFunc(ctx, func() {
+		_ = conn.Close()
+	})
+
+	sshConn, chans, reqs, err := ssh.NewClientConn(conn, addr, cfg.ClientConfig)
+	if err != nil {
+		stop()
+		_ = conn.Close()
+		return nil, fmt.Errorf("failed to establish SSH connection: %w", err)
+	}
+
+	client := ssh.NewClient(sshConn, chans, reqs)
+	go func() {
+		_ = client.Wait()
+		stop()
+	}()
+
+	return client, nil
+}
+
 func getSSHAuthMethod(password, keyPath string) (ssh.AuthMethod, error) {
 	if keyPath != "" {
 		key, err := os.ReadFile(keyPath)
@@ -0,0 +1,159 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/logger"
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/model"
+	"github.com/google/uuid"
+)
+
+const (
+	logBatchSize     = 100
+	logFlushInterval = 500 * time.Millisecond
+	// maxLogLineBytes bounds a log entry. Longer lines are split into several entries.
+	maxLogLineBytes = 4 * 1024
+)
+
+// logBatcher collects task output lines of a process run and persists them in batches.
+type logBatcher struct {
+	store     ProcessStore
+	processID uuid.UUID
+	lines     chan model.ProcessLog
+	done      chan struct{}
+}
+
+func newLogBatcher(ctx context.Context, store ProcessStore, processID uuid.UUID) *logBatcher {
+	b := &logBatcher{
+		store:     store,
+		processID: processID,
+		lines:     make(chan model.ProcessLog, logBatchSize),
+		done:      make(chan struct{}),
+	}
+	go b.loop(ctx)
+	return b
+}
+
+func (b *logBatcher) loop(ctx context.Context) {
+	defer close(b.done)
+
+	ticker := time.NewTicker(logFlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]model.ProcessLog, 0, logBatchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if err := b.store.AppendProcessLogs(ctx, batch); err != nil {
+			logger.GetLogger().Errorf("failed to persist %d log lines of process %s: %v", len(batch), b.processID, err)
+		}
+		batch = make([]model.ProcessLog, 0, logBatchSize)
+	}
+
+	for {
+		select {
+		case line, ok := <-b.lines:
+			if !ok {
+				flush()
+				return
+			}
+			batch = append(batch, line)
+			if len(batch) >= logBatchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
+
+// writer returns an io.Writer emitting every complete line written to it as a
+// log entry of the given task and stream. It must be closed to emit a trailing partial line.
+// Writes after it was closed are dropped, as the output of a killed command may still
+// be copied after its executor returned.
+func (b *logBatcher) writer(taskName string, stream model.LogStream) *lineWriter {
+	return &lineWriter{
+		batcher:  b,
+		taskName: taskName,
+		stream:   stream,
+	}
+}
+
+// close flushes all pending lines. No writer may be used afterwards.
+func (b *logBatcher) close() {
+	close(b.lines)
+	<-b.done
+}
+
+type lineWriter struct {
+	mu       sync.Mutex
+	batcher  *logBatcher
+	taskName string
+	stream   model.LogStream
+	pending  []byte
+	closed   bool
+}
+
+func (w *lineWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return len(p), nil
+	}
+	w.pending = append(w.pending, p...)
+	for {
+		idx := bytes.IndexByte(w.pending, '\n')
+		if idx >= 0 && idx <= maxLogLineBytes {
+			w.emit(string(w.pending[:idx]))
+			w.pending = w.pending[idx+1:]
+			continue
+		}
+		if len(w.pending) <= maxLogLineBytes {
+			break
+		}
+		// Output without line breaks is not buffered without bound. The line is cut
+		// at a rune boundary, so every entry stays valid UTF-8.
+		cut := maxLogLineBytes
+		for cut > 0 && !utf8.RuneStart(w.pending[cut]) {
+			cut--
+		}
+		if cut == 0 {
+			cut = maxLogLineBytes
+		}
+		w.emit(string(w.pending[:cut]))
+		w.pending = w.pending[cut:]
+	}
+	return len(p), nil
+}
+
+func (w *lineWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	if len(w.pending) > 0 {
+		w.emit(string(w.pending))
+		w.pending = nil
+	}
+	return nil
+}
+
+func (w *lineWriter) emit(line string) {
+	w.batcher.lines <- model.ProcessLog{
+		ProcessID: w.batcher.processID,
+		TaskName:  w.taskName,
+		Stream:    w.stream,
+		Log:       strings.TrimSuffix(line, "\r"),
+		CreatedAt: time.Now(),
+	}
+}
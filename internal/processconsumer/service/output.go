@@ -0,0 +1,14 @@
+package service
+
+// maxTaskOutputBytes bounds the stdout/stderr persisted per task run.
+const maxTaskOutputBytes = 4 * 1024
+
+const truncatedMarker = "...(truncated)\n"
+
+// truncateOutput keeps the tail of the output, where failures are usually reported.
+func truncateOutput(output string) string {
+	if len(output) <= maxTaskOutputBytes {
+		return output
+	}
+	return truncatedMarker + output[len(output)-maxTaskOutputBytes:]
+}
@@ -0,0 +1,88 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// ErrStopRequested is the cancellation cause of runs stopped through the API.
+var ErrStopRequested = errors.New("stop requested")
+
+// Registry keeps the cancel functions and pause signals of the runs owned by this consumer.
+type Registry struct {
+	mu   sync.Mutex
+	runs map[uuid.UUID]*entry
+}
+
+type entry struct {
+	cancel    context.CancelCauseFunc
+	pause     chan struct{}
+	pauseOnce sync.Once
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		runs: make(map[uuid.UUID]*entry),
+	}
+}
+
+// Register derives a cancellable context for the given run. The returned
+// release func must be called once the run is over.
+func (r *Registry) Register(ctx context.Context, id uuid.UUID) (context.Context, func()) {
+	runCtx, cancel := context.WithCancelCause(ctx)
+
+	r.mu.Lock()
+	r.runs[id] = &entry{cancel: cancel, pause: make(chan struct{})}
+	r.mu.Unlock()
+
+	return runCtx, func() {
+		r.mu.Lock()
+		delete(r.runs, id)
+		r.mu.Unlock()
+
+		cancel(nil)
+	}
+}
+
+// Cancel aborts the run if it is owned by this consumer and reports whether it was found.
+func (r *Registry) Cancel(id uuid.UUID) bool {
+	r.mu.Lock()
+	run, ok := r.runs[id]
+	r.mu.Unlock()
+
+	if !ok {
+		return false
+	}
+
+	run.cancel(ErrStopRequested)
+	return true
+}
+
+// Pause signals the run to pause if it is owned by this consumer and reports whether it was found.
+func (r *Registry) Pause(id uuid.UUID) bool {
+	r.mu.Lock()
+	run, ok := r.runs[id]
+	r.mu.Unlock()
+
+	if !ok {
+		return false
+	}
+
+	run.pauseOnce.Do(func() { close(run.pause) })
+	return true
+}
+
+// Paused returns a channel that is closed once a pause of the run is requested. It is
+// nil for runs that are not owned by this consumer.
+func (r *Registry) Paused(id uuid.UUID) <-chan struct{} {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if run, ok := r.runs[id]; ok {
+		return run.pause
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package registry_test
+
+import (
+	"context"
+
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/processconsumer/service/registry"
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Registry", func() {
+	var (
+		reg     *registry.Registry
+		ctx     context.Context
+		runCtx  context.Context
+		release func()
+		runID   uuid.UUID
+	)
+
+	BeforeEach(func() {
+		reg = registry.NewRegistry()
+		ctx = context.Background()
+		runID = uuid.New()
+	})
+
+	JustBeforeEach(func() {
+		runCtx, release = reg.Register(ctx, runID)
+	})
+
+	It("returns an active context", func() {
+		Expect(runCtx.Err()).ToNot(HaveOccurred())
+	})
+
+	Describe("Cancel", func() {
+		var found bool
+
+		JustBeforeEach(func() {
+			found = reg.Cancel(runID)
+		})
+
+		It("cancels the run context with a stop cause", func() {
+			Expect(found).To(BeTrue())
+			Expect(runCtx.Err()).To(MatchError(context.Canceled))
+			Expect(context.Cause(runCtx)).To(MatchError(registry.ErrStopRequested))
+		})
+
+		When("the run is not owned by the registry", func() {
+			It("reports it was not found", func() {
+				Expect(reg.Cancel(uuid.New())).To(BeFalse())
+			})
+		})
+	})
+
+	Describe("Pause", func() {
+		var found bool
+
+		JustBeforeEach(func() {
+			found = reg.Pause(runID)
+		})
+
+		It("signals the pause without cancelling the run", func() {
+			Expect(found).To(BeTrue())
+			Expect(reg.Paused(runID)).To(BeClosed())
+			Expect(runCtx.Err()).ToNot(HaveOccurred())
+		})
+
+		It("can be requested more than once", func() {
+			Expect(reg.Pause(runID)).To(BeTrue())
+		})
+
+		When("the run is not owned by the registry", func() {
+			It("reports it was not found", func() {
+				Expect(reg.Pause(uuid.New())).To(BeFalse())
+				Expect(reg.Paused(uuid.New())).To(BeNil())
+			})
+		})
+	})
+
+	When("the run is released", func() {
+		JustBeforeEach(func() {
+			release()
+		})
+
+		It("is no longer cancellable", func() {
+			Expect(reg.Cancel(runID)).To(BeFalse())
+			Expect(reg.Pause(runID)).To(BeFalse())
+			Expect(context.Cause(runCtx)).To(MatchError(context.Canceled))
+		})
+	})
+})
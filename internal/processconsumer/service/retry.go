@@ -0,0 +1,41 @@
+package service
+
+import (
+	"slices"
+	"time"
+
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/model"
+)
+
+// maxAttempts returns how many times a task may be executed, at least once.
+func maxAttempts(policy *model.RetryPolicy) int {
+	if policy == nil || policy.Attempts < 1 {
+		return 1
+	}
+	return policy.Attempts
+}
+
+// retryDelay returns the back-off before the attempt following the given one.
+func retryDelay(policy *model.RetryPolicy, attempt int) time.Duration {
+	delay := time.Duration(policy.InitialDelay)
+	if policy.Backoff == model.BackoffExponential {
+		for i := 1; i < attempt; i++ {
+			delay *= 2
+			if policy.MaxDelay > 0 && delay >= time.Duration(policy.MaxDelay) {
+				break
+			}
+		}
+	}
+
+	if policy.MaxDelay > 0 && delay > time.Duration(policy.MaxDelay) {
+		return time.Duration(policy.MaxDelay)
+	}
+	return delay
+}
+
+func isRetryable(policy *model.RetryPolicy, exitCode *int) bool {
+	if len(policy.RetryableExitCodes) == 0 || exitCode == nil {
+		return true
+	}
+	return slices.Contains(policy.RetryableExitCodes, *exitCode)
+}
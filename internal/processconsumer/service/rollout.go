@@ -0,0 +1,105 @@
+package service
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/model"
+)
+
+// rollout holds the instances of a task with a rolling policy in declaration order.
+type rollout struct {
+	task      string
+	policy    model.RollingPolicy
+	instances []string
+}
+
+// newRollouts collects the rollouts of the tasks and maps every instance to its rollout.
+func newRollouts(tasks []model.Task) ([]*rollout, map[string]*rollout) {
+	var rollouts []*rollout
+	byInstance := make(map[string]*rollout)
+	byTask := make(map[string]*rollout)
+	for _, task := range tasks {
+		if task.Rolling == nil || task.Group == "" {
+			continue
+		}
+		r, ok := byTask[task.Group]
+		if !ok {
+			r = &rollout{task: task.Group, policy: *task.Rolling}
+			r.policy.BatchSize = max(r.policy.BatchSize, 1)
+			byTask[task.Group] = r
+			rollouts = append(rollouts, r)
+		}
+		r.instances = append(r.instances, task.Name)
+		byInstance[task.Name] = r
+	}
+	return rollouts, byInstance
+}
+
+// batch returns the zero based batch of an instance.
+func (r *rollout) batch(name string) int {
+	return slices.Index(r.instances, name) / r.policy.BatchSize
+}
+
+func (r *rollout) batches() int {
+	return (len(r.instances) + r.policy.BatchSize - 1) / r.policy.BatchSize
+}
+
+func (r *rollout) batchInstances(batch int) []string {
+	start := batch * r.policy.BatchSize
+	return r.instances[start:min(start+r.policy.BatchSize, len(r.instances))]
+}
+
+func (r *rollout) pause() time.Duration {
+	return time.Duration(r.policy.PauseBetweenBatches)
+}
+
+// rolloutState is the progress of a rollout while the scheduler runs.
+type rolloutState struct {
+	// open is the last batch whose instances may start.
+	open int
+	// announced is the last batch whose start was logged.
+	announced int
+	failures  int
+	aborted   bool
+	// waiting is set during the pause before the next batch.
+	waiting bool
+}
+
+func (r *rollout) abortReason(state *rolloutState) string {
+	return fmt.Sprintf("rolling task '%s' aborted after %d failures, more than maxFailures %d", r.task, state.failures, r.policy.MaxFailures)
+}
+
+// summary describes the outcome of the rollout for the process log.
+func (r *rollout) summary(statuses map[string]model.TaskStatus, state *rolloutState) string {
+	counts := make(map[model.TaskStatus]int)
+	for _, name := range r.instances {
+		counts[statuses[name]]++
+	}
+	var parts []string
+	for _, status := range []model.TaskStatus{
+		model.TaskStatusSucceeded,
+		model.TaskStatusFailed,
+		model.TaskStatusTimedOut,
+		model.TaskStatusSkipped,
+		model.TaskStatusCancelled,
+		model.TaskStatusAwaitingApproval,
+		model.TaskStatusPending,
+	} {
+		if counts[status] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", counts[status], status))
+		}
+	}
+
+	msg := fmt.Sprintf("Rolling task %s: %s in %d batches of %d", r.task, strings.Join(parts, ", "), r.batches(), r.policy.BatchSize)
+	if state.aborted {
+		msg += fmt.Sprintf(" (aborted after %d failures, maxFailures %d)", state.failures, r.policy.MaxFailures)
+	}
+	return msg
+}
+
+func isFailure(status model.TaskStatus) bool {
+	return status == model.TaskStatusFailed || status == model.TaskStatusTimedOut
+}
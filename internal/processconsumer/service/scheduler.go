@@ -0,0 +1,530 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/condition"
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/model"
+)
+
+// taskGraph is the dependency graph of a process definition in topological order.
+type taskGraph struct {
+	tasks      map[string]model.Task
+	order      []string
+	dependents map[string][]string
+	// maxParallel limits the number of concurrently executing tasks, zero means no limit.
+	maxParallel int
+	// rollouts are the tasks with a rolling policy, rolloutOf maps their instances to them.
+	rollouts  []*rollout
+	rolloutOf map[string]*rollout
+}
+
+func newTaskGraph(def model.ProcessDefinition) (*taskGraph, error) {
+	tasks := def.Tasks
+	g := &taskGraph{
+		tasks:       make(map[string]model.Task, len(tasks)),
+		dependents:  make(map[string][]string),
+		maxParallel: def.MaxParallel,
+	}
+	g.rollouts, g.rolloutOf = newRollouts(tasks)
+
+	for _, task := range tasks {
+		if _, exists := g.tasks[task.Name]; exists {
+			return nil, fmt.Errorf("duplicate task name found: %s", task.Name)
+		}
+		g.tasks[task.Name] = task
+	}
+
+	inDegree := make(map[string]int, len(tasks))
+	for _, task := range tasks {
+		for _, dep := range task.WaitFor {
+			if _, ok := g.tasks[dep]; !ok {
+				return nil, fmt.Errorf("task '%s' waits for unknown task '%s'", task.Name, dep)
+			}
+			g.dependents[dep] = append(g.dependents[dep], task.Name)
+		}
+		inDegree[task.Name] = len(task.WaitFor)
+	}
+
+	// Kahn's algorithm, seeded in declaration order to keep the ordering stable.
+	var queue []string
+	for _, task := range tasks {
+		if inDegree[task.Name] == 0 {
+			queue = append(queue, task.Name)
+		}
+	}
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		g.order = append(g.order, name)
+
+		for _, dependent := range g.dependents[name] {
+			inDegree[dependent]--
+			if inDegree[dependent] == 0 {
+				queue = append(queue, dependent)
+			}
+		}
+	}
+
+	if len(g.order) != len(tasks) {
+		var cyclic []string
+		for _, task := range tasks {
+			if inDegree[task.Name] > 0 {
+				cyclic = append(cyclic, task.Name)
+			}
+		}
+		return nil, fmt.Errorf("dependency cycle between tasks: %s", strings.Join(cyclic, ", "))
+	}
+
+	return g, nil
+}
+
+// newFinallyGraph builds the graph of the finally tasks of a process definition. They
+// run once all other tasks are done and may only wait for each other.
+func newFinallyGraph(def model.ProcessDefinition) (*taskGraph, error) {
+	for _, task := range def.Finally {
+		if slices.ContainsFunc(def.Tasks, func(t model.Task) bool { return t.Name == task.Name }) {
+			return nil, fmt.Errorf("duplicate task name found: %s", task.Name)
+		}
+	}
+
+	g, err := newTaskGraph(model.ProcessDefinition{Tasks: def.Finally, MaxParallel: def.MaxParallel})
+	if err != nil {
+		return nil, fmt.Errorf("finally: %w", err)
+	}
+	return g, nil
+}
+
+// failedTasks returns the tasks of the graph that failed or timed out, in topological order.
+func failedTasks(graph *taskGraph, statuses map[string]model.TaskStatus) []string {
+	var failed []string
+	for _, name := range graph.order {
+		if statuses[name] == model.TaskStatusFailed || statuses[name] == model.TaskStatusTimedOut {
+			failed = append(failed, name)
+		}
+	}
+	return failed
+}
+
+// tasksWithStatus returns the tasks of the graph with the given status, in topological order.
+func tasksWithStatus(graph *taskGraph, statuses map[string]model.TaskStatus, status model.TaskStatus) []string {
+	var names []string
+	for _, name := range graph.order {
+		if statuses[name] == status {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+type taskResult struct {
+	name string
+	err  error
+}
+
+// scheduler starts every task once all of its dependencies reached a terminal state
+// and its condition holds. Without a condition a task only runs if all of its
+// dependencies succeeded. Tasks that do not run are skipped, so every task ends
+// in a terminal state. Failures of tasks that continue on error are reported to
+// their dependents as successes and do not fail the run. Tasks awaiting approval
+// hold back their dependents; if nothing else is left to do, the run is parked and
+// they are left awaiting approval along with the pending tasks. A paused run is
+// parked once its executing tasks are done. The instances of a rolling task start a
+// batch at a time and their failures count towards its threshold instead of failing
+// the run.
+type scheduler struct {
+	graph *taskGraph
+	// finished are tasks that already reached a state before the scheduler started, i.e.
+	// succeeded in an earlier attempt of the run or finished before the run was parked.
+	finished map[string]model.TaskRun
+	// upstream holds the final statuses of tasks outside of the graph that finished
+	// before it started, e.g. the main tasks for the finally tasks.
+	upstream map[string]model.TaskStatus
+	execute  func(context.Context, model.Task) error
+	// notRun is called for every task that reaches a terminal state without being executed.
+	notRun func(task model.Task, status model.TaskStatus, reason string)
+	// output returns an output captured from a finished task.
+	output func(task, key string) (string, bool)
+	// pause is closed once the run is to be paused. Executing tasks finish, but no new ones start.
+	pause <-chan struct{}
+	// log records the progress of rollouts in the process log, if set.
+	log func(msg string)
+	// rollouts holds the state of the rollouts of the graph during run.
+	rollouts map[*rollout]*rolloutState
+}
+
+func newScheduler(
+	graph *taskGraph,
+	finished map[string]model.TaskRun,
+	execute func(context.Context, model.Task) error,
+	notRun func(model.Task, model.TaskStatus, string),
+	output func(string, string) (string, bool),
+) *scheduler {
+	return &scheduler{
+		graph:    graph,
+		finished: finished,
+		execute:  execute,
+		notRun:   notRun,
+		output:   output,
+	}
+}
+
+// run blocks until all started tasks are finished. Once the context is done no
+// new tasks are started and the ones that never ran are reported as cancelled.
+// It returns the final status of every task and the first task error.
+func (s *scheduler) run(ctx context.Context) (map[string]model.TaskStatus, error) {
+	statuses := make(map[string]model.TaskStatus, len(s.graph.order))
+	remaining := make(map[string]int, len(s.graph.order))
+	for _, name := range s.graph.order {
+		statuses[name] = model.TaskStatusPending
+		remaining[name] = len(s.graph.tasks[name].WaitFor)
+	}
+
+	results := make(chan taskResult)
+	running := 0
+	// wakeups receives the rollouts whose pause before the next batch is over.
+	wakeups := make(chan *rollout)
+	sleeping := 0
+	// queued holds tasks that are ready to run but wait for a free slot.
+	var queued []string
+	var firstErr error
+
+	launch := func(name string) {
+		statuses[name] = model.TaskStatusRunning
+		running++
+		go func(task model.Task) {
+			results <- taskResult{name: task.Name, err: s.execute(ctx, task)}
+		}(s.graph.tasks[name])
+	}
+	start := func(name string) {
+		if s.graph.maxParallel > 0 && (running >= s.graph.maxParallel || len(queued) > 0) {
+			queued = append(queued, name)
+			return
+		}
+		launch(name)
+	}
+
+	var settle func(name string)
+	var advance func(r *rollout)
+	decide := func(name string) {
+		if ctx.Err() != nil || statuses[name] != model.TaskStatusPending || isClosed(s.pause) {
+			return
+		}
+
+		task := s.graph.tasks[name]
+		if r := s.graph.rolloutOf[name]; r != nil {
+			state := s.rollouts[r]
+			if state.aborted {
+				statuses[name] = model.TaskStatusCancelled
+				s.notRun(task, model.TaskStatusCancelled, r.abortReason(state))
+				settle(name)
+				return
+			}
+			// The instance is decided again once its batch opens.
+			if r.batch(name) > state.open {
+				return
+			}
+		}
+
+		ok, reason, err := s.shouldRun(task, statuses)
+		switch {
+		case err != nil:
+			statuses[name] = model.TaskStatusFailed
+			s.notRun(task, model.TaskStatusFailed, reason)
+			if firstErr == nil {
+				firstErr = err
+			}
+		case !ok:
+			statuses[name] = model.TaskStatusSkipped
+			s.notRun(task, model.TaskStatusSkipped, reason)
+		default:
+			if r := s.graph.rolloutOf[name]; r != nil {
+				s.announce(r, r.batch(name))
+			}
+			start(name)
+			return
+		}
+		settle(name)
+	}
+	// settle releases the dependents of a task that reached a terminal state.
+	settle = func(name string) {
+		for _, dependent := range s.graph.dependents[name] {
+			remaining[dependent]--
+			if remaining[dependent] == 0 {
+				decide(dependent)
+			}
+		}
+		if r := s.graph.rolloutOf[name]; r != nil {
+			advance(r)
+		}
+	}
+	openBatch := func(r *rollout, batch int) {
+		s.rollouts[r].open = batch
+		for _, name := range r.batchInstances(batch) {
+			if remaining[name] == 0 {
+				decide(name)
+			}
+		}
+	}
+	// advance opens the next batch of a rollout once its open batch is done, after
+	// the pause between batches.
+	advance = func(r *rollout) {
+		state := s.rollouts[r]
+		for !state.aborted && !state.waiting && state.open < r.batches()-1 && batchDone(r, state.open, statuses) {
+			if r.pause() > 0 && ctx.Err() == nil && !isClosed(s.pause) {
+				state.waiting = true
+				sleeping++
+				s.logf("Rolling task %s: batch %d/%d done, pausing for %s", r.task, state.open+1, r.batches(), r.pause())
+				go func() {
+					select {
+					case <-time.After(r.pause()):
+					case <-ctx.Done():
+					case <-s.pause:
+					}
+					wakeups <- r
+				}()
+				return
+			}
+			openBatch(r, state.open+1)
+		}
+	}
+	// fail records the failure of a task. Failures of the instances of a rollout count
+	// towards its threshold instead, the rollout is aborted once they exceed it.
+	fail := func(name string, err error) {
+		r := s.graph.rolloutOf[name]
+		if r == nil {
+			if firstErr == nil && !s.graph.tasks[name].ContinueOnError {
+				firstErr = err
+			}
+			return
+		}
+
+		state := s.rollouts[r]
+		state.failures++
+		if state.aborted || state.failures <= r.policy.MaxFailures {
+			return
+		}
+		state.aborted = true
+		reason := r.abortReason(state)
+		s.logf("Rolling task %s aborted: %d instances failed, more than maxFailures %d", r.task, state.failures, r.policy.MaxFailures)
+		if firstErr == nil && !s.graph.tasks[name].ContinueOnError {
+			firstErr = errors.New(reason)
+		}
+		for _, instance := range r.instances {
+			if statuses[instance] == model.TaskStatusPending && remaining[instance] == 0 {
+				statuses[instance] = model.TaskStatusCancelled
+				s.notRun(s.graph.tasks[instance], model.TaskStatusCancelled, reason)
+				settle(instance)
+			}
+		}
+	}
+
+	s.rollouts = make(map[*rollout]*rolloutState, len(s.graph.rollouts))
+	for _, r := range s.graph.rollouts {
+		// A rollout that carries on starts with its first batch that is not done.
+		state := &rolloutState{announced: -1}
+		for state.open < r.batches()-1 && batchDone(r, state.open, finishedStatuses(s.finished)) {
+			state.open++
+		}
+		s.rollouts[r] = state
+	}
+
+	for _, name := range s.graph.order {
+		if taskRun, ok := s.finished[name]; ok {
+			statuses[name] = taskRun.Status
+		}
+	}
+	for _, name := range s.graph.order {
+		taskRun, ok := s.finished[name]
+		if !ok || taskRun.Status == model.TaskStatusAwaitingApproval {
+			continue
+		}
+		if isFailure(taskRun.Status) {
+			fail(name, fmt.Errorf("task '%s' %s: %s", name, taskRun.Status, taskRun.Error))
+		}
+		settle(name)
+	}
+	for _, name := range s.graph.order {
+		if remaining[name] == 0 && statuses[name] == model.TaskStatusPending {
+			decide(name)
+		}
+	}
+
+	for running > 0 || sleeping > 0 {
+		var res taskResult
+		select {
+		case res = <-results:
+			running--
+		case r := <-wakeups:
+			sleeping--
+			s.rollouts[r].waiting = false
+			openBatch(r, s.rollouts[r].open+1)
+			advance(r)
+			continue
+		}
+
+		switch {
+		case res.err == nil:
+			statuses[res.name] = model.TaskStatusSucceeded
+		case ctx.Err() != nil:
+			statuses[res.name] = model.TaskStatusCancelled
+		case errors.Is(res.err, model.ErrAwaitingApproval):
+			statuses[res.name] = model.TaskStatusAwaitingApproval
+		case errors.Is(res.err, errTaskTimedOut):
+			statuses[res.name] = model.TaskStatusTimedOut
+			fail(res.name, res.err)
+		default:
+			statuses[res.name] = model.TaskStatusFailed
+			fail(res.name, res.err)
+		}
+
+		if statuses[res.name] != model.TaskStatusAwaitingApproval {
+			settle(res.name)
+		}
+
+		for len(queued) > 0 && running < s.graph.maxParallel && ctx.Err() == nil && !isClosed(s.pause) {
+			launch(queued[0])
+			queued = queued[1:]
+		}
+	}
+
+	// A paused run, or one that only waits for approvals any more, is parked with its
+	// tasks as they are.
+	awaiting := tasksWithStatus(s.graph, statuses, model.TaskStatusAwaitingApproval)
+	if (len(awaiting) > 0 || isClosed(s.pause)) && firstErr == nil && ctx.Err() == nil {
+		return statuses, nil
+	}
+
+	for _, name := range s.graph.order {
+		if statuses[name] == model.TaskStatusPending || statuses[name] == model.TaskStatusAwaitingApproval {
+			statuses[name] = model.TaskStatusCancelled
+			s.notRun(s.graph.tasks[name], model.TaskStatusCancelled, "process was cancelled")
+		}
+	}
+
+	for _, r := range s.graph.rollouts {
+		s.logf("%s", r.summary(statuses, s.rollouts[r]))
+	}
+
+	return statuses, firstErr
+}
+
+// announce logs the start of a batch of a rollout when its first instance starts.
+func (s *scheduler) announce(r *rollout, batch int) {
+	state := s.rollouts[r]
+	if batch <= state.announced {
+		return
+	}
+	state.announced = batch
+	s.logf("Rolling task %s: starting batch %d/%d: %s", r.task, batch+1, r.batches(), strings.Join(r.batchInstances(batch), ", "))
+}
+
+func (s *scheduler) logf(format string, args ...any) {
+	if s.log != nil {
+		s.log(fmt.Sprintf(format, args...))
+	}
+}
+
+// batchDone reports whether all instances of a batch of a rollout reached a terminal state.
+func batchDone(r *rollout, batch int, statuses map[string]model.TaskStatus) bool {
+	for _, name := range r.batchInstances(batch) {
+		switch statuses[name] {
+		case model.TaskStatusPending, model.TaskStatusRunning, model.TaskStatusAwaitingApproval, "":
+			return false
+		}
+	}
+	return true
+}
+
+// finishedStatuses returns the statuses of the task runs.
+func finishedStatuses(taskRuns map[string]model.TaskRun) map[string]model.TaskStatus {
+	statuses := make(map[string]model.TaskStatus, len(taskRuns))
+	for name, taskRun := range taskRuns {
+		statuses[name] = taskRun.Status
+	}
+	return statuses
+}
+
+// shouldRun decides whether a task whose dependencies are all done runs. If it does
+// not, the reason is returned as well.
+func (s *scheduler) shouldRun(task model.Task, statuses map[string]model.TaskStatus) (bool, string, error) {
+	if task.When == "" {
+		for _, dep := range task.WaitFor {
+			switch s.effectiveStatus(dep, statuses) {
+			case model.TaskStatusSucceeded:
+			case model.TaskStatusSkipped:
+				return false, fmt.Sprintf("dependency '%s' was skipped", dep), nil
+			default:
+				return false, fmt.Sprintf("dependency '%s' %s", dep, statuses[dep]), nil
+			}
+		}
+		return true, "", nil
+	}
+
+	failed := func(err error) (bool, string, error) {
+		reason := fmt.Sprintf("failed to evaluate condition '%s': %v", task.When, err)
+		return false, reason, fmt.Errorf("task '%s' %s", task.Name, reason)
+	}
+
+	expr, err := condition.Parse(task.When)
+	if err != nil {
+		return failed(err)
+	}
+	ok, err := expr.Eval(&conditionEnv{scheduler: s, task: task, statuses: statuses})
+	if err != nil {
+		return failed(err)
+	}
+	if !ok {
+		return false, fmt.Sprintf("condition '%s' not met", task.When), nil
+	}
+	return true, "", nil
+}
+
+// effectiveStatus is the status of a task as seen by its dependents: failures of
+// tasks that continue on error count as successes.
+func (s *scheduler) effectiveStatus(name string, statuses map[string]model.TaskStatus) model.TaskStatus {
+	status := statuses[name]
+	if !isFailure(status) {
+		return status
+	}
+	if s.graph.tasks[name].ContinueOnError {
+		return model.TaskStatusSucceeded
+	}
+	// So do failures of the instances of a rollout that was not aborted.
+	if r := s.graph.rolloutOf[name]; r != nil && !s.rollouts[r].aborted {
+		return model.TaskStatusSucceeded
+	}
+	return status
+}
+
+// conditionEnv exposes the state of the run to the condition of a task.
+type conditionEnv struct {
+	scheduler *scheduler
+	task      model.Task
+	statuses  map[string]model.TaskStatus
+}
+
+func (e *conditionEnv) Dependencies() []model.TaskStatus {
+	statuses := make([]model.TaskStatus, 0, len(e.task.WaitFor))
+	for _, dep := range e.task.WaitFor {
+		statuses = append(statuses, e.scheduler.effectiveStatus(dep, e.statuses))
+	}
+	return statuses
+}
+
+func (e *conditionEnv) Status(task string) (model.TaskStatus, bool) {
+	if status, ok := e.statuses[task]; ok {
+		return status, true
+	}
+	status, ok := e.scheduler.upstream[task]
+	return status, ok
+}
+
+func (e *conditionEnv) Output(task, key string) (string, bool) {
+	return e.scheduler.output(task, key)
+}
@@ -4,83 +4,187 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
+	"slices"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/logger"
 	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/model"
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/processconsumer/service/registry"
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/taskoutput"
 	"github.com/google/uuid"
 )
 
+var (
+	errTaskTimedOut    = errors.New("task timed out")
+	errProcessTimedOut = errors.New("process timed out")
+	// errRunNotStarted marks failures that happened before anything of the run was recorded.
+	errRunNotStarted = errors.New("run not started")
+)
+
 //counterfeiter:generate . ProcessStore
 type ProcessStore interface {
 	InsertProcess(context.Context, model.ProcessRun) error
 	AppendProcessLog(context.Context, uuid.UUID, string) error
-	UpdateProcessStatus(context.Context, uuid.UUID, model.ProcessStatus) error
+	UpdateProcessStatus(context.Context, uuid.UUID, int, model.ProcessStatus) error
+	InsertTaskRun(context.Context, int, model.TaskRun) error
+	UpdateTaskRun(context.Context, int, model.TaskRun) error
+	AppendProcessLogs(context.Context, []model.ProcessLog) error
+	BeginRetry(context.Context, uuid.UUID) (int, error)
+	BeginContinue(context.Context, uuid.UUID) (int, error)
+	GetTaskRuns(context.Context, uuid.UUID) ([]model.TaskRun, error)
+	GetProcessByID(context.Context, uuid.UUID) (model.ProcessRun, error)
 }
 
 //counterfeiter:generate . Store
 type Store interface {
-	AddMessage(context.Context, model.Message) error
-	MarkCompleted(context.Context, uuid.UUID) error
-	MessageExists(context.Context, uuid.UUID) (bool, error)
-	RunInAtomically(context.Context, func(context.Context) error) error
+	ClaimMessage(context.Context, model.Message, string, time.Duration) (bool, error)
+	ExtendLease(context.Context, uuid.UUID, string, time.Duration) error
+	CompleteMessage(context.Context, uuid.UUID, string) error
+	ReleaseMessage(context.Context, uuid.UUID, string) error
 }
 
 //counterfeiter:generate . Executor
 type Executor interface {
-	Run(ctx context.Context, task model.Task) error
+	// Run executes the task, streaming its output to stdout and stderr as it is produced.
+	Run(ctx context.Context, task model.Task, stdout, stderr io.Writer) (model.TaskOutput, error)
+}
+
+//counterfeiter:generate . Registry
+type Registry interface {
+	Register(context.Context, uuid.UUID) (context.Context, func())
+	Cancel(uuid.UUID) bool
+	Pause(uuid.UUID) bool
+	Paused(uuid.UUID) <-chan struct{}
+}
+
+const defaultLeaseTTL = 30 * time.Second
+
+// Lease describes the claims a consumer takes on the messages it processes.
+type Lease struct {
+	// Owner identifies the consumer holding the claims.
+	Owner string
+	// TTL is how long a claim lasts without being extended. Claims are extended every third of it.
+	TTL time.Duration
 }
 
 type Service struct {
 	store        Store
 	processStore ProcessStore
 	executors    map[model.ClassType]Executor
+	registry     Registry
+	lease        Lease
 }
 
 func NewService(
 	store Store,
 	processStore ProcessStore,
 	executors map[model.ClassType]Executor,
+	registry Registry,
+	lease Lease,
 ) *Service {
+	if lease.TTL <= 0 {
+		lease.TTL = defaultLeaseTTL
+	}
 	return &Service{
 		store:        store,
 		processStore: processStore,
 		executors:    executors,
+		registry:     registry,
+		lease:        lease,
 	}
 }
 
+// Run processes the message once. It claims the message, executes the run outside of
+// any transaction while extending the claim, and records the message as completed once
+// the run is finished, whether it succeeded or not.
 func (s *Service) Run(ctx context.Context, message model.Message) error {
-	return s.store.RunInAtomically(ctx, func(ctx context.Context) error {
-		exists, err := s.store.MessageExists(ctx, message.UUID)
-		if err != nil {
-			return fmt.Errorf("failed to check message: %w", err)
-		}
-		if exists {
-			logger.GetLogger().Infof("Skipping already processed process with UUID: %s", message.UUID)
-			return nil
-		}
+	claimed, err := s.store.ClaimMessage(ctx, message, s.lease.Owner, s.lease.TTL)
+	if err != nil {
+		return fmt.Errorf("failed to claim message: %w", err)
+	}
+	if !claimed {
+		logger.GetLogger().Infof("Skipping already claimed process with UUID: %s", message.UUID)
+		return nil
+	}
 
-		if err := s.store.AddMessage(ctx, message); err != nil {
-			return fmt.Errorf("failed to persist process event: %w", err)
-		}
+	// The run is aborted and no longer recorded once the lease on the message is lost.
+	leaseCtx, stopHeartbeat := s.heartbeat(ctx, message.UUID)
+	logger.GetLogger().Infof("Running process: %s...", message.ProcessDefinition.Name)
+	switch {
+	case message.RetryOf != nil:
+		err = s.resumeProcess(leaseCtx, *message.RetryOf, message.ProcessDefinition)
+	case message.ContinueOf != nil:
+		err = s.continueProcess(leaseCtx, *message.ContinueOf, message.ProcessDefinition)
+	default:
+		err = s.runProcessDefinition(leaseCtx, message.UUID, message.ProcessDefinition)
+	}
+	stopHeartbeat()
+
+	// The message was handed to another consumer, which carries on with the run.
+	if errors.Is(context.Cause(leaseCtx), model.ErrLeaseLost) || errors.Is(err, model.ErrOwnershipLost) {
+		logger.GetLogger().Warnf("Abandoned process %s after losing the lease on message %s: %v", message.ProcessDefinition.Name, message.UUID, err)
+		return nil
+	}
 
-		logger.GetLogger().Infof("Running process: %s...", message.ProcessDefinition.Name)
-		if err := s.runProcessDefinition(ctx, message.ProcessDefinition); err != nil {
-			return fmt.Errorf("task execution failed: %w", err)
+	// A message whose run never started is handed back, so that a redelivery can start it.
+	if errors.Is(err, errRunNotStarted) {
+		if err := s.store.ReleaseMessage(ctx, message.UUID, s.lease.Owner); err != nil {
+			logger.GetLogger().Errorf("failed to release message %s: %v", message.UUID, err)
 		}
-		logger.GetLogger().Info("Process executed successfully!")
+		return fmt.Errorf("task execution failed: %w", err)
+	}
+
+	if err := s.store.CompleteMessage(ctx, message.UUID, s.lease.Owner); err != nil {
+		return fmt.Errorf("failed to mark process as completed: %w", err)
+	}
+	if err != nil {
+		return fmt.Errorf("task execution failed: %w", err)
+	}
+	logger.GetLogger().Info("Process executed successfully!")
+	return nil
+}
+
+// heartbeat extends the claim on the message until the returned function is called.
+// The returned context is cancelled with model.ErrLeaseLost once the claim is lost.
+func (s *Service) heartbeat(ctx context.Context, id uuid.UUID) (context.Context, func()) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(s.lease.TTL / 3)
+		defer ticker.Stop()
 
-		if err := s.store.MarkCompleted(ctx, message.UUID); err != nil {
-			return fmt.Errorf("failed to mark process as completed: %w", err)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			err := s.store.ExtendLease(ctx, id, s.lease.Owner, s.lease.TTL)
+			if errors.Is(err, model.ErrLeaseLost) {
+				logger.GetLogger().Errorf("Lost the lease on message %s, aborting its run", id)
+				cancel(model.ErrLeaseLost)
+				return
+			}
+			if err != nil && ctx.Err() == nil {
+				logger.GetLogger().Warnf("failed to extend lease on message %s: %v", id, err)
+			}
 		}
+	}()
 
-		return nil
-	})
+	return ctx, func() {
+		cancel(nil)
+		<-done
+	}
 }
 
-func (s *Service) runProcessDefinition(ctx context.Context, def model.ProcessDefinition) error {
-	processID := uuid.New()
+// runProcessDefinition starts a new run. The run has the ID of the message that requested it.
+func (s *Service) runProcessDefinition(ctx context.Context, processID uuid.UUID, def model.ProcessDefinition) error {
 	startedAt := time.Now()
 
 	process := model.ProcessRun{
@@ -91,95 +195,647 @@ func (s *Service) runProcessDefinition(ctx context.Context, def model.ProcessDef
 	}
 
 	if err := s.processStore.InsertProcess(ctx, process); err != nil {
-		return fmt.Errorf("failed to insert process: %w", err)
-	}
-
-	var (
-		wg     sync.WaitGroup
-		mu     sync.Mutex
-		errMsg error
-
-		taskStatus = make(map[string]bool)
-		statusMu   sync.Mutex
-	)
-
-	for _, task := range def.Tasks {
-		wg.Add(1)
-		go func(t model.Task) {
-			defer wg.Done()
-			if err := s.runTask(ctx, processID, task, &taskStatus, &statusMu); err != nil {
-				mu.Lock()
-				if errMsg == nil {
-					errMsg = err
-				}
-				mu.Unlock()
+		return fmt.Errorf("%w: failed to insert process: %w", errRunNotStarted, err)
+	}
+
+	return s.executeRun(ctx, ctx, processID, 1, def, nil, false)
+}
+
+// resumeProcess starts the next attempt of a finished run. Tasks that succeeded in an
+// earlier attempt keep their results and outputs, all others are executed again.
+func (s *Service) resumeProcess(ctx context.Context, processID uuid.UUID, def model.ProcessDefinition) error {
+	attempt, err := s.processStore.BeginRetry(ctx, processID)
+	if errors.Is(err, model.ErrProcessNotRetryable) {
+		logger.GetLogger().Warnf("Skipping retry of process %s: %v", processID, err)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("%w: failed to begin retry: %w", errRunNotStarted, err)
+	}
+
+	taskRuns, err := s.processStore.GetTaskRuns(ctx, processID)
+	if err != nil {
+		return fmt.Errorf("failed to get task runs: %w", err)
+	}
+
+	msg := fmt.Sprintf("Retrying process, attempt %d", attempt)
+	if err := s.processStore.AppendProcessLog(ctx, processID, msg); err != nil {
+		return fmt.Errorf("failed to append to process log: %w", err)
+	}
+
+	return s.executeRun(ctx, ctx, processID, attempt, def, model.LatestTaskRuns(taskRuns), false)
+}
+
+// continueProcess carries on with a run that was parked until its approval tasks were
+// decided or it was resumed. The run stays in its attempt: the tasks that finished before it was parked,
+// including the decided approvals, keep their results and the pending ones are executed.
+func (s *Service) continueProcess(ctx context.Context, processID uuid.UUID, def model.ProcessDefinition) error {
+	attempt, err := s.processStore.BeginContinue(ctx, processID)
+	if errors.Is(err, model.ErrProcessNotParked) {
+		logger.GetLogger().Warnf("Skipping continuation of process %s: %v", processID, err)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("%w: failed to begin continuation: %w", errRunNotStarted, err)
+	}
+
+	taskRuns, err := s.processStore.GetTaskRuns(ctx, processID)
+	if err != nil {
+		return fmt.Errorf("failed to get task runs: %w", err)
+	}
+
+	if err := s.processStore.AppendProcessLog(ctx, processID, "Continuing parked process"); err != nil {
+		return fmt.Errorf("failed to append to process log: %w", err)
+	}
+
+	return s.executeRun(ctx, ctx, processID, attempt, def, model.LatestTaskRuns(taskRuns), true)
+}
+
+// executeRun executes the tasks of an attempt of a run and records its final status. previous holds
+// the latest task runs of earlier attempts of the run, if any. A continued run carries
+// on with the attempt previous belongs to instead of starting a new one. The run is
+// aborted once parent is done, which is ctx itself unless the run is a sub-process.
+func (s *Service) executeRun(
+	ctx context.Context,
+	parent context.Context,
+	processID uuid.UUID,
+	attempt int,
+	def model.ProcessDefinition,
+	previous map[string]model.TaskRun,
+	continued bool,
+) error {
+	lineage := append(slices.Clone(lineageOf(parent)), def.Name)
+	ctx = withLineage(ctx, lineage)
+
+	// Executors run on a context that is cancelled when a stop is requested for this run.
+	runCtx, release := s.registry.Register(parent, processID)
+	defer release()
+	runCtx = withLineage(runCtx, lineage)
+	s.applyPendingRequests(ctx, processID)
+
+	// Sub-processes cannot be parked, as the task that started them waits for them.
+	var paused <-chan struct{}
+	if len(lineage) == 1 {
+		paused = s.registry.Paused(processID)
+	}
+
+	if def.Timeout > 0 {
+		var cancel context.CancelFunc
+		runCtx, cancel = context.WithTimeoutCause(runCtx, time.Duration(def.Timeout), errProcessTimedOut)
+		defer cancel()
+	}
+
+	graph, err := newTaskGraph(def)
+	var finallyGraph *taskGraph
+	if err == nil {
+		finallyGraph, err = newFinallyGraph(def)
+	}
+	if err != nil {
+		msg := fmt.Sprintf("Invalid task graph: %v", err)
+		if err := s.processStore.AppendProcessLog(ctx, processID, msg); err != nil {
+			return fmt.Errorf("failed to append to process log: %w", err)
+		}
+		_ = s.processStore.UpdateProcessStatus(ctx, processID, attempt, model.StatusFailed)
+		return fmt.Errorf("invalid task graph: %w", err)
+	}
+
+	run := &runState{
+		processID: processID,
+		attempt:   attempt,
+		attempts:  make(map[string]int, len(graph.order)+len(finallyGraph.order)),
+	}
+	finished := make(map[string]model.TaskRun)
+
+	for _, name := range graph.order {
+		prev, ok := previous[name]
+		if ok && prev.Status == model.TaskStatusSucceeded && !wasCompensated(graph.tasks[name], prev, previous) {
+			finished[name] = prev
+			run.setOutputs(name, prev.Outputs)
+			continue
+		}
+		if continued && ok {
+			run.attempts[name] = prev.Attempt
+			if prev.Status != model.TaskStatusPending {
+				finished[name] = prev
 			}
-		}(task)
+			continue
+		}
+		if err := s.insertPendingTaskRun(ctx, run, graph.tasks[name], prev.Attempt+1); err != nil {
+			return err
+		}
+	}
+	// Cleanup runs again on every attempt, even if it succeeded before.
+	for _, name := range finallyGraph.order {
+		if continued {
+			run.attempts[name] = previous[name].Attempt
+			continue
+		}
+		if err := s.insertPendingTaskRun(ctx, run, finallyGraph.tasks[name], previous[name].Attempt+1); err != nil {
+			return err
+		}
+	}
+
+	run.logs = newLogBatcher(ctx, s.processStore, processID)
+
+	execute := func(runCtx context.Context, task model.Task) error {
+		return s.runTask(ctx, runCtx, run, task)
+	}
+	notRun := func(task model.Task, status model.TaskStatus, reason string) {
+		s.recordNotRunTask(ctx, run, task, status, reason)
+	}
+	logProgress := func(msg string) {
+		if err := s.processStore.AppendProcessLog(ctx, processID, msg); err != nil {
+			logger.GetLogger().Errorf("failed to append to process log: %v", err)
+		}
+	}
+	sched := newScheduler(graph, finished, execute, notRun, run.output)
+	sched.pause = paused
+	sched.log = logProgress
+	statuses, errMsg := sched.run(runCtx)
+
+	// Another consumer took over the run, so none of its outcome is recorded here.
+	if errors.Is(context.Cause(ctx), model.ErrLeaseLost) {
+		run.logs.close()
+		return fmt.Errorf("run abandoned: %w", context.Cause(ctx))
+	}
+
+	// The scheduler only leaves tasks pending or awaiting approval if the run is parked.
+	pending := tasksWithStatus(graph, statuses, model.TaskStatusPending)
+	awaiting := tasksWithStatus(graph, statuses, model.TaskStatusAwaitingApproval)
+	if len(pending)+len(awaiting) > 0 {
+		run.logs.close()
+		if isClosed(paused) {
+			return s.park(ctx, run, model.StatusPaused, "Process paused")
+		}
+		msg := fmt.Sprintf("Process awaiting approval of tasks: %s", strings.Join(awaiting, ", "))
+		return s.park(ctx, run, model.StatusAwaitingApproval, msg)
+	}
+
+	if errMsg != nil {
+		if err := s.compensate(ctx, run, graph, statuses, previous); err != nil {
+			run.logs.close()
+			return err
+		}
+	}
+
+	var finallyStatuses map[string]model.TaskStatus
+	if len(finallyGraph.order) > 0 {
+		if err := s.processStore.AppendProcessLog(ctx, processID, "Running finally tasks"); err != nil {
+			run.logs.close()
+			return fmt.Errorf("failed to append to process log: %w", err)
+		}
+		// Finally tasks run on the consumer context, so neither a stop request nor the
+		// process timeout prevents the cleanup. Their own timeouts still apply.
+		finallySched := newScheduler(finallyGraph, nil, execute, notRun, run.output)
+		finallySched.upstream = statuses
+		finallySched.log = logProgress
+		finallyStatuses, _ = finallySched.run(ctx)
 	}
+	run.logs.close()
 
-	wg.Wait()
+	if errors.Is(context.Cause(runCtx), registry.ErrStopRequested) {
+		if err := s.processStore.AppendProcessLog(ctx, processID, "Process stopped on request"); err != nil {
+			return fmt.Errorf("failed to append to process log: %w", err)
+		}
+		if err := s.processStore.UpdateProcessStatus(ctx, processID, attempt, model.StatusStopped); err != nil {
+			return fmt.Errorf("failed to update process status: %w", err)
+		}
+		return nil
+	}
+
+	if errors.Is(context.Cause(runCtx), errProcessTimedOut) {
+		msg := fmt.Sprintf("Process timed out after %s", time.Duration(def.Timeout))
+		if err := s.processStore.AppendProcessLog(ctx, processID, msg); err != nil {
+			return fmt.Errorf("failed to append to process log: %w", err)
+		}
+		_ = s.processStore.UpdateProcessStatus(ctx, processID, attempt, model.StatusTimedOut)
+		return fmt.Errorf("%w after %s", errProcessTimedOut, time.Duration(def.Timeout))
+	}
 
 	if errMsg != nil {
-		_ = s.processStore.UpdateProcessStatus(ctx, processID, model.StatusFailed)
+		_ = s.processStore.UpdateProcessStatus(ctx, processID, attempt, model.StatusFailed)
 		return fmt.Errorf("failed task: %w", errMsg)
 	}
 
-	if err := s.processStore.UpdateProcessStatus(ctx, processID, model.StatusCompleted); err != nil {
+	if runCtx.Err() != nil {
+		_ = s.processStore.UpdateProcessStatus(ctx, processID, attempt, model.StatusFailed)
+		return fmt.Errorf("process aborted: %w", context.Cause(runCtx))
+	}
+
+	// Failures of tasks that continue on error and of finally tasks do not fail the run.
+	if failed := append(failedTasks(graph, statuses), failedTasks(finallyGraph, finallyStatuses)...); len(failed) > 0 {
+		msg := fmt.Sprintf("Process completed with failed tasks: %s", strings.Join(failed, ", "))
+		if err := s.processStore.AppendProcessLog(ctx, processID, msg); err != nil {
+			return fmt.Errorf("failed to append to process log: %w", err)
+		}
+		if err := s.processStore.UpdateProcessStatus(ctx, processID, attempt, model.StatusCompletedWithErrors); err != nil {
+			return fmt.Errorf("failed to update process status: %w", err)
+		}
+		return nil
+	}
+
+	if err := s.processStore.UpdateProcessStatus(ctx, processID, attempt, model.StatusCompleted); err != nil {
 		return fmt.Errorf("failed to update process status: %w", err)
 	}
 
 	return nil
 }
 
-func (s *Service) runTask(
+// applyPendingRequests stops or pauses the run if that was requested before it was
+// registered, as the notification of the request found no run to stop or pause then.
+func (s *Service) applyPendingRequests(ctx context.Context, processID uuid.UUID) {
+	run, err := s.processStore.GetProcessByID(ctx, processID)
+	if err != nil {
+		logger.GetLogger().Warnf("failed to check pending requests of process %s: %v", processID, err)
+		return
+	}
+	if run.StopRequestedAt != nil {
+		s.registry.Cancel(processID)
+	}
+	if run.PauseRequestedAt != nil {
+		s.registry.Pause(processID)
+	}
+}
+
+// park records that the run waits for its approvals or to be resumed. Compensations
+// and finally tasks are left for when the run continues.
+func (s *Service) park(ctx context.Context, run *runState, status model.ProcessStatus, msg string) error {
+	if err := s.processStore.AppendProcessLog(ctx, run.processID, msg); err != nil {
+		return fmt.Errorf("failed to append to process log: %w", err)
+	}
+	if err := s.processStore.UpdateProcessStatus(ctx, run.processID, run.attempt, status); err != nil {
+		return fmt.Errorf("failed to update process status: %w", err)
+	}
+	return nil
+}
+
+// isClosed reports whether the channel is closed. A nil channel never is.
+func isClosed(ch <-chan struct{}) bool {
+	select {
+	case <-ch:
+		return true
+	default:
+		return false
+	}
+}
+
+// wasCompensated reports whether the compensation of a task ran after its latest success.
+func wasCompensated(task model.Task, taskRun model.TaskRun, previous map[string]model.TaskRun) bool {
+	compensation, ok := task.Compensation()
+	if !ok {
+		return false
+	}
+	compensationRun, ok := previous[compensation.Name]
+	return ok && compensationRun.Status != model.TaskStatusPending && compensationRun.ID > taskRun.ID
+}
+
+// compensate undoes the tasks that succeeded before the run failed. The compensations run
+// one by one in reverse dependency order, so a task is only undone once every task built
+// on it is. A failing compensation does not prevent the remaining ones.
+func (s *Service) compensate(
 	ctx context.Context,
-	processID uuid.UUID,
-	task model.Task,
-	taskStatus *map[string]bool,
-	statusMu *sync.Mutex,
+	run *runState,
+	graph *taskGraph,
+	statuses map[string]model.TaskStatus,
+	previous map[string]model.TaskRun,
 ) error {
-	// Wait for all dependencies
-	for {
-		statusMu.Lock()
-		allDepsDone := true
-		for _, dep := range task.WaitFor {
-			if !(*taskStatus)[dep] {
-				allDepsDone = false
-				break
-			}
+	var compensations []model.Task
+	for i := len(graph.order) - 1; i >= 0; i-- {
+		task := graph.tasks[graph.order[i]]
+		if compensation, ok := task.Compensation(); ok && statuses[task.Name] == model.TaskStatusSucceeded {
+			compensations = append(compensations, compensation)
 		}
-		statusMu.Unlock()
+	}
+	if len(compensations) == 0 {
+		return nil
+	}
 
-		if allDepsDone {
-			break
+	if err := s.processStore.AppendProcessLog(ctx, run.processID, "Compensating succeeded tasks"); err != nil {
+		return fmt.Errorf("failed to append to process log: %w", err)
+	}
+	for _, compensation := range compensations {
+		if err := s.insertPendingTaskRun(ctx, run, compensation, previous[compensation.Name].Attempt+1); err != nil {
+			return err
 		}
-		time.Sleep(100 * time.Millisecond)
+		// The failure is recorded on the task run and in the process log.
+		_ = s.runTask(ctx, ctx, run, compensation)
+	}
+	return nil
+}
+
+// insertPendingTaskRun records a task that is about to be scheduled.
+func (s *Service) insertPendingTaskRun(ctx context.Context, run *runState, task model.Task, attempt int) error {
+	run.attempts[task.Name] = attempt
+	taskRun := model.TaskRun{
+		ProcessID: run.processID,
+		Name:      task.Name,
+		Class:     task.Class,
+		Status:    model.TaskStatusPending,
+		Attempt:   attempt,
+	}
+	if err := s.processStore.InsertTaskRun(ctx, run.attempt, taskRun); err != nil {
+		return fmt.Errorf("failed to insert task run: %w", err)
 	}
+	return nil
+}
 
-	executor, ok := s.executors[task.Class]
+// runState holds what the tasks of a single process run share.
+type runState struct {
+	processID uuid.UUID
+	// attempt is the attempt of the run the tasks are executed in.
+	attempt int
+	logs    *logBatcher
+	// attempts holds the attempt number every task to execute starts with. It is
+	// above one for tasks that already ran in an earlier attempt of the run. It is only
+	// written while no task is executing.
+	attempts map[string]int
+
+	mu      sync.Mutex
+	outputs map[string]map[string]string
+}
+
+func (r *runState) firstAttempt(taskName string) int {
+	if attempt, ok := r.attempts[taskName]; ok {
+		return attempt
+	}
+	return 1
+}
+
+func (r *runState) setOutputs(taskName string, outputs map[string]string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.outputs == nil {
+		r.outputs = make(map[string]map[string]string)
+	}
+	r.outputs[taskName] = outputs
+}
+
+func (r *runState) output(taskName, key string) (string, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	value, ok := r.outputs[taskName][key]
+	return value, ok
+}
+
+// renderTask renders the references to outputs of upstream tasks in the task parameters.
+func (r *runState) renderTask(task model.Task) (model.Task, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	params := make(map[string]string, len(task.Parameters))
+	for key, value := range task.Parameters {
+		rendered, err := taskoutput.Render(value, r.outputs)
+		if err != nil {
+			return model.Task{}, fmt.Errorf("param %s: %w", key, err)
+		}
+		params[key] = rendered
+	}
+	task.Parameters = params
+	return task, nil
+}
+
+// runTask executes a single task on the run context, retrying it according to its
+// retry policy and recording every attempt with the consumer context.
+func (s *Service) runTask(
+	ctx context.Context,
+	runCtx context.Context,
+	run *runState,
+	task model.Task,
+) error {
+	executor, ok := s.executor(ctx, run, task.Class)
 	if !ok {
 		msg := fmt.Sprintf("No executor registered for class type: %s", task.Class)
-		if err := s.processStore.AppendProcessLog(ctx, processID, msg); err != nil {
-			return fmt.Errorf("failed to append to process log: %w", err)
-		}
-		return errors.New(msg)
+		return s.failTaskBeforeRun(ctx, runCtx, run, task, msg)
 	}
 
-	if err := executor.Run(ctx, task); err != nil {
-		msg := fmt.Sprintf("Failed to run task %s: %v", task.Name, err)
-		if err := s.processStore.AppendProcessLog(ctx, processID, msg); err != nil {
+	rendered, err := run.renderTask(task)
+	if err != nil {
+		msg := fmt.Sprintf("Failed to render task %s: %v", task.Name, err)
+		return s.failTaskBeforeRun(ctx, runCtx, run, task, msg)
+	}
+	task = rendered
+
+	first := run.firstAttempt(task.Name)
+	attempts := maxAttempts(task.Retry)
+	for attempt := 1; ; attempt++ {
+		result, err := s.runTaskAttempt(ctx, runCtx, run, executor, task, first+attempt-1)
+		if err != nil {
+			return err
+		}
+		execErr := result.err
+
+		if errors.Is(execErr, model.ErrAwaitingApproval) {
+			msg := fmt.Sprintf("Task %s is awaiting approval", task.Name)
+			if err := s.processStore.AppendProcessLog(ctx, run.processID, msg); err != nil {
+				return fmt.Errorf("failed to append to process log: %w", err)
+			}
+			return execErr
+		}
+
+		if execErr == nil {
+			msg := fmt.Sprintf("Task %s completed", task.Name)
+			if attempts > 1 {
+				msg = fmt.Sprintf("Task %s completed on attempt %d/%d", task.Name, attempt, attempts)
+			}
+			if err := s.processStore.AppendProcessLog(ctx, run.processID, msg); err != nil {
+				return fmt.Errorf("failed to append to process log: %w", err)
+			}
+			return nil
+		}
+
+		canRetry := attempt < attempts && runCtx.Err() == nil && isRetryable(task.Retry, result.output.ExitCode)
+		if !canRetry {
+			msg := fmt.Sprintf("Failed to run task %s: %v", task.Name, execErr)
+			if err := s.processStore.AppendProcessLog(ctx, run.processID, msg); err != nil {
+				return fmt.Errorf("failed to append to process log: %w", err)
+			}
+			return fmt.Errorf("executor error: %w", execErr)
+		}
+
+		delay := retryDelay(task.Retry, attempt)
+		msg := fmt.Sprintf("Task %s attempt %d/%d failed: %v. Retrying in %s", task.Name, attempt, attempts, execErr, delay)
+		if err := s.processStore.AppendProcessLog(ctx, run.processID, msg); err != nil {
 			return fmt.Errorf("failed to append to process log: %w", err)
 		}
-		return fmt.Errorf("executor error: %w", err)
+
+		select {
+		case <-runCtx.Done():
+			return fmt.Errorf("executor error: %w", execErr)
+		case <-time.After(delay):
+		}
 	}
+}
 
-	if err := s.processStore.AppendProcessLog(ctx, processID, fmt.Sprintf("Task %s completed", task.Name)); err != nil {
+// executor returns the executor of the class. processCmd tasks are run by the service itself.
+func (s *Service) executor(ctx context.Context, run *runState, class model.ClassType) (Executor, bool) {
+	if class == model.ProcessCmd {
+		return &subprocessExecutor{service: s, parentID: run.processID, recordCtx: ctx}, true
+	}
+	executor, ok := s.executors[class]
+	return executor, ok
+}
+
+// failTaskBeforeRun records a task that could not be started as failed.
+func (s *Service) failTaskBeforeRun(
+	ctx context.Context,
+	runCtx context.Context,
+	run *runState,
+	task model.Task,
+	msg string,
+) error {
+	taskRun := model.TaskRun{
+		ProcessID: run.processID,
+		Name:      task.Name,
+		Class:     task.Class,
+		Attempt:   run.firstAttempt(task.Name),
+	}
+	if err := s.finishTaskRun(ctx, run, taskRun, model.TaskOutput{}, errors.New(msg), runCtx.Err()); err != nil {
+		return err
+	}
+	if err := s.processStore.AppendProcessLog(ctx, run.processID, msg); err != nil {
 		return fmt.Errorf("failed to append to process log: %w", err)
 	}
+	return errors.New(msg)
+}
+
+// attemptResult is the outcome of a single task execution.
+type attemptResult struct {
+	output model.TaskOutput
+	err    error
+}
+
+// runTaskAttempt executes the task once and persists the attempt. The returned error
+// is only set when the attempt could not be recorded.
+func (s *Service) runTaskAttempt(
+	ctx context.Context,
+	runCtx context.Context,
+	run *runState,
+	executor Executor,
+	task model.Task,
+	attempt int,
+) (attemptResult, error) {
+	startedAt := time.Now()
+	taskRun := model.TaskRun{
+		ProcessID: run.processID,
+		Name:      task.Name,
+		Class:     task.Class,
+		Status:    model.TaskStatusRunning,
+		Attempt:   attempt,
+		StartedAt: &startedAt,
+	}
 
-	statusMu.Lock()
-	(*taskStatus)[task.Name] = true
-	statusMu.Unlock()
+	// The first attempt was recorded as pending when the run started.
+	record := s.processStore.UpdateTaskRun
+	if attempt > run.firstAttempt(task.Name) {
+		record = s.processStore.InsertTaskRun
+	}
+	if err := record(ctx, run.attempt, taskRun); err != nil {
+		return attemptResult{}, fmt.Errorf("failed to record task run: %w", err)
+	}
+
+	attemptCtx := runCtx
+	if task.Timeout > 0 {
+		var cancel context.CancelFunc
+		attemptCtx, cancel = context.WithTimeoutCause(runCtx, time.Duration(task.Timeout), errTaskTimedOut)
+		defer cancel()
+	}
+
+	stdout := run.logs.writer(task.Name, model.StreamStdout)
+	stderr := run.logs.writer(task.Name, model.StreamStderr)
+	output, execErr := executor.Run(attemptCtx, task, stdout, stderr)
+	_ = stdout.Close()
+	_ = stderr.Close()
+
+	if execErr != nil && errors.Is(context.Cause(attemptCtx), errTaskTimedOut) {
+		execErr = fmt.Errorf("%w after %s: %w", errTaskTimedOut, time.Duration(task.Timeout), execErr)
+	}
+
+	if execErr == nil {
+		outputs, err := taskoutput.CaptureAll(task.Outputs, output.Stdout)
+		if err != nil {
+			execErr = fmt.Errorf("failed to capture outputs: %w", err)
+		}
+		taskRun.Outputs = outputs
+		if len(outputs) > 0 {
+			run.setOutputs(task.Name, outputs)
+		}
+	}
 
+	if errors.Is(execErr, model.ErrAwaitingApproval) && task.Timeout > 0 {
+		expiresAt := startedAt.Add(time.Duration(task.Timeout))
+		taskRun.ApprovalExpiresAt = &expiresAt
+	}
+
+	result := attemptResult{output: output, err: execErr}
+	if err := s.finishTaskRun(ctx, run, taskRun, output, execErr, runCtx.Err()); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+func (s *Service) finishTaskRun(
+	ctx context.Context,
+	run *runState,
+	taskRun model.TaskRun,
+	output model.TaskOutput,
+	execErr error,
+	runErr error,
+) error {
+	endedAt := time.Now()
+	taskRun.EndedAt = &endedAt
+	taskRun.ExitCode = output.ExitCode
+	taskRun.Stdout = truncateOutput(output.Stdout)
+	taskRun.Stderr = truncateOutput(output.Stderr)
+
+	switch {
+	case execErr == nil:
+		taskRun.Status = model.TaskStatusSucceeded
+	case errors.Is(execErr, errTaskTimedOut):
+		taskRun.Status = model.TaskStatusTimedOut
+		taskRun.Error = execErr.Error()
+	case runErr != nil:
+		taskRun.Status = model.TaskStatusCancelled
+		taskRun.Error = execErr.Error()
+	case errors.Is(execErr, model.ErrAwaitingApproval):
+		taskRun.Status = model.TaskStatusAwaitingApproval
+		taskRun.EndedAt = nil
+	default:
+		taskRun.Status = model.TaskStatusFailed
+		taskRun.Error = execErr.Error()
+	}
+
+	if err := s.processStore.UpdateTaskRun(ctx, run.attempt, taskRun); err != nil {
+		return fmt.Errorf("failed to update task run: %w", err)
+	}
 	return nil
 }
+
+// recordNotRunTask persists the terminal state of a task that was never executed.
+func (s *Service) recordNotRunTask(
+	ctx context.Context,
+	run *runState,
+	task model.Task,
+	status model.TaskStatus,
+	reason string,
+) {
+	endedAt := time.Now()
+	taskRun := model.TaskRun{
+		ProcessID: run.processID,
+		Name:      task.Name,
+		Class:     task.Class,
+		Status:    status,
+		Attempt:   run.firstAttempt(task.Name),
+		Error:     reason,
+		EndedAt:   &endedAt,
+	}
+	if err := s.processStore.UpdateTaskRun(ctx, run.attempt, taskRun); err != nil {
+		logger.GetLogger().Errorf("failed to update task run: %v", err)
+	}
+
+	msg := fmt.Sprintf("Task %s %s: %s", task.Name, status, reason)
+	if err := s.processStore.AppendProcessLog(ctx, run.processID, msg); err != nil {
+		logger.GetLogger().Errorf("failed to append to process log: %v", err)
+	}
+}
@@ -3,6 +3,12 @@ package service_test
 import (
 	"context"
 	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
 	. "github.com/onsi/ginkgo/v2"
@@ -10,19 +16,23 @@ import (
 
 	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/model"
 	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/processconsumer/service"
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/processconsumer/service/registry"
 	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/processconsumer/service/servicefakes"
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/taskoutput"
 )
 
 var (
-	ErrDb            = errors.New("db error")
-	ErrInsert        = errors.New("insert error")
-	ErrMarkCompleted = errors.New("mark completed error")
+	ErrDb          = errors.New("db error")
+	ErrInsert      = errors.New("insert error")
+	ErrComplete    = errors.New("complete message error")
+	ErrExtendLease = errors.New("extend lease error")
+	ErrExecutor    = errors.New("executor error")
 )
 
 var _ = Describe("Service", func() {
 	When("created", func() {
 		It("should instantiate the service", func() {
-			Expect(service.NewService(nil, nil, nil)).NotTo(BeNil())
+			Expect(service.NewService(nil, nil, nil, nil, service.Lease{})).NotTo(BeNil())
 		})
 	})
 
@@ -34,6 +44,8 @@ var _ = Describe("Service", func() {
 			processStore *servicefakes.FakeProcessStore
 			executor     *servicefakes.FakeExecutor
 			executors    map[model.ClassType]service.Executor
+			runRegistry  *registry.Registry
+			lease        service.Lease
 			msg          model.Message
 			errAction    error
 		)
@@ -46,7 +58,9 @@ var _ = Describe("Service", func() {
 			executors = map[model.ClassType]service.Executor{
 				"someCmd": executor,
 			}
-			svc = service.NewService(store, processStore, executors)
+			runRegistry = registry.NewRegistry()
+			lease = service.Lease{Owner: "consumer-1", TTL: time.Minute}
+			svc = service.NewService(store, processStore, executors, runRegistry, lease)
 
 			msg = model.Message{
 				UUID: uuid.New(),
@@ -55,13 +69,7 @@ var _ = Describe("Service", func() {
 				},
 			}
 
-			store.RunInAtomicallyStub = func(ctx context.Context, fn func(context.Context) error) error {
-				return fn(ctx)
-			}
-
-			store.MessageExistsReturns(false, nil)
-			store.AddMessageReturns(nil)
-			store.MarkCompletedReturns(nil)
+			store.ClaimMessageReturns(true, nil)
 		})
 
 		JustBeforeEach(func() {
@@ -71,24 +79,43 @@ var _ = Describe("Service", func() {
 		It("succeeds", func() {
 			Expect(errAction).ToNot(HaveOccurred())
 
-			Expect(store.MessageExistsCallCount()).To(Equal(1))
-			Expect(store.AddMessageCallCount()).To(Equal(1))
-			Expect(store.MarkCompletedCallCount()).To(Equal(1))
+			Expect(store.ClaimMessageCallCount()).To(Equal(1))
+			Expect(store.CompleteMessageCallCount()).To(Equal(1))
+		})
+
+		It("claims and completes the message as the consumer", func() {
+			_, claimedMsg, owner, ttl := store.ClaimMessageArgsForCall(0)
+			Expect(claimedMsg.UUID).To(Equal(msg.UUID))
+			Expect(owner).To(Equal("consumer-1"))
+			Expect(ttl).To(Equal(time.Minute))
+
+			_, id, owner := store.CompleteMessageArgsForCall(0)
+			Expect(id).To(Equal(msg.UUID))
+			Expect(owner).To(Equal("consumer-1"))
+		})
+
+		It("records the run under the message ID", func() {
+			Expect(processStore.InsertProcessCallCount()).To(Equal(1))
+			_, run := processStore.InsertProcessArgsForCall(0)
+			Expect(run.ID).To(Equal(msg.UUID))
+			Expect(run.Status).To(Equal(model.StatusRunning))
 		})
 
-		When("the message exists", func() {
+		When("the message is already claimed", func() {
 			BeforeEach(func() {
-				store.MessageExistsReturns(true, nil)
+				store.ClaimMessageReturns(false, nil)
 			})
 
 			It("should skip processing the message", func() {
-				Expect(store.AddMessageCallCount()).To(Equal(0))
+				Expect(errAction).ToNot(HaveOccurred())
+				Expect(processStore.InsertProcessCallCount()).To(Equal(0))
+				Expect(store.CompleteMessageCallCount()).To(Equal(0))
 			})
 		})
 
-		When("MessageExists fails", func() {
+		When("ClaimMessage fails", func() {
 			BeforeEach(func() {
-				store.MessageExistsReturns(false, ErrDb)
+				store.ClaimMessageReturns(false, ErrDb)
 			})
 
 			It("returns an error", func() {
@@ -96,23 +123,1594 @@ var _ = Describe("Service", func() {
 			})
 		})
 
-		When("AddMessage fails", func() {
+		When("the run cannot be started", func() {
 			BeforeEach(func() {
-				store.AddMessageReturns(ErrInsert)
+				processStore.InsertProcessReturns(ErrInsert)
 			})
 
 			It("returns an error", func() {
 				Expect(errAction).To(MatchError(ErrInsert))
 			})
+
+			It("releases the message instead of completing it", func() {
+				Expect(store.CompleteMessageCallCount()).To(Equal(0))
+				Expect(store.ReleaseMessageCallCount()).To(Equal(1))
+				_, id, owner := store.ReleaseMessageArgsForCall(0)
+				Expect(id).To(Equal(msg.UUID))
+				Expect(owner).To(Equal("consumer-1"))
+			})
+		})
+
+		When("a task runs longer than a third of the lease", func() {
+			BeforeEach(func() {
+				lease.TTL = 30 * time.Millisecond
+				svc = service.NewService(store, processStore, executors, runRegistry, lease)
+				msg.ProcessDefinition.Tasks = []model.Task{
+					{Name: "long", Class: "someCmd"},
+				}
+
+				executor.RunStub = func(context.Context, model.Task, io.Writer, io.Writer) (model.TaskOutput, error) {
+					time.Sleep(100 * time.Millisecond)
+					return model.TaskOutput{}, nil
+				}
+			})
+
+			It("extends the lease while the task runs", func() {
+				Expect(errAction).ToNot(HaveOccurred())
+				Expect(store.ExtendLeaseCallCount()).To(BeNumerically(">=", 2))
+				_, id, owner, ttl := store.ExtendLeaseArgsForCall(0)
+				Expect(id).To(Equal(msg.UUID))
+				Expect(owner).To(Equal("consumer-1"))
+				Expect(ttl).To(Equal(30 * time.Millisecond))
+			})
+
+			It("stops extending the lease once the run is finished", func() {
+				calls := store.ExtendLeaseCallCount()
+				Consistently(store.ExtendLeaseCallCount, 50*time.Millisecond).Should(Equal(calls))
+			})
+
+			When("extending the lease fails", func() {
+				BeforeEach(func() {
+					store.ExtendLeaseReturns(ErrExtendLease)
+				})
+
+				It("keeps running the process", func() {
+					Expect(errAction).ToNot(HaveOccurred())
+					Expect(store.CompleteMessageCallCount()).To(Equal(1))
+				})
+			})
+
+			When("the lease on the message is lost", func() {
+				var cause error
+
+				BeforeEach(func() {
+					cause = nil
+					store.ExtendLeaseReturns(model.ErrLeaseLost)
+					executor.RunStub = func(ctx context.Context, _ model.Task, _, _ io.Writer) (model.TaskOutput, error) {
+						select {
+						case <-ctx.Done():
+							cause = context.Cause(ctx)
+							return model.TaskOutput{}, ctx.Err()
+						case <-time.After(time.Second):
+							return model.TaskOutput{}, nil
+						}
+					}
+				})
+
+				It("aborts the run", func() {
+					Expect(errAction).ToNot(HaveOccurred())
+					Expect(cause).To(MatchError(model.ErrLeaseLost))
+				})
+
+				It("stops recording the run", func() {
+					Expect(processStore.UpdateProcessStatusCallCount()).To(Equal(0))
+				})
+
+				It("leaves the message to the consumer that claimed it", func() {
+					Expect(store.CompleteMessageCallCount()).To(Equal(0))
+					Expect(store.ReleaseMessageCallCount()).To(Equal(0))
+				})
+			})
+		})
+
+		When("tasks depend on each other", func() {
+			var executed []string
+
+			BeforeEach(func() {
+				executed = nil
+				msg.ProcessDefinition.Tasks = []model.Task{
+					{Name: "last", Class: "someCmd", WaitFor: []string{"first", "second"}},
+					{Name: "second", Class: "someCmd", WaitFor: []string{"first"}},
+					{Name: "first", Class: "someCmd"},
+				}
+
+				executor.RunStub = func(_ context.Context, task model.Task, _, _ io.Writer) (model.TaskOutput, error) {
+					executed = append(executed, task.Name)
+					return model.TaskOutput{}, nil
+				}
+			})
+
+			It("runs them in dependency order", func() {
+				Expect(errAction).ToNot(HaveOccurred())
+				Expect(executed).To(Equal([]string{"first", "second", "last"}))
+			})
+
+			It("records the process as completed", func() {
+				_, _, _, status := processStore.UpdateProcessStatusArgsForCall(0)
+				Expect(status).To(Equal(model.StatusCompleted))
+			})
+
+			It("records a pending task run per task", func() {
+				Expect(processStore.InsertTaskRunCallCount()).To(Equal(3))
+				_, _, taskRun := processStore.InsertTaskRunArgsForCall(0)
+				Expect(taskRun.Name).To(Equal("first"))
+				Expect(taskRun.Status).To(Equal(model.TaskStatusPending))
+				Expect(taskRun.Attempt).To(Equal(1))
+			})
+		})
+
+		When("a task writes output after its executor returned", func() {
+			var (
+				write   chan struct{}
+				written chan struct{}
+			)
+
+			BeforeEach(func() {
+				write = make(chan struct{})
+				written = make(chan struct{})
+				msg.ProcessDefinition.Tasks = []model.Task{
+					{Name: "killed", Class: "someCmd"},
+				}
+
+				executor.RunStub = func(_ context.Context, _ model.Task, stdout, _ io.Writer) (model.TaskOutput, error) {
+					go func() {
+						defer close(written)
+						<-write
+						_, _ = io.WriteString(stdout, "late line\n")
+					}()
+					return model.TaskOutput{}, nil
+				}
+			})
+
+			It("drops the output", func() {
+				Expect(errAction).ToNot(HaveOccurred())
+				close(write)
+				Eventually(written).Should(BeClosed())
+
+				for i := 0; i < processStore.AppendProcessLogsCallCount(); i++ {
+					_, logs := processStore.AppendProcessLogsArgsForCall(i)
+					Expect(logs).ToNot(ContainElement(HaveField("Log", "late line")))
+				}
+			})
+		})
+
+		When("a task writes a line longer than a log entry", func() {
+			BeforeEach(func() {
+				msg.ProcessDefinition.Tasks = []model.Task{
+					{Name: "dump", Class: "someCmd"},
+				}
+
+				executor.RunStub = func(_ context.Context, _ model.Task, stdout, _ io.Writer) (model.TaskOutput, error) {
+					for range 10 {
+						_, _ = io.WriteString(stdout, strings.Repeat("é", 1000))
+					}
+					_, _ = io.WriteString(stdout, "\nend\n")
+					return model.TaskOutput{}, nil
+				}
+			})
+
+			It("splits it into entries of bounded size", func() {
+				var lines []model.ProcessLog
+				for i := 0; i < processStore.AppendProcessLogsCallCount(); i++ {
+					_, batch := processStore.AppendProcessLogsArgsForCall(i)
+					lines = append(lines, batch...)
+				}
+
+				var dumped string
+				for _, line := range lines[:len(lines)-1] {
+					Expect(len(line.Log)).To(BeNumerically("<=", 4*1024))
+					Expect(utf8.ValidString(line.Log)).To(BeTrue())
+					dumped += line.Log
+				}
+				Expect(len(lines)).To(BeNumerically(">", 2))
+				Expect(dumped).To(Equal(strings.Repeat("é", 10000)))
+				Expect(lines[len(lines)-1].Log).To(Equal("end"))
+			})
+		})
+
+		When("a task produces output", func() {
+			BeforeEach(func() {
+				msg.ProcessDefinition.Tasks = []model.Task{
+					{Name: "build", Class: "someCmd"},
+				}
+
+				executor.RunStub = func(_ context.Context, _ model.Task, stdout, stderr io.Writer) (model.TaskOutput, error) {
+					_, _ = io.WriteString(stdout, "compiling\ndone")
+					_, _ = io.WriteString(stderr, "warning\n")
+					return model.TaskOutput{}, nil
+				}
+			})
+
+			It("streams every line into the process log", func() {
+				var lines []model.ProcessLog
+				for i := 0; i < processStore.AppendProcessLogsCallCount(); i++ {
+					_, batch := processStore.AppendProcessLogsArgsForCall(i)
+					lines = append(lines, batch...)
+				}
+
+				Expect(lines).To(HaveLen(3))
+				Expect(lines).To(ContainElements(
+					SatisfyAll(
+						HaveField("TaskName", "build"),
+						HaveField("Stream", model.StreamStdout),
+						HaveField("Log", "compiling"),
+					),
+					SatisfyAll(
+						HaveField("Stream", model.StreamStdout),
+						HaveField("Log", "done"),
+					),
+					SatisfyAll(
+						HaveField("Stream", model.StreamStderr),
+						HaveField("Log", "warning"),
+					),
+				))
+			})
+		})
+
+		When("a task fails", func() {
+			BeforeEach(func() {
+				msg.ProcessDefinition.Tasks = []model.Task{
+					{Name: "first", Class: "someCmd"},
+					{Name: "second", Class: "someCmd", WaitFor: []string{"first"}},
+					{Name: "third", Class: "someCmd", WaitFor: []string{"second"}},
+				}
+
+				exitCode := 3
+				executor.RunReturns(model.TaskOutput{ExitCode: &exitCode, Stderr: "boom"}, ErrExecutor)
+			})
+
+			It("returns an error", func() {
+				Expect(errAction).To(MatchError(ErrExecutor))
+			})
+
+			It("still completes the message so the run is not executed again", func() {
+				Expect(store.CompleteMessageCallCount()).To(Equal(1))
+				Expect(store.ReleaseMessageCallCount()).To(Equal(0))
+			})
+
+			It("skips its dependents", func() {
+				Expect(executor.RunCallCount()).To(Equal(1))
+
+				var logs []string
+				for i := 0; i < processStore.AppendProcessLogCallCount(); i++ {
+					_, _, log := processStore.AppendProcessLogArgsForCall(i)
+					logs = append(logs, log)
+				}
+				Expect(logs).To(ContainElements(
+					"Task second skipped: dependency 'first' failed",
+					"Task third skipped: dependency 'second' was skipped",
+				))
+			})
+
+			It("records the process as failed", func() {
+				Expect(processStore.UpdateProcessStatusCallCount()).To(Equal(1))
+				_, _, _, status := processStore.UpdateProcessStatusArgsForCall(0)
+				Expect(status).To(Equal(model.StatusFailed))
+			})
+
+			It("records the task outcomes", func() {
+				taskRuns := map[string]model.TaskRun{}
+				for i := 0; i < processStore.UpdateTaskRunCallCount(); i++ {
+					_, _, taskRun := processStore.UpdateTaskRunArgsForCall(i)
+					taskRuns[taskRun.Name] = taskRun
+				}
+
+				Expect(taskRuns["first"].Status).To(Equal(model.TaskStatusFailed))
+				Expect(taskRuns["first"].ExitCode).To(HaveValue(Equal(3)))
+				Expect(taskRuns["first"].Stderr).To(Equal("boom"))
+				Expect(taskRuns["first"].StartedAt).ToNot(BeNil())
+				Expect(taskRuns["first"].EndedAt).ToNot(BeNil())
+				Expect(taskRuns["second"].Status).To(Equal(model.TaskStatusSkipped))
+				Expect(taskRuns["third"].Status).To(Equal(model.TaskStatusSkipped))
+			})
+		})
+
+		When("a task has a retry policy", func() {
+			var exitCode int
+
+			BeforeEach(func() {
+				exitCode = 1
+				msg.ProcessDefinition.Tasks = []model.Task{
+					{
+						Name:  "flaky",
+						Class: "someCmd",
+						Retry: &model.RetryPolicy{
+							Attempts:           3,
+							Backoff:            model.BackoffExponential,
+							InitialDelay:       model.Duration(time.Millisecond),
+							RetryableExitCodes: []int{1},
+						},
+					},
+				}
+
+				executor.RunReturnsOnCall(0, model.TaskOutput{ExitCode: &exitCode}, ErrExecutor)
+				executor.RunReturnsOnCall(1, model.TaskOutput{}, nil)
+			})
+
+			It("retries the failed task", func() {
+				Expect(errAction).ToNot(HaveOccurred())
+				Expect(executor.RunCallCount()).To(Equal(2))
+			})
+
+			It("records every attempt", func() {
+				Expect(processStore.InsertTaskRunCallCount()).To(Equal(2))
+				_, _, retried := processStore.InsertTaskRunArgsForCall(1)
+				Expect(retried.Attempt).To(Equal(2))
+
+				var outcomes []model.TaskStatus
+				for i := 0; i < processStore.UpdateTaskRunCallCount(); i++ {
+					_, _, taskRun := processStore.UpdateTaskRunArgsForCall(i)
+					if taskRun.EndedAt != nil {
+						outcomes = append(outcomes, taskRun.Status)
+					}
+				}
+				Expect(outcomes).To(Equal([]model.TaskStatus{model.TaskStatusFailed, model.TaskStatusSucceeded}))
+			})
+
+			When("the exit code is not retryable", func() {
+				BeforeEach(func() {
+					exitCode = 2
+				})
+
+				It("fails without retrying", func() {
+					Expect(errAction).To(MatchError(ErrExecutor))
+					Expect(executor.RunCallCount()).To(Equal(1))
+				})
+			})
+
+			When("all attempts fail", func() {
+				BeforeEach(func() {
+					executor.RunReturnsOnCall(1, model.TaskOutput{ExitCode: &exitCode}, ErrExecutor)
+					executor.RunReturnsOnCall(2, model.TaskOutput{ExitCode: &exitCode}, ErrExecutor)
+				})
+
+				It("fails after the last attempt", func() {
+					Expect(errAction).To(MatchError(ErrExecutor))
+					Expect(executor.RunCallCount()).To(Equal(3))
+				})
+			})
+		})
+
+		When("a task exceeds its timeout", func() {
+			BeforeEach(func() {
+				msg.ProcessDefinition.Tasks = []model.Task{
+					{Name: "slow", Class: "someCmd", Timeout: model.Duration(10 * time.Millisecond)},
+					{Name: "after", Class: "someCmd", WaitFor: []string{"slow"}},
+				}
+
+				executor.RunStub = func(ctx context.Context, _ model.Task, _, _ io.Writer) (model.TaskOutput, error) {
+					<-ctx.Done()
+					return model.TaskOutput{}, ctx.Err()
+				}
+			})
+
+			It("returns an error", func() {
+				Expect(errAction).To(MatchError(ContainSubstring("task timed out after 10ms")))
+				Expect(executor.RunCallCount()).To(Equal(1))
+			})
+
+			It("records the task as timed out", func() {
+				taskRuns := map[string]model.TaskRun{}
+				for i := 0; i < processStore.UpdateTaskRunCallCount(); i++ {
+					_, _, taskRun := processStore.UpdateTaskRunArgsForCall(i)
+					taskRuns[taskRun.Name] = taskRun
+				}
+
+				Expect(taskRuns["slow"].Status).To(Equal(model.TaskStatusTimedOut))
+				Expect(taskRuns["after"].Status).To(Equal(model.TaskStatusSkipped))
+				Expect(taskRuns["after"].Error).To(Equal("dependency 'slow' timed_out"))
+			})
+
+			It("records the process as failed", func() {
+				_, _, _, status := processStore.UpdateProcessStatusArgsForCall(0)
+				Expect(status).To(Equal(model.StatusFailed))
+			})
+		})
+
+		When("the process exceeds its timeout", func() {
+			BeforeEach(func() {
+				msg.ProcessDefinition.Timeout = model.Duration(10 * time.Millisecond)
+				msg.ProcessDefinition.Tasks = []model.Task{
+					{Name: "slow", Class: "someCmd"},
+				}
+
+				executor.RunStub = func(ctx context.Context, _ model.Task, _, _ io.Writer) (model.TaskOutput, error) {
+					<-ctx.Done()
+					return model.TaskOutput{}, ctx.Err()
+				}
+			})
+
+			It("returns an error", func() {
+				Expect(errAction).To(MatchError(ContainSubstring("process timed out after 10ms")))
+			})
+
+			It("records the process as timed out", func() {
+				Expect(processStore.UpdateProcessStatusCallCount()).To(Equal(1))
+				_, _, _, status := processStore.UpdateProcessStatusArgsForCall(0)
+				Expect(status).To(Equal(model.StatusTimedOut))
+			})
+
+			It("cancels the running task", func() {
+				_, _, taskRun := processStore.UpdateTaskRunArgsForCall(processStore.UpdateTaskRunCallCount() - 1)
+				Expect(taskRun.Status).To(Equal(model.TaskStatusCancelled))
+			})
+		})
+
+		When("a task uses the output of an upstream task", func() {
+			var deployed model.Task
+
+			BeforeEach(func() {
+				msg.ProcessDefinition.Tasks = []model.Task{
+					{
+						Name:    "build",
+						Class:   "someCmd",
+						Outputs: []model.OutputCapture{{Name: "buildId", Regex: `BUILD_ID=(\w+)`}},
+					},
+					{
+						Name:       "deploy",
+						Class:      "someCmd",
+						WaitFor:    []string{"build"},
+						Parameters: map[string]string{"command": "deploy " + taskoutput.Deferred("{{.tasks.build.outputs.buildId}}")},
+					},
+				}
+
+				executor.RunStub = func(_ context.Context, task model.Task, _, _ io.Writer) (model.TaskOutput, error) {
+					if task.Name == "build" {
+						return model.TaskOutput{Stdout: "compiling\nBUILD_ID=b42\n"}, nil
+					}
+					deployed = task
+					return model.TaskOutput{}, nil
+				}
+			})
+
+			It("renders the output into the downstream task", func() {
+				Expect(errAction).ToNot(HaveOccurred())
+				Expect(deployed.Parameters["command"]).To(Equal("deploy b42"))
+			})
+
+			It("persists the captured outputs", func() {
+				var outputs map[string]string
+				for i := 0; i < processStore.UpdateTaskRunCallCount(); i++ {
+					_, _, taskRun := processStore.UpdateTaskRunArgsForCall(i)
+					if taskRun.Name == "build" && taskRun.Status == model.TaskStatusSucceeded {
+						outputs = taskRun.Outputs
+					}
+				}
+				Expect(outputs).To(Equal(map[string]string{"buildId": "b42"}))
+			})
+
+			When("the output cannot be captured", func() {
+				BeforeEach(func() {
+					executor.RunReturns(model.TaskOutput{Stdout: "no id"}, nil)
+					executor.RunStub = nil
+				})
+
+				It("fails the task", func() {
+					Expect(errAction).To(MatchError(ContainSubstring("output 'buildId'")))
+					Expect(executor.RunCallCount()).To(Equal(1))
+				})
+			})
+		})
+
+		When("tasks have conditions", func() {
+			var executed []string
+
+			BeforeEach(func() {
+				executed = nil
+				msg.ProcessDefinition.Tasks = []model.Task{
+					{Name: "deploy", Class: "someCmd"},
+					{Name: "rollback", Class: "someCmd", WaitFor: []string{"deploy"}, When: "failure()"},
+					{Name: "notify", Class: "someCmd", WaitFor: []string{"deploy"}},
+					{Name: "copy", Class: "someCmd", When: `dev == "prod"`},
+				}
+
+				executor.RunStub = func(_ context.Context, task model.Task, _, _ io.Writer) (model.TaskOutput, error) {
+					executed = append(executed, task.Name)
+					if task.Name == "deploy" {
+						return model.TaskOutput{}, ErrExecutor
+					}
+					return model.TaskOutput{}, nil
+				}
+			})
+
+			It("runs the tasks whose condition holds", func() {
+				Expect(errAction).To(MatchError(ErrExecutor))
+				Expect(executed).To(Equal([]string{"deploy", "rollback"}))
+			})
+
+			It("records the other tasks as skipped", func() {
+				taskRuns := map[string]model.TaskRun{}
+				for i := 0; i < processStore.UpdateTaskRunCallCount(); i++ {
+					_, _, taskRun := processStore.UpdateTaskRunArgsForCall(i)
+					taskRuns[taskRun.Name] = taskRun
+				}
+
+				Expect(taskRuns["rollback"].Status).To(Equal(model.TaskStatusSucceeded))
+				Expect(taskRuns["notify"].Status).To(Equal(model.TaskStatusSkipped))
+				Expect(taskRuns["copy"].Status).To(Equal(model.TaskStatusSkipped))
+				Expect(taskRuns["copy"].Error).To(Equal(`condition 'dev == "prod"' not met`))
+			})
+		})
+
+		When("a failing task continues on error", func() {
+			var executed []string
+
+			BeforeEach(func() {
+				executed = nil
+				msg.ProcessDefinition.Tasks = []model.Task{
+					{Name: "lint", Class: "someCmd", ContinueOnError: true},
+					{Name: "build", Class: "someCmd", WaitFor: []string{"lint"}},
+					{Name: "report", Class: "someCmd", WaitFor: []string{"lint"}, When: "success()"},
+				}
+
+				executor.RunStub = func(_ context.Context, task model.Task, _, _ io.Writer) (model.TaskOutput, error) {
+					executed = append(executed, task.Name)
+					if task.Name == "lint" {
+						return model.TaskOutput{}, ErrExecutor
+					}
+					return model.TaskOutput{}, nil
+				}
+			})
+
+			It("runs its dependents", func() {
+				Expect(errAction).ToNot(HaveOccurred())
+				Expect(executed).To(ConsistOf("lint", "build", "report"))
+			})
+
+			It("records the task as failed", func() {
+				taskRuns := map[string]model.TaskRun{}
+				for i := 0; i < processStore.UpdateTaskRunCallCount(); i++ {
+					_, _, taskRun := processStore.UpdateTaskRunArgsForCall(i)
+					taskRuns[taskRun.Name] = taskRun
+				}
+				Expect(taskRuns["lint"].Status).To(Equal(model.TaskStatusFailed))
+			})
+
+			It("records the process as completed with errors", func() {
+				Expect(processStore.UpdateProcessStatusCallCount()).To(Equal(1))
+				_, _, _, status := processStore.UpdateProcessStatusArgsForCall(0)
+				Expect(status).To(Equal(model.StatusCompletedWithErrors))
+			})
+		})
+
+		When("tasks declare compensations", func() {
+			var executed []string
+
+			BeforeEach(func() {
+				executed = nil
+				msg.ProcessDefinition.Tasks = []model.Task{
+					{Name: "lock", Class: "someCmd"},
+					{Name: "stop", Class: "someCmd", WaitFor: []string{"lock"}, Compensate: &model.Task{Class: "someCmd"}},
+					{
+						Name:    "migrate",
+						Class:   "someCmd",
+						WaitFor: []string{"stop"},
+						Outputs: []model.OutputCapture{{Name: "version", LastLine: true}},
+						Compensate: &model.Task{
+							Name:       "downgrade",
+							Class:      "someCmd",
+							Parameters: map[string]string{"command": "downgrade " + taskoutput.Deferred("{{.tasks.migrate.outputs.version}}")},
+						},
+					},
+					{Name: "deploy", Class: "someCmd", WaitFor: []string{"migrate"}, Compensate: &model.Task{Class: "someCmd"}},
+				}
+				msg.ProcessDefinition.Finally = []model.Task{
+					{Name: "unlock", Class: "someCmd"},
+				}
+
+				executor.RunStub = func(_ context.Context, task model.Task, _, _ io.Writer) (model.TaskOutput, error) {
+					executed = append(executed, task.Name)
+					switch task.Name {
+					case "migrate":
+						return model.TaskOutput{Stdout: "v7\n"}, nil
+					case "downgrade":
+						Expect(task.Parameters["command"]).To(Equal("downgrade v7"))
+					case "deploy":
+						return model.TaskOutput{}, ErrExecutor
+					}
+					return model.TaskOutput{}, nil
+				}
+			})
+
+			It("undoes the succeeded tasks in reverse order before the finally tasks", func() {
+				Expect(errAction).To(MatchError(ErrExecutor))
+				Expect(executed).To(Equal([]string{"lock", "stop", "migrate", "deploy", "downgrade", "compensate-stop", "unlock"}))
+			})
+
+			It("records the compensations as separate task runs", func() {
+				var inserted []string
+				for i := 0; i < processStore.InsertTaskRunCallCount(); i++ {
+					_, _, taskRun := processStore.InsertTaskRunArgsForCall(i)
+					inserted = append(inserted, taskRun.Name)
+				}
+				Expect(inserted).To(ContainElements("downgrade", "compensate-stop"))
+
+				taskRuns := map[string]model.TaskRun{}
+				for i := 0; i < processStore.UpdateTaskRunCallCount(); i++ {
+					_, _, taskRun := processStore.UpdateTaskRunArgsForCall(i)
+					taskRuns[taskRun.Name] = taskRun
+				}
+				Expect(taskRuns["downgrade"].Status).To(Equal(model.TaskStatusSucceeded))
+				Expect(taskRuns["compensate-stop"].Status).To(Equal(model.TaskStatusSucceeded))
+				Expect(taskRuns["stop"].Status).To(Equal(model.TaskStatusSucceeded))
+			})
+
+			It("records the process as failed", func() {
+				_, _, _, status := processStore.UpdateProcessStatusArgsForCall(0)
+				Expect(status).To(Equal(model.StatusFailed))
+			})
+
+			When("a compensation fails", func() {
+				BeforeEach(func() {
+					executor.RunStub = func(_ context.Context, task model.Task, _, _ io.Writer) (model.TaskOutput, error) {
+						executed = append(executed, task.Name)
+						if task.Name == "deploy" || task.Name == "downgrade" {
+							return model.TaskOutput{}, ErrExecutor
+						}
+						return model.TaskOutput{}, nil
+					}
+				})
+
+				It("still runs the remaining ones", func() {
+					Expect(executed).To(Equal([]string{"lock", "stop", "migrate", "deploy", "downgrade", "compensate-stop", "unlock"}))
+				})
+			})
+
+			When("the run succeeds", func() {
+				BeforeEach(func() {
+					executor.RunReturns(model.TaskOutput{Stdout: "v7"}, nil)
+					executor.RunStub = nil
+				})
+
+				It("does not compensate anything", func() {
+					Expect(errAction).ToNot(HaveOccurred())
+					Expect(executor.RunCallCount()).To(Equal(5))
+				})
+			})
+		})
+
+		When("the process has finally tasks", func() {
+			var (
+				mu       sync.Mutex
+				executed []string
+			)
+
+			// Finally tasks without dependencies between them run concurrently.
+			record := func(name string) {
+				mu.Lock()
+				defer mu.Unlock()
+				executed = append(executed, name)
+			}
+
+			BeforeEach(func() {
+				executed = nil
+				msg.ProcessDefinition.Tasks = []model.Task{
+					{Name: "deploy", Class: "someCmd"},
+					{Name: "verify", Class: "someCmd", WaitFor: []string{"deploy"}},
+				}
+				msg.ProcessDefinition.Finally = []model.Task{
+					{Name: "unlock", Class: "someCmd"},
+					{Name: "rollback", Class: "someCmd", When: "status('deploy') != 'succeeded'"},
+					{Name: "cleanup", Class: "someCmd", WaitFor: []string{"unlock"}},
+				}
+
+				executor.RunStub = func(_ context.Context, task model.Task, _, _ io.Writer) (model.TaskOutput, error) {
+					record(task.Name)
+					return model.TaskOutput{}, nil
+				}
+			})
+
+			It("runs them after the other tasks", func() {
+				Expect(errAction).ToNot(HaveOccurred())
+				Expect(executed).To(HaveLen(4))
+				Expect(executed[:2]).To(Equal([]string{"deploy", "verify"}))
+				Expect(executed[2:]).To(Equal([]string{"unlock", "cleanup"}))
+			})
+
+			It("records a pending task run for them", func() {
+				var names []string
+				for i := 0; i < processStore.InsertTaskRunCallCount(); i++ {
+					_, _, taskRun := processStore.InsertTaskRunArgsForCall(i)
+					names = append(names, taskRun.Name)
+				}
+				Expect(names).To(Equal([]string{"deploy", "verify", "unlock", "rollback", "cleanup"}))
+			})
+
+			It("records the process as completed", func() {
+				_, _, _, status := processStore.UpdateProcessStatusArgsForCall(0)
+				Expect(status).To(Equal(model.StatusCompleted))
+			})
+
+			When("a task fails", func() {
+				BeforeEach(func() {
+					executor.RunStub = func(_ context.Context, task model.Task, _, _ io.Writer) (model.TaskOutput, error) {
+						record(task.Name)
+						if task.Name == "deploy" {
+							return model.TaskOutput{}, ErrExecutor
+						}
+						return model.TaskOutput{}, nil
+					}
+				})
+
+				It("still runs them", func() {
+					Expect(errAction).To(MatchError(ErrExecutor))
+					Expect(executed).To(ConsistOf("deploy", "unlock", "rollback", "cleanup"))
+				})
+
+				It("records the process as failed", func() {
+					_, _, _, status := processStore.UpdateProcessStatusArgsForCall(0)
+					Expect(status).To(Equal(model.StatusFailed))
+				})
+			})
+
+			When("a finally task fails", func() {
+				BeforeEach(func() {
+					executor.RunStub = func(_ context.Context, task model.Task, _, _ io.Writer) (model.TaskOutput, error) {
+						if task.Name == "unlock" {
+							return model.TaskOutput{}, ErrExecutor
+						}
+						return model.TaskOutput{}, nil
+					}
+				})
+
+				It("records the process as completed with errors", func() {
+					Expect(errAction).ToNot(HaveOccurred())
+					_, _, _, status := processStore.UpdateProcessStatusArgsForCall(0)
+					Expect(status).To(Equal(model.StatusCompletedWithErrors))
+				})
+			})
+
+			When("a stop is requested while a task runs", func() {
+				BeforeEach(func() {
+					executor.RunStub = func(ctx context.Context, task model.Task, _, _ io.Writer) (model.TaskOutput, error) {
+						record(task.Name)
+						if task.Name == "deploy" {
+							_, run := processStore.InsertProcessArgsForCall(0)
+							Expect(runRegistry.Cancel(run.ID)).To(BeTrue())
+							<-ctx.Done()
+							return model.TaskOutput{}, ctx.Err()
+						}
+						return model.TaskOutput{}, ctx.Err()
+					}
+				})
+
+				It("still runs them to completion", func() {
+					Expect(errAction).ToNot(HaveOccurred())
+					Expect(executed).To(HaveLen(4))
+					Expect(executed[0]).To(Equal("deploy"))
+					Expect(executed[1:]).To(ConsistOf("unlock", "rollback", "cleanup"))
+				})
+
+				It("records the process as stopped", func() {
+					_, _, _, status := processStore.UpdateProcessStatusArgsForCall(0)
+					Expect(status).To(Equal(model.StatusStopped))
+				})
+			})
+		})
+
+		When("the process limits its parallelism", func() {
+			var (
+				mu          sync.Mutex
+				running     int
+				maxObserved int
+			)
+
+			BeforeEach(func() {
+				running, maxObserved = 0, 0
+				msg.ProcessDefinition.MaxParallel = 2
+				for _, name := range []string{"a", "b", "c", "d", "e"} {
+					msg.ProcessDefinition.Tasks = append(msg.ProcessDefinition.Tasks, model.Task{Name: name, Class: "someCmd"})
+				}
+
+				executor.RunStub = func(context.Context, model.Task, io.Writer, io.Writer) (model.TaskOutput, error) {
+					mu.Lock()
+					running++
+					maxObserved = max(maxObserved, running)
+					mu.Unlock()
+
+					time.Sleep(5 * time.Millisecond)
+
+					mu.Lock()
+					running--
+					mu.Unlock()
+					return model.TaskOutput{}, nil
+				}
+			})
+
+			It("never runs more tasks at once", func() {
+				Expect(errAction).ToNot(HaveOccurred())
+				Expect(executor.RunCallCount()).To(Equal(5))
+				Expect(maxObserved).To(Equal(2))
+			})
+		})
+
+		When("the message retries a failed run", func() {
+			var (
+				runID    uuid.UUID
+				executed []model.Task
+			)
+
+			BeforeEach(func() {
+				executed = nil
+				runID = uuid.New()
+				msg.RetryOf = &runID
+				msg.ProcessDefinition.Tasks = []model.Task{
+					{Name: "build", Class: "someCmd", Outputs: []model.OutputCapture{{Name: "id", LastLine: true}}},
+					{
+						Name:       "deploy",
+						Class:      "someCmd",
+						WaitFor:    []string{"build"},
+						Parameters: map[string]string{"command": "deploy " + taskoutput.Deferred("{{.tasks.build.outputs.id}}")},
+					},
+					{Name: "verify", Class: "someCmd", WaitFor: []string{"deploy"}},
+				}
+
+				processStore.BeginRetryReturns(2, nil)
+				processStore.GetTaskRunsReturns([]model.TaskRun{
+					{Name: "build", Status: model.TaskStatusSucceeded, Attempt: 1, Outputs: map[string]string{"id": "b42"}},
+					{Name: "deploy", Status: model.TaskStatusFailed, Attempt: 1},
+					{Name: "deploy", Status: model.TaskStatusFailed, Attempt: 2},
+					{Name: "verify", Status: model.TaskStatusSkipped, Attempt: 1},
+				}, nil)
+
+				executor.RunStub = func(_ context.Context, task model.Task, _, _ io.Writer) (model.TaskOutput, error) {
+					executed = append(executed, task)
+					return model.TaskOutput{}, nil
+				}
+			})
+
+			It("resumes the same run", func() {
+				Expect(errAction).ToNot(HaveOccurred())
+				Expect(processStore.InsertProcessCallCount()).To(Equal(0))
+				_, id := processStore.BeginRetryArgsForCall(0)
+				Expect(id).To(Equal(runID))
+
+				_, updatedID, attempt, status := processStore.UpdateProcessStatusArgsForCall(0)
+				Expect(updatedID).To(Equal(runID))
+				Expect(attempt).To(Equal(2))
+				Expect(status).To(Equal(model.StatusCompleted))
+			})
+
+			It("records the task runs in the new attempt of the run", func() {
+				for i := 0; i < processStore.InsertTaskRunCallCount(); i++ {
+					_, attempt, _ := processStore.InsertTaskRunArgsForCall(i)
+					Expect(attempt).To(Equal(2))
+				}
+				for i := 0; i < processStore.UpdateTaskRunCallCount(); i++ {
+					_, attempt, _ := processStore.UpdateTaskRunArgsForCall(i)
+					Expect(attempt).To(Equal(2))
+				}
+			})
+
+			When("the attempt was abandoned meanwhile", func() {
+				BeforeEach(func() {
+					processStore.UpdateProcessStatusReturns(model.ErrOwnershipLost)
+				})
+
+				It("leaves the message to the consumer that took over the run", func() {
+					Expect(errAction).ToNot(HaveOccurred())
+					Expect(store.CompleteMessageCallCount()).To(Equal(0))
+					Expect(store.ReleaseMessageCallCount()).To(Equal(0))
+				})
+			})
+
+			It("only re-executes the tasks that did not succeed", func() {
+				Expect(executed).To(HaveLen(2))
+				Expect(executed[0].Name).To(Equal("deploy"))
+				Expect(executed[0].Parameters["command"]).To(Equal("deploy b42"))
+				Expect(executed[1].Name).To(Equal("verify"))
+			})
+
+			It("records the next attempt of every re-executed task", func() {
+				attempts := map[string]int{}
+				for i := 0; i < processStore.InsertTaskRunCallCount(); i++ {
+					_, _, taskRun := processStore.InsertTaskRunArgsForCall(i)
+					attempts[taskRun.Name] = taskRun.Attempt
+				}
+				Expect(attempts).To(Equal(map[string]int{"deploy": 3, "verify": 2}))
+			})
+
+			When("a succeeded task was compensated", func() {
+				BeforeEach(func() {
+					msg.ProcessDefinition.Tasks[0].Compensate = &model.Task{Class: "someCmd"}
+					processStore.GetTaskRunsReturns([]model.TaskRun{
+						{ID: 1, Name: "build", Status: model.TaskStatusSucceeded, Attempt: 1, Outputs: map[string]string{"id": "b42"}},
+						{ID: 2, Name: "deploy", Status: model.TaskStatusFailed, Attempt: 1},
+						{ID: 3, Name: "verify", Status: model.TaskStatusSkipped, Attempt: 1},
+						{ID: 4, Name: "compensate-build", Status: model.TaskStatusSucceeded, Attempt: 1},
+					}, nil)
+				})
+
+				It("re-executes it", func() {
+					Expect(errAction).ToNot(HaveOccurred())
+					Expect(executed).To(HaveLen(3))
+					Expect(executed[0].Name).To(Equal("build"))
+				})
+			})
+
+			When("the run is not retryable anymore", func() {
+				BeforeEach(func() {
+					processStore.BeginRetryReturns(0, model.ErrProcessNotRetryable)
+				})
+
+				It("skips the message", func() {
+					Expect(errAction).ToNot(HaveOccurred())
+					Expect(executor.RunCallCount()).To(Equal(0))
+					Expect(store.CompleteMessageCallCount()).To(Equal(1))
+				})
+			})
+		})
+
+		When("a task waits for approval", func() {
+			var executed []string
+
+			BeforeEach(func() {
+				executed = nil
+				msg.ProcessDefinition.Tasks = []model.Task{
+					{Name: "build", Class: "someCmd"},
+					{Name: "approve", Class: "someCmd", WaitFor: []string{"build"}, Timeout: model.Duration(time.Hour)},
+					{Name: "deploy", Class: "someCmd", WaitFor: []string{"approve"}},
+					{Name: "lint", Class: "someCmd"},
+				}
+				msg.ProcessDefinition.Finally = []model.Task{{Name: "cleanup", Class: "someCmd"}}
+
+				executor.RunStub = func(_ context.Context, task model.Task, _, _ io.Writer) (model.TaskOutput, error) {
+					executed = append(executed, task.Name)
+					if task.Name == "approve" {
+						return model.TaskOutput{}, model.ErrAwaitingApproval
+					}
+					return model.TaskOutput{}, nil
+				}
+			})
+
+			It("parks the run once nothing else can run", func() {
+				Expect(errAction).ToNot(HaveOccurred())
+				Expect(executed).To(ConsistOf("build", "approve", "lint"))
+
+				Expect(processStore.UpdateProcessStatusCallCount()).To(Equal(1))
+				_, _, _, status := processStore.UpdateProcessStatusArgsForCall(0)
+				Expect(status).To(Equal(model.StatusAwaitingApproval))
+				Expect(store.CompleteMessageCallCount()).To(Equal(1))
+			})
+
+			It("records the task as awaiting approval until its timeout", func() {
+				taskRuns := map[string]model.TaskRun{}
+				for i := 0; i < processStore.UpdateTaskRunCallCount(); i++ {
+					_, _, taskRun := processStore.UpdateTaskRunArgsForCall(i)
+					taskRuns[taskRun.Name] = taskRun
+				}
+				Expect(taskRuns["approve"].Status).To(Equal(model.TaskStatusAwaitingApproval))
+				Expect(taskRuns["approve"].EndedAt).To(BeNil())
+				Expect(taskRuns["approve"].ApprovalExpiresAt).To(HaveValue(BeTemporally("~", taskRuns["approve"].StartedAt.Add(time.Hour))))
+				Expect(taskRuns).NotTo(HaveKey("deploy"))
+				Expect(taskRuns).NotTo(HaveKey("cleanup"))
+			})
+
+			When("another task fails", func() {
+				BeforeEach(func() {
+					executor.RunStub = func(_ context.Context, task model.Task, _, _ io.Writer) (model.TaskOutput, error) {
+						switch task.Name {
+						case "approve":
+							return model.TaskOutput{}, model.ErrAwaitingApproval
+						case "lint":
+							return model.TaskOutput{}, ErrExecutor
+						}
+						return model.TaskOutput{}, nil
+					}
+				})
+
+				It("fails the run and cancels the approval", func() {
+					Expect(errAction).To(MatchError(ErrExecutor))
+
+					taskRuns := map[string]model.TaskRun{}
+					for i := 0; i < processStore.UpdateTaskRunCallCount(); i++ {
+						_, _, taskRun := processStore.UpdateTaskRunArgsForCall(i)
+						taskRuns[taskRun.Name] = taskRun
+					}
+					Expect(taskRuns["approve"].Status).To(Equal(model.TaskStatusCancelled))
+					Expect(taskRuns["cleanup"].Status).To(Equal(model.TaskStatusSucceeded))
+
+					_, _, _, status := processStore.UpdateProcessStatusArgsForCall(0)
+					Expect(status).To(Equal(model.StatusFailed))
+				})
+			})
+		})
+
+		When("a pause is requested while a task runs", func() {
+			var executed []string
+
+			BeforeEach(func() {
+				executed = nil
+				msg.ProcessDefinition.Tasks = []model.Task{
+					{Name: "build", Class: "someCmd"},
+					{Name: "deploy", Class: "someCmd", WaitFor: []string{"build"}},
+				}
+				msg.ProcessDefinition.Finally = []model.Task{{Name: "cleanup", Class: "someCmd"}}
+
+				executor.RunStub = func(ctx context.Context, task model.Task, _, _ io.Writer) (model.TaskOutput, error) {
+					executed = append(executed, task.Name)
+					_, run := processStore.InsertProcessArgsForCall(0)
+					Expect(runRegistry.Pause(run.ID)).To(BeTrue())
+					return model.TaskOutput{}, ctx.Err()
+				}
+			})
+
+			It("lets the task finish but starts no new ones", func() {
+				Expect(errAction).ToNot(HaveOccurred())
+				Expect(executed).To(Equal([]string{"build"}))
+
+				taskRuns := map[string]model.TaskRun{}
+				for i := 0; i < processStore.UpdateTaskRunCallCount(); i++ {
+					_, _, taskRun := processStore.UpdateTaskRunArgsForCall(i)
+					taskRuns[taskRun.Name] = taskRun
+				}
+				Expect(taskRuns["build"].Status).To(Equal(model.TaskStatusSucceeded))
+				Expect(taskRuns).NotTo(HaveKey("deploy"))
+				Expect(taskRuns).NotTo(HaveKey("cleanup"))
+			})
+
+			It("records the process as paused", func() {
+				Expect(processStore.UpdateProcessStatusCallCount()).To(Equal(1))
+				_, _, _, status := processStore.UpdateProcessStatusArgsForCall(0)
+				Expect(status).To(Equal(model.StatusPaused))
+				Expect(store.CompleteMessageCallCount()).To(Equal(1))
+			})
+		})
+
+		When("the message continues a parked run", func() {
+			var (
+				runID    uuid.UUID
+				executed []string
+			)
+
+			BeforeEach(func() {
+				executed = nil
+				runID = uuid.New()
+				msg.ContinueOf = &runID
+				processStore.BeginContinueReturns(3, nil)
+				msg.ProcessDefinition.Tasks = []model.Task{
+					{Name: "build", Class: "someCmd"},
+					{Name: "approve", Class: "someCmd", WaitFor: []string{"build"}},
+					{Name: "deploy", Class: "someCmd", WaitFor: []string{"approve"}},
+				}
+				msg.ProcessDefinition.Finally = []model.Task{{Name: "cleanup", Class: "someCmd"}}
+
+				processStore.GetTaskRunsReturns([]model.TaskRun{
+					{Name: "build", Status: model.TaskStatusSucceeded, Attempt: 2},
+					{Name: "approve", Status: model.TaskStatusSucceeded, Attempt: 2, DecidedBy: "alice"},
+					{Name: "deploy", Status: model.TaskStatusFailed, Attempt: 1},
+					{Name: "deploy", Status: model.TaskStatusPending, Attempt: 2},
+					{Name: "cleanup", Status: model.TaskStatusPending, Attempt: 2},
+				}, nil)
+
+				executor.RunStub = func(_ context.Context, task model.Task, _, _ io.Writer) (model.TaskOutput, error) {
+					executed = append(executed, task.Name)
+					return model.TaskOutput{}, nil
+				}
+			})
+
+			It("continues the same attempt of the run", func() {
+				Expect(errAction).ToNot(HaveOccurred())
+				Expect(processStore.InsertProcessCallCount()).To(Equal(0))
+				Expect(processStore.BeginRetryCallCount()).To(Equal(0))
+				_, id := processStore.BeginContinueArgsForCall(0)
+				Expect(id).To(Equal(runID))
+
+				Expect(processStore.InsertTaskRunCallCount()).To(Equal(0))
+				_, attempt, taskRun := processStore.UpdateTaskRunArgsForCall(0)
+				Expect(attempt).To(Equal(3))
+				Expect(taskRun.Name).To(Equal("deploy"))
+				Expect(taskRun.Attempt).To(Equal(2))
+
+				_, _, attempt, _ = processStore.UpdateProcessStatusArgsForCall(0)
+				Expect(attempt).To(Equal(3))
+			})
+
+			It("executes the pending tasks", func() {
+				Expect(executed).To(Equal([]string{"deploy", "cleanup"}))
+
+				_, _, _, status := processStore.UpdateProcessStatusArgsForCall(0)
+				Expect(status).To(Equal(model.StatusCompleted))
+			})
+
+			When("the approval was rejected", func() {
+				BeforeEach(func() {
+					processStore.GetTaskRunsReturns([]model.TaskRun{
+						{Name: "build", Status: model.TaskStatusSucceeded, Attempt: 1},
+						{Name: "approve", Status: model.TaskStatusFailed, Attempt: 1, Error: "rejected by alice"},
+						{Name: "deploy", Status: model.TaskStatusPending, Attempt: 1},
+						{Name: "cleanup", Status: model.TaskStatusPending, Attempt: 1},
+					}, nil)
+				})
+
+				It("fails the run after its finally tasks", func() {
+					Expect(errAction).To(MatchError(ContainSubstring("task 'approve' failed: rejected by alice")))
+					Expect(executed).To(Equal([]string{"cleanup"}))
+
+					_, _, taskRun := processStore.UpdateTaskRunArgsForCall(0)
+					Expect(taskRun.Name).To(Equal("deploy"))
+					Expect(taskRun.Status).To(Equal(model.TaskStatusSkipped))
+
+					_, _, _, status := processStore.UpdateProcessStatusArgsForCall(0)
+					Expect(status).To(Equal(model.StatusFailed))
+				})
+			})
+
+			When("the run is not parked anymore", func() {
+				BeforeEach(func() {
+					processStore.BeginContinueReturns(0, model.ErrProcessNotParked)
+				})
+
+				It("skips the message", func() {
+					Expect(errAction).ToNot(HaveOccurred())
+					Expect(executor.RunCallCount()).To(Equal(0))
+					Expect(store.CompleteMessageCallCount()).To(Equal(1))
+				})
+			})
+		})
+
+		When("a task rolls out in batches", func() {
+			var (
+				mu       sync.Mutex
+				executed []string
+				done     map[string]time.Time
+				started  map[string]time.Time
+				failing  map[string]bool
+			)
+
+			instance := func(i int, host string) model.Task {
+				return model.Task{
+					Name:       fmt.Sprintf("upgrade_%d", i),
+					Class:      "someCmd",
+					Parameters: map[string]string{"host": host},
+					Rolling:    &model.RollingPolicy{BatchSize: 2, MaxFailures: 1},
+					Group:      "upgrade",
+					Item:       host,
+				}
+			}
+			logs := func() []string {
+				var messages []string
+				for i := 0; i < processStore.AppendProcessLogCallCount(); i++ {
+					_, _, message := processStore.AppendProcessLogArgsForCall(i)
+					messages = append(messages, message)
+				}
+				return messages
+			}
+			taskRuns := func() map[string]model.TaskRun {
+				runs := map[string]model.TaskRun{}
+				for i := 0; i < processStore.UpdateTaskRunCallCount(); i++ {
+					_, _, taskRun := processStore.UpdateTaskRunArgsForCall(i)
+					runs[taskRun.Name] = taskRun
+				}
+				return runs
+			}
+
+			BeforeEach(func() {
+				executed = nil
+				done = map[string]time.Time{}
+				started = map[string]time.Time{}
+				failing = map[string]bool{}
+				for i, host := range []string{"web1", "web2", "web3", "web4", "web5"} {
+					msg.ProcessDefinition.Tasks = append(msg.ProcessDefinition.Tasks, instance(i, host))
+				}
+				msg.ProcessDefinition.Tasks = append(msg.ProcessDefinition.Tasks, model.Task{
+					Name:    "notify",
+					Class:   "someCmd",
+					WaitFor: []string{"upgrade_0", "upgrade_1", "upgrade_2", "upgrade_3", "upgrade_4"},
+				})
+
+				executor.RunStub = func(_ context.Context, task model.Task, _, _ io.Writer) (model.TaskOutput, error) {
+					mu.Lock()
+					executed = append(executed, task.Name)
+					started[task.Name] = time.Now()
+					mu.Unlock()
+
+					time.Sleep(5 * time.Millisecond)
+
+					mu.Lock()
+					defer mu.Unlock()
+					done[task.Name] = time.Now()
+					if failing[task.Name] {
+						return model.TaskOutput{}, ErrExecutor
+					}
+					return model.TaskOutput{}, nil
+				}
+			})
+
+			It("starts a batch once the previous one is done", func() {
+				Expect(errAction).ToNot(HaveOccurred())
+				Expect(executed).To(HaveLen(6))
+				Expect(executed[:2]).To(ConsistOf("upgrade_0", "upgrade_1"))
+				Expect(executed[2:4]).To(ConsistOf("upgrade_2", "upgrade_3"))
+				Expect(executed[4:]).To(Equal([]string{"upgrade_4", "notify"}))
+
+				for _, previous := range []string{"upgrade_0", "upgrade_1"} {
+					Expect(started["upgrade_2"]).To(BeTemporally(">=", done[previous]))
+					Expect(started["upgrade_3"]).To(BeTemporally(">=", done[previous]))
+				}
+			})
+
+			It("logs the batches and a summary", func() {
+				Expect(logs()).To(ContainElements(
+					"Rolling task upgrade: starting batch 1/3: upgrade_0, upgrade_1",
+					"Rolling task upgrade: starting batch 2/3: upgrade_2, upgrade_3",
+					"Rolling task upgrade: starting batch 3/3: upgrade_4",
+					"Rolling task upgrade: 5 succeeded in 3 batches of 2",
+				))
+
+				_, _, _, status := processStore.UpdateProcessStatusArgsForCall(0)
+				Expect(status).To(Equal(model.StatusCompleted))
+			})
+
+			When("the failures stay within maxFailures", func() {
+				BeforeEach(func() {
+					failing["upgrade_1"] = true
+				})
+
+				It("rolls out to the remaining items", func() {
+					Expect(errAction).ToNot(HaveOccurred())
+					Expect(executed).To(HaveLen(6))
+					Expect(executed).To(ContainElement("notify"))
+					Expect(taskRuns()["upgrade_1"].Status).To(Equal(model.TaskStatusFailed))
+				})
+
+				It("records the process as completed with errors", func() {
+					Expect(logs()).To(ContainElement("Rolling task upgrade: 4 succeeded, 1 failed in 3 batches of 2"))
+
+					_, _, _, status := processStore.UpdateProcessStatusArgsForCall(0)
+					Expect(status).To(Equal(model.StatusCompletedWithErrors))
+				})
+			})
+
+			When("the failures exceed maxFailures", func() {
+				BeforeEach(func() {
+					failing["upgrade_0"] = true
+					failing["upgrade_1"] = true
+				})
+
+				It("cancels the remaining items", func() {
+					Expect(errAction).To(MatchError(ContainSubstring("rolling task 'upgrade' aborted after 2 failures, more than maxFailures 1")))
+					Expect(executed).To(ConsistOf("upgrade_0", "upgrade_1"))
+
+					runs := taskRuns()
+					for _, name := range []string{"upgrade_2", "upgrade_3", "upgrade_4"} {
+						Expect(runs[name].Status).To(Equal(model.TaskStatusCancelled))
+						Expect(runs[name].Error).To(Equal("rolling task 'upgrade' aborted after 2 failures, more than maxFailures 1"))
+					}
+					Expect(runs["notify"].Status).To(Equal(model.TaskStatusSkipped))
+				})
+
+				It("records the process as failed", func() {
+					Expect(logs()).To(ContainElements(
+						"Rolling task upgrade aborted: 2 instances failed, more than maxFailures 1",
+						"Rolling task upgrade: 2 failed, 3 cancelled in 3 batches of 2 (aborted after 2 failures, maxFailures 1)",
+					))
+
+					_, _, _, status := processStore.UpdateProcessStatusArgsForCall(0)
+					Expect(status).To(Equal(model.StatusFailed))
+				})
+			})
+
+			When("the batches are paused between", func() {
+				BeforeEach(func() {
+					for i := range 5 {
+						msg.ProcessDefinition.Tasks[i].Rolling.PauseBetweenBatches = model.Duration(20 * time.Millisecond)
+					}
+				})
+
+				It("waits before starting the next batch", func() {
+					Expect(errAction).ToNot(HaveOccurred())
+					Expect(started["upgrade_2"].Sub(done["upgrade_1"])).To(BeNumerically(">=", 20*time.Millisecond))
+					Expect(started["upgrade_4"].Sub(done["upgrade_3"])).To(BeNumerically(">=", 20*time.Millisecond))
+					Expect(logs()).To(ContainElement("Rolling task upgrade: batch 1/3 done, pausing for 20ms"))
+				})
+
+				When("a stop is requested during the pause", func() {
+					BeforeEach(func() {
+						msg.ProcessDefinition.Tasks[0].Rolling = &model.RollingPolicy{BatchSize: 2, PauseBetweenBatches: model.Duration(time.Hour)}
+						for i := range 5 {
+							msg.ProcessDefinition.Tasks[i].Rolling = msg.ProcessDefinition.Tasks[0].Rolling
+						}
+						go func() {
+							defer GinkgoRecover()
+							Eventually(func() bool {
+								mu.Lock()
+								defer mu.Unlock()
+								return len(done) == 2
+							}).Should(BeTrue())
+							_, run := processStore.InsertProcessArgsForCall(0)
+							Expect(runRegistry.Cancel(run.ID)).To(BeTrue())
+						}()
+					})
+
+					It("does not start the next batch", func() {
+						Expect(errAction).ToNot(HaveOccurred())
+						Expect(executed).To(ConsistOf("upgrade_0", "upgrade_1"))
+
+						_, _, _, status := processStore.UpdateProcessStatusArgsForCall(0)
+						Expect(status).To(Equal(model.StatusStopped))
+					})
+				})
+			})
+		})
+
+		When("a task runs a sub-process", func() {
+			var (
+				mu       sync.Mutex
+				statuses map[uuid.UUID]model.ProcessStatus
+				taskRuns map[uuid.UUID]map[string]model.TaskRun
+				params   map[string]map[string]string
+			)
+
+			// childRun returns the run the processCmd task started.
+			childRun := func() model.ProcessRun {
+				Expect(processStore.InsertProcessCallCount()).To(Equal(2))
+				_, run := processStore.InsertProcessArgsForCall(1)
+				return run
+			}
+
+			BeforeEach(func() {
+				statuses = map[uuid.UUID]model.ProcessStatus{}
+				taskRuns = map[uuid.UUID]map[string]model.TaskRun{}
+				params = map[string]map[string]string{}
+
+				msg.ProcessDefinition.Tasks = []model.Task{
+					{
+						Name:       "provision",
+						Class:      model.ProcessCmd,
+						Parameters: map[string]string{model.ProcessParam: "provision-vm", "size": "large"},
+						Outputs:    []model.OutputCapture{{Name: "vm", JSONPath: "$.tasks.create.outputs.id"}},
+						Subprocess: &model.ProcessDefinition{
+							Name: "provision-vm",
+							Tasks: []model.Task{{
+								Name:       "create",
+								Class:      "someCmd",
+								Parameters: map[string]string{"command": "create-vm --size {{.size}}"},
+								Outputs:    []model.OutputCapture{{Name: "id", LastLine: true}},
+							}},
+						},
+					},
+					{
+						Name:       "report",
+						Class:      "someCmd",
+						WaitFor:    []string{"provision"},
+						Parameters: map[string]string{"command": "echo " + taskoutput.Deferred("{{.tasks.provision.outputs.vm}}")},
+					},
+				}
+
+				processStore.UpdateProcessStatusStub = func(_ context.Context, id uuid.UUID, _ int, status model.ProcessStatus) error {
+					mu.Lock()
+					defer mu.Unlock()
+					statuses[id] = status
+					return nil
+				}
+				processStore.UpdateTaskRunStub = func(_ context.Context, _ int, taskRun model.TaskRun) error {
+					mu.Lock()
+					defer mu.Unlock()
+					if taskRuns[taskRun.ProcessID] == nil {
+						taskRuns[taskRun.ProcessID] = map[string]model.TaskRun{}
+					}
+					taskRuns[taskRun.ProcessID][taskRun.Name] = taskRun
+					return nil
+				}
+				processStore.GetProcessByIDStub = func(_ context.Context, id uuid.UUID) (model.ProcessRun, error) {
+					mu.Lock()
+					defer mu.Unlock()
+					return model.ProcessRun{ID: id, Status: statuses[id]}, nil
+				}
+				processStore.GetTaskRunsStub = func(_ context.Context, id uuid.UUID) ([]model.TaskRun, error) {
+					mu.Lock()
+					defer mu.Unlock()
+					var runs []model.TaskRun
+					for _, taskRun := range taskRuns[id] {
+						runs = append(runs, taskRun)
+					}
+					return runs, nil
+				}
+				executor.RunStub = func(_ context.Context, task model.Task, stdout, _ io.Writer) (model.TaskOutput, error) {
+					mu.Lock()
+					params[task.Name] = task.Parameters
+					mu.Unlock()
+					if task.Name == "create" {
+						_, _ = io.WriteString(stdout, "vm-42\n")
+						return model.TaskOutput{Stdout: "vm-42\n"}, nil
+					}
+					return model.TaskOutput{}, nil
+				}
+			})
+
+			It("runs the definition as a child run of the task", func() {
+				Expect(errAction).ToNot(HaveOccurred())
+
+				child := childRun()
+				Expect(child.ParentID).To(Equal(&msg.UUID))
+				Expect(child.ParentTask).To(Equal("provision"))
+				Expect(child.Status).To(Equal(model.StatusRunning))
+				Expect(statuses[child.ID]).To(Equal(model.StatusCompleted))
+				Expect(statuses[msg.UUID]).To(Equal(model.StatusCompleted))
+			})
+
+			It("maps the task parameters to the inputs of the definition", func() {
+				Expect(childRun().Definition.Tasks[0].Parameters).To(HaveKeyWithValue("command", "create-vm --size large"))
+				Expect(params["create"]).To(HaveKeyWithValue("command", "create-vm --size large"))
+			})
+
+			It("surfaces the status and outputs of the child run to the parent", func() {
+				provision := taskRuns[msg.UUID]["provision"]
+				Expect(provision.Status).To(Equal(model.TaskStatusSucceeded))
+				Expect(provision.Stdout).To(ContainSubstring(`"status":"completed"`))
+				Expect(provision.Stdout).To(ContainSubstring(`"run_id":"` + childRun().ID.String() + `"`))
+				Expect(provision.Outputs).To(HaveKeyWithValue("vm", "vm-42"))
+				Expect(params["report"]).To(HaveKeyWithValue("command", "echo vm-42"))
+			})
+
+			It("logs which run the task started", func() {
+				var logs []string
+				for i := 0; i < processStore.AppendProcessLogCallCount(); i++ {
+					_, id, log := processStore.AppendProcessLogArgsForCall(i)
+					if id == msg.UUID {
+						logs = append(logs, log)
+					}
+				}
+				Expect(logs).To(ContainElement("Task provision started process provision-vm as run " + childRun().ID.String()))
+			})
+
+			When("the child run fails", func() {
+				BeforeEach(func() {
+					executor.RunStub = func(_ context.Context, task model.Task, _, _ io.Writer) (model.TaskOutput, error) {
+						if task.Name == "create" {
+							return model.TaskOutput{}, ErrExecutor
+						}
+						return model.TaskOutput{}, nil
+					}
+				})
+
+				It("fails the task and the parent run", func() {
+					Expect(errAction).To(MatchError(ContainSubstring("process provision-vm ended as failed")))
+
+					provision := taskRuns[msg.UUID]["provision"]
+					Expect(provision.Status).To(Equal(model.TaskStatusFailed))
+					Expect(provision.Stdout).To(ContainSubstring(`"create":{"status":"failed"}`))
+					Expect(statuses[childRun().ID]).To(Equal(model.StatusFailed))
+					Expect(statuses[msg.UUID]).To(Equal(model.StatusFailed))
+				})
+			})
+
+			When("a stop is requested while the child run executes", func() {
+				BeforeEach(func() {
+					executor.RunStub = func(ctx context.Context, _ model.Task, _, _ io.Writer) (model.TaskOutput, error) {
+						Expect(runRegistry.Cancel(msg.UUID)).To(BeTrue())
+						<-ctx.Done()
+						return model.TaskOutput{}, ctx.Err()
+					}
+				})
+
+				It("stops the child run along with the parent", func() {
+					Expect(errAction).ToNot(HaveOccurred())
+					Expect(statuses[childRun().ID]).To(Equal(model.StatusStopped))
+					Expect(statuses[msg.UUID]).To(Equal(model.StatusStopped))
+				})
+			})
+
+			When("the definition runs itself", func() {
+				BeforeEach(func() {
+					msg.ProcessDefinition.Tasks[0].Subprocess.Name = msg.ProcessDefinition.Name
+				})
+
+				It("fails the task without starting a child run", func() {
+					Expect(errAction).To(HaveOccurred())
+					Expect(processStore.InsertProcessCallCount()).To(Equal(1))
+					provision := taskRuns[msg.UUID]["provision"]
+					Expect(provision.Error).To(Equal("cycle between process definitions: TestProcess -> TestProcess"))
+				})
+			})
+
+			When("the definition was not resolved", func() {
+				BeforeEach(func() {
+					msg.ProcessDefinition.Tasks[0].Subprocess = nil
+				})
+
+				It("fails the task", func() {
+					Expect(errAction).To(HaveOccurred())
+					provision := taskRuns[msg.UUID]["provision"]
+					Expect(provision.Error).To(Equal(`process "provision-vm" of task "provision" was not resolved`))
+				})
+			})
+		})
+
+		When("tasks form a cycle", func() {
+			BeforeEach(func() {
+				msg.ProcessDefinition.Tasks = []model.Task{
+					{Name: "a", Class: "someCmd", WaitFor: []string{"b"}},
+					{Name: "b", Class: "someCmd", WaitFor: []string{"a"}},
+				}
+			})
+
+			It("fails without running any task", func() {
+				Expect(errAction).To(MatchError(ContainSubstring("dependency cycle between tasks: a, b")))
+				Expect(executor.RunCallCount()).To(Equal(0))
+			})
+		})
+
+		When("a stop is requested while a task runs", func() {
+			BeforeEach(func() {
+				msg.ProcessDefinition.Tasks = []model.Task{
+					{Name: "long", Class: "someCmd"},
+					{Name: "after", Class: "someCmd", WaitFor: []string{"long"}},
+				}
+
+				executor.RunStub = func(ctx context.Context, _ model.Task, _, _ io.Writer) (model.TaskOutput, error) {
+					_, run := processStore.InsertProcessArgsForCall(0)
+					Expect(runRegistry.Cancel(run.ID)).To(BeTrue())
+					<-ctx.Done()
+					return model.TaskOutput{}, ctx.Err()
+				}
+			})
+
+			It("aborts the remaining tasks", func() {
+				Expect(errAction).ToNot(HaveOccurred())
+				Expect(executor.RunCallCount()).To(Equal(1))
+			})
+
+			It("records the process as stopped", func() {
+				Expect(processStore.UpdateProcessStatusCallCount()).To(Equal(1))
+				_, _, _, status := processStore.UpdateProcessStatusArgsForCall(0)
+				Expect(status).To(Equal(model.StatusStopped))
+				Expect(store.CompleteMessageCallCount()).To(Equal(1))
+			})
+		})
+
+		When("a stop was requested before the run was registered", func() {
+			BeforeEach(func() {
+				msg.ProcessDefinition.Tasks = []model.Task{{Name: "build", Class: "someCmd"}}
+				requestedAt := time.Now()
+				processStore.GetProcessByIDReturns(model.ProcessRun{StopRequestedAt: &requestedAt}, nil)
+			})
+
+			It("runs no task and records the process as stopped", func() {
+				Expect(errAction).ToNot(HaveOccurred())
+				Expect(executor.RunCallCount()).To(Equal(0))
+
+				_, id := processStore.GetProcessByIDArgsForCall(0)
+				Expect(id).To(Equal(msg.UUID))
+				_, _, _, status := processStore.UpdateProcessStatusArgsForCall(0)
+				Expect(status).To(Equal(model.StatusStopped))
+			})
+		})
+
+		When("a pause was requested before the run was registered", func() {
+			BeforeEach(func() {
+				msg.ProcessDefinition.Tasks = []model.Task{{Name: "build", Class: "someCmd"}}
+				requestedAt := time.Now()
+				processStore.GetProcessByIDReturns(model.ProcessRun{PauseRequestedAt: &requestedAt}, nil)
+			})
+
+			It("runs no task and records the process as paused", func() {
+				Expect(errAction).ToNot(HaveOccurred())
+				Expect(executor.RunCallCount()).To(Equal(0))
+
+				_, _, _, status := processStore.UpdateProcessStatusArgsForCall(0)
+				Expect(status).To(Equal(model.StatusPaused))
+			})
 		})
 
-		When("MarkCompleted fails", func() {
+		When("CompleteMessage fails", func() {
 			BeforeEach(func() {
-				store.MarkCompletedReturns(ErrMarkCompleted)
+				store.CompleteMessageReturns(ErrComplete)
 			})
 
 			It("returns an error", func() {
-				Expect(errAction).To(MatchError(ErrMarkCompleted))
+				Expect(errAction).To(MatchError(ErrComplete))
 			})
 		})
 	})
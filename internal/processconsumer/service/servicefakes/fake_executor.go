@@ -3,6 +3,7 @@ package servicefakes
 
 import (
 	"context"
+	"io"
 	"sync"
 
 	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/model"
@@ -10,40 +11,46 @@ import (
 )
 
 type FakeExecutor struct {
-	RunStub        func(context.Context, model.Task) error
+	RunStub        func(context.Context, model.Task, io.Writer, io.Writer) (model.TaskOutput, error)
 	runMutex       sync.RWMutex
 	runArgsForCall []struct {
 		arg1 context.Context
 		arg2 model.Task
+		arg3 io.Writer
+		arg4 io.Writer
 	}
 	runReturns struct {
-		result1 error
+		result1 model.TaskOutput
+		result2 error
 	}
 	runReturnsOnCall map[int]struct {
-		result1 error
+		result1 model.TaskOutput
+		result2 error
 	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakeExecutor) Run(arg1 context.Context, arg2 model.Task) error {
+func (fake *FakeExecutor) Run(arg1 context.Context, arg2 model.Task, arg3 io.Writer, arg4 io.Writer) (model.TaskOutput, error) {
 	fake.runMutex.Lock()
 	ret, specificReturn := fake.runReturnsOnCall[len(fake.runArgsForCall)]
 	fake.runArgsForCall = append(fake.runArgsForCall, struct {
 		arg1 context.Context
 		arg2 model.Task
-	}{arg1, arg2})
+		arg3 io.Writer
+		arg4 io.Writer
+	}{arg1, arg2, arg3, arg4})
 	stub := fake.RunStub
 	fakeReturns := fake.runReturns
-	fake.recordInvocation("Run", []interface{}{arg1, arg2})
+	fake.recordInvocation("Run", []interface{}{arg1, arg2, arg3, arg4})
 	fake.runMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2)
+		return stub(arg1, arg2, arg3, arg4)
 	}
 	if specificReturn {
-		return ret.result1
+		return ret.result1, ret.result2
 	}
-	return fakeReturns.result1
+	return fakeReturns.result1, fakeReturns.result2
 }
 
 func (fake *FakeExecutor) RunCallCount() int {
@@ -52,40 +59,43 @@ func (fake *FakeExecutor) RunCallCount() int {
 	return len(fake.runArgsForCall)
 }
 
-func (fake *FakeExecutor) RunCalls(stub func(context.Context, model.Task) error) {
+func (fake *FakeExecutor) RunCalls(stub func(context.Context, model.Task, io.Writer, io.Writer) (model.TaskOutput, error)) {
 	fake.runMutex.Lock()
 	defer fake.runMutex.Unlock()
 	fake.RunStub = stub
 }
 
-func (fake *FakeExecutor) RunArgsForCall(i int) (context.Context, model.Task) {
+func (fake *FakeExecutor) RunArgsForCall(i int) (context.Context, model.Task, io.Writer, io.Writer) {
 	fake.runMutex.RLock()
 	defer fake.runMutex.RUnlock()
 	argsForCall := fake.runArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
 }
 
-func (fake *FakeExecutor) RunReturns(result1 error) {
+func (fake *FakeExecutor) RunReturns(result1 model.TaskOutput, result2 error) {
 	fake.runMutex.Lock()
 	defer fake.runMutex.Unlock()
 	fake.RunStub = nil
 	fake.runReturns = struct {
-		result1 error
-	}{result1}
+		result1 model.TaskOutput
+		result2 error
+	}{result1, result2}
 }
 
-func (fake *FakeExecutor) RunReturnsOnCall(i int, result1 error) {
+func (fake *FakeExecutor) RunReturnsOnCall(i int, result1 model.TaskOutput, result2 error) {
 	fake.runMutex.Lock()
 	defer fake.runMutex.Unlock()
 	fake.RunStub = nil
 	if fake.runReturnsOnCall == nil {
 		fake.runReturnsOnCall = make(map[int]struct {
-			result1 error
+			result1 model.TaskOutput
+			result2 error
 		})
 	}
 	fake.runReturnsOnCall[i] = struct {
-		result1 error
-	}{result1}
+		result1 model.TaskOutput
+		result2 error
+	}{result1, result2}
 }
 
 func (fake *FakeExecutor) Invocations() map[string][][]interface{} {
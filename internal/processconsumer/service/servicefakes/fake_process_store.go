@@ -24,6 +24,74 @@ type FakeProcessStore struct {
 	appendProcessLogReturnsOnCall map[int]struct {
 		result1 error
 	}
+	AppendProcessLogsStub        func(context.Context, []model.ProcessLog) error
+	appendProcessLogsMutex       sync.RWMutex
+	appendProcessLogsArgsForCall []struct {
+		arg1 context.Context
+		arg2 []model.ProcessLog
+	}
+	appendProcessLogsReturns struct {
+		result1 error
+	}
+	appendProcessLogsReturnsOnCall map[int]struct {
+		result1 error
+	}
+	BeginContinueStub        func(context.Context, uuid.UUID) (int, error)
+	beginContinueMutex       sync.RWMutex
+	beginContinueArgsForCall []struct {
+		arg1 context.Context
+		arg2 uuid.UUID
+	}
+	beginContinueReturns struct {
+		result1 int
+		result2 error
+	}
+	beginContinueReturnsOnCall map[int]struct {
+		result1 int
+		result2 error
+	}
+	BeginRetryStub        func(context.Context, uuid.UUID) (int, error)
+	beginRetryMutex       sync.RWMutex
+	beginRetryArgsForCall []struct {
+		arg1 context.Context
+		arg2 uuid.UUID
+	}
+	beginRetryReturns struct {
+		result1 int
+		result2 error
+	}
+	beginRetryReturnsOnCall map[int]struct {
+		result1 int
+		result2 error
+	}
+	GetProcessByIDStub        func(context.Context, uuid.UUID) (model.ProcessRun, error)
+	getProcessByIDMutex       sync.RWMutex
+	getProcessByIDArgsForCall []struct {
+		arg1 context.Context
+		arg2 uuid.UUID
+	}
+	getProcessByIDReturns struct {
+		result1 model.ProcessRun
+		result2 error
+	}
+	getProcessByIDReturnsOnCall map[int]struct {
+		result1 model.ProcessRun
+		result2 error
+	}
+	GetTaskRunsStub        func(context.Context, uuid.UUID) ([]model.TaskRun, error)
+	getTaskRunsMutex       sync.RWMutex
+	getTaskRunsArgsForCall []struct {
+		arg1 context.Context
+		arg2 uuid.UUID
+	}
+	getTaskRunsReturns struct {
+		result1 []model.TaskRun
+		result2 error
+	}
+	getTaskRunsReturnsOnCall map[int]struct {
+		result1 []model.TaskRun
+		result2 error
+	}
 	InsertProcessStub        func(context.Context, model.ProcessRun) error
 	insertProcessMutex       sync.RWMutex
 	insertProcessArgsForCall []struct {
@@ -36,12 +104,26 @@ type FakeProcessStore struct {
 	insertProcessReturnsOnCall map[int]struct {
 		result1 error
 	}
-	UpdateProcessStatusStub        func(context.Context, uuid.UUID, model.ProcessStatus) error
+	InsertTaskRunStub        func(context.Context, int, model.TaskRun) error
+	insertTaskRunMutex       sync.RWMutex
+	insertTaskRunArgsForCall []struct {
+		arg1 context.Context
+		arg2 int
+		arg3 model.TaskRun
+	}
+	insertTaskRunReturns struct {
+		result1 error
+	}
+	insertTaskRunReturnsOnCall map[int]struct {
+		result1 error
+	}
+	UpdateProcessStatusStub        func(context.Context, uuid.UUID, int, model.ProcessStatus) error
 	updateProcessStatusMutex       sync.RWMutex
 	updateProcessStatusArgsForCall []struct {
 		arg1 context.Context
 		arg2 uuid.UUID
-		arg3 model.ProcessStatus
+		arg3 int
+		arg4 model.ProcessStatus
 	}
 	updateProcessStatusReturns struct {
 		result1 error
@@ -49,6 +131,19 @@ type FakeProcessStore struct {
 	updateProcessStatusReturnsOnCall map[int]struct {
 		result1 error
 	}
+	UpdateTaskRunStub        func(context.Context, int, model.TaskRun) error
+	updateTaskRunMutex       sync.RWMutex
+	updateTaskRunArgsForCall []struct {
+		arg1 context.Context
+		arg2 int
+		arg3 model.TaskRun
+	}
+	updateTaskRunReturns struct {
+		result1 error
+	}
+	updateTaskRunReturnsOnCall map[int]struct {
+		result1 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -116,6 +211,333 @@ func (fake *FakeProcessStore) AppendProcessLogReturnsOnCall(i int, result1 error
 	}{result1}
 }
 
+func (fake *FakeProcessStore) AppendProcessLogs(arg1 context.Context, arg2 []model.ProcessLog) error {
+	var arg2Copy []model.ProcessLog
+	if arg2 != nil {
+		arg2Copy = make([]model.ProcessLog, len(arg2))
+		copy(arg2Copy, arg2)
+	}
+	fake.appendProcessLogsMutex.Lock()
+	ret, specificReturn := fake.appendProcessLogsReturnsOnCall[len(fake.appendProcessLogsArgsForCall)]
+	fake.appendProcessLogsArgsForCall = append(fake.appendProcessLogsArgsForCall, struct {
+		arg1 context.Context
+		arg2 []model.ProcessLog
+	}{arg1, arg2Copy})
+	stub := fake.AppendProcessLogsStub
+	fakeReturns := fake.appendProcessLogsReturns
+	fake.recordInvocation("AppendProcessLogs", []interface{}{arg1, arg2Copy})
+	fake.appendProcessLogsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeProcessStore) AppendProcessLogsCallCount() int {
+	fake.appendProcessLogsMutex.RLock()
+	defer fake.appendProcessLogsMutex.RUnlock()
+	return len(fake.appendProcessLogsArgsForCall)
+}
+
+func (fake *FakeProcessStore) AppendProcessLogsCalls(stub func(context.Context, []model.ProcessLog) error) {
+	fake.appendProcessLogsMutex.Lock()
+	defer fake.appendProcessLogsMutex.Unlock()
+	fake.AppendProcessLogsStub = stub
+}
+
+func (fake *FakeProcessStore) AppendProcessLogsArgsForCall(i int) (context.Context, []model.ProcessLog) {
+	fake.appendProcessLogsMutex.RLock()
+	defer fake.appendProcessLogsMutex.RUnlock()
+	argsForCall := fake.appendProcessLogsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeProcessStore) AppendProcessLogsReturns(result1 error) {
+	fake.appendProcessLogsMutex.Lock()
+	defer fake.appendProcessLogsMutex.Unlock()
+	fake.AppendProcessLogsStub = nil
+	fake.appendProcessLogsReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeProcessStore) AppendProcessLogsReturnsOnCall(i int, result1 error) {
+	fake.appendProcessLogsMutex.Lock()
+	defer fake.appendProcessLogsMutex.Unlock()
+	fake.AppendProcessLogsStub = nil
+	if fake.appendProcessLogsReturnsOnCall == nil {
+		fake.appendProcessLogsReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.appendProcessLogsReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeProcessStore) BeginContinue(arg1 context.Context, arg2 uuid.UUID) (int, error) {
+	fake.beginContinueMutex.Lock()
+	ret, specificReturn := fake.beginContinueReturnsOnCall[len(fake.beginContinueArgsForCall)]
+	fake.beginContinueArgsForCall = append(fake.beginContinueArgsForCall, struct {
+		arg1 context.Context
+		arg2 uuid.UUID
+	}{arg1, arg2})
+	stub := fake.BeginContinueStub
+	fakeReturns := fake.beginContinueReturns
+	fake.recordInvocation("BeginContinue", []interface{}{arg1, arg2})
+	fake.beginContinueMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeProcessStore) BeginContinueCallCount() int {
+	fake.beginContinueMutex.RLock()
+	defer fake.beginContinueMutex.RUnlock()
+	return len(fake.beginContinueArgsForCall)
+}
+
+func (fake *FakeProcessStore) BeginContinueCalls(stub func(context.Context, uuid.UUID) (int, error)) {
+	fake.beginContinueMutex.Lock()
+	defer fake.beginContinueMutex.Unlock()
+	fake.BeginContinueStub = stub
+}
+
+func (fake *FakeProcessStore) BeginContinueArgsForCall(i int) (context.Context, uuid.UUID) {
+	fake.beginContinueMutex.RLock()
+	defer fake.beginContinueMutex.RUnlock()
+	argsForCall := fake.beginContinueArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeProcessStore) BeginContinueReturns(result1 int, result2 error) {
+	fake.beginContinueMutex.Lock()
+	defer fake.beginContinueMutex.Unlock()
+	fake.BeginContinueStub = nil
+	fake.beginContinueReturns = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeProcessStore) BeginContinueReturnsOnCall(i int, result1 int, result2 error) {
+	fake.beginContinueMutex.Lock()
+	defer fake.beginContinueMutex.Unlock()
+	fake.BeginContinueStub = nil
+	if fake.beginContinueReturnsOnCall == nil {
+		fake.beginContinueReturnsOnCall = make(map[int]struct {
+			result1 int
+			result2 error
+		})
+	}
+	fake.beginContinueReturnsOnCall[i] = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeProcessStore) BeginRetry(arg1 context.Context, arg2 uuid.UUID) (int, error) {
+	fake.beginRetryMutex.Lock()
+	ret, specificReturn := fake.beginRetryReturnsOnCall[len(fake.beginRetryArgsForCall)]
+	fake.beginRetryArgsForCall = append(fake.beginRetryArgsForCall, struct {
+		arg1 context.Context
+		arg2 uuid.UUID
+	}{arg1, arg2})
+	stub := fake.BeginRetryStub
+	fakeReturns := fake.beginRetryReturns
+	fake.recordInvocation("BeginRetry", []interface{}{arg1, arg2})
+	fake.beginRetryMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeProcessStore) BeginRetryCallCount() int {
+	fake.beginRetryMutex.RLock()
+	defer fake.beginRetryMutex.RUnlock()
+	return len(fake.beginRetryArgsForCall)
+}
+
+func (fake *FakeProcessStore) BeginRetryCalls(stub func(context.Context, uuid.UUID) (int, error)) {
+	fake.beginRetryMutex.Lock()
+	defer fake.beginRetryMutex.Unlock()
+	fake.BeginRetryStub = stub
+}
+
+func (fake *FakeProcessStore) BeginRetryArgsForCall(i int) (context.Context, uuid.UUID) {
+	fake.beginRetryMutex.RLock()
+	defer fake.beginRetryMutex.RUnlock()
+	argsForCall := fake.beginRetryArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeProcessStore) BeginRetryReturns(result1 int, result2 error) {
+	fake.beginRetryMutex.Lock()
+	defer fake.beginRetryMutex.Unlock()
+	fake.BeginRetryStub = nil
+	fake.beginRetryReturns = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeProcessStore) BeginRetryReturnsOnCall(i int, result1 int, result2 error) {
+	fake.beginRetryMutex.Lock()
+	defer fake.beginRetryMutex.Unlock()
+	fake.BeginRetryStub = nil
+	if fake.beginRetryReturnsOnCall == nil {
+		fake.beginRetryReturnsOnCall = make(map[int]struct {
+			result1 int
+			result2 error
+		})
+	}
+	fake.beginRetryReturnsOnCall[i] = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeProcessStore) GetProcessByID(arg1 context.Context, arg2 uuid.UUID) (model.ProcessRun, error) {
+	fake.getProcessByIDMutex.Lock()
+	ret, specificReturn := fake.getProcessByIDReturnsOnCall[len(fake.getProcessByIDArgsForCall)]
+	fake.getProcessByIDArgsForCall = append(fake.getProcessByIDArgsForCall, struct {
+		arg1 context.Context
+		arg2 uuid.UUID
+	}{arg1, arg2})
+	stub := fake.GetProcessByIDStub
+	fakeReturns := fake.getProcessByIDReturns
+	fake.recordInvocation("GetProcessByID", []interface{}{arg1, arg2})
+	fake.getProcessByIDMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeProcessStore) GetProcessByIDCallCount() int {
+	fake.getProcessByIDMutex.RLock()
+	defer fake.getProcessByIDMutex.RUnlock()
+	return len(fake.getProcessByIDArgsForCall)
+}
+
+func (fake *FakeProcessStore) GetProcessByIDCalls(stub func(context.Context, uuid.UUID) (model.ProcessRun, error)) {
+	fake.getProcessByIDMutex.Lock()
+	defer fake.getProcessByIDMutex.Unlock()
+	fake.GetProcessByIDStub = stub
+}
+
+func (fake *FakeProcessStore) GetProcessByIDArgsForCall(i int) (context.Context, uuid.UUID) {
+	fake.getProcessByIDMutex.RLock()
+	defer fake.getProcessByIDMutex.RUnlock()
+	argsForCall := fake.getProcessByIDArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeProcessStore) GetProcessByIDReturns(result1 model.ProcessRun, result2 error) {
+	fake.getProcessByIDMutex.Lock()
+	defer fake.getProcessByIDMutex.Unlock()
+	fake.GetProcessByIDStub = nil
+	fake.getProcessByIDReturns = struct {
+		result1 model.ProcessRun
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeProcessStore) GetProcessByIDReturnsOnCall(i int, result1 model.ProcessRun, result2 error) {
+	fake.getProcessByIDMutex.Lock()
+	defer fake.getProcessByIDMutex.Unlock()
+	fake.GetProcessByIDStub = nil
+	if fake.getProcessByIDReturnsOnCall == nil {
+		fake.getProcessByIDReturnsOnCall = make(map[int]struct {
+			result1 model.ProcessRun
+			result2 error
+		})
+	}
+	fake.getProcessByIDReturnsOnCall[i] = struct {
+		result1 model.ProcessRun
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeProcessStore) GetTaskRuns(arg1 context.Context, arg2 uuid.UUID) ([]model.TaskRun, error) {
+	fake.getTaskRunsMutex.Lock()
+	ret, specificReturn := fake.getTaskRunsReturnsOnCall[len(fake.getTaskRunsArgsForCall)]
+	fake.getTaskRunsArgsForCall = append(fake.getTaskRunsArgsForCall, struct {
+		arg1 context.Context
+		arg2 uuid.UUID
+	}{arg1, arg2})
+	stub := fake.GetTaskRunsStub
+	fakeReturns := fake.getTaskRunsReturns
+	fake.recordInvocation("GetTaskRuns", []interface{}{arg1, arg2})
+	fake.getTaskRunsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeProcessStore) GetTaskRunsCallCount() int {
+	fake.getTaskRunsMutex.RLock()
+	defer fake.getTaskRunsMutex.RUnlock()
+	return len(fake.getTaskRunsArgsForCall)
+}
+
+func (fake *FakeProcessStore) GetTaskRunsCalls(stub func(context.Context, uuid.UUID) ([]model.TaskRun, error)) {
+	fake.getTaskRunsMutex.Lock()
+	defer fake.getTaskRunsMutex.Unlock()
+	fake.GetTaskRunsStub = stub
+}
+
+func (fake *FakeProcessStore) GetTaskRunsArgsForCall(i int) (context.Context, uuid.UUID) {
+	fake.getTaskRunsMutex.RLock()
+	defer fake.getTaskRunsMutex.RUnlock()
+	argsForCall := fake.getTaskRunsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeProcessStore) GetTaskRunsReturns(result1 []model.TaskRun, result2 error) {
+	fake.getTaskRunsMutex.Lock()
+	defer fake.getTaskRunsMutex.Unlock()
+	fake.GetTaskRunsStub = nil
+	fake.getTaskRunsReturns = struct {
+		result1 []model.TaskRun
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeProcessStore) GetTaskRunsReturnsOnCall(i int, result1 []model.TaskRun, result2 error) {
+	fake.getTaskRunsMutex.Lock()
+	defer fake.getTaskRunsMutex.Unlock()
+	fake.GetTaskRunsStub = nil
+	if fake.getTaskRunsReturnsOnCall == nil {
+		fake.getTaskRunsReturnsOnCall = make(map[int]struct {
+			result1 []model.TaskRun
+			result2 error
+		})
+	}
+	fake.getTaskRunsReturnsOnCall[i] = struct {
+		result1 []model.TaskRun
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeProcessStore) InsertProcess(arg1 context.Context, arg2 model.ProcessRun) error {
 	fake.insertProcessMutex.Lock()
 	ret, specificReturn := fake.insertProcessReturnsOnCall[len(fake.insertProcessArgsForCall)]
@@ -178,20 +600,84 @@ func (fake *FakeProcessStore) InsertProcessReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
-func (fake *FakeProcessStore) UpdateProcessStatus(arg1 context.Context, arg2 uuid.UUID, arg3 model.ProcessStatus) error {
+func (fake *FakeProcessStore) InsertTaskRun(arg1 context.Context, arg2 int, arg3 model.TaskRun) error {
+	fake.insertTaskRunMutex.Lock()
+	ret, specificReturn := fake.insertTaskRunReturnsOnCall[len(fake.insertTaskRunArgsForCall)]
+	fake.insertTaskRunArgsForCall = append(fake.insertTaskRunArgsForCall, struct {
+		arg1 context.Context
+		arg2 int
+		arg3 model.TaskRun
+	}{arg1, arg2, arg3})
+	stub := fake.InsertTaskRunStub
+	fakeReturns := fake.insertTaskRunReturns
+	fake.recordInvocation("InsertTaskRun", []interface{}{arg1, arg2, arg3})
+	fake.insertTaskRunMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeProcessStore) InsertTaskRunCallCount() int {
+	fake.insertTaskRunMutex.RLock()
+	defer fake.insertTaskRunMutex.RUnlock()
+	return len(fake.insertTaskRunArgsForCall)
+}
+
+func (fake *FakeProcessStore) InsertTaskRunCalls(stub func(context.Context, int, model.TaskRun) error) {
+	fake.insertTaskRunMutex.Lock()
+	defer fake.insertTaskRunMutex.Unlock()
+	fake.InsertTaskRunStub = stub
+}
+
+func (fake *FakeProcessStore) InsertTaskRunArgsForCall(i int) (context.Context, int, model.TaskRun) {
+	fake.insertTaskRunMutex.RLock()
+	defer fake.insertTaskRunMutex.RUnlock()
+	argsForCall := fake.insertTaskRunArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeProcessStore) InsertTaskRunReturns(result1 error) {
+	fake.insertTaskRunMutex.Lock()
+	defer fake.insertTaskRunMutex.Unlock()
+	fake.InsertTaskRunStub = nil
+	fake.insertTaskRunReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeProcessStore) InsertTaskRunReturnsOnCall(i int, result1 error) {
+	fake.insertTaskRunMutex.Lock()
+	defer fake.insertTaskRunMutex.Unlock()
+	fake.InsertTaskRunStub = nil
+	if fake.insertTaskRunReturnsOnCall == nil {
+		fake.insertTaskRunReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.insertTaskRunReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeProcessStore) UpdateProcessStatus(arg1 context.Context, arg2 uuid.UUID, arg3 int, arg4 model.ProcessStatus) error {
 	fake.updateProcessStatusMutex.Lock()
 	ret, specificReturn := fake.updateProcessStatusReturnsOnCall[len(fake.updateProcessStatusArgsForCall)]
 	fake.updateProcessStatusArgsForCall = append(fake.updateProcessStatusArgsForCall, struct {
 		arg1 context.Context
 		arg2 uuid.UUID
-		arg3 model.ProcessStatus
-	}{arg1, arg2, arg3})
+		arg3 int
+		arg4 model.ProcessStatus
+	}{arg1, arg2, arg3, arg4})
 	stub := fake.UpdateProcessStatusStub
 	fakeReturns := fake.updateProcessStatusReturns
-	fake.recordInvocation("UpdateProcessStatus", []interface{}{arg1, arg2, arg3})
+	fake.recordInvocation("UpdateProcessStatus", []interface{}{arg1, arg2, arg3, arg4})
 	fake.updateProcessStatusMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2, arg3)
+		return stub(arg1, arg2, arg3, arg4)
 	}
 	if specificReturn {
 		return ret.result1
@@ -205,17 +691,17 @@ func (fake *FakeProcessStore) UpdateProcessStatusCallCount() int {
 	return len(fake.updateProcessStatusArgsForCall)
 }
 
-func (fake *FakeProcessStore) UpdateProcessStatusCalls(stub func(context.Context, uuid.UUID, model.ProcessStatus) error) {
+func (fake *FakeProcessStore) UpdateProcessStatusCalls(stub func(context.Context, uuid.UUID, int, model.ProcessStatus) error) {
 	fake.updateProcessStatusMutex.Lock()
 	defer fake.updateProcessStatusMutex.Unlock()
 	fake.UpdateProcessStatusStub = stub
 }
 
-func (fake *FakeProcessStore) UpdateProcessStatusArgsForCall(i int) (context.Context, uuid.UUID, model.ProcessStatus) {
+func (fake *FakeProcessStore) UpdateProcessStatusArgsForCall(i int) (context.Context, uuid.UUID, int, model.ProcessStatus) {
 	fake.updateProcessStatusMutex.RLock()
 	defer fake.updateProcessStatusMutex.RUnlock()
 	argsForCall := fake.updateProcessStatusArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
 }
 
 func (fake *FakeProcessStore) UpdateProcessStatusReturns(result1 error) {
@@ -241,15 +727,92 @@ func (fake *FakeProcessStore) UpdateProcessStatusReturnsOnCall(i int, result1 er
 	}{result1}
 }
 
+func (fake *FakeProcessStore) UpdateTaskRun(arg1 context.Context, arg2 int, arg3 model.TaskRun) error {
+	fake.updateTaskRunMutex.Lock()
+	ret, specificReturn := fake.updateTaskRunReturnsOnCall[len(fake.updateTaskRunArgsForCall)]
+	fake.updateTaskRunArgsForCall = append(fake.updateTaskRunArgsForCall, struct {
+		arg1 context.Context
+		arg2 int
+		arg3 model.TaskRun
+	}{arg1, arg2, arg3})
+	stub := fake.UpdateTaskRunStub
+	fakeReturns := fake.updateTaskRunReturns
+	fake.recordInvocation("UpdateTaskRun", []interface{}{arg1, arg2, arg3})
+	fake.updateTaskRunMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeProcessStore) UpdateTaskRunCallCount() int {
+	fake.updateTaskRunMutex.RLock()
+	defer fake.updateTaskRunMutex.RUnlock()
+	return len(fake.updateTaskRunArgsForCall)
+}
+
+func (fake *FakeProcessStore) UpdateTaskRunCalls(stub func(context.Context, int, model.TaskRun) error) {
+	fake.updateTaskRunMutex.Lock()
+	defer fake.updateTaskRunMutex.Unlock()
+	fake.UpdateTaskRunStub = stub
+}
+
+func (fake *FakeProcessStore) UpdateTaskRunArgsForCall(i int) (context.Context, int, model.TaskRun) {
+	fake.updateTaskRunMutex.RLock()
+	defer fake.updateTaskRunMutex.RUnlock()
+	argsForCall := fake.updateTaskRunArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeProcessStore) UpdateTaskRunReturns(result1 error) {
+	fake.updateTaskRunMutex.Lock()
+	defer fake.updateTaskRunMutex.Unlock()
+	fake.UpdateTaskRunStub = nil
+	fake.updateTaskRunReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeProcessStore) UpdateTaskRunReturnsOnCall(i int, result1 error) {
+	fake.updateTaskRunMutex.Lock()
+	defer fake.updateTaskRunMutex.Unlock()
+	fake.UpdateTaskRunStub = nil
+	if fake.updateTaskRunReturnsOnCall == nil {
+		fake.updateTaskRunReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.updateTaskRunReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeProcessStore) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
 	fake.appendProcessLogMutex.RLock()
 	defer fake.appendProcessLogMutex.RUnlock()
+	fake.appendProcessLogsMutex.RLock()
+	defer fake.appendProcessLogsMutex.RUnlock()
+	fake.beginContinueMutex.RLock()
+	defer fake.beginContinueMutex.RUnlock()
+	fake.beginRetryMutex.RLock()
+	defer fake.beginRetryMutex.RUnlock()
+	fake.getProcessByIDMutex.RLock()
+	defer fake.getProcessByIDMutex.RUnlock()
+	fake.getTaskRunsMutex.RLock()
+	defer fake.getTaskRunsMutex.RUnlock()
 	fake.insertProcessMutex.RLock()
 	defer fake.insertProcessMutex.RUnlock()
+	fake.insertTaskRunMutex.RLock()
+	defer fake.insertTaskRunMutex.RUnlock()
 	fake.updateProcessStatusMutex.RLock()
 	defer fake.updateProcessStatusMutex.RUnlock()
+	fake.updateTaskRunMutex.RLock()
+	defer fake.updateTaskRunMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
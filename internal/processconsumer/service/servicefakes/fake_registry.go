@@ -0,0 +1,342 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package servicefakes
+
+import (
+	"context"
+	"sync"
+
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/processconsumer/service"
+	"github.com/google/uuid"
+)
+
+type FakeRegistry struct {
+	CancelStub        func(uuid.UUID) bool
+	cancelMutex       sync.RWMutex
+	cancelArgsForCall []struct {
+		arg1 uuid.UUID
+	}
+	cancelReturns struct {
+		result1 bool
+	}
+	cancelReturnsOnCall map[int]struct {
+		result1 bool
+	}
+	PauseStub        func(uuid.UUID) bool
+	pauseMutex       sync.RWMutex
+	pauseArgsForCall []struct {
+		arg1 uuid.UUID
+	}
+	pauseReturns struct {
+		result1 bool
+	}
+	pauseReturnsOnCall map[int]struct {
+		result1 bool
+	}
+	PausedStub        func(uuid.UUID) <-chan struct{}
+	pausedMutex       sync.RWMutex
+	pausedArgsForCall []struct {
+		arg1 uuid.UUID
+	}
+	pausedReturns struct {
+		result1 <-chan struct{}
+	}
+	pausedReturnsOnCall map[int]struct {
+		result1 <-chan struct{}
+	}
+	RegisterStub        func(context.Context, uuid.UUID) (context.Context, func())
+	registerMutex       sync.RWMutex
+	registerArgsForCall []struct {
+		arg1 context.Context
+		arg2 uuid.UUID
+	}
+	registerReturns struct {
+		result1 context.Context
+		result2 func()
+	}
+	registerReturnsOnCall map[int]struct {
+		result1 context.Context
+		result2 func()
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeRegistry) Cancel(arg1 uuid.UUID) bool {
+	fake.cancelMutex.Lock()
+	ret, specificReturn := fake.cancelReturnsOnCall[len(fake.cancelArgsForCall)]
+	fake.cancelArgsForCall = append(fake.cancelArgsForCall, struct {
+		arg1 uuid.UUID
+	}{arg1})
+	stub := fake.CancelStub
+	fakeReturns := fake.cancelReturns
+	fake.recordInvocation("Cancel", []interface{}{arg1})
+	fake.cancelMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeRegistry) CancelCallCount() int {
+	fake.cancelMutex.RLock()
+	defer fake.cancelMutex.RUnlock()
+	return len(fake.cancelArgsForCall)
+}
+
+func (fake *FakeRegistry) CancelCalls(stub func(uuid.UUID) bool) {
+	fake.cancelMutex.Lock()
+	defer fake.cancelMutex.Unlock()
+	fake.CancelStub = stub
+}
+
+func (fake *FakeRegistry) CancelArgsForCall(i int) uuid.UUID {
+	fake.cancelMutex.RLock()
+	defer fake.cancelMutex.RUnlock()
+	argsForCall := fake.cancelArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeRegistry) CancelReturns(result1 bool) {
+	fake.cancelMutex.Lock()
+	defer fake.cancelMutex.Unlock()
+	fake.CancelStub = nil
+	fake.cancelReturns = struct {
+		result1 bool
+	}{result1}
+}
+
+func (fake *FakeRegistry) CancelReturnsOnCall(i int, result1 bool) {
+	fake.cancelMutex.Lock()
+	defer fake.cancelMutex.Unlock()
+	fake.CancelStub = nil
+	if fake.cancelReturnsOnCall == nil {
+		fake.cancelReturnsOnCall = make(map[int]struct {
+			result1 bool
+		})
+	}
+	fake.cancelReturnsOnCall[i] = struct {
+		result1 bool
+	}{result1}
+}
+
+func (fake *FakeRegistry) Pause(arg1 uuid.UUID) bool {
+	fake.pauseMutex.Lock()
+	ret, specificReturn := fake.pauseReturnsOnCall[len(fake.pauseArgsForCall)]
+	fake.pauseArgsForCall = append(fake.pauseArgsForCall, struct {
+		arg1 uuid.UUID
+	}{arg1})
+	stub := fake.PauseStub
+	fakeReturns := fake.pauseReturns
+	fake.recordInvocation("Pause", []interface{}{arg1})
+	fake.pauseMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeRegistry) PauseCallCount() int {
+	fake.pauseMutex.RLock()
+	defer fake.pauseMutex.RUnlock()
+	return len(fake.pauseArgsForCall)
+}
+
+func (fake *FakeRegistry) PauseCalls(stub func(uuid.UUID) bool) {
+	fake.pauseMutex.Lock()
+	defer fake.pauseMutex.Unlock()
+	fake.PauseStub = stub
+}
+
+func (fake *FakeRegistry) PauseArgsForCall(i int) uuid.UUID {
+	fake.pauseMutex.RLock()
+	defer fake.pauseMutex.RUnlock()
+	argsForCall := fake.pauseArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeRegistry) PauseReturns(result1 bool) {
+	fake.pauseMutex.Lock()
+	defer fake.pauseMutex.Unlock()
+	fake.PauseStub = nil
+	fake.pauseReturns = struct {
+		result1 bool
+	}{result1}
+}
+
+func (fake *FakeRegistry) PauseReturnsOnCall(i int, result1 bool) {
+	fake.pauseMutex.Lock()
+	defer fake.pauseMutex.Unlock()
+	fake.PauseStub = nil
+	if fake.pauseReturnsOnCall == nil {
+		fake.pauseReturnsOnCall = make(map[int]struct {
+			result1 bool
+		})
+	}
+	fake.pauseReturnsOnCall[i] = struct {
+		result1 bool
+	}{result1}
+}
+
+func (fake *FakeRegistry) Paused(arg1 uuid.UUID) <-chan struct{} {
+	fake.pausedMutex.Lock()
+	ret, specificReturn := fake.pausedReturnsOnCall[len(fake.pausedArgsForCall)]
+	fake.pausedArgsForCall = append(fake.pausedArgsForCall, struct {
+		arg1 uuid.UUID
+	}{arg1})
+	stub := fake.PausedStub
+	fakeReturns := fake.pausedReturns
+	fake.recordInvocation("Paused", []interface{}{arg1})
+	fake.pausedMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeRegistry) PausedCallCount() int {
+	fake.pausedMutex.RLock()
+	defer fake.pausedMutex.RUnlock()
+	return len(fake.pausedArgsForCall)
+}
+
+func (fake *FakeRegistry) PausedCalls(stub func(uuid.UUID) <-chan struct{}) {
+	fake.pausedMutex.Lock()
+	defer fake.pausedMutex.Unlock()
+	fake.PausedStub = stub
+}
+
+func (fake *FakeRegistry) PausedArgsForCall(i int) uuid.UUID {
+	fake.pausedMutex.RLock()
+	defer fake.pausedMutex.RUnlock()
+	argsForCall := fake.pausedArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeRegistry) PausedReturns(result1 <-chan struct{}) {
+	fake.pausedMutex.Lock()
+	defer fake.pausedMutex.Unlock()
+	fake.PausedStub = nil
+	fake.pausedReturns = struct {
+		result1 <-chan struct{}
+	}{result1}
+}
+
+func (fake *FakeRegistry) PausedReturnsOnCall(i int, result1 <-chan struct{}) {
+	fake.pausedMutex.Lock()
+	defer fake.pausedMutex.Unlock()
+	fake.PausedStub = nil
+	if fake.pausedReturnsOnCall == nil {
+		fake.pausedReturnsOnCall = make(map[int]struct {
+			result1 <-chan struct{}
+		})
+	}
+	fake.pausedReturnsOnCall[i] = struct {
+		result1 <-chan struct{}
+	}{result1}
+}
+
+func (fake *FakeRegistry) Register(arg1 context.Context, arg2 uuid.UUID) (context.Context, func()) {
+	fake.registerMutex.Lock()
+	ret, specificReturn := fake.registerReturnsOnCall[len(fake.registerArgsForCall)]
+	fake.registerArgsForCall = append(fake.registerArgsForCall, struct {
+		arg1 context.Context
+		arg2 uuid.UUID
+	}{arg1, arg2})
+	stub := fake.RegisterStub
+	fakeReturns := fake.registerReturns
+	fake.recordInvocation("Register", []interface{}{arg1, arg2})
+	fake.registerMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeRegistry) RegisterCallCount() int {
+	fake.registerMutex.RLock()
+	defer fake.registerMutex.RUnlock()
+	return len(fake.registerArgsForCall)
+}
+
+func (fake *FakeRegistry) RegisterCalls(stub func(context.Context, uuid.UUID) (context.Context, func())) {
+	fake.registerMutex.Lock()
+	defer fake.registerMutex.Unlock()
+	fake.RegisterStub = stub
+}
+
+func (fake *FakeRegistry) RegisterArgsForCall(i int) (context.Context, uuid.UUID) {
+	fake.registerMutex.RLock()
+	defer fake.registerMutex.RUnlock()
+	argsForCall := fake.registerArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeRegistry) RegisterReturns(result1 context.Context, result2 func()) {
+	fake.registerMutex.Lock()
+	defer fake.registerMutex.Unlock()
+	fake.RegisterStub = nil
+	fake.registerReturns = struct {
+		result1 context.Context
+		result2 func()
+	}{result1, result2}
+}
+
+func (fake *FakeRegistry) RegisterReturnsOnCall(i int, result1 context.Context, result2 func()) {
+	fake.registerMutex.Lock()
+	defer fake.registerMutex.Unlock()
+	fake.RegisterStub = nil
+	if fake.registerReturnsOnCall == nil {
+		fake.registerReturnsOnCall = make(map[int]struct {
+			result1 context.Context
+			result2 func()
+		})
+	}
+	fake.registerReturnsOnCall[i] = struct {
+		result1 context.Context
+		result2 func()
+	}{result1, result2}
+}
+
+func (fake *FakeRegistry) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.cancelMutex.RLock()
+	defer fake.cancelMutex.RUnlock()
+	fake.pauseMutex.RLock()
+	defer fake.pauseMutex.RUnlock()
+	fake.pausedMutex.RLock()
+	defer fake.pausedMutex.RUnlock()
+	fake.registerMutex.RLock()
+	defer fake.registerMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeRegistry) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ service.Registry = new(FakeRegistry)
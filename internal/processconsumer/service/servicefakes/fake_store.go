@@ -4,6 +4,7 @@ package servicefakes
 import (
 	"context"
 	"sync"
+	"time"
 
 	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/model"
 	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/processconsumer/service"
@@ -11,135 +12,147 @@ import (
 )
 
 type FakeStore struct {
-	AddMessageStub        func(context.Context, model.Message) error
-	addMessageMutex       sync.RWMutex
-	addMessageArgsForCall []struct {
+	ClaimMessageStub        func(context.Context, model.Message, string, time.Duration) (bool, error)
+	claimMessageMutex       sync.RWMutex
+	claimMessageArgsForCall []struct {
 		arg1 context.Context
 		arg2 model.Message
+		arg3 string
+		arg4 time.Duration
 	}
-	addMessageReturns struct {
-		result1 error
+	claimMessageReturns struct {
+		result1 bool
+		result2 error
 	}
-	addMessageReturnsOnCall map[int]struct {
-		result1 error
+	claimMessageReturnsOnCall map[int]struct {
+		result1 bool
+		result2 error
 	}
-	MarkCompletedStub        func(context.Context, uuid.UUID) error
-	markCompletedMutex       sync.RWMutex
-	markCompletedArgsForCall []struct {
+	CompleteMessageStub        func(context.Context, uuid.UUID, string) error
+	completeMessageMutex       sync.RWMutex
+	completeMessageArgsForCall []struct {
 		arg1 context.Context
 		arg2 uuid.UUID
+		arg3 string
 	}
-	markCompletedReturns struct {
+	completeMessageReturns struct {
 		result1 error
 	}
-	markCompletedReturnsOnCall map[int]struct {
+	completeMessageReturnsOnCall map[int]struct {
 		result1 error
 	}
-	MessageExistsStub        func(context.Context, uuid.UUID) (bool, error)
-	messageExistsMutex       sync.RWMutex
-	messageExistsArgsForCall []struct {
+	ExtendLeaseStub        func(context.Context, uuid.UUID, string, time.Duration) error
+	extendLeaseMutex       sync.RWMutex
+	extendLeaseArgsForCall []struct {
 		arg1 context.Context
 		arg2 uuid.UUID
+		arg3 string
+		arg4 time.Duration
 	}
-	messageExistsReturns struct {
-		result1 bool
-		result2 error
+	extendLeaseReturns struct {
+		result1 error
 	}
-	messageExistsReturnsOnCall map[int]struct {
-		result1 bool
-		result2 error
+	extendLeaseReturnsOnCall map[int]struct {
+		result1 error
 	}
-	RunInAtomicallyStub        func(context.Context, func(context.Context) error) error
-	runInAtomicallyMutex       sync.RWMutex
-	runInAtomicallyArgsForCall []struct {
+	ReleaseMessageStub        func(context.Context, uuid.UUID, string) error
+	releaseMessageMutex       sync.RWMutex
+	releaseMessageArgsForCall []struct {
 		arg1 context.Context
-		arg2 func(context.Context) error
+		arg2 uuid.UUID
+		arg3 string
 	}
-	runInAtomicallyReturns struct {
+	releaseMessageReturns struct {
 		result1 error
 	}
-	runInAtomicallyReturnsOnCall map[int]struct {
+	releaseMessageReturnsOnCall map[int]struct {
 		result1 error
 	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakeStore) AddMessage(arg1 context.Context, arg2 model.Message) error {
-	fake.addMessageMutex.Lock()
-	ret, specificReturn := fake.addMessageReturnsOnCall[len(fake.addMessageArgsForCall)]
-	fake.addMessageArgsForCall = append(fake.addMessageArgsForCall, struct {
+func (fake *FakeStore) ClaimMessage(arg1 context.Context, arg2 model.Message, arg3 string, arg4 time.Duration) (bool, error) {
+	fake.claimMessageMutex.Lock()
+	ret, specificReturn := fake.claimMessageReturnsOnCall[len(fake.claimMessageArgsForCall)]
+	fake.claimMessageArgsForCall = append(fake.claimMessageArgsForCall, struct {
 		arg1 context.Context
 		arg2 model.Message
-	}{arg1, arg2})
-	stub := fake.AddMessageStub
-	fakeReturns := fake.addMessageReturns
-	fake.recordInvocation("AddMessage", []interface{}{arg1, arg2})
-	fake.addMessageMutex.Unlock()
+		arg3 string
+		arg4 time.Duration
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.ClaimMessageStub
+	fakeReturns := fake.claimMessageReturns
+	fake.recordInvocation("ClaimMessage", []interface{}{arg1, arg2, arg3, arg4})
+	fake.claimMessageMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2)
+		return stub(arg1, arg2, arg3, arg4)
 	}
 	if specificReturn {
-		return ret.result1
+		return ret.result1, ret.result2
 	}
-	return fakeReturns.result1
+	return fakeReturns.result1, fakeReturns.result2
 }
 
-func (fake *FakeStore) AddMessageCallCount() int {
-	fake.addMessageMutex.RLock()
-	defer fake.addMessageMutex.RUnlock()
-	return len(fake.addMessageArgsForCall)
+func (fake *FakeStore) ClaimMessageCallCount() int {
+	fake.claimMessageMutex.RLock()
+	defer fake.claimMessageMutex.RUnlock()
+	return len(fake.claimMessageArgsForCall)
 }
 
-func (fake *FakeStore) AddMessageCalls(stub func(context.Context, model.Message) error) {
-	fake.addMessageMutex.Lock()
-	defer fake.addMessageMutex.Unlock()
-	fake.AddMessageStub = stub
+func (fake *FakeStore) ClaimMessageCalls(stub func(context.Context, model.Message, string, time.Duration) (bool, error)) {
+	fake.claimMessageMutex.Lock()
+	defer fake.claimMessageMutex.Unlock()
+	fake.ClaimMessageStub = stub
 }
 
-func (fake *FakeStore) AddMessageArgsForCall(i int) (context.Context, model.Message) {
-	fake.addMessageMutex.RLock()
-	defer fake.addMessageMutex.RUnlock()
-	argsForCall := fake.addMessageArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+func (fake *FakeStore) ClaimMessageArgsForCall(i int) (context.Context, model.Message, string, time.Duration) {
+	fake.claimMessageMutex.RLock()
+	defer fake.claimMessageMutex.RUnlock()
+	argsForCall := fake.claimMessageArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
 }
 
-func (fake *FakeStore) AddMessageReturns(result1 error) {
-	fake.addMessageMutex.Lock()
-	defer fake.addMessageMutex.Unlock()
-	fake.AddMessageStub = nil
-	fake.addMessageReturns = struct {
-		result1 error
-	}{result1}
+func (fake *FakeStore) ClaimMessageReturns(result1 bool, result2 error) {
+	fake.claimMessageMutex.Lock()
+	defer fake.claimMessageMutex.Unlock()
+	fake.ClaimMessageStub = nil
+	fake.claimMessageReturns = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
 }
 
-func (fake *FakeStore) AddMessageReturnsOnCall(i int, result1 error) {
-	fake.addMessageMutex.Lock()
-	defer fake.addMessageMutex.Unlock()
-	fake.AddMessageStub = nil
-	if fake.addMessageReturnsOnCall == nil {
-		fake.addMessageReturnsOnCall = make(map[int]struct {
-			result1 error
+func (fake *FakeStore) ClaimMessageReturnsOnCall(i int, result1 bool, result2 error) {
+	fake.claimMessageMutex.Lock()
+	defer fake.claimMessageMutex.Unlock()
+	fake.ClaimMessageStub = nil
+	if fake.claimMessageReturnsOnCall == nil {
+		fake.claimMessageReturnsOnCall = make(map[int]struct {
+			result1 bool
+			result2 error
 		})
 	}
-	fake.addMessageReturnsOnCall[i] = struct {
-		result1 error
-	}{result1}
+	fake.claimMessageReturnsOnCall[i] = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
 }
 
-func (fake *FakeStore) MarkCompleted(arg1 context.Context, arg2 uuid.UUID) error {
-	fake.markCompletedMutex.Lock()
-	ret, specificReturn := fake.markCompletedReturnsOnCall[len(fake.markCompletedArgsForCall)]
-	fake.markCompletedArgsForCall = append(fake.markCompletedArgsForCall, struct {
+func (fake *FakeStore) CompleteMessage(arg1 context.Context, arg2 uuid.UUID, arg3 string) error {
+	fake.completeMessageMutex.Lock()
+	ret, specificReturn := fake.completeMessageReturnsOnCall[len(fake.completeMessageArgsForCall)]
+	fake.completeMessageArgsForCall = append(fake.completeMessageArgsForCall, struct {
 		arg1 context.Context
 		arg2 uuid.UUID
-	}{arg1, arg2})
-	stub := fake.MarkCompletedStub
-	fakeReturns := fake.markCompletedReturns
-	fake.recordInvocation("MarkCompleted", []interface{}{arg1, arg2})
-	fake.markCompletedMutex.Unlock()
+		arg3 string
+	}{arg1, arg2, arg3})
+	stub := fake.CompleteMessageStub
+	fakeReturns := fake.completeMessageReturns
+	fake.recordInvocation("CompleteMessage", []interface{}{arg1, arg2, arg3})
+	fake.completeMessageMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2)
+		return stub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1
@@ -147,126 +160,126 @@ func (fake *FakeStore) MarkCompleted(arg1 context.Context, arg2 uuid.UUID) error
 	return fakeReturns.result1
 }
 
-func (fake *FakeStore) MarkCompletedCallCount() int {
-	fake.markCompletedMutex.RLock()
-	defer fake.markCompletedMutex.RUnlock()
-	return len(fake.markCompletedArgsForCall)
+func (fake *FakeStore) CompleteMessageCallCount() int {
+	fake.completeMessageMutex.RLock()
+	defer fake.completeMessageMutex.RUnlock()
+	return len(fake.completeMessageArgsForCall)
 }
 
-func (fake *FakeStore) MarkCompletedCalls(stub func(context.Context, uuid.UUID) error) {
-	fake.markCompletedMutex.Lock()
-	defer fake.markCompletedMutex.Unlock()
-	fake.MarkCompletedStub = stub
+func (fake *FakeStore) CompleteMessageCalls(stub func(context.Context, uuid.UUID, string) error) {
+	fake.completeMessageMutex.Lock()
+	defer fake.completeMessageMutex.Unlock()
+	fake.CompleteMessageStub = stub
 }
 
-func (fake *FakeStore) MarkCompletedArgsForCall(i int) (context.Context, uuid.UUID) {
-	fake.markCompletedMutex.RLock()
-	defer fake.markCompletedMutex.RUnlock()
-	argsForCall := fake.markCompletedArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+func (fake *FakeStore) CompleteMessageArgsForCall(i int) (context.Context, uuid.UUID, string) {
+	fake.completeMessageMutex.RLock()
+	defer fake.completeMessageMutex.RUnlock()
+	argsForCall := fake.completeMessageArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
-func (fake *FakeStore) MarkCompletedReturns(result1 error) {
-	fake.markCompletedMutex.Lock()
-	defer fake.markCompletedMutex.Unlock()
-	fake.MarkCompletedStub = nil
-	fake.markCompletedReturns = struct {
+func (fake *FakeStore) CompleteMessageReturns(result1 error) {
+	fake.completeMessageMutex.Lock()
+	defer fake.completeMessageMutex.Unlock()
+	fake.CompleteMessageStub = nil
+	fake.completeMessageReturns = struct {
 		result1 error
 	}{result1}
 }
 
-func (fake *FakeStore) MarkCompletedReturnsOnCall(i int, result1 error) {
-	fake.markCompletedMutex.Lock()
-	defer fake.markCompletedMutex.Unlock()
-	fake.MarkCompletedStub = nil
-	if fake.markCompletedReturnsOnCall == nil {
-		fake.markCompletedReturnsOnCall = make(map[int]struct {
+func (fake *FakeStore) CompleteMessageReturnsOnCall(i int, result1 error) {
+	fake.completeMessageMutex.Lock()
+	defer fake.completeMessageMutex.Unlock()
+	fake.CompleteMessageStub = nil
+	if fake.completeMessageReturnsOnCall == nil {
+		fake.completeMessageReturnsOnCall = make(map[int]struct {
 			result1 error
 		})
 	}
-	fake.markCompletedReturnsOnCall[i] = struct {
+	fake.completeMessageReturnsOnCall[i] = struct {
 		result1 error
 	}{result1}
 }
 
-func (fake *FakeStore) MessageExists(arg1 context.Context, arg2 uuid.UUID) (bool, error) {
-	fake.messageExistsMutex.Lock()
-	ret, specificReturn := fake.messageExistsReturnsOnCall[len(fake.messageExistsArgsForCall)]
-	fake.messageExistsArgsForCall = append(fake.messageExistsArgsForCall, struct {
+func (fake *FakeStore) ExtendLease(arg1 context.Context, arg2 uuid.UUID, arg3 string, arg4 time.Duration) error {
+	fake.extendLeaseMutex.Lock()
+	ret, specificReturn := fake.extendLeaseReturnsOnCall[len(fake.extendLeaseArgsForCall)]
+	fake.extendLeaseArgsForCall = append(fake.extendLeaseArgsForCall, struct {
 		arg1 context.Context
 		arg2 uuid.UUID
-	}{arg1, arg2})
-	stub := fake.MessageExistsStub
-	fakeReturns := fake.messageExistsReturns
-	fake.recordInvocation("MessageExists", []interface{}{arg1, arg2})
-	fake.messageExistsMutex.Unlock()
+		arg3 string
+		arg4 time.Duration
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.ExtendLeaseStub
+	fakeReturns := fake.extendLeaseReturns
+	fake.recordInvocation("ExtendLease", []interface{}{arg1, arg2, arg3, arg4})
+	fake.extendLeaseMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2)
+		return stub(arg1, arg2, arg3, arg4)
 	}
 	if specificReturn {
-		return ret.result1, ret.result2
+		return ret.result1
 	}
-	return fakeReturns.result1, fakeReturns.result2
+	return fakeReturns.result1
 }
 
-func (fake *FakeStore) MessageExistsCallCount() int {
-	fake.messageExistsMutex.RLock()
-	defer fake.messageExistsMutex.RUnlock()
-	return len(fake.messageExistsArgsForCall)
+func (fake *FakeStore) ExtendLeaseCallCount() int {
+	fake.extendLeaseMutex.RLock()
+	defer fake.extendLeaseMutex.RUnlock()
+	return len(fake.extendLeaseArgsForCall)
 }
 
-func (fake *FakeStore) MessageExistsCalls(stub func(context.Context, uuid.UUID) (bool, error)) {
-	fake.messageExistsMutex.Lock()
-	defer fake.messageExistsMutex.Unlock()
-	fake.MessageExistsStub = stub
+func (fake *FakeStore) ExtendLeaseCalls(stub func(context.Context, uuid.UUID, string, time.Duration) error) {
+	fake.extendLeaseMutex.Lock()
+	defer fake.extendLeaseMutex.Unlock()
+	fake.ExtendLeaseStub = stub
 }
 
-func (fake *FakeStore) MessageExistsArgsForCall(i int) (context.Context, uuid.UUID) {
-	fake.messageExistsMutex.RLock()
-	defer fake.messageExistsMutex.RUnlock()
-	argsForCall := fake.messageExistsArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+func (fake *FakeStore) ExtendLeaseArgsForCall(i int) (context.Context, uuid.UUID, string, time.Duration) {
+	fake.extendLeaseMutex.RLock()
+	defer fake.extendLeaseMutex.RUnlock()
+	argsForCall := fake.extendLeaseArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
 }
 
-func (fake *FakeStore) MessageExistsReturns(result1 bool, result2 error) {
-	fake.messageExistsMutex.Lock()
-	defer fake.messageExistsMutex.Unlock()
-	fake.MessageExistsStub = nil
-	fake.messageExistsReturns = struct {
-		result1 bool
-		result2 error
-	}{result1, result2}
+func (fake *FakeStore) ExtendLeaseReturns(result1 error) {
+	fake.extendLeaseMutex.Lock()
+	defer fake.extendLeaseMutex.Unlock()
+	fake.ExtendLeaseStub = nil
+	fake.extendLeaseReturns = struct {
+		result1 error
+	}{result1}
 }
 
-func (fake *FakeStore) MessageExistsReturnsOnCall(i int, result1 bool, result2 error) {
-	fake.messageExistsMutex.Lock()
-	defer fake.messageExistsMutex.Unlock()
-	fake.MessageExistsStub = nil
-	if fake.messageExistsReturnsOnCall == nil {
-		fake.messageExistsReturnsOnCall = make(map[int]struct {
-			result1 bool
-			result2 error
+func (fake *FakeStore) ExtendLeaseReturnsOnCall(i int, result1 error) {
+	fake.extendLeaseMutex.Lock()
+	defer fake.extendLeaseMutex.Unlock()
+	fake.ExtendLeaseStub = nil
+	if fake.extendLeaseReturnsOnCall == nil {
+		fake.extendLeaseReturnsOnCall = make(map[int]struct {
+			result1 error
 		})
 	}
-	fake.messageExistsReturnsOnCall[i] = struct {
-		result1 bool
-		result2 error
-	}{result1, result2}
+	fake.extendLeaseReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
 }
 
-func (fake *FakeStore) RunInAtomically(arg1 context.Context, arg2 func(context.Context) error) error {
-	fake.runInAtomicallyMutex.Lock()
-	ret, specificReturn := fake.runInAtomicallyReturnsOnCall[len(fake.runInAtomicallyArgsForCall)]
-	fake.runInAtomicallyArgsForCall = append(fake.runInAtomicallyArgsForCall, struct {
+func (fake *FakeStore) ReleaseMessage(arg1 context.Context, arg2 uuid.UUID, arg3 string) error {
+	fake.releaseMessageMutex.Lock()
+	ret, specificReturn := fake.releaseMessageReturnsOnCall[len(fake.releaseMessageArgsForCall)]
+	fake.releaseMessageArgsForCall = append(fake.releaseMessageArgsForCall, struct {
 		arg1 context.Context
-		arg2 func(context.Context) error
-	}{arg1, arg2})
-	stub := fake.RunInAtomicallyStub
-	fakeReturns := fake.runInAtomicallyReturns
-	fake.recordInvocation("RunInAtomically", []interface{}{arg1, arg2})
-	fake.runInAtomicallyMutex.Unlock()
+		arg2 uuid.UUID
+		arg3 string
+	}{arg1, arg2, arg3})
+	stub := fake.ReleaseMessageStub
+	fakeReturns := fake.releaseMessageReturns
+	fake.recordInvocation("ReleaseMessage", []interface{}{arg1, arg2, arg3})
+	fake.releaseMessageMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2)
+		return stub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1
@@ -274,44 +287,44 @@ func (fake *FakeStore) RunInAtomically(arg1 context.Context, arg2 func(context.C
 	return fakeReturns.result1
 }
 
-func (fake *FakeStore) RunInAtomicallyCallCount() int {
-	fake.runInAtomicallyMutex.RLock()
-	defer fake.runInAtomicallyMutex.RUnlock()
-	return len(fake.runInAtomicallyArgsForCall)
+func (fake *FakeStore) ReleaseMessageCallCount() int {
+	fake.releaseMessageMutex.RLock()
+	defer fake.releaseMessageMutex.RUnlock()
+	return len(fake.releaseMessageArgsForCall)
 }
 
-func (fake *FakeStore) RunInAtomicallyCalls(stub func(context.Context, func(context.Context) error) error) {
-	fake.runInAtomicallyMutex.Lock()
-	defer fake.runInAtomicallyMutex.Unlock()
-	fake.RunInAtomicallyStub = stub
+func (fake *FakeStore) ReleaseMessageCalls(stub func(context.Context, uuid.UUID, string) error) {
+	fake.releaseMessageMutex.Lock()
+	defer fake.releaseMessageMutex.Unlock()
+	fake.ReleaseMessageStub = stub
 }
 
-func (fake *FakeStore) RunInAtomicallyArgsForCall(i int) (context.Context, func(context.Context) error) {
-	fake.runInAtomicallyMutex.RLock()
-	defer fake.runInAtomicallyMutex.RUnlock()
-	argsForCall := fake.runInAtomicallyArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+func (fake *FakeStore) ReleaseMessageArgsForCall(i int) (context.Context, uuid.UUID, string) {
+	fake.releaseMessageMutex.RLock()
+	defer fake.releaseMessageMutex.RUnlock()
+	argsForCall := fake.releaseMessageArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
-func (fake *FakeStore) RunInAtomicallyReturns(result1 error) {
-	fake.runInAtomicallyMutex.Lock()
-	defer fake.runInAtomicallyMutex.Unlock()
-	fake.RunInAtomicallyStub = nil
-	fake.runInAtomicallyReturns = struct {
+func (fake *FakeStore) ReleaseMessageReturns(result1 error) {
+	fake.releaseMessageMutex.Lock()
+	defer fake.releaseMessageMutex.Unlock()
+	fake.ReleaseMessageStub = nil
+	fake.releaseMessageReturns = struct {
 		result1 error
 	}{result1}
 }
 
-func (fake *FakeStore) RunInAtomicallyReturnsOnCall(i int, result1 error) {
-	fake.runInAtomicallyMutex.Lock()
-	defer fake.runInAtomicallyMutex.Unlock()
-	fake.RunInAtomicallyStub = nil
-	if fake.runInAtomicallyReturnsOnCall == nil {
-		fake.runInAtomicallyReturnsOnCall = make(map[int]struct {
+func (fake *FakeStore) ReleaseMessageReturnsOnCall(i int, result1 error) {
+	fake.releaseMessageMutex.Lock()
+	defer fake.releaseMessageMutex.Unlock()
+	fake.ReleaseMessageStub = nil
+	if fake.releaseMessageReturnsOnCall == nil {
+		fake.releaseMessageReturnsOnCall = make(map[int]struct {
 			result1 error
 		})
 	}
-	fake.runInAtomicallyReturnsOnCall[i] = struct {
+	fake.releaseMessageReturnsOnCall[i] = struct {
 		result1 error
 	}{result1}
 }
@@ -319,14 +332,14 @@ func (fake *FakeStore) RunInAtomicallyReturnsOnCall(i int, result1 error) {
 func (fake *FakeStore) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
-	fake.addMessageMutex.RLock()
-	defer fake.addMessageMutex.RUnlock()
-	fake.markCompletedMutex.RLock()
-	defer fake.markCompletedMutex.RUnlock()
-	fake.messageExistsMutex.RLock()
-	defer fake.messageExistsMutex.RUnlock()
-	fake.runInAtomicallyMutex.RLock()
-	defer fake.runInAtomicallyMutex.RUnlock()
+	fake.claimMessageMutex.RLock()
+	defer fake.claimMessageMutex.RUnlock()
+	fake.completeMessageMutex.RLock()
+	defer fake.completeMessageMutex.RUnlock()
+	fake.extendLeaseMutex.RLock()
+	defer fake.extendLeaseMutex.RUnlock()
+	fake.releaseMessageMutex.RLock()
+	defer fake.releaseMessageMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/model"
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/templating"
+	"github.com/google/uuid"
+)
+
+// lineageKey is the context key of the names of the definitions a run is nested in,
+// its own included.
+type lineageKey struct{}
+
+func lineageOf(ctx context.Context) []string {
+	lineage, _ := ctx.Value(lineageKey{}).([]string)
+	return lineage
+}
+
+func withLineage(ctx context.Context, lineage []string) context.Context {
+	return context.WithValue(ctx, lineageKey{}, lineage)
+}
+
+// SubprocessResult is what a processCmd task writes to stdout once its child run is
+// over. Outputs of the child tasks can be captured from it with jsonPath, e.g.
+// "$.tasks.build.outputs.version".
+type SubprocessResult struct {
+	RunID   uuid.UUID                       `json:"run_id"`
+	Process string                          `json:"process"`
+	Status  model.ProcessStatus             `json:"status"`
+	Tasks   map[string]SubprocessTaskResult `json:"tasks"`
+}
+
+type SubprocessTaskResult struct {
+	Status  model.TaskStatus  `json:"status"`
+	Outputs map[string]string `json:"outputs,omitempty"`
+}
+
+// subprocessExecutor runs the definition of a processCmd task as a child run of the
+// run the task belongs to. The child run is executed right away by the same consumer.
+type subprocessExecutor struct {
+	service  *Service
+	parentID uuid.UUID
+	// recordCtx is the context the parent run is recorded with.
+	recordCtx context.Context
+}
+
+func (e *subprocessExecutor) Run(ctx context.Context, task model.Task, stdout, _ io.Writer) (model.TaskOutput, error) {
+	def := task.Subprocess
+	if def == nil {
+		return model.TaskOutput{}, fmt.Errorf("process %q of task %q was not resolved", task.Parameters[model.ProcessParam], task.Name)
+	}
+
+	// The definitions were checked when the run was started. This only guards
+	// against runs that were queued with definitions not checked that way.
+	lineage := lineageOf(ctx)
+	chain := append(slices.Clone(lineage), def.Name)
+	if slices.Contains(lineage, def.Name) {
+		return model.TaskOutput{}, fmt.Errorf("cycle between process definitions: %s", strings.Join(chain, " -> "))
+	}
+	if len(chain) > model.MaxProcessDepth {
+		return model.TaskOutput{}, fmt.Errorf("process definitions are nested deeper than %d: %s", model.MaxProcessDepth, strings.Join(chain, " -> "))
+	}
+
+	inputs := maps.Clone(task.Parameters)
+	delete(inputs, model.ProcessParam)
+	rendered, err := renderDefinition(*def, inputs)
+	if err != nil {
+		return model.TaskOutput{}, fmt.Errorf("failed to apply parameters to process %s: %w", def.Name, err)
+	}
+
+	// The child run is recorded even if the task is cancelled, like the tasks of the parent.
+	recordCtx := e.recordCtx
+	store := e.service.processStore
+	child := model.ProcessRun{
+		ID:         uuid.New(),
+		Definition: rendered,
+		Status:     model.StatusRunning,
+		StartedAt:  time.Now(),
+		ParentID:   &e.parentID,
+		ParentTask: task.Name,
+	}
+	if err := store.InsertProcess(recordCtx, child); err != nil {
+		return model.TaskOutput{}, fmt.Errorf("failed to insert sub-process: %w", err)
+	}
+
+	msg := fmt.Sprintf("Task %s started process %s as run %s", task.Name, def.Name, child.ID)
+	if err := store.AppendProcessLog(recordCtx, e.parentID, msg); err != nil {
+		return model.TaskOutput{}, fmt.Errorf("failed to append to process log: %w", err)
+	}
+
+	runErr := e.service.executeRun(recordCtx, ctx, child.ID, 1, rendered, nil, false)
+
+	result, err := e.result(recordCtx, child)
+	if err != nil {
+		return model.TaskOutput{}, err
+	}
+	encoded, err := json.Marshal(result)
+	if err != nil {
+		return model.TaskOutput{}, fmt.Errorf("failed to encode sub-process result: %w", err)
+	}
+	output := model.TaskOutput{Stdout: string(encoded)}
+	_, _ = stdout.Write(encoded)
+
+	switch result.Status {
+	case model.StatusCompleted, model.StatusCompletedWithErrors:
+		return output, nil
+	}
+	if runErr != nil {
+		return output, fmt.Errorf("process %s ended as %s: %w", def.Name, result.Status, runErr)
+	}
+	return output, fmt.Errorf("process %s ended as %s", def.Name, result.Status)
+}
+
+// result collects the status of the child run and the latest results of its tasks.
+func (e *subprocessExecutor) result(ctx context.Context, child model.ProcessRun) (SubprocessResult, error) {
+	run, err := e.service.processStore.GetProcessByID(ctx, child.ID)
+	if err != nil {
+		return SubprocessResult{}, fmt.Errorf("failed to get sub-process: %w", err)
+	}
+	taskRuns, err := e.service.processStore.GetTaskRuns(ctx, child.ID)
+	if err != nil {
+		return SubprocessResult{}, fmt.Errorf("failed to get task runs of sub-process: %w", err)
+	}
+
+	result := SubprocessResult{
+		RunID:   child.ID,
+		Process: child.Definition.Name,
+		Status:  run.Status,
+		Tasks:   make(map[string]SubprocessTaskResult, len(taskRuns)),
+	}
+	for name, taskRun := range model.LatestTaskRuns(taskRuns) {
+		result.Tasks[name] = SubprocessTaskResult{Status: taskRun.Status, Outputs: taskRun.Outputs}
+	}
+	return result, nil
+}
+
+// renderDefinition renders the tasks of def with the inputs the processCmd task maps to it.
+func renderDefinition(def model.ProcessDefinition, inputs map[string]string) (model.ProcessDefinition, error) {
+	tasks, err := templating.Apply(def.Tasks, inputs)
+	if err != nil {
+		return model.ProcessDefinition{}, err
+	}
+	def.Tasks = tasks
+
+	if len(def.Finally) > 0 {
+		finally, err := templating.Apply(def.Finally, inputs)
+		if err != nil {
+			return model.ProcessDefinition{}, err
+		}
+		def.Finally = finally
+	}
+	return def, nil
+}
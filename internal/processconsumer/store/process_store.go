@@ -2,17 +2,30 @@ package store
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
 	"time"
 
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/logger"
 	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/model"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 var (
 	ProcessRunsTable = "process_runs"
 	ProcessLogsTable = "process_logs"
+	TaskRunsTable    = "task_runs"
+	RunAttemptsTable = "process_run_attempts"
+)
+
+const (
+	// StopChannel is the Postgres notification channel used to broadcast stop requests to all consumers.
+	StopChannel = "process_stop"
+	// PauseChannel is the Postgres notification channel used to broadcast pause requests to all consumers.
+	PauseChannel = "process_pause"
 )
 
 type ProcessDBStore struct {
@@ -23,52 +36,582 @@ func NewProcessDBStore(pool *pgxpool.Pool) *ProcessDBStore {
 	return &ProcessDBStore{pool: pool}
 }
 
+// InsertProcess records a started run. It takes over the run with the same ID if it was
+// queued by the producer.
 func (s *ProcessDBStore) InsertProcess(ctx context.Context, run model.ProcessRun) error {
 	query := fmt.Sprintf(`
-		INSERT INTO %s (id, definition, status, started_at)
-		VALUES ($1, $2, $3, $4)
+		INSERT INTO %[1]s (id, definition, status, started_at, parent_id, parent_task)
+		VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''))
+		ON CONFLICT (id) DO UPDATE
+		SET definition = EXCLUDED.definition, status = EXCLUDED.status, started_at = EXCLUDED.started_at
+		WHERE %[1]s.status = $7
 	`, ProcessRunsTable)
 
-	_, err := s.pool.Exec(ctx, query, run.ID, run.Definition, run.Status, run.StartedAt)
-	return err
+	tag, err := s.pool.Exec(ctx, query,
+		run.ID, run.Definition, run.Status, run.StartedAt, run.ParentID, run.ParentTask, model.StatusQueued,
+	)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("process %s was already started", run.ID)
+	}
+	return nil
 }
 
-func (s *ProcessDBStore) UpdateProcessStatus(ctx context.Context, id uuid.UUID, status model.ProcessStatus) error {
+// UpdateProcessStatus records the status the given attempt of a running process ended with.
+// It returns model.ErrOwnershipLost if the attempt is no longer running.
+func (s *ProcessDBStore) UpdateProcessStatus(ctx context.Context, id uuid.UUID, attempt int, status model.ProcessStatus) error {
 	query := fmt.Sprintf(`
-		UPDATE %s SET status = $1, ended_at = $2 WHERE id = $3
+		UPDATE %s SET status = $1, ended_at = $2 WHERE id = $3 AND attempt = $4 AND status = $5
 	`, ProcessRunsTable)
 	
 	completedAt := time.Now()
-	_, err := s.pool.Exec(ctx, query, status, &completedAt, id)
-	return err
+	tag, err := s.pool.Exec(ctx, query, status, &completedAt, id, attempt, model.StatusRunning)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return model.ErrOwnershipLost
+	}
+	return nil
+}
+
+// AbandonProcess marks the given attempt of a running process as abandoned and cancels its
+// unfinished tasks with the given reason. The running sub-process runs it started are abandoned along with it,
+// as they were executed by the same consumer.
+func (s *ProcessDBStore) AbandonProcess(ctx context.Context, id uuid.UUID, attempt int, reason string) error {
+	return pgx.BeginFunc(ctx, s.pool, func(tx pgx.Tx) error {
+		now := time.Now()
+		query := fmt.Sprintf(`
+			WITH RECURSIVE runs AS (
+				SELECT id FROM %[1]s WHERE id = $3 AND status = $4 AND attempt = $5
+				UNION
+				SELECT child.id FROM %[1]s child JOIN runs ON child.parent_id = runs.id
+				WHERE child.status = $4
+			)
+			UPDATE %[1]s SET status = $1, ended_at = $2 WHERE id IN (SELECT id FROM runs)
+			RETURNING id
+		`, ProcessRunsTable)
+		rows, err := tx.Query(ctx, query, model.StatusAbandoned, now, id, model.StatusRunning, attempt)
+		if err != nil {
+			return fmt.Errorf("failed to abandon process: %w", err)
+		}
+		var abandoned []uuid.UUID
+		for rows.Next() {
+			var runID uuid.UUID
+			if err := rows.Scan(&runID); err != nil {
+				rows.Close()
+				return fmt.Errorf("failed to scan abandoned process: %w", err)
+			}
+			abandoned = append(abandoned, runID)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("failed to abandon process: %w", err)
+		}
+		if !slices.Contains(abandoned, id) {
+			return model.ErrProcessNotRunning
+		}
+
+		query = fmt.Sprintf(`
+			UPDATE %s SET status = $1, error = $2, ended_at = $3
+			WHERE process_id = ANY($4) AND status IN ($5, $6, $7)
+		`, TaskRunsTable)
+		_, err = tx.Exec(ctx, query,
+			model.TaskStatusCancelled, reason, now, abandoned,
+			model.TaskStatusPending, model.TaskStatusRunning, model.TaskStatusAwaitingApproval,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to cancel tasks: %w", err)
+		}
+		return nil
+	})
+}
+
+// RequestStop records the stop request on a running process and notifies all consumers about it.
+// A paused run or one awaiting approval is owned by no consumer, so it is stopped right away
+// along with its unfinished tasks.
+func (s *ProcessDBStore) RequestStop(ctx context.Context, id uuid.UUID, reason string) error {
+	return pgx.BeginFunc(ctx, s.pool, func(tx pgx.Tx) error {
+		now := time.Now()
+		query := fmt.Sprintf(`
+			UPDATE %s SET stop_reason = $1, stop_requested_at = $2,
+				status = CASE WHEN status = $4 THEN status ELSE $5 END,
+				ended_at = CASE WHEN status = $4 THEN ended_at ELSE $2 END
+			WHERE id = $3 AND status IN ($4, $6, $7)
+			RETURNING status
+		`, ProcessRunsTable)
+
+		var status model.ProcessStatus
+		err := tx.QueryRow(ctx, query,
+			reason, now, id, model.StatusRunning, model.StatusStopped, model.StatusPaused, model.StatusAwaitingApproval,
+		).Scan(&status)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.ErrProcessNotRunning
+		}
+		if err != nil {
+			return fmt.Errorf("failed to request process stop: %w", err)
+		}
+
+		if status == model.StatusRunning {
+			if _, err := tx.Exec(ctx, "SELECT pg_notify($1, $2)", StopChannel, id.String()); err != nil {
+				return fmt.Errorf("failed to notify process stop: %w", err)
+			}
+			return nil
+		}
+
+		query = fmt.Sprintf(`
+			UPDATE %s SET status = $1, error = $2, ended_at = $3
+			WHERE process_id = $4 AND status IN ($5, $6)
+		`, TaskRunsTable)
+		_, err = tx.Exec(ctx, query,
+			model.TaskStatusCancelled, reason, now, id, model.TaskStatusPending, model.TaskStatusAwaitingApproval,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to cancel tasks: %w", err)
+		}
+		return appendProcessLog(ctx, tx, id, "Process stopped on request")
+	})
+}
+
+// RequestPause records the pause request on a running process and notifies all consumers about it.
+func (s *ProcessDBStore) RequestPause(ctx context.Context, id uuid.UUID) error {
+	query := fmt.Sprintf(`
+		WITH requested AS (
+			UPDATE %s SET pause_requested_at = $1
+			WHERE id = $2 AND status = $3
+			RETURNING id
+		)
+		SELECT pg_notify($4, id::text) FROM requested
+	`, ProcessRunsTable)
+
+	tag, err := s.pool.Exec(ctx, query, time.Now(), id, model.StatusRunning, PauseChannel)
+	if err != nil {
+		return fmt.Errorf("failed to request process pause: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return model.ErrProcessNotRunning
+	}
+	return nil
+}
+
+// ResumeProcess calls resume with a paused run before committing that its resume was requested.
+// A run is only resumed once until it continues, so a second request for it fails.
+func (s *ProcessDBStore) ResumeProcess(ctx context.Context, id uuid.UUID, resume func(context.Context, model.ProcessRun) error) error {
+	return pgx.BeginFunc(ctx, s.pool, func(tx pgx.Tx) error {
+		run, err := lockProcess(ctx, tx, id)
+		if errors.Is(err, pgx.ErrNoRows) || (err == nil && run.Status != model.StatusPaused) {
+			return model.ErrProcessNotPaused
+		}
+		if err != nil {
+			return err
+		}
+
+		query := fmt.Sprintf(`
+			UPDATE %s SET resume_requested_at = $1 WHERE id = $2 AND resume_requested_at IS NULL
+		`, ProcessRunsTable)
+		tag, err := tx.Exec(ctx, query, time.Now(), id)
+		if err != nil {
+			return fmt.Errorf("failed to request resume: %w", err)
+		}
+		if tag.RowsAffected() == 0 {
+			return model.ErrProcessNotPaused
+		}
+
+		if err := appendProcessLog(ctx, tx, id, "Resume requested"); err != nil {
+			return err
+		}
+		return resume(ctx, run)
+	})
+}
+
+// CancelScheduledProcess cancels a run that waits for its run_at time. Runs that were
+// queued already cannot be cancelled.
+func (s *ProcessDBStore) CancelScheduledProcess(ctx context.Context, id uuid.UUID, reason string) error {
+	query := fmt.Sprintf(`
+		UPDATE %s SET status = $1, ended_at = $2, stop_reason = $3
+		WHERE id = $4 AND status = $5
+	`, ProcessRunsTable)
+
+	tag, err := s.pool.Exec(ctx, query, model.StatusCancelled, time.Now(), reason, id, model.StatusScheduled)
+	if err != nil {
+		return fmt.Errorf("failed to cancel process: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return model.ErrProcessNotScheduled
+	}
+	return nil
+}
+
+// listenRetryDelay is how long the listener waits before it reconnects.
+const listenRetryDelay = 5 * time.Second
+
+// ListenRunRequests blocks until the context is done, calling onStop and onPause for
+// every broadcast stop and pause request. It reconnects when the connection is lost.
+func (s *ProcessDBStore) ListenRunRequests(ctx context.Context, onStop, onPause func(uuid.UUID) bool) error {
+	for {
+		err := s.listenRunRequests(ctx, onStop, onPause)
+		if ctx.Err() != nil {
+			return nil
+		}
+		logger.GetLogger().Warnf("run request listener failed, reconnecting in %s: %v", listenRetryDelay, err)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(listenRetryDelay):
+		}
+	}
+}
+
+func (s *ProcessDBStore) listenRunRequests(ctx context.Context, onStop, onPause func(uuid.UUID) bool) error {
+	conn, err := s.pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	defer conn.Release()
+
+	for _, channel := range []string{StopChannel, PauseChannel} {
+		if _, err := conn.Exec(ctx, "LISTEN "+channel); err != nil {
+			return fmt.Errorf("failed to listen on %s: %w", channel, err)
+		}
+	}
+
+	// Requests notified while no connection was listening are only recorded on the runs.
+	if err := s.replayRunRequests(ctx, onStop, onPause); err != nil {
+		return err
+	}
+
+	for {
+		notification, err := conn.Conn().WaitForNotification(ctx)
+		if err != nil {
+			return fmt.Errorf("failed waiting for run request notification: %w", err)
+		}
+
+		id, err := uuid.Parse(notification.Payload)
+		if err != nil {
+			logger.GetLogger().Warnf("ignoring malformed %s notification %q: %v", notification.Channel, notification.Payload, err)
+			continue
+		}
+
+		switch notification.Channel {
+		case StopChannel:
+			if onStop(id) {
+				logger.GetLogger().Infof("Cancelling process %s on stop request", id)
+			}
+		case PauseChannel:
+			if onPause(id) {
+				logger.GetLogger().Infof("Pausing process %s on pause request", id)
+			}
+		}
+	}
+}
+
+// replayRunRequests calls onStop and onPause for the running runs a stop or pause was requested for.
+func (s *ProcessDBStore) replayRunRequests(ctx context.Context, onStop, onPause func(uuid.UUID) bool) error {
+	query := fmt.Sprintf(`
+		SELECT id, stop_requested_at IS NOT NULL, pause_requested_at IS NOT NULL
+		FROM %s
+		WHERE status = $1 AND (stop_requested_at IS NOT NULL OR pause_requested_at IS NOT NULL)
+	`, ProcessRunsTable)
+
+	rows, err := s.pool.Query(ctx, query, model.StatusRunning)
+	if err != nil {
+		return fmt.Errorf("failed to list run requests: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			id          uuid.UUID
+			stop, pause bool
+		)
+		if err := rows.Scan(&id, &stop, &pause); err != nil {
+			return fmt.Errorf("failed to scan run request: %w", err)
+		}
+		if stop && onStop(id) {
+			logger.GetLogger().Infof("Cancelling process %s on stop request", id)
+		}
+		if pause && onPause(id) {
+			logger.GetLogger().Infof("Pausing process %s on pause request", id)
+		}
+	}
+	return rows.Err()
+}
+
+// BeginRetry archives the current attempt of a finished run in the attempt history
+// and starts its next attempt. It returns the number of the new attempt.
+func (s *ProcessDBStore) BeginRetry(ctx context.Context, id uuid.UUID) (int, error) {
+	var attempt int
+	err := pgx.BeginFunc(ctx, s.pool, func(tx pgx.Tx) error {
+		var current model.ProcessRunAttempt
+		query := fmt.Sprintf(`
+			SELECT attempt, status, started_at, ended_at FROM %s WHERE id = $1 FOR UPDATE
+		`, ProcessRunsTable)
+		if err := tx.QueryRow(ctx, query, id).Scan(&current.Attempt, &current.Status, &current.StartedAt, &current.EndedAt); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return model.ErrProcessNotRetryable
+			}
+			return err
+		}
+		if !slices.Contains(model.RetryableStatuses, current.Status) {
+			return model.ErrProcessNotRetryable
+		}
+
+		query = fmt.Sprintf(`
+			INSERT INTO %s (process_id, attempt, status, started_at, ended_at)
+			VALUES ($1, $2, $3, $4, $5)
+		`, RunAttemptsTable)
+		if _, err := tx.Exec(ctx, query, id, current.Attempt, current.Status, current.StartedAt, current.EndedAt); err != nil {
+			return err
+		}
+
+		attempt = current.Attempt + 1
+		query = fmt.Sprintf(`
+			UPDATE %s
+			SET status = $1, attempt = $2, started_at = $3, ended_at = NULL, stop_reason = NULL, stop_requested_at = NULL,
+				pause_requested_at = NULL, resume_requested_at = NULL
+			WHERE id = $4
+		`, ProcessRunsTable)
+		_, err := tx.Exec(ctx, query, model.StatusRunning, attempt, time.Now(), id)
+		return err
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin retry: %w", err)
+	}
+	return attempt, nil
+}
+
+// BeginContinue starts running a run that was parked for approval or paused again.
+// Unlike a retry it stays in the same attempt, which it returns.
+func (s *ProcessDBStore) BeginContinue(ctx context.Context, id uuid.UUID) (int, error) {
+	query := fmt.Sprintf(`
+		UPDATE %s SET status = $1, ended_at = NULL, pause_requested_at = NULL, resume_requested_at = NULL
+		WHERE id = $2 AND status IN ($3, $4)
+		RETURNING attempt
+	`, ProcessRunsTable)
+
+	var attempt int
+	err := s.pool.QueryRow(ctx, query, model.StatusRunning, id, model.StatusAwaitingApproval, model.StatusPaused).Scan(&attempt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, model.ErrProcessNotParked
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to continue process: %w", err)
+	}
+	return attempt, nil
+}
+
+// DecideApproval records the decision on a task of a parked run and calls resume with
+// the run before committing it. An approved task succeeds, a rejected one fails.
+func (s *ProcessDBStore) DecideApproval(
+	ctx context.Context,
+	id uuid.UUID,
+	taskName string,
+	decision model.ApprovalDecision,
+	resume func(context.Context, model.ProcessRun) error,
+) error {
+	return pgx.BeginFunc(ctx, s.pool, func(tx pgx.Tx) error {
+		// The lock keeps the run from being continued before the decision is visible.
+		run, err := lockProcess(ctx, tx, id)
+		if errors.Is(err, pgx.ErrNoRows) || (err == nil && run.Status != model.StatusAwaitingApproval) {
+			return model.ErrProcessNotAwaitingApproval
+		}
+		if err != nil {
+			return err
+		}
+
+		status, taskErr, verdict := model.TaskStatusSucceeded, "", "approved"
+		if !decision.Approved {
+			status, taskErr, verdict = model.TaskStatusFailed, "rejected by "+decision.By, "rejected"
+			if decision.Comment != "" {
+				taskErr += ": " + decision.Comment
+			}
+		}
+
+		now := time.Now()
+		query := fmt.Sprintf(`
+			UPDATE %s SET status = $1, error = $2, decided_by = $3, decided_at = $4, ended_at = $4
+			WHERE process_id = $5 AND name = $6 AND status = $7
+		`, TaskRunsTable)
+		tag, err := tx.Exec(ctx, query, status, taskErr, decision.By, now, id, taskName, model.TaskStatusAwaitingApproval)
+		if err != nil {
+			return fmt.Errorf("failed to record decision: %w", err)
+		}
+		if tag.RowsAffected() == 0 {
+			return model.ErrTaskNotAwaitingApproval
+		}
+
+		log := fmt.Sprintf("Task %s %s by %s", taskName, verdict, decision.By)
+		if decision.Comment != "" {
+			log += ": " + decision.Comment
+		}
+		if err := appendProcessLog(ctx, tx, id, log); err != nil {
+			return err
+		}
+		return resume(ctx, run)
+	})
+}
+
+// ExpireApprovals marks the approvals that were not given in time as timed out and
+// calls resume for each of their runs before committing. It returns the number of
+// resumed runs. Runs locked by a decision or another consumer are left for later.
+func (s *ProcessDBStore) ExpireApprovals(
+	ctx context.Context,
+	now time.Time,
+	resume func(context.Context, model.ProcessRun) error,
+) (int, error) {
+	resumed := 0
+	err := pgx.BeginFunc(ctx, s.pool, func(tx pgx.Tx) error {
+		query := fmt.Sprintf(`
+			SELECT id, definition, status, attempt, priority FROM %[1]s r
+			WHERE status = $1 AND EXISTS (
+				SELECT 1 FROM %[2]s t
+				WHERE t.process_id = r.id AND t.status = $2 AND t.approval_expires_at <= $3
+			)
+			FOR UPDATE SKIP LOCKED
+		`, ProcessRunsTable, TaskRunsTable)
+		rows, err := tx.Query(ctx, query, model.StatusAwaitingApproval, model.TaskStatusAwaitingApproval, now)
+		if err != nil {
+			return fmt.Errorf("failed to select expired approvals: %w", err)
+		}
+		var runs []model.ProcessRun
+		for rows.Next() {
+			var run model.ProcessRun
+			if err := rows.Scan(&run.ID, &run.Definition, &run.Status, &run.Attempt, &run.Priority); err != nil {
+				rows.Close()
+				return err
+			}
+			runs = append(runs, run)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		query = fmt.Sprintf(`
+			UPDATE %s SET status = $1, error = $2, ended_at = $3
+			WHERE process_id = $4 AND status = $5 AND approval_expires_at <= $3
+			RETURNING name
+		`, TaskRunsTable)
+		for _, run := range runs {
+			rows, err := tx.Query(ctx, query, model.TaskStatusTimedOut, "approval expired", now, run.ID, model.TaskStatusAwaitingApproval)
+			if err != nil {
+				return fmt.Errorf("failed to expire approvals: %w", err)
+			}
+			var names []string
+			for rows.Next() {
+				var name string
+				if err := rows.Scan(&name); err != nil {
+					rows.Close()
+					return err
+				}
+				names = append(names, name)
+			}
+			rows.Close()
+			if err := rows.Err(); err != nil {
+				return err
+			}
+
+			for _, name := range names {
+				if err := appendProcessLog(ctx, tx, run.ID, fmt.Sprintf("Approval of task %s expired", name)); err != nil {
+					return err
+				}
+			}
+			if err := resume(ctx, run); err != nil {
+				return err
+			}
+			resumed++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return resumed, nil
+}
+
+// lockProcess locks a run, so that it cannot be continued until the transaction is over.
+func lockProcess(ctx context.Context, tx pgx.Tx, id uuid.UUID) (model.ProcessRun, error) {
+	var run model.ProcessRun
+	query := fmt.Sprintf(`
+		SELECT id, definition, status, attempt, priority FROM %s WHERE id = $1 FOR UPDATE
+	`, ProcessRunsTable)
+	err := tx.QueryRow(ctx, query, id).Scan(&run.ID, &run.Definition, &run.Status, &run.Attempt, &run.Priority)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return run, fmt.Errorf("failed to lock process: %w", err)
+	}
+	return run, err
+}
+
+func appendProcessLog(ctx context.Context, tx pgx.Tx, processID uuid.UUID, log string) error {
+	query := fmt.Sprintf(`
+		INSERT INTO %s (process_id, log) VALUES ($1, $2)
+	`, ProcessLogsTable)
+
+	if _, err := tx.Exec(ctx, query, processID, log); err != nil {
+		return fmt.Errorf("failed to append to process log: %w", err)
+	}
+	return nil
+}
+
+// GetRunAttempts returns the archived attempts of a run, oldest first.
+func (s *ProcessDBStore) GetRunAttempts(ctx context.Context, id uuid.UUID) ([]model.ProcessRunAttempt, error) {
+	query := fmt.Sprintf(`
+		SELECT attempt, status, started_at, ended_at FROM %s WHERE process_id = $1 ORDER BY attempt ASC
+	`, RunAttemptsTable)
+
+	rows, err := s.pool.Query(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var attempts []model.ProcessRunAttempt
+	for rows.Next() {
+		var a model.ProcessRunAttempt
+		if err := rows.Scan(&a.Attempt, &a.Status, &a.StartedAt, &a.EndedAt); err != nil {
+			return nil, err
+		}
+		attempts = append(attempts, a)
+	}
+	return attempts, nil
 }
 
 func (s *ProcessDBStore) GetProcessByID(ctx context.Context, id uuid.UUID) (model.ProcessRun, error) {
 	var (
-		run     model.ProcessRun
-		endedAt *time.Time
+		run        model.ProcessRun
+		endedAt    *time.Time
+		stopReason *string
 	)
 
 	query := fmt.Sprintf(`
-		SELECT id, definition, status, started_at, ended_at
+		SELECT id, definition, status, attempt, priority, started_at, ended_at, run_at, stop_reason, stop_requested_at,
+			pause_requested_at, parent_id, COALESCE(parent_task, '')
 		FROM %s WHERE id = $1
 	`, ProcessRunsTable)
 
 	err := s.pool.QueryRow(ctx, query, id).Scan(
-		&run.ID, &run.Definition, &run.Status, &run.StartedAt, &endedAt,
+		&run.ID, &run.Definition, &run.Status, &run.Attempt, &run.Priority, &run.StartedAt, &endedAt, &run.RunAt, &stopReason, &run.StopRequestedAt,
+		&run.PauseRequestedAt, &run.ParentID, &run.ParentTask,
 	)
 	if err != nil {
 		return model.ProcessRun{}, err
 	}
 
 	run.EndedAt = endedAt
+	if stopReason != nil {
+		run.StopReason = *stopReason
+	}
 	return run, nil
 }
 
 // Currenly lists all process, not only with status "running". The goal here is to have some processes to list.
 func (s *ProcessDBStore) ListRunningProcesses(ctx context.Context) ([]model.ProcessRun, error) {
 	query := fmt.Sprintf(`
-		SELECT id, definition, status, started_at, ended_at
+		SELECT id, definition, status, attempt, priority, started_at, ended_at, run_at, stop_reason, stop_requested_at,
+			pause_requested_at, parent_id, COALESCE(parent_task, '')
 		FROM %s ORDER BY started_at DESC
 	`, ProcessRunsTable)
 
@@ -82,12 +625,16 @@ func (s *ProcessDBStore) ListRunningProcesses(ctx context.Context) ([]model.Proc
 	for rows.Next() {
 		var run model.ProcessRun
 		var endedAt *time.Time
+		var stopReason *string
 
-		if err := rows.Scan(&run.ID, &run.Definition, &run.Status, &run.StartedAt, &endedAt); err != nil {
+		if err := rows.Scan(&run.ID, &run.Definition, &run.Status, &run.Attempt, &run.Priority, &run.StartedAt, &endedAt, &run.RunAt, &stopReason, &run.StopRequestedAt, &run.PauseRequestedAt, &run.ParentID, &run.ParentTask); err != nil {
 			return nil, err
 		}
 
 		run.EndedAt = endedAt
+		if stopReason != nil {
+			run.StopReason = *stopReason
+		}
 		results = append(results, run)
 	}
 
@@ -103,10 +650,24 @@ func (s *ProcessDBStore) AppendProcessLog(ctx context.Context, processID uuid.UU
 	return err
 }
 
+// AppendProcessLogs inserts a batch of log lines in a single round trip.
+func (s *ProcessDBStore) AppendProcessLogs(ctx context.Context, logs []model.ProcessLog) error {
+	_, err := s.pool.CopyFrom(
+		ctx,
+		pgx.Identifier{ProcessLogsTable},
+		[]string{"process_id", "task_name", "stream", "log", "created_at"},
+		pgx.CopyFromSlice(len(logs), func(i int) ([]any, error) {
+			l := logs[i]
+			return []any{l.ProcessID, l.TaskName, string(l.Stream), l.Log, l.CreatedAt}, nil
+		}),
+	)
+	return err
+}
+
 func (s *ProcessDBStore) GetProcessLogs(ctx context.Context, processID uuid.UUID) ([]model.ProcessLog, error) {
 	query := fmt.Sprintf(`
-		SELECT id, process_id, log, created_at FROM %s
-		WHERE process_id = $1 ORDER BY created_at ASC
+		SELECT id, process_id, COALESCE(task_name, ''), COALESCE(stream, ''), log, created_at FROM %s
+		WHERE process_id = $1 ORDER BY created_at ASC, id ASC
 	`, ProcessLogsTable)
 
 	rows, err := s.pool.Query(ctx, query, processID)
@@ -118,10 +679,83 @@ func (s *ProcessDBStore) GetProcessLogs(ctx context.Context, processID uuid.UUID
 	var logs []model.ProcessLog
 	for rows.Next() {
 		var l model.ProcessLog
-		if err := rows.Scan(&l.ID, &l.ProcessID, &l.Log, &l.CreatedAt); err != nil {
+		if err := rows.Scan(&l.ID, &l.ProcessID, &l.TaskName, &l.Stream, &l.Log, &l.CreatedAt); err != nil {
 			return nil, err
 		}
 		logs = append(logs, l)
 	}
 	return logs, nil
 }
+
+// InsertTaskRun records a task run of the given attempt of a running process. It returns
+// model.ErrOwnershipLost if the attempt is no longer running.
+func (s *ProcessDBStore) InsertTaskRun(ctx context.Context, attempt int, run model.TaskRun) error {
+	query := fmt.Sprintf(`
+		INSERT INTO %s (process_id, name, class, status, attempt, started_at)
+		SELECT $1, $2, $3, $4, $5, $6
+		WHERE EXISTS (SELECT 1 FROM %s WHERE id = $1 AND attempt = $7 AND status = $8)
+	`, TaskRunsTable, ProcessRunsTable)
+
+	tag, err := s.pool.Exec(ctx, query,
+		run.ProcessID, run.Name, run.Class, run.Status, run.Attempt, run.StartedAt, attempt, model.StatusRunning,
+	)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return model.ErrOwnershipLost
+	}
+	return nil
+}
+
+// UpdateTaskRun records the progress of a task run of the given attempt of a running process.
+// It returns model.ErrOwnershipLost if the attempt is no longer running.
+func (s *ProcessDBStore) UpdateTaskRun(ctx context.Context, attempt int, run model.TaskRun) error {
+	query := fmt.Sprintf(`
+		UPDATE %s
+		SET status = $1, exit_code = $2, stdout = $3, stderr = $4, error = $5, outputs = $6, started_at = $7, ended_at = $8,
+			approval_expires_at = $9
+		WHERE process_id = $10 AND name = $11 AND attempt = $12
+			AND EXISTS (SELECT 1 FROM %s WHERE id = $10 AND attempt = $13 AND status = $14)
+	`, TaskRunsTable, ProcessRunsTable)
+
+	tag, err := s.pool.Exec(ctx, query,
+		run.Status, run.ExitCode, run.Stdout, run.Stderr, run.Error, run.Outputs, run.StartedAt, run.EndedAt,
+		run.ApprovalExpiresAt, run.ProcessID, run.Name, run.Attempt, attempt, model.StatusRunning,
+	)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return model.ErrOwnershipLost
+	}
+	return nil
+}
+
+func (s *ProcessDBStore) GetTaskRuns(ctx context.Context, processID uuid.UUID) ([]model.TaskRun, error) {
+	query := fmt.Sprintf(`
+		SELECT id, process_id, name, class, status, attempt, exit_code, stdout, stderr, error, outputs, started_at, ended_at,
+			approval_expires_at, COALESCE(decided_by, ''), decided_at
+		FROM %s WHERE process_id = $1 ORDER BY id ASC
+	`, TaskRunsTable)
+
+	rows, err := s.pool.Query(ctx, query, processID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var runs []model.TaskRun
+	for rows.Next() {
+		var r model.TaskRun
+		if err := rows.Scan(
+			&r.ID, &r.ProcessID, &r.Name, &r.Class, &r.Status, &r.Attempt, &r.ExitCode,
+			&r.Stdout, &r.Stderr, &r.Error, &r.Outputs, &r.StartedAt, &r.EndedAt,
+			&r.ApprovalExpiresAt, &r.DecidedBy, &r.DecidedAt,
+		); err != nil {
+			return nil, err
+		}
+		runs = append(runs, r)
+	}
+	return runs, nil
+}
@@ -1,6 +1,10 @@
 package store_test
 
 import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
 	"time"
 
 	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/model"
@@ -22,6 +26,7 @@ var _ = Describe("ProcessDBStore", func() {
 		s = store.NewProcessDBStore(pool)
 
 		// Clean tables before test. TODO: adjust to proper cleanup in after each / just after each.
+		_, _ = pool.Exec(ctx, "DELETE FROM task_runs")
 		_, _ = pool.Exec(ctx, "DELETE FROM process_logs")
 		_, _ = pool.Exec(ctx, "DELETE FROM process_runs")
 
@@ -43,6 +48,57 @@ var _ = Describe("ProcessDBStore", func() {
 		It("succeeds", func() {
 			Expect(errAction).ToNot(HaveOccurred())
 		})
+
+		When("the run was queued by the producer", func() {
+			BeforeEach(func() {
+				queued := run
+				queued.Status = model.StatusQueued
+				queued.StartedAt = time.Now().Add(-time.Minute)
+				Expect(s.InsertProcess(ctx, queued)).To(Succeed())
+			})
+
+			It("takes it over", func() {
+				Expect(errAction).ToNot(HaveOccurred())
+
+				stored, err := s.GetProcessByID(ctx, runID)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(stored.Status).To(Equal(model.StatusRunning))
+				Expect(stored.StartedAt).To(BeTemporally("~", run.StartedAt, time.Second))
+			})
+		})
+
+		When("the run was started already", func() {
+			BeforeEach(func() {
+				Expect(s.InsertProcess(ctx, run)).To(Succeed())
+			})
+
+			It("returns an error", func() {
+				Expect(errAction).To(MatchError(ContainSubstring("already started")))
+			})
+		})
+
+		When("the run is a sub-process", func() {
+			var parentID uuid.UUID
+
+			BeforeEach(func() {
+				parentID = uuid.New()
+				parent := run
+				parent.ID = parentID
+				Expect(s.InsertProcess(ctx, parent)).To(Succeed())
+
+				run.ParentID = &parentID
+				run.ParentTask = "provision"
+			})
+
+			It("links it to its parent", func() {
+				Expect(errAction).ToNot(HaveOccurred())
+
+				stored, err := s.GetProcessByID(ctx, runID)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(stored.ParentID).To(Equal(&parentID))
+				Expect(stored.ParentTask).To(Equal("provision"))
+			})
+		})
 	})
 
 	Describe("UpdateProcessStatus", func() {
@@ -51,7 +107,7 @@ var _ = Describe("ProcessDBStore", func() {
 		})
 
 		JustBeforeEach(func() {
-			errAction = s.UpdateProcessStatus(ctx, runID, model.StatusCompleted)
+			errAction = s.UpdateProcessStatus(ctx, runID, 1, model.StatusCompleted)
 		})
 
 		It("succeeds", func() {
@@ -64,6 +120,628 @@ var _ = Describe("ProcessDBStore", func() {
 			Expect(stored.Status).To(Equal(model.StatusCompleted))
 			Expect(stored.EndedAt).ToNot(BeNil())
 		})
+
+		When("the process was abandoned", func() {
+			BeforeEach(func() {
+				Expect(s.AbandonProcess(ctx, runID, 1, "consumer lost")).To(Succeed())
+			})
+
+			It("returns an error", func() {
+				Expect(errAction).To(MatchError(model.ErrOwnershipLost))
+			})
+
+			It("keeps the status", func() {
+				stored, err := s.GetProcessByID(ctx, runID)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(stored.Status).To(Equal(model.StatusAbandoned))
+			})
+		})
+
+		When("another attempt of the process is running", func() {
+			BeforeEach(func() {
+				Expect(s.AbandonProcess(ctx, runID, 1, "consumer lost")).To(Succeed())
+				Expect(s.BeginRetry(ctx, runID)).To(Equal(2))
+			})
+
+			It("returns an error", func() {
+				Expect(errAction).To(MatchError(model.ErrOwnershipLost))
+			})
+
+			It("keeps the status", func() {
+				stored, err := s.GetProcessByID(ctx, runID)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(stored.Status).To(Equal(model.StatusRunning))
+			})
+		})
+	})
+
+	Describe("RequestStop", func() {
+		BeforeEach(func() {
+			Expect(s.InsertProcess(ctx, run)).To(Succeed())
+		})
+
+		JustBeforeEach(func() {
+			errAction = s.RequestStop(ctx, runID, "maintenance")
+		})
+
+		It("succeeds", func() {
+			Expect(errAction).ToNot(HaveOccurred())
+		})
+
+		It("records the stop reason and request time", func() {
+			stored, err := s.GetProcessByID(ctx, runID)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(stored.StopReason).To(Equal("maintenance"))
+			Expect(stored.StopRequestedAt).ToNot(BeNil())
+			Expect(stored.Status).To(Equal(model.StatusRunning))
+		})
+
+		When("the process is parked", func() {
+			BeforeEach(func() {
+				Expect(s.InsertTaskRun(ctx, 1, model.TaskRun{
+					ProcessID: runID, Name: "approve", Class: model.ApprovalCmd, Status: model.TaskStatusAwaitingApproval, Attempt: 1,
+				})).To(Succeed())
+				Expect(s.InsertTaskRun(ctx, 1, model.TaskRun{
+					ProcessID: runID, Name: "deploy", Class: model.SshCmd, Status: model.TaskStatusPending, Attempt: 1,
+				})).To(Succeed())
+				Expect(s.UpdateProcessStatus(ctx, runID, 1, model.StatusAwaitingApproval)).To(Succeed())
+			})
+
+			It("stops it right away", func() {
+				Expect(errAction).ToNot(HaveOccurred())
+
+				stored, err := s.GetProcessByID(ctx, runID)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(stored.Status).To(Equal(model.StatusStopped))
+				Expect(stored.StopReason).To(Equal("maintenance"))
+				Expect(stored.EndedAt).ToNot(BeNil())
+			})
+
+			It("cancels its unfinished tasks", func() {
+				taskRuns, err := s.GetTaskRuns(ctx, runID)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(taskRuns).To(HaveLen(2))
+				for _, taskRun := range taskRuns {
+					Expect(taskRun.Status).To(Equal(model.TaskStatusCancelled))
+					Expect(taskRun.Error).To(Equal("maintenance"))
+				}
+			})
+		})
+
+		When("the process is not running", func() {
+			BeforeEach(func() {
+				Expect(s.UpdateProcessStatus(ctx, runID, 1, model.StatusCompleted)).To(Succeed())
+			})
+
+			It("returns an error", func() {
+				Expect(errAction).To(MatchError(model.ErrProcessNotRunning))
+			})
+		})
+	})
+
+	Describe("RequestPause", func() {
+		BeforeEach(func() {
+			Expect(s.InsertProcess(ctx, run)).To(Succeed())
+		})
+
+		JustBeforeEach(func() {
+			errAction = s.RequestPause(ctx, runID)
+		})
+
+		It("records the pause request", func() {
+			Expect(errAction).ToNot(HaveOccurred())
+
+			stored, err := s.GetProcessByID(ctx, runID)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(stored.PauseRequestedAt).ToNot(BeNil())
+			Expect(stored.Status).To(Equal(model.StatusRunning))
+		})
+
+		When("the process is not running", func() {
+			BeforeEach(func() {
+				Expect(s.UpdateProcessStatus(ctx, runID, 1, model.StatusCompleted)).To(Succeed())
+			})
+
+			It("returns an error", func() {
+				Expect(errAction).To(MatchError(model.ErrProcessNotRunning))
+			})
+		})
+	})
+
+	Describe("ListenRunRequests", func() {
+		var (
+			mu      sync.Mutex
+			stopped []uuid.UUID
+			paused  []uuid.UUID
+		)
+
+		BeforeEach(func() {
+			stopped, paused = nil, nil
+			Expect(s.InsertProcess(ctx, run)).To(Succeed())
+		})
+
+		JustBeforeEach(func() {
+			listenCtx, cancel := context.WithCancel(ctx)
+			done := make(chan struct{})
+			DeferCleanup(func() {
+				cancel()
+				<-done
+			})
+
+			record := func(ids *[]uuid.UUID) func(uuid.UUID) bool {
+				return func(id uuid.UUID) bool {
+					mu.Lock()
+					defer mu.Unlock()
+					*ids = append(*ids, id)
+					return true
+				}
+			}
+			go func() {
+				defer close(done)
+				_ = s.ListenRunRequests(listenCtx, record(&stopped), record(&paused))
+			}()
+		})
+
+		When("requests were made while no one listened", func() {
+			BeforeEach(func() {
+				Expect(s.RequestStop(ctx, runID, "maintenance")).To(Succeed())
+				Expect(s.RequestPause(ctx, runID)).To(Succeed())
+			})
+
+			It("replays them", func() {
+				Eventually(func() []uuid.UUID {
+					mu.Lock()
+					defer mu.Unlock()
+					return slices.Clone(stopped)
+				}).Should(ConsistOf(runID))
+				Eventually(func() []uuid.UUID {
+					mu.Lock()
+					defer mu.Unlock()
+					return slices.Clone(paused)
+				}).Should(ConsistOf(runID))
+			})
+		})
+	})
+
+	Describe("ResumeProcess", func() {
+		var (
+			resumed   []model.ProcessRun
+			resumeErr error
+		)
+
+		BeforeEach(func() {
+			resumed = nil
+			resumeErr = nil
+			Expect(s.InsertProcess(ctx, run)).To(Succeed())
+			Expect(s.RequestPause(ctx, runID)).To(Succeed())
+			Expect(s.UpdateProcessStatus(ctx, runID, 1, model.StatusPaused)).To(Succeed())
+		})
+
+		JustBeforeEach(func() {
+			errAction = s.ResumeProcess(ctx, runID, func(_ context.Context, run model.ProcessRun) error {
+				resumed = append(resumed, run)
+				return resumeErr
+			})
+		})
+
+		It("resumes the run", func() {
+			Expect(errAction).ToNot(HaveOccurred())
+			Expect(resumed).To(HaveLen(1))
+			Expect(resumed[0].ID).To(Equal(runID))
+			Expect(resumed[0].Definition).To(Equal(run.Definition))
+		})
+
+		It("can be continued", func() {
+			Expect(s.BeginContinue(ctx, runID)).To(Equal(1))
+
+			stored, err := s.GetProcessByID(ctx, runID)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(stored.Status).To(Equal(model.StatusRunning))
+			Expect(stored.PauseRequestedAt).To(BeNil())
+		})
+
+		It("rejects a second resume", func() {
+			err := s.ResumeProcess(ctx, runID, func(_ context.Context, run model.ProcessRun) error {
+				resumed = append(resumed, run)
+				return nil
+			})
+			Expect(err).To(MatchError(model.ErrProcessNotPaused))
+			Expect(resumed).To(HaveLen(1))
+		})
+
+		It("can be resumed again after it continued and paused", func() {
+			Expect(s.BeginContinue(ctx, runID)).To(Equal(1))
+			Expect(s.UpdateProcessStatus(ctx, runID, 1, model.StatusPaused)).To(Succeed())
+
+			err := s.ResumeProcess(ctx, runID, func(_ context.Context, run model.ProcessRun) error {
+				resumed = append(resumed, run)
+				return nil
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(resumed).To(HaveLen(2))
+		})
+
+		When("resuming fails", func() {
+			BeforeEach(func() {
+				resumeErr = errors.New("publish failed")
+			})
+
+			It("does not record the resume", func() {
+				Expect(errAction).To(MatchError(resumeErr))
+
+				err := s.ResumeProcess(ctx, runID, func(context.Context, model.ProcessRun) error {
+					return nil
+				})
+				Expect(err).ToNot(HaveOccurred())
+			})
+		})
+
+		When("the process is not paused", func() {
+			BeforeEach(func() {
+				Expect(s.SetProcessStatus(ctx, runID, model.StatusAwaitingApproval)).To(Succeed())
+			})
+
+			It("returns an error", func() {
+				Expect(errAction).To(MatchError(model.ErrProcessNotPaused))
+				Expect(resumed).To(BeEmpty())
+			})
+		})
+	})
+
+	Describe("CancelScheduledProcess", func() {
+		var runAt time.Time
+
+		BeforeEach(func() {
+			runAt = time.Now().Add(time.Hour).Truncate(time.Second)
+			_, err := pool.Exec(ctx,
+				"INSERT INTO process_runs (id, definition, status, started_at, run_at) VALUES ($1, $2, $3, $4, $5)",
+				runID, run.Definition, model.StatusScheduled, run.StartedAt, runAt,
+			)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		JustBeforeEach(func() {
+			errAction = s.CancelScheduledProcess(ctx, runID, "not needed")
+		})
+
+		It("cancels the run", func() {
+			Expect(errAction).ToNot(HaveOccurred())
+
+			stored, err := s.GetProcessByID(ctx, runID)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(stored.Status).To(Equal(model.StatusCancelled))
+			Expect(stored.StopReason).To(Equal("not needed"))
+			Expect(stored.RunAt).To(HaveValue(BeTemporally("==", runAt)))
+			Expect(stored.EndedAt).ToNot(BeNil())
+		})
+
+		When("the run was queued already", func() {
+			BeforeEach(func() {
+				_, err := pool.Exec(ctx, "UPDATE process_runs SET status = $1 WHERE id = $2", model.StatusQueued, runID)
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("returns an error", func() {
+				Expect(errAction).To(MatchError(model.ErrProcessNotScheduled))
+			})
+		})
+	})
+
+	Describe("AbandonProcess", func() {
+		BeforeEach(func() {
+			Expect(s.InsertProcess(ctx, run)).To(Succeed())
+			Expect(s.InsertTaskRun(ctx, 1, model.TaskRun{
+				ProcessID: runID, Name: "build", Class: model.LocalCmd, Status: model.TaskStatusSucceeded, Attempt: 1,
+			})).To(Succeed())
+			Expect(s.InsertTaskRun(ctx, 1, model.TaskRun{
+				ProcessID: runID, Name: "deploy", Class: model.LocalCmd, Status: model.TaskStatusRunning, Attempt: 1,
+			})).To(Succeed())
+		})
+
+		JustBeforeEach(func() {
+			errAction = s.AbandonProcess(ctx, runID, 1, "consumer lost")
+		})
+
+		It("marks the process as abandoned", func() {
+			Expect(errAction).ToNot(HaveOccurred())
+
+			stored, err := s.GetProcessByID(ctx, runID)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(stored.Status).To(Equal(model.StatusAbandoned))
+			Expect(stored.EndedAt).ToNot(BeNil())
+		})
+
+		It("cancels the unfinished tasks", func() {
+			taskRuns, err := s.GetTaskRuns(ctx, runID)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(taskRuns).To(ConsistOf(
+				SatisfyAll(HaveField("Name", "build"), HaveField("Status", model.TaskStatusSucceeded)),
+				SatisfyAll(
+					HaveField("Name", "deploy"),
+					HaveField("Status", model.TaskStatusCancelled),
+					HaveField("Error", "consumer lost"),
+				),
+			))
+		})
+
+		When("another attempt of the process is running", func() {
+			BeforeEach(func() {
+				Expect(s.UpdateProcessStatus(ctx, runID, 1, model.StatusFailed)).To(Succeed())
+				Expect(s.BeginRetry(ctx, runID)).To(Equal(2))
+			})
+
+			It("returns an error", func() {
+				Expect(errAction).To(MatchError(model.ErrProcessNotRunning))
+
+				stored, err := s.GetProcessByID(ctx, runID)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(stored.Status).To(Equal(model.StatusRunning))
+			})
+		})
+
+		When("the process started a sub-process", func() {
+			var childID uuid.UUID
+
+			BeforeEach(func() {
+				childID = uuid.New()
+				Expect(s.InsertProcess(ctx, model.ProcessRun{
+					ID:         childID,
+					Definition: model.ProcessDefinition{Name: "provision"},
+					Status:     model.StatusRunning,
+					StartedAt:  time.Now(),
+					ParentID:   &runID,
+					ParentTask: "deploy",
+				})).To(Succeed())
+				Expect(s.InsertTaskRun(ctx, 1, model.TaskRun{
+					ProcessID: childID, Name: "create-vm", Class: model.LocalCmd, Status: model.TaskStatusRunning, Attempt: 1,
+				})).To(Succeed())
+			})
+
+			It("abandons it as well", func() {
+				Expect(errAction).ToNot(HaveOccurred())
+
+				stored, err := s.GetProcessByID(ctx, childID)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(stored.Status).To(Equal(model.StatusAbandoned))
+
+				taskRuns, err := s.GetTaskRuns(ctx, childID)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(taskRuns).To(ConsistOf(HaveField("Status", model.TaskStatusCancelled)))
+			})
+		})
+
+		When("the process is not running", func() {
+			BeforeEach(func() {
+				Expect(s.UpdateProcessStatus(ctx, runID, 1, model.StatusCompleted)).To(Succeed())
+			})
+
+			It("returns an error", func() {
+				Expect(errAction).To(MatchError(model.ErrProcessNotRunning))
+			})
+		})
+	})
+
+	Describe("BeginRetry", func() {
+		var attempt int
+
+		BeforeEach(func() {
+			Expect(s.InsertProcess(ctx, run)).To(Succeed())
+			Expect(s.UpdateProcessStatus(ctx, runID, 1, model.StatusFailed)).To(Succeed())
+		})
+
+		JustBeforeEach(func() {
+			attempt, errAction = s.BeginRetry(ctx, runID)
+		})
+
+		It("starts the next attempt", func() {
+			Expect(errAction).ToNot(HaveOccurred())
+			Expect(attempt).To(Equal(2))
+
+			stored, err := s.GetProcessByID(ctx, runID)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(stored.Status).To(Equal(model.StatusRunning))
+			Expect(stored.Attempt).To(Equal(2))
+			Expect(stored.EndedAt).To(BeNil())
+		})
+
+		It("archives the previous attempt", func() {
+			attempts, err := s.GetRunAttempts(ctx, runID)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(attempts).To(HaveLen(1))
+			Expect(attempts[0].Attempt).To(Equal(1))
+			Expect(attempts[0].Status).To(Equal(model.StatusFailed))
+			Expect(attempts[0].EndedAt).ToNot(BeNil())
+		})
+
+		When("the process completed", func() {
+			BeforeEach(func() {
+				Expect(s.SetProcessStatus(ctx, runID, model.StatusCompleted)).To(Succeed())
+			})
+
+			It("returns an error", func() {
+				Expect(errAction).To(MatchError(model.ErrProcessNotRetryable))
+			})
+		})
+	})
+
+	Describe("approvals", func() {
+		var (
+			resumed    []model.ProcessRun
+			resumeErr  error
+			resume     func(context.Context, model.ProcessRun) error
+			expiresAt  time.Time
+			parkedTask model.TaskRun
+		)
+
+		BeforeEach(func() {
+			resumed = nil
+			resumeErr = nil
+			resume = func(_ context.Context, run model.ProcessRun) error {
+				resumed = append(resumed, run)
+				return resumeErr
+			}
+
+			Expect(s.InsertProcess(ctx, run)).To(Succeed())
+			expiresAt = time.Now().Add(-time.Minute)
+			parkedTask = model.TaskRun{
+				ProcessID: runID, Name: "approve", Class: model.ApprovalCmd, Status: model.TaskStatusPending, Attempt: 1,
+			}
+			Expect(s.InsertTaskRun(ctx, 1, parkedTask)).To(Succeed())
+			parkedTask.Status = model.TaskStatusAwaitingApproval
+			parkedTask.ApprovalExpiresAt = &expiresAt
+			Expect(s.UpdateTaskRun(ctx, 1, parkedTask)).To(Succeed())
+			Expect(s.UpdateProcessStatus(ctx, runID, 1, model.StatusAwaitingApproval)).To(Succeed())
+		})
+
+		Describe("DecideApproval", func() {
+			var decision model.ApprovalDecision
+
+			BeforeEach(func() {
+				decision = model.ApprovalDecision{Approved: true, By: "alice", Comment: "looks good"}
+			})
+
+			JustBeforeEach(func() {
+				errAction = s.DecideApproval(ctx, runID, "approve", decision, resume)
+			})
+
+			It("records the approval", func() {
+				Expect(errAction).ToNot(HaveOccurred())
+
+				taskRuns, err := s.GetTaskRuns(ctx, runID)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(taskRuns).To(HaveLen(1))
+				Expect(taskRuns[0].Status).To(Equal(model.TaskStatusSucceeded))
+				Expect(taskRuns[0].DecidedBy).To(Equal("alice"))
+				Expect(taskRuns[0].DecidedAt).ToNot(BeNil())
+				Expect(taskRuns[0].ApprovalExpiresAt).To(HaveValue(BeTemporally("~", expiresAt, time.Millisecond)))
+			})
+
+			It("logs the decision and resumes the run", func() {
+				logs, err := s.GetProcessLogs(ctx, runID)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(logs).To(ContainElement(HaveField("Log", "Task approve approved by alice: looks good")))
+
+				Expect(resumed).To(HaveLen(1))
+				Expect(resumed[0].ID).To(Equal(runID))
+				Expect(resumed[0].Definition).To(Equal(run.Definition))
+			})
+
+			When("the task is rejected", func() {
+				BeforeEach(func() {
+					decision.Approved = false
+					decision.Comment = "too risky"
+				})
+
+				It("fails the task", func() {
+					taskRuns, err := s.GetTaskRuns(ctx, runID)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(taskRuns[0].Status).To(Equal(model.TaskStatusFailed))
+					Expect(taskRuns[0].Error).To(Equal("rejected by alice: too risky"))
+				})
+			})
+
+			When("the task is not awaiting approval", func() {
+				BeforeEach(func() {
+					// The task ran before the run was parked.
+					Expect(s.SetProcessStatus(ctx, runID, model.StatusRunning)).To(Succeed())
+					Expect(s.InsertTaskRun(ctx, 1, model.TaskRun{
+						ProcessID: runID, Name: "build", Class: model.LocalCmd, Status: model.TaskStatusSucceeded, Attempt: 1,
+					})).To(Succeed())
+					Expect(s.SetProcessStatus(ctx, runID, model.StatusAwaitingApproval)).To(Succeed())
+				})
+
+				It("returns an error", func() {
+					Expect(s.DecideApproval(ctx, runID, "build", decision, resume)).To(MatchError(model.ErrTaskNotAwaitingApproval))
+				})
+			})
+
+			When("the run is not parked", func() {
+				BeforeEach(func() {
+					Expect(s.SetProcessStatus(ctx, runID, model.StatusRunning)).To(Succeed())
+				})
+
+				It("returns an error", func() {
+					Expect(errAction).To(MatchError(model.ErrProcessNotAwaitingApproval))
+					Expect(resumed).To(BeEmpty())
+				})
+			})
+
+			When("resuming the run fails", func() {
+				BeforeEach(func() {
+					resumeErr = errors.New("publish failed")
+				})
+
+				It("does not record the decision", func() {
+					Expect(errAction).To(MatchError("publish failed"))
+
+					taskRuns, err := s.GetTaskRuns(ctx, runID)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(taskRuns[0].Status).To(Equal(model.TaskStatusAwaitingApproval))
+				})
+			})
+		})
+
+		Describe("ExpireApprovals", func() {
+			var count int
+
+			JustBeforeEach(func() {
+				count, errAction = s.ExpireApprovals(ctx, time.Now(), resume)
+			})
+
+			It("times out the expired approvals and resumes the run", func() {
+				Expect(errAction).ToNot(HaveOccurred())
+				Expect(count).To(Equal(1))
+				Expect(resumed).To(HaveLen(1))
+				Expect(resumed[0].ID).To(Equal(runID))
+
+				taskRuns, err := s.GetTaskRuns(ctx, runID)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(taskRuns[0].Status).To(Equal(model.TaskStatusTimedOut))
+				Expect(taskRuns[0].Error).To(Equal("approval expired"))
+			})
+
+			When("the approval did not expire yet", func() {
+				BeforeEach(func() {
+					later := time.Now().Add(time.Hour)
+					parkedTask.ApprovalExpiresAt = &later
+					Expect(s.SetProcessStatus(ctx, runID, model.StatusRunning)).To(Succeed())
+					Expect(s.UpdateTaskRun(ctx, 1, parkedTask)).To(Succeed())
+					Expect(s.SetProcessStatus(ctx, runID, model.StatusAwaitingApproval)).To(Succeed())
+				})
+
+				It("leaves it", func() {
+					Expect(errAction).ToNot(HaveOccurred())
+					Expect(count).To(Equal(0))
+					Expect(resumed).To(BeEmpty())
+				})
+			})
+		})
+
+		Describe("BeginContinue", func() {
+			JustBeforeEach(func() {
+				_, errAction = s.BeginContinue(ctx, runID)
+			})
+
+			It("runs the process again in the same attempt", func() {
+				Expect(errAction).ToNot(HaveOccurred())
+
+				stored, err := s.GetProcessByID(ctx, runID)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(stored.Status).To(Equal(model.StatusRunning))
+				Expect(stored.Attempt).To(Equal(1))
+				Expect(stored.EndedAt).To(BeNil())
+			})
+
+			When("the run is not parked", func() {
+				BeforeEach(func() {
+					Expect(s.SetProcessStatus(ctx, runID, model.StatusFailed)).To(Succeed())
+				})
+
+				It("returns an error", func() {
+					Expect(errAction).To(MatchError(model.ErrProcessNotParked))
+				})
+			})
+		})
 	})
 
 	Describe("GetProcessByID", func() {
@@ -134,4 +812,111 @@ var _ = Describe("ProcessDBStore", func() {
 			Expect(logs[1].Log).To(Equal("log 2"))
 		})
 	})
+
+	Describe("AppendProcessLogs", func() {
+		BeforeEach(func() {
+			Expect(s.InsertProcess(ctx, run)).To(Succeed())
+		})
+
+		JustBeforeEach(func() {
+			now := time.Now()
+			errAction = s.AppendProcessLogs(ctx, []model.ProcessLog{
+				{ProcessID: runID, TaskName: "build", Stream: model.StreamStdout, Log: "line 1", CreatedAt: now},
+				{ProcessID: runID, TaskName: "build", Stream: model.StreamStderr, Log: "line 2", CreatedAt: now.Add(time.Millisecond)},
+			})
+		})
+
+		It("succeeds", func() {
+			Expect(errAction).ToNot(HaveOccurred())
+		})
+
+		It("stores the lines tagged with task and stream", func() {
+			logs, err := s.GetProcessLogs(ctx, runID)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(logs).To(HaveLen(2))
+			Expect(logs[0].TaskName).To(Equal("build"))
+			Expect(logs[0].Stream).To(Equal(model.StreamStdout))
+			Expect(logs[1].Stream).To(Equal(model.StreamStderr))
+			Expect(logs[1].Log).To(Equal("line 2"))
+		})
+	})
+
+	Describe("InsertTaskRun, UpdateTaskRun and GetTaskRuns", func() {
+		var taskRun model.TaskRun
+
+		BeforeEach(func() {
+			Expect(s.InsertProcess(ctx, run)).To(Succeed())
+
+			taskRun = model.TaskRun{
+				ProcessID: runID,
+				Name:      "build",
+				Class:     model.LocalCmd,
+				Status:    model.TaskStatusPending,
+				Attempt:   1,
+			}
+			Expect(s.InsertTaskRun(ctx, 1, taskRun)).To(Succeed())
+		})
+
+		JustBeforeEach(func() {
+			startedAt := time.Now()
+			exitCode := 2
+
+			taskRun.Status = model.TaskStatusFailed
+			taskRun.ExitCode = &exitCode
+			taskRun.Stderr = "boom"
+			taskRun.StartedAt = &startedAt
+			taskRun.EndedAt = &startedAt
+			errAction = s.UpdateTaskRun(ctx, 1, taskRun)
+		})
+
+		It("succeeds", func() {
+			Expect(errAction).ToNot(HaveOccurred())
+		})
+
+		It("fetches the updated task runs", func() {
+			runs, err := s.GetTaskRuns(ctx, runID)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(runs).To(HaveLen(1))
+			Expect(runs[0].Name).To(Equal("build"))
+			Expect(runs[0].Class).To(Equal(model.LocalCmd))
+			Expect(runs[0].Status).To(Equal(model.TaskStatusFailed))
+			Expect(*runs[0].ExitCode).To(Equal(2))
+			Expect(runs[0].Stderr).To(Equal("boom"))
+			Expect(runs[0].EndedAt).ToNot(BeNil())
+			Expect(runs[0].Outputs).To(BeNil())
+		})
+
+		When("the task run has outputs", func() {
+			BeforeEach(func() {
+				taskRun.Outputs = map[string]string{"buildId": "42"}
+			})
+
+			It("persists the outputs", func() {
+				runs, err := s.GetTaskRuns(ctx, runID)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(runs[0].Outputs).To(Equal(map[string]string{"buildId": "42"}))
+			})
+		})
+
+		When("the process was abandoned", func() {
+			BeforeEach(func() {
+				Expect(s.AbandonProcess(ctx, runID, 1, "consumer lost")).To(Succeed())
+			})
+
+			It("returns an error", func() {
+				Expect(errAction).To(MatchError(model.ErrOwnershipLost))
+			})
+
+			It("keeps the task run as the abandonment left it", func() {
+				runs, err := s.GetTaskRuns(ctx, runID)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(runs[0].Status).To(Equal(model.TaskStatusCancelled))
+			})
+
+			It("does not record new task runs", func() {
+				taskRun.Attempt = 2
+				Expect(s.InsertTaskRun(ctx, 1, taskRun)).To(MatchError(model.ErrOwnershipLost))
+			})
+		})
+	})
 })
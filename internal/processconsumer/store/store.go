@@ -6,15 +6,21 @@ import (
 	"time"
 
 	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/model"
-	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/pg/pgtx"
-	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/pg/txctx"
 	"github.com/google/uuid"
-	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 const ProcessEventsTable = "process_events"
 
+// States of a process event. A claimed event is being processed by the consumer that
+// holds its lease. A released event was given up before its run started and can be
+// claimed again.
+const (
+	eventClaimed   = "claimed"
+	eventCompleted = "completed"
+	eventReleased  = "released"
+)
+
 type Store struct {
 	pool *pgxpool.Pool
 }
@@ -23,60 +29,104 @@ func NewStore(pool *pgxpool.Pool) *Store {
 	return &Store{pool: pool}
 }
 
-func (s *Store) AddMessage(ctx context.Context, m model.Message) error {
-	tx, err := txctx.GetTx(ctx)
+// ClaimMessage records the message as claimed by owner for ttl. It reports false if the
+// message is already claimed or completed.
+func (s *Store) ClaimMessage(ctx context.Context, m model.Message, owner string, ttl time.Duration) (bool, error) {
+	query := fmt.Sprintf(`
+		INSERT INTO %[1]s (uuid, payload, created_at, state, claimed_by, lease_expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (uuid) DO UPDATE
+		SET state = EXCLUDED.state, claimed_by = EXCLUDED.claimed_by, lease_expires_at = EXCLUDED.lease_expires_at
+		WHERE %[1]s.state = $7
+	`, ProcessEventsTable)
+
+	now := time.Now().UTC()
+	tag, err := s.pool.Exec(ctx, query, m.UUID, m, now, eventClaimed, owner, now.Add(ttl), eventReleased)
 	if err != nil {
-		return err
+		return false, fmt.Errorf("failed to claim message: %w", err)
 	}
+	return tag.RowsAffected() == 1, nil
+}
 
+// ClaimExpiredMessages takes over the claims whose lease expired, i.e. whose consumer
+// stopped extending them, for owner for ttl. It returns the messages along with the
+// consumers that claimed them before.
+func (s *Store) ClaimExpiredMessages(ctx context.Context, owner string, ttl time.Duration) ([]model.ClaimedMessage, error) {
 	query := fmt.Sprintf(`
-		INSERT INTO %s (uuid, payload, created_at)
-		VALUES ($1, $2, $3)
-		ON CONFLICT (uuid) DO NOTHING
+		WITH expired AS (
+			SELECT uuid, claimed_by FROM %[1]s
+			WHERE state = $1 AND lease_expires_at < $2
+			FOR UPDATE SKIP LOCKED
+		)
+		UPDATE %[1]s AS e SET claimed_by = $3, lease_expires_at = $4
+		FROM expired
+		WHERE e.uuid = expired.uuid
+		RETURNING e.payload, expired.claimed_by
 	`, ProcessEventsTable)
 
-	_, err = tx.Exec(ctx, query, m.UUID, m, time.Now().UTC())
-	return err
-}
-
-func (s *Store) MarkCompleted(ctx context.Context, uuid uuid.UUID) error {
-	tx, err := txctx.GetTx(ctx)
+	now := time.Now().UTC()
+	rows, err := s.pool.Query(ctx, query, eventClaimed, now, owner, now.Add(ttl))
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("failed to claim expired messages: %w", err)
 	}
+	defer rows.Close()
 
-	query := fmt.Sprintf(`UPDATE %s SET completed_at = $1 WHERE uuid = $2`, ProcessEventsTable)
-	_, err = tx.Exec(ctx, query, time.Now().UTC(), uuid)
-	return err
+	var claimed []model.ClaimedMessage
+	for rows.Next() {
+		var c model.ClaimedMessage
+		if err := rows.Scan(&c.Message, &c.Owner); err != nil {
+			return nil, fmt.Errorf("failed to scan expired message: %w", err)
+		}
+		claimed = append(claimed, c)
+	}
+	return claimed, rows.Err()
 }
 
-func (s *Store) MessageExists(ctx context.Context, id uuid.UUID) (bool, error) {
-	tx, err := txctx.GetTx(ctx)
-	if err != nil {
-		return false, err
-	}
+// ExtendLease extends the claim of owner on the message by ttl from now.
+func (s *Store) ExtendLease(ctx context.Context, id uuid.UUID, owner string, ttl time.Duration) error {
+	query := fmt.Sprintf(`
+		UPDATE %s SET lease_expires_at = $1
+		WHERE uuid = $2 AND state = $3 AND claimed_by = $4
+	`, ProcessEventsTable)
 
-	query := fmt.Sprintf(`SELECT EXISTS (SELECT 1 FROM %s WHERE uuid = $1)`, ProcessEventsTable)
-	var exists bool
-	err = tx.QueryRow(ctx, query, id).Scan(&exists)
+	tag, err := s.pool.Exec(ctx, query, time.Now().UTC().Add(ttl), id, eventClaimed, owner)
 	if err != nil {
-		return false, fmt.Errorf("query failed: %w", err)
+		return fmt.Errorf("failed to extend lease: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return model.ErrLeaseLost
 	}
+	return nil
+}
 
-	return exists, nil
+// CompleteMessage records the message claimed by owner as processed.
+func (s *Store) CompleteMessage(ctx context.Context, id uuid.UUID, owner string) error {
+	return s.endClaim(ctx, id, owner, eventCompleted)
 }
 
-func (s *Store) RunInAtomically(ctx context.Context, cb func(ctx context.Context) error) error {
-	err := pgtx.Atomically(ctx, s.pool, pgx.Serializable, func(ctx context.Context, tx pgx.Tx) error {
-		ctxWithTx := txctx.WithTx(ctx, tx)
+// ReleaseMessage gives up the claim of owner on the message so it can be claimed again.
+func (s *Store) ReleaseMessage(ctx context.Context, id uuid.UUID, owner string) error {
+	return s.endClaim(ctx, id, owner, eventReleased)
+}
 
-		if err := cb(ctxWithTx); err != nil {
-			return fmt.Errorf("callback failed: %w", err)
-		}
-		return nil
-	})
+func (s *Store) endClaim(ctx context.Context, id uuid.UUID, owner, state string) error {
+	var completedAt *time.Time
+	if state == eventCompleted {
+		now := time.Now().UTC()
+		completedAt = &now
+	}
+
+	query := fmt.Sprintf(`
+		UPDATE %s SET state = $1, completed_at = $2, lease_expires_at = NULL
+		WHERE uuid = $3 AND state = $4 AND claimed_by = $5
+	`, ProcessEventsTable)
+
+	tag, err := s.pool.Exec(ctx, query, state, completedAt, id, eventClaimed, owner)
 	if err != nil {
-		return fmt.Errorf("transaction failed: %w", err)
+		return fmt.Errorf("failed to update message state: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return model.ErrLeaseLost
 	}
 	return nil
-}
\ No newline at end of file
+}
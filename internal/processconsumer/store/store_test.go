@@ -1,7 +1,7 @@
 package store_test
 
 import (
-	"context"
+	"time"
 
 	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/model"
 	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/processconsumer/store"
@@ -43,11 +43,11 @@ var _ = Describe("Store", func() {
 			}
 		})
 
-		Describe("AddMessage", func() {
+		Describe("ClaimMessage", func() {
+			var claimed bool
+
 			JustBeforeEach(func() {
-				errAction = s.RunInAtomically(ctx, func(ctx context.Context) error {
-					return s.AddMessage(ctx, msg)
-				})
+				claimed, errAction = s.ClaimMessage(ctx, msg, "consumer-1", time.Minute)
 			})
 
 			JustAfterEach(func() {
@@ -55,8 +55,9 @@ var _ = Describe("Store", func() {
 				Expect(err).NotTo(HaveOccurred())
 			})
 
-			It("succeeds", func() {
+			It("claims the message", func() {
 				Expect(errAction).NotTo(HaveOccurred())
+				Expect(claimed).To(BeTrue())
 			})
 
 			It("inserts the correct message", func() {
@@ -67,13 +68,57 @@ var _ = Describe("Store", func() {
 				Expect(storedMsg.ProcessDefinition.Tasks).To(HaveLen(1))
 				Expect(storedMsg.ProcessDefinition.Tasks[0].Name).To(Equal("step1"))
 			})
+
+			It("sets the lease expiry", func() {
+				expiresAt, err := s.GetLeaseExpiresAtByUUID(ctx, messageUUID)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(*expiresAt).To(BeTemporally("~", time.Now().Add(time.Minute), 5*time.Second))
+			})
+
+			When("the message is claimed by another consumer", func() {
+				BeforeEach(func() {
+					_, err := s.ClaimMessage(ctx, msg, "consumer-2", time.Minute)
+					Expect(err).NotTo(HaveOccurred())
+				})
+
+				It("does not claim it", func() {
+					Expect(errAction).NotTo(HaveOccurred())
+					Expect(claimed).To(BeFalse())
+				})
+			})
+
+			When("the message was released", func() {
+				BeforeEach(func() {
+					_, err := s.ClaimMessage(ctx, msg, "consumer-2", time.Minute)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(s.ReleaseMessage(ctx, msg.UUID, "consumer-2")).To(Succeed())
+				})
+
+				It("claims it again", func() {
+					Expect(errAction).NotTo(HaveOccurred())
+					Expect(claimed).To(BeTrue())
+				})
+			})
+
+			When("the message was completed", func() {
+				BeforeEach(func() {
+					_, err := s.ClaimMessage(ctx, msg, "consumer-2", time.Minute)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(s.CompleteMessage(ctx, msg.UUID, "consumer-2")).To(Succeed())
+				})
+
+				It("does not claim it", func() {
+					Expect(errAction).NotTo(HaveOccurred())
+					Expect(claimed).To(BeFalse())
+				})
+			})
 		})
 
-		Describe("MarkCompleted", func() {
+		Describe("ClaimExpiredMessages", func() {
+			var claimed []model.ClaimedMessage
+
 			BeforeEach(func() {
-				err := s.RunInAtomically(ctx, func(ctx context.Context) error {
-					return s.AddMessage(ctx, msg)
-				})
+				_, err := s.ClaimMessage(ctx, msg, "consumer-1", -time.Second)
 				Expect(err).NotTo(HaveOccurred())
 			})
 
@@ -83,64 +128,107 @@ var _ = Describe("Store", func() {
 			})
 
 			JustBeforeEach(func() {
-				errAction = s.RunInAtomically(ctx, func(txCtx context.Context) error {
-					return s.MarkCompleted(txCtx, msg.UUID)
-				})
+				claimed, errAction = s.ClaimExpiredMessages(ctx, "consumer-2", time.Minute)
 			})
 
-			It("succeeds", func() {
+			It("takes over the expired claim", func() {
 				Expect(errAction).NotTo(HaveOccurred())
+				Expect(claimed).To(ContainElement(SatisfyAll(
+					HaveField("Message.UUID", msg.UUID),
+					HaveField("Owner", "consumer-1"),
+				)))
+				Expect(s.ExtendLease(ctx, msg.UUID, "consumer-2", time.Minute)).To(Succeed())
 			})
 
-			It("marks the message as completed", func() {
-				completedAt, err := s.GetCompletedAtByUUID(ctx, msg.UUID)
-				Expect(err).NotTo(HaveOccurred())
-				Expect(completedAt).NotTo(BeZero())
+			When("the claim did not expire", func() {
+				BeforeEach(func() {
+					Expect(s.ExtendLease(ctx, msg.UUID, "consumer-1", time.Minute)).To(Succeed())
+				})
+
+				It("leaves it", func() {
+					Expect(errAction).NotTo(HaveOccurred())
+					Expect(claimed).NotTo(ContainElement(HaveField("Message.UUID", msg.UUID)))
+				})
 			})
 		})
 
-		Describe("MessageExists", func() {
-			var exists bool
+		Describe("ExtendLease", func() {
+			var owner string
+
+			BeforeEach(func() {
+				owner = "consumer-1"
+				_, err := s.ClaimMessage(ctx, msg, "consumer-1", time.Second)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			AfterEach(func() {
+				err := s.DeleteMessageByUUID(ctx, messageUUID)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
 			JustBeforeEach(func() {
-				errAction = s.RunInAtomically(ctx, func(txCtx context.Context) error {
-					var err error
-					exists, err = s.MessageExists(txCtx, uuid.New())
-					return err
-				})
+				errAction = s.ExtendLease(ctx, msg.UUID, owner, time.Hour)
 			})
 
-			It("returns false for non-existing UUID", func() {
-				Expect(exists).To(BeFalse())
+			It("extends the lease", func() {
+				Expect(errAction).NotTo(HaveOccurred())
+				expiresAt, err := s.GetLeaseExpiresAtByUUID(ctx, messageUUID)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(*expiresAt).To(BeTemporally("~", time.Now().Add(time.Hour), 5*time.Second))
 			})
 
-			Context("and a message is added", func() {
-				var exists bool
+			When("another consumer holds the claim", func() {
 				BeforeEach(func() {
-					err := s.RunInAtomically(ctx, func(txCtx context.Context) error {
-						return s.AddMessage(txCtx, msg)
-					})
-					Expect(err).NotTo(HaveOccurred())
+					owner = "consumer-2"
 				})
 
-				AfterEach(func() {
-					err := s.DeleteMessageByUUID(ctx, messageUUID)
-					Expect(err).NotTo(HaveOccurred())
+				It("returns ErrLeaseLost", func() {
+					Expect(errAction).To(MatchError(model.ErrLeaseLost))
 				})
+			})
+		})
 
-				JustBeforeEach(func() {
-					errAction = s.RunInAtomically(ctx, func(txCtx context.Context) error {
-						var err error
-						exists, err = s.MessageExists(txCtx, msg.UUID)
-						return err
-					})
-				})
+		Describe("CompleteMessage", func() {
+			var owner string
 
-				It("succeeds", func() {
-					Expect(errAction).NotTo(HaveOccurred())
+			BeforeEach(func() {
+				owner = "consumer-1"
+				_, err := s.ClaimMessage(ctx, msg, "consumer-1", time.Minute)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			AfterEach(func() {
+				err := s.DeleteMessageByUUID(ctx, messageUUID)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			JustBeforeEach(func() {
+				errAction = s.CompleteMessage(ctx, msg.UUID, owner)
+			})
+
+			It("succeeds", func() {
+				Expect(errAction).NotTo(HaveOccurred())
+			})
+
+			It("marks the message as completed", func() {
+				completedAt, err := s.GetCompletedAtByUUID(ctx, msg.UUID)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(completedAt).NotTo(BeZero())
+			})
+
+			It("clears the lease", func() {
+				expiresAt, err := s.GetLeaseExpiresAtByUUID(ctx, messageUUID)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(expiresAt).To(BeNil())
+			})
+
+			When("another consumer holds the claim", func() {
+				BeforeEach(func() {
+					owner = "consumer-2"
 				})
 
-				It("returns true for inserted message", func() {
-					Expect(exists).To(BeTrue())
+				It("returns ErrLeaseLost", func() {
+					Expect(errAction).To(MatchError(model.ErrLeaseLost))
 				})
 			})
 		})
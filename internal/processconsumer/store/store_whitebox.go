@@ -39,4 +39,24 @@ func (s *Store) GetCompletedAtByUUID(ctx context.Context, uuid uuid.UUID) (time.
 		return time.Time{}, fmt.Errorf("failed to get completed_at for UUID %s: %w", uuid, err)
 	}
 	return completedAt, nil
-}
\ No newline at end of file
+}
+
+func (s *Store) GetLeaseExpiresAtByUUID(ctx context.Context, uuid uuid.UUID) (*time.Time, error) {
+	var expiresAt *time.Time
+	query := fmt.Sprintf(`SELECT lease_expires_at FROM %s WHERE uuid = $1`, ProcessEventsTable)
+	err := s.pool.QueryRow(ctx, query, uuid).Scan(&expiresAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get lease_expires_at for UUID %s: %w", uuid, err)
+	}
+	return expiresAt, nil
+}
+
+func (s *ProcessDBStore) SetProcessStatus(ctx context.Context, id uuid.UUID, status model.ProcessStatus) error {
+	query := fmt.Sprintf(`UPDATE %s SET status = $1 WHERE id = $2`, ProcessRunsTable)
+
+	_, err := s.pool.Exec(ctx, query, status, id)
+	if err != nil {
+		return fmt.Errorf("failed to set process status: %w", err)
+	}
+	return nil
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/processloader/service"
 	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/processloader/service/reader"
 	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/processloader/store"
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/processproducer/validator"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -19,7 +20,7 @@ func Process(
 ) (*store.Store, *reader.ConfigReader) {
 	store := store.NewStore(pool)
 	reader := reader.NewConfigReader(dir)
-	processLoaderSvc := service.NewService(store, reader)
+	processLoaderSvc := service.NewService(store, reader, validator.NewProcessValidator())
 
 	process.Process(procSpawnFn, ctx, processLoaderSvc)
 
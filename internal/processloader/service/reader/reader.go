@@ -1,14 +1,13 @@
 package reader
 
 import (
-	"bytes"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
-	"text/template"
 
 	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/model"
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/templating"
 	"gopkg.in/yaml.v3"
 )
 
@@ -73,23 +72,9 @@ func (cr *ConfigReader) GetProcessNameFromFile(path string) (string, error) {
 	return process.Name, nil
 }
 
+// ApplyTemplatingToTasks renders the task parameters, conditions and compensations with the request inputs
+// and expands the tasks with a forEach into their instances.
+// References to outputs of other tasks are kept as they are and rendered by the consumer before execution.
 func (cr *ConfigReader) ApplyTemplatingToTasks(tasks []model.Task, inputs map[string]string) ([]model.Task, error) {
-	var rendered []model.Task
-	for _, t := range tasks {
-		params := make(map[string]string)
-		for key, tmplStr := range t.Parameters {
-			tmpl, err := template.New(key).Parse(tmplStr)
-			if err != nil {
-				return nil, fmt.Errorf("failed to parse template for task %s param %s: %w", t.Name, key, err)
-			}
-			var buf bytes.Buffer
-			if err := tmpl.Execute(&buf, inputs); err != nil {
-				return nil, fmt.Errorf("failed to render template for task %s param %s: %w", t.Name, key, err)
-			}
-			params[key] = buf.String()
-		}
-		t.Parameters = params
-		rendered = append(rendered, t)
-	}
-	return rendered, nil
+	return templating.Apply(tasks, inputs)
 }
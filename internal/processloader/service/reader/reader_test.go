@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/model"
 	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/processloader/service/reader"
@@ -87,6 +88,36 @@ tasks:
 			})
 		})
 
+		When("a task declares a retry policy", func() {
+			BeforeEach(func() {
+				fileContent = []byte(`name: retry-process
+tasks:
+  - name: t1
+    class: sshCmd
+    retry:
+      attempts: 3
+      backoff: exponential
+      initialDelay: 2s
+      maxDelay: 1m
+      retryableExitCodes: [1, 255]
+`)
+				filePath = filepath.Join(tmpDir, "retry.yaml")
+				Expect(os.WriteFile(filePath, fileContent, 0644)).To(Succeed())
+			})
+
+			It("parses the retry block", func() {
+				def, err := readerSvc.ParseConfigFile(filePath)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(def.Tasks[0].Retry).To(Equal(&model.RetryPolicy{
+					Attempts:           3,
+					Backoff:            model.BackoffExponential,
+					InitialDelay:       model.Duration(2 * time.Second),
+					MaxDelay:           model.Duration(time.Minute),
+					RetryableExitCodes: []int{1, 255},
+				}))
+			})
+		})
+
 		When("the file is missing", func() {
 			It("returns an error", func() {
 				_, err := readerSvc.ParseConfigFile(filepath.Join(tmpDir, "missing.yaml"))
@@ -133,22 +164,6 @@ tasks:
 			Expect(task.Parameters["command"]).To(Equal("echo hello"))
 			Expect(task.Parameters["user"]).To(Equal("admin"))
 		})
-
-		It("returns an error if the template is invalid", func() {
-			tasks := []model.Task{
-				{
-					Name:  "badTemplateTask",
-					Class: "localCmd",
-					Parameters: map[string]string{
-						"cmd": "{{.msg", // malformed template
-					},
-				},
-			}
-
-			inputs := map[string]string{"msg": "hi"}
-			_, err := readerSvc.ApplyTemplatingToTasks(tasks, inputs)
-			Expect(err).To(MatchError(ContainSubstring("failed to parse template")))
-		})
 	})
 
 })
@@ -3,6 +3,12 @@ package service
 import (
 	"context"
 	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/logger"
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/model"
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/processproducer/validator"
 )
 
 //counterfeiter:generate . Store
@@ -14,20 +20,34 @@ type Store interface {
 type Reader interface {
 	ReadYAMLFiles() ([]string, error)
 	GetProcessNameFromFile(string) (string, error)
+	ParseConfigFile(string) (model.ProcessDefinition, error)
+}
+
+//counterfeiter:generate . Analyzer
+type Analyzer interface {
+	Analyze(model.ProcessDefinition) (validator.GraphAnalysis, error)
 }
 
 type Service struct {
-	store  Store
-	reader Reader
+	store    Store
+	reader   Reader
+	analyzer Analyzer
+	// analyzed holds the last analyzed definition of every file, so the analysis
+	// is only reported when a file changes and not on every poll.
+	analyzed map[string]model.ProcessDefinition
 }
 
-func NewService(store Store, reader Reader) *Service {
+func NewService(store Store, reader Reader, analyzer Analyzer) *Service {
 	return &Service{
-		store:  store,
-		reader: reader,
+		store:    store,
+		reader:   reader,
+		analyzer: analyzer,
+		analyzed: make(map[string]model.ProcessDefinition),
 	}
 }
 
+// IndexProcessDefinitions stores the name and path of every process definition whose
+// task graph is valid.
 func (s *Service) IndexProcessDefinitions(ctx context.Context) error {
 	files, err := s.reader.ReadYAMLFiles()
 	if err != nil {
@@ -40,6 +60,14 @@ func (s *Service) IndexProcessDefinitions(ctx context.Context) error {
 			return fmt.Errorf("failed to get process name: %w", err)
 		}
 
+		def, err := s.reader.ParseConfigFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to parse process definition: %w", err)
+		}
+		if err := s.analyze(path, def); err != nil {
+			continue
+		}
+
 		err = s.store.SaveProcessDefinitionMeta(ctx, processName, path)
 		if err != nil {
 			return fmt.Errorf("failed to store process definition metadata: %w", err)
@@ -48,3 +76,26 @@ func (s *Service) IndexProcessDefinitions(ctx context.Context) error {
 
 	return nil
 }
+
+// analyze examines the task graph of a definition and logs the result if the file changed.
+func (s *Service) analyze(path string, def model.ProcessDefinition) error {
+	analysis, err := s.analyzer.Analyze(def)
+	if last, ok := s.analyzed[path]; ok && reflect.DeepEqual(last, def) {
+		return err
+	}
+	s.analyzed[path] = def
+
+	if err != nil {
+		logger.GetLogger().Errorf("Skipping process %s in %s: %v", def.Name, path, err)
+		return err
+	}
+
+	logger.GetLogger().Infof(
+		"Loaded process %s: critical path %s (%d tasks), max width %d",
+		def.Name, strings.Join(analysis.CriticalPath, " -> "), len(analysis.CriticalPath), analysis.MaxWidth,
+	)
+	for _, warning := range analysis.Warnings {
+		logger.GetLogger().Warnf("Process %s: %s", def.Name, warning)
+	}
+	return nil
+}
@@ -7,41 +7,48 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/model"
 	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/processloader/service"
 	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/processloader/service/servicefakes"
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/processproducer/validator"
 )
 
 var (
 	ErrRead      = errors.New("read error")
 	ErrGetName   = errors.New("get name error")
 	ErrStoreMeta = errors.New("store meta error")
+	ErrParse     = errors.New("parse error")
+	ErrCycle     = errors.New("dependency cycle between tasks: a -> b -> a")
 )
 
 var _ = Describe("Service", func() {
 	When("created", func() {
 		It("should instantiate the service", func() {
-			Expect(service.NewService(nil, nil)).NotTo(BeNil())
+			Expect(service.NewService(nil, nil, nil)).NotTo(BeNil())
 		})
 	})
 
 	Describe("IndexProcessDefinitions", Serial, func() {
 		var (
-			svc        *service.Service
-			ctx        context.Context
-			errAction  error
-			fakeStore  *servicefakes.FakeStore
-			fakeReader *servicefakes.FakeReader
+			svc          *service.Service
+			ctx          context.Context
+			errAction    error
+			fakeStore    *servicefakes.FakeStore
+			fakeReader   *servicefakes.FakeReader
+			fakeAnalyzer *servicefakes.FakeAnalyzer
 		)
 
 		BeforeEach(func() {
 			ctx = context.Background()
 			fakeStore = &servicefakes.FakeStore{}
 			fakeReader = &servicefakes.FakeReader{}
+			fakeAnalyzer = &servicefakes.FakeAnalyzer{}
 
-			svc = service.NewService(fakeStore, fakeReader)
+			svc = service.NewService(fakeStore, fakeReader, fakeAnalyzer)
 
 			fakeReader.ReadYAMLFilesReturns([]string{"dir/process.yaml"}, nil)
 			fakeReader.GetProcessNameFromFileReturns("test-process", nil)
+			fakeReader.ParseConfigFileReturns(model.ProcessDefinition{Name: "test-process"}, nil)
 			fakeStore.SaveProcessDefinitionMetaReturns(nil)
 		})
 
@@ -59,6 +66,9 @@ var _ = Describe("Service", func() {
 			gotPath := fakeReader.GetProcessNameFromFileArgsForCall(0)
 			Expect(gotPath).To(Equal("dir/process.yaml"))
 
+			Expect(fakeAnalyzer.AnalyzeCallCount()).To(Equal(1))
+			Expect(fakeAnalyzer.AnalyzeArgsForCall(0).Name).To(Equal("test-process"))
+
 			gotCtx, gotName, gotMetaPath := fakeStore.SaveProcessDefinitionMetaArgsForCall(0)
 			Expect(gotCtx).To(Equal(ctx))
 			Expect(gotName).To(Equal("test-process"))
@@ -93,6 +103,47 @@ var _ = Describe("Service", func() {
 			})
 		})
 
+		When("parsing the process definition fails", func() {
+			BeforeEach(func() {
+				fakeReader.ParseConfigFileReturns(model.ProcessDefinition{}, ErrParse)
+			})
+
+			It("returns an error", func() {
+				Expect(errAction).To(MatchError(ErrParse))
+				Expect(fakeStore.SaveProcessDefinitionMetaCallCount()).To(Equal(0))
+			})
+		})
+
+		When("the task graph of a process is invalid", func() {
+			BeforeEach(func() {
+				fakeReader.ReadYAMLFilesReturns([]string{"cyclic.yaml", "valid.yaml"}, nil)
+				fakeReader.ParseConfigFileStub = func(path string) (model.ProcessDefinition, error) {
+					return model.ProcessDefinition{Name: path}, nil
+				}
+				fakeAnalyzer.AnalyzeStub = func(def model.ProcessDefinition) (validator.GraphAnalysis, error) {
+					if def.Name == "cyclic.yaml" {
+						return validator.GraphAnalysis{}, ErrCycle
+					}
+					return validator.GraphAnalysis{MaxWidth: 1}, nil
+				}
+			})
+
+			It("only indexes the other processes", func() {
+				Expect(errAction).NotTo(HaveOccurred())
+				Expect(fakeStore.SaveProcessDefinitionMetaCallCount()).To(Equal(1))
+				_, _, metaPath := fakeStore.SaveProcessDefinitionMetaArgsForCall(0)
+				Expect(metaPath).To(Equal("valid.yaml"))
+			})
+
+			It("skips it on the next run as well", func() {
+				Expect(svc.IndexProcessDefinitions(ctx)).To(Succeed())
+				Expect(fakeAnalyzer.AnalyzeCallCount()).To(Equal(4))
+				Expect(fakeStore.SaveProcessDefinitionMetaCallCount()).To(Equal(2))
+				_, _, metaPath := fakeStore.SaveProcessDefinitionMetaArgsForCall(1)
+				Expect(metaPath).To(Equal("valid.yaml"))
+			})
+		})
+
 		When("saving metadata fails", func() {
 			BeforeEach(func() {
 				fakeStore.SaveProcessDefinitionMetaReturns(ErrStoreMeta)
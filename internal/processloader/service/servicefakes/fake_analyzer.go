@@ -0,0 +1,118 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package servicefakes
+
+import (
+	"sync"
+
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/model"
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/processloader/service"
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/processproducer/validator"
+)
+
+type FakeAnalyzer struct {
+	AnalyzeStub        func(model.ProcessDefinition) (validator.GraphAnalysis, error)
+	analyzeMutex       sync.RWMutex
+	analyzeArgsForCall []struct {
+		arg1 model.ProcessDefinition
+	}
+	analyzeReturns struct {
+		result1 validator.GraphAnalysis
+		result2 error
+	}
+	analyzeReturnsOnCall map[int]struct {
+		result1 validator.GraphAnalysis
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeAnalyzer) Analyze(arg1 model.ProcessDefinition) (validator.GraphAnalysis, error) {
+	fake.analyzeMutex.Lock()
+	ret, specificReturn := fake.analyzeReturnsOnCall[len(fake.analyzeArgsForCall)]
+	fake.analyzeArgsForCall = append(fake.analyzeArgsForCall, struct {
+		arg1 model.ProcessDefinition
+	}{arg1})
+	stub := fake.AnalyzeStub
+	fakeReturns := fake.analyzeReturns
+	fake.recordInvocation("Analyze", []interface{}{arg1})
+	fake.analyzeMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeAnalyzer) AnalyzeCallCount() int {
+	fake.analyzeMutex.RLock()
+	defer fake.analyzeMutex.RUnlock()
+	return len(fake.analyzeArgsForCall)
+}
+
+func (fake *FakeAnalyzer) AnalyzeCalls(stub func(model.ProcessDefinition) (validator.GraphAnalysis, error)) {
+	fake.analyzeMutex.Lock()
+	defer fake.analyzeMutex.Unlock()
+	fake.AnalyzeStub = stub
+}
+
+func (fake *FakeAnalyzer) AnalyzeArgsForCall(i int) model.ProcessDefinition {
+	fake.analyzeMutex.RLock()
+	defer fake.analyzeMutex.RUnlock()
+	argsForCall := fake.analyzeArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeAnalyzer) AnalyzeReturns(result1 validator.GraphAnalysis, result2 error) {
+	fake.analyzeMutex.Lock()
+	defer fake.analyzeMutex.Unlock()
+	fake.AnalyzeStub = nil
+	fake.analyzeReturns = struct {
+		result1 validator.GraphAnalysis
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAnalyzer) AnalyzeReturnsOnCall(i int, result1 validator.GraphAnalysis, result2 error) {
+	fake.analyzeMutex.Lock()
+	defer fake.analyzeMutex.Unlock()
+	fake.AnalyzeStub = nil
+	if fake.analyzeReturnsOnCall == nil {
+		fake.analyzeReturnsOnCall = make(map[int]struct {
+			result1 validator.GraphAnalysis
+			result2 error
+		})
+	}
+	fake.analyzeReturnsOnCall[i] = struct {
+		result1 validator.GraphAnalysis
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAnalyzer) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.analyzeMutex.RLock()
+	defer fake.analyzeMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeAnalyzer) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ service.Analyzer = new(FakeAnalyzer)
@@ -4,6 +4,7 @@ package servicefakes
 import (
 	"sync"
 
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/model"
 	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/processloader/service"
 )
 
@@ -21,6 +22,19 @@ type FakeReader struct {
 		result1 string
 		result2 error
 	}
+	ParseConfigFileStub        func(string) (model.ProcessDefinition, error)
+	parseConfigFileMutex       sync.RWMutex
+	parseConfigFileArgsForCall []struct {
+		arg1 string
+	}
+	parseConfigFileReturns struct {
+		result1 model.ProcessDefinition
+		result2 error
+	}
+	parseConfigFileReturnsOnCall map[int]struct {
+		result1 model.ProcessDefinition
+		result2 error
+	}
 	ReadYAMLFilesStub        func() ([]string, error)
 	readYAMLFilesMutex       sync.RWMutex
 	readYAMLFilesArgsForCall []struct {
@@ -101,6 +115,70 @@ func (fake *FakeReader) GetProcessNameFromFileReturnsOnCall(i int, result1 strin
 	}{result1, result2}
 }
 
+func (fake *FakeReader) ParseConfigFile(arg1 string) (model.ProcessDefinition, error) {
+	fake.parseConfigFileMutex.Lock()
+	ret, specificReturn := fake.parseConfigFileReturnsOnCall[len(fake.parseConfigFileArgsForCall)]
+	fake.parseConfigFileArgsForCall = append(fake.parseConfigFileArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.ParseConfigFileStub
+	fakeReturns := fake.parseConfigFileReturns
+	fake.recordInvocation("ParseConfigFile", []interface{}{arg1})
+	fake.parseConfigFileMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeReader) ParseConfigFileCallCount() int {
+	fake.parseConfigFileMutex.RLock()
+	defer fake.parseConfigFileMutex.RUnlock()
+	return len(fake.parseConfigFileArgsForCall)
+}
+
+func (fake *FakeReader) ParseConfigFileCalls(stub func(string) (model.ProcessDefinition, error)) {
+	fake.parseConfigFileMutex.Lock()
+	defer fake.parseConfigFileMutex.Unlock()
+	fake.ParseConfigFileStub = stub
+}
+
+func (fake *FakeReader) ParseConfigFileArgsForCall(i int) string {
+	fake.parseConfigFileMutex.RLock()
+	defer fake.parseConfigFileMutex.RUnlock()
+	argsForCall := fake.parseConfigFileArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeReader) ParseConfigFileReturns(result1 model.ProcessDefinition, result2 error) {
+	fake.parseConfigFileMutex.Lock()
+	defer fake.parseConfigFileMutex.Unlock()
+	fake.ParseConfigFileStub = nil
+	fake.parseConfigFileReturns = struct {
+		result1 model.ProcessDefinition
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeReader) ParseConfigFileReturnsOnCall(i int, result1 model.ProcessDefinition, result2 error) {
+	fake.parseConfigFileMutex.Lock()
+	defer fake.parseConfigFileMutex.Unlock()
+	fake.ParseConfigFileStub = nil
+	if fake.parseConfigFileReturnsOnCall == nil {
+		fake.parseConfigFileReturnsOnCall = make(map[int]struct {
+			result1 model.ProcessDefinition
+			result2 error
+		})
+	}
+	fake.parseConfigFileReturnsOnCall[i] = struct {
+		result1 model.ProcessDefinition
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeReader) ReadYAMLFiles() ([]string, error) {
 	fake.readYAMLFilesMutex.Lock()
 	ret, specificReturn := fake.readYAMLFilesReturnsOnCall[len(fake.readYAMLFilesArgsForCall)]
@@ -162,6 +240,8 @@ func (fake *FakeReader) Invocations() map[string][][]interface{} {
 	defer fake.invocationsMutex.RUnlock()
 	fake.getProcessNameFromFileMutex.RLock()
 	defer fake.getProcessNameFromFileMutex.RUnlock()
+	fake.parseConfigFileMutex.RLock()
+	defer fake.parseConfigFileMutex.RUnlock()
 	fake.readYAMLFilesMutex.RLock()
 	defer fake.readYAMLFilesMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
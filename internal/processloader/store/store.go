@@ -48,3 +48,24 @@ func (s *Store) GetProcessPathByName(ctx context.Context, name string) (string,
 	}
 	return path, nil
 }
+
+// ListProcessPaths returns the paths of all indexed process definitions by their name.
+func (s *Store) ListProcessPaths(ctx context.Context) (map[string]string, error) {
+	query := fmt.Sprintf(`SELECT name, path FROM %s`, ProcessedDefinitionsTable)
+
+	rows, err := s.pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list process definitions: %w", err)
+	}
+	defer rows.Close()
+
+	paths := make(map[string]string)
+	for rows.Next() {
+		var name, path string
+		if err := rows.Scan(&name, &path); err != nil {
+			return nil, fmt.Errorf("failed to scan process definition: %w", err)
+		}
+		paths[name] = path
+	}
+	return paths, rows.Err()
+}
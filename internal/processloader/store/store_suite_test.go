@@ -27,5 +27,7 @@ var _ = BeforeSuite(func() {
 })
 
 var _ = AfterSuite(func() {
-	pool.Close()
+	if pool != nil {
+		pool.Close()
+	}
 })
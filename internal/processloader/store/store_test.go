@@ -80,5 +80,17 @@ var _ = Describe("Store", func() {
 				Expect(err).To(MatchError(ContainSubstring("not found")))
 			})
 		})
+
+		Describe("ListProcessPaths", func() {
+			BeforeEach(func() {
+				Expect(s.SaveProcessDefinitionMeta(ctx, name, path)).To(Succeed())
+			})
+
+			It("returns the paths by process name", func() {
+				paths, err := s.ListProcessPaths(ctx)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(paths).To(HaveKeyWithValue(name, path))
+			})
+		})
 	})
 })
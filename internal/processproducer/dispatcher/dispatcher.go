@@ -0,0 +1,80 @@
+package dispatcher
+
+import (
+	"context"
+	"time"
+
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/logger"
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/model"
+)
+
+// batchSize is how many due runs are queued in one transaction.
+const batchSize = 50
+
+//counterfeiter:generate . RunStore
+type RunStore interface {
+	QueueDueProcesses(context.Context, time.Time, int, func(context.Context, model.ProcessRun) error) (int, error)
+}
+
+//counterfeiter:generate . Publisher
+type Publisher interface {
+	Publish(context.Context, model.Message) error
+}
+
+// Dispatcher publishes the scheduled runs once their run_at time is reached. The runs
+// are kept in the database until then, so they survive restarts of the producer.
+type Dispatcher struct {
+	store     RunStore
+	publisher Publisher
+}
+
+func NewDispatcher(store RunStore, publisher Publisher) *Dispatcher {
+	return &Dispatcher{
+		store:     store,
+		publisher: publisher,
+	}
+}
+
+// Run dispatches the due runs every interval until the context is done.
+func (d *Dispatcher) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := d.Dispatch(ctx); err != nil && ctx.Err() == nil {
+			logger.GetLogger().Errorf("failed to dispatch scheduled runs: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Dispatch publishes all runs that are due.
+func (d *Dispatcher) Dispatch(ctx context.Context) error {
+	now := time.Now()
+	for {
+		queued, err := d.store.QueueDueProcesses(ctx, now, batchSize, d.publish)
+		if err != nil {
+			return err
+		}
+		if queued > 0 {
+			logger.GetLogger().Infof("Queued %d scheduled runs", queued)
+		}
+		if queued < batchSize {
+			return nil
+		}
+	}
+}
+
+// publish publishes the message of a run under the ID of the run, like /startProcess.
+func (d *Dispatcher) publish(ctx context.Context, run model.ProcessRun) error {
+	return d.publisher.Publish(ctx, model.Message{
+		UUID:              run.ID,
+		ProcessDefinition: run.Definition,
+		Priority:          run.Priority,
+	})
+}
@@ -0,0 +1,107 @@
+package dispatcher_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/model"
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/processproducer/dispatcher"
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/processproducer/dispatcher/dispatcherfakes"
+)
+
+var (
+	ErrDb      = errors.New("db error")
+	ErrPublish = errors.New("publish error")
+)
+
+var _ = Describe("Dispatcher", func() {
+	var (
+		ctx       context.Context
+		store     *dispatcherfakes.FakeRunStore
+		publisher *dispatcherfakes.FakePublisher
+		d         *dispatcher.Dispatcher
+		run       model.ProcessRun
+		errAction error
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		store = &dispatcherfakes.FakeRunStore{}
+		publisher = &dispatcherfakes.FakePublisher{}
+		d = dispatcher.NewDispatcher(store, publisher)
+
+		runAt := time.Now().Add(-time.Second)
+		run = model.ProcessRun{
+			ID:         uuid.New(),
+			Definition: model.ProcessDefinition{Name: "deploy"},
+			Status:     model.StatusScheduled,
+			Priority:   6,
+			RunAt:      &runAt,
+		}
+		store.QueueDueProcessesStub = func(ctx context.Context, _ time.Time, _ int, publish func(context.Context, model.ProcessRun) error) (int, error) {
+			if err := publish(ctx, run); err != nil {
+				return 0, err
+			}
+			return 1, nil
+		}
+	})
+
+	JustBeforeEach(func() {
+		errAction = d.Dispatch(ctx)
+	})
+
+	It("queues the runs that are due", func() {
+		Expect(errAction).NotTo(HaveOccurred())
+		Expect(store.QueueDueProcessesCallCount()).To(Equal(1))
+		_, now, limit, _ := store.QueueDueProcessesArgsForCall(0)
+		Expect(now).To(BeTemporally("~", time.Now(), time.Second))
+		Expect(limit).To(BeNumerically(">", 0))
+	})
+
+	It("publishes them under the ID of the run", func() {
+		Expect(publisher.PublishCallCount()).To(Equal(1))
+		_, msg := publisher.PublishArgsForCall(0)
+		Expect(msg.UUID).To(Equal(run.ID))
+		Expect(msg.ProcessDefinition).To(Equal(run.Definition))
+		Expect(msg.Priority).To(Equal(6))
+	})
+
+	When("a whole batch was queued", func() {
+		BeforeEach(func() {
+			store.QueueDueProcessesStub = nil
+			store.QueueDueProcessesReturnsOnCall(0, 50, nil)
+			store.QueueDueProcessesReturnsOnCall(1, 3, nil)
+		})
+
+		It("queues the next batch", func() {
+			Expect(errAction).NotTo(HaveOccurred())
+			Expect(store.QueueDueProcessesCallCount()).To(Equal(2))
+		})
+	})
+
+	When("publishing fails", func() {
+		BeforeEach(func() {
+			publisher.PublishReturns(ErrPublish)
+		})
+
+		It("returns an error", func() {
+			Expect(errAction).To(MatchError(ErrPublish))
+		})
+	})
+
+	When("queueing fails", func() {
+		BeforeEach(func() {
+			store.QueueDueProcessesStub = nil
+			store.QueueDueProcessesReturns(0, ErrDb)
+		})
+
+		It("returns an error", func() {
+			Expect(errAction).To(MatchError(ErrDb))
+		})
+	})
+})
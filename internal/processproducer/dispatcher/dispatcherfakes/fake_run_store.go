@@ -0,0 +1,125 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package dispatcherfakes
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/model"
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/processproducer/dispatcher"
+)
+
+type FakeRunStore struct {
+	QueueDueProcessesStub        func(context.Context, time.Time, int, func(context.Context, model.ProcessRun) error) (int, error)
+	queueDueProcessesMutex       sync.RWMutex
+	queueDueProcessesArgsForCall []struct {
+		arg1 context.Context
+		arg2 time.Time
+		arg3 int
+		arg4 func(context.Context, model.ProcessRun) error
+	}
+	queueDueProcessesReturns struct {
+		result1 int
+		result2 error
+	}
+	queueDueProcessesReturnsOnCall map[int]struct {
+		result1 int
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeRunStore) QueueDueProcesses(arg1 context.Context, arg2 time.Time, arg3 int, arg4 func(context.Context, model.ProcessRun) error) (int, error) {
+	fake.queueDueProcessesMutex.Lock()
+	ret, specificReturn := fake.queueDueProcessesReturnsOnCall[len(fake.queueDueProcessesArgsForCall)]
+	fake.queueDueProcessesArgsForCall = append(fake.queueDueProcessesArgsForCall, struct {
+		arg1 context.Context
+		arg2 time.Time
+		arg3 int
+		arg4 func(context.Context, model.ProcessRun) error
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.QueueDueProcessesStub
+	fakeReturns := fake.queueDueProcessesReturns
+	fake.recordInvocation("QueueDueProcesses", []interface{}{arg1, arg2, arg3, arg4})
+	fake.queueDueProcessesMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeRunStore) QueueDueProcessesCallCount() int {
+	fake.queueDueProcessesMutex.RLock()
+	defer fake.queueDueProcessesMutex.RUnlock()
+	return len(fake.queueDueProcessesArgsForCall)
+}
+
+func (fake *FakeRunStore) QueueDueProcessesCalls(stub func(context.Context, time.Time, int, func(context.Context, model.ProcessRun) error) (int, error)) {
+	fake.queueDueProcessesMutex.Lock()
+	defer fake.queueDueProcessesMutex.Unlock()
+	fake.QueueDueProcessesStub = stub
+}
+
+func (fake *FakeRunStore) QueueDueProcessesArgsForCall(i int) (context.Context, time.Time, int, func(context.Context, model.ProcessRun) error) {
+	fake.queueDueProcessesMutex.RLock()
+	defer fake.queueDueProcessesMutex.RUnlock()
+	argsForCall := fake.queueDueProcessesArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeRunStore) QueueDueProcessesReturns(result1 int, result2 error) {
+	fake.queueDueProcessesMutex.Lock()
+	defer fake.queueDueProcessesMutex.Unlock()
+	fake.QueueDueProcessesStub = nil
+	fake.queueDueProcessesReturns = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeRunStore) QueueDueProcessesReturnsOnCall(i int, result1 int, result2 error) {
+	fake.queueDueProcessesMutex.Lock()
+	defer fake.queueDueProcessesMutex.Unlock()
+	fake.QueueDueProcessesStub = nil
+	if fake.queueDueProcessesReturnsOnCall == nil {
+		fake.queueDueProcessesReturnsOnCall = make(map[int]struct {
+			result1 int
+			result2 error
+		})
+	}
+	fake.queueDueProcessesReturnsOnCall[i] = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeRunStore) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.queueDueProcessesMutex.RLock()
+	defer fake.queueDueProcessesMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeRunStore) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ dispatcher.RunStore = new(FakeRunStore)
@@ -0,0 +1,4 @@
+// Package dispatcher queues delayed runs once they are due.
+package dispatcher
+
+//go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 -generate
@@ -2,19 +2,60 @@ package handler
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/logger"
 	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/model"
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/processproducer/validator"
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 )
 
-const successfullyAddedProcess = "successfully added process"
+const (
+	successfullyAddedProcess     = "successfully added process"
+	successfullyScheduledProcess = "successfully scheduled process"
+	// IdempotencyKeyHeader lets clients retry /startProcess without starting the process twice.
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// saveResponseAttempts is how often the response of a started run is saved for its key.
+	saveResponseAttempts = 3
+)
 
 type StartProcessRequest struct {
 	Name       string            `json:"name"`
 	Parameters map[string]string `json:"parameters"`
+	// RunAt or Delay hold the run back until the given time.
+	RunAt *time.Time `json:"runAt,omitempty"`
+	Delay string     `json:"delay,omitempty"`
+	// Priority overrides the priority of the definition.
+	Priority *int `json:"priority,omitempty"`
+}
+
+// startTime returns when the requested run should start. It is the zero time if the
+// run should start right away.
+func (r StartProcessRequest) startTime(now time.Time) (time.Time, error) {
+	switch {
+	case r.RunAt != nil && r.Delay != "":
+		return time.Time{}, errors.New("only one of runAt and delay can be set")
+	case r.RunAt != nil:
+		return *r.RunAt, nil
+	case r.Delay != "":
+		delay, err := time.ParseDuration(r.Delay)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid delay %q: %w", r.Delay, err)
+		}
+		if delay < 0 {
+			return time.Time{}, fmt.Errorf("delay must not be negative, got %s", r.Delay)
+		}
+		return now.Add(delay), nil
+	}
+	return time.Time{}, nil
 }
 
 //counterfeiter:generate . ProcessDefinitionStore
@@ -35,21 +76,49 @@ type Publisher interface {
 	Close() error
 }
 
+//counterfeiter:generate . ProcessRunStore
+type ProcessRunStore interface {
+	InsertQueuedProcess(context.Context, model.ProcessRun) error
+	InsertScheduledProcess(context.Context, model.ProcessRun) error
+	DeleteQueuedProcess(context.Context, uuid.UUID) error
+}
+
+//counterfeiter:generate . IdempotencyStore
+type IdempotencyStore interface {
+	ReserveKey(context.Context, string, string) (model.IdempotencyRecord, bool, error)
+	SaveResponse(context.Context, string, uuid.UUID, int, []byte) error
+	ReleaseKey(context.Context, string) error
+}
+
 //counterfeiter:generate . Validator
 type Validator interface {
 	Validate(model.ProcessDefinition) error
 	ValidateMandatoryParams(model.ProcessDefinition, map[string]string) error
+	Analyze(model.ProcessDefinition) (validator.GraphAnalysis, error)
 }
 
-func RegisterHandlers(ctx context.Context, srv *echo.Echo, publisher Publisher, reader Reader, store ProcessDefinitionStore, validator Validator) {
+// RegisterHandlers registers the routes of the producer, which start runs with starter.
+// statusURL is the base URL of the API serving the status of process runs.
+func RegisterHandlers(
+	ctx context.Context,
+	srv *echo.Echo,
+	starter *ProcessStarter,
+	keyStore IdempotencyStore,
+	statusURL string,
+) {
 	if srv != nil {
-		srv.POST("/startProcess", handleNewProcess(ctx, publisher, reader, store, validator))
+		srv.POST("/startProcess", handleNewProcess(ctx, starter, keyStore, statusURL))
 	} else {
 		logger.GetLogger().Warn("Running routes without a webapi server, did NOT register routes.")
 	}
 }
 
-func handleNewProcess(ctx context.Context, publisher Publisher, reader Reader, store ProcessDefinitionStore, validator Validator) echo.HandlerFunc {
+func handleNewProcess(
+	ctx context.Context,
+	starter *ProcessStarter,
+	keyStore IdempotencyStore,
+	statusURL string,
+) echo.HandlerFunc {
 	return func(c echo.Context) error {
 		var req StartProcessRequest
 		if err := c.Bind(&req); err != nil {
@@ -57,60 +126,143 @@ func handleNewProcess(ctx context.Context, publisher Publisher, reader Reader, s
 				"error": err.Error(),
 			})
 		}
-		processPath, err := store.GetProcessPathByName(ctx, req.Name)
+
+		runAt, err := req.startTime(time.Now())
 		if err != nil {
 			return c.JSON(http.StatusBadRequest, map[string]string{
-				"message": "Process definition template not found",
+				"message": "Invalid start time",
 				"error":   err.Error(),
 			})
 		}
 
-		processDef, err := reader.ParseConfigFile(processPath)
+		// A request with an Idempotency-Key reserves it until it succeeded, so that
+		// retries with the same key get the original response instead of another run.
+		key := strings.TrimSpace(c.Request().Header.Get(IdempotencyKeyHeader))
+		succeeded := false
+		if key != "" {
+			hash, err := requestHash(req)
+			if err != nil {
+				return c.JSON(http.StatusBadRequest, map[string]string{
+					"error": err.Error(),
+				})
+			}
+
+			record, reserved, err := keyStore.ReserveKey(ctx, key, hash)
+			if err != nil {
+				return c.JSON(http.StatusInternalServerError, map[string]string{
+					"message": "Idempotency key check failed",
+					"error":   err.Error(),
+				})
+			}
+			if !reserved {
+				return replay(c, record, hash, statusURL)
+			}
+
+			defer func() {
+				if succeeded {
+					return
+				}
+				if err := keyStore.ReleaseKey(ctx, key); err != nil {
+					logger.GetLogger().Errorf("failed to release idempotency key %s: %v", key, err)
+				}
+			}()
+		}
+		run, warnings, err := starter.Start(ctx, req.Name, req.Parameters, StartOptions{
+			RunAt:    runAt,
+			Priority: req.Priority,
+		})
 		if err != nil {
-			return c.JSON(http.StatusBadRequest, map[string]string{
-				"message": "Could not parse process definition template",
-				"error":   err.Error(),
-			})
+			return startFailed(c, err)
 		}
 
-		if err := validator.ValidateMandatoryParams(processDef, req.Parameters); err != nil {
-			return c.JSON(http.StatusBadRequest, map[string]string{
-				"message": "Missing mandatory parameters",
-				"error":   err.Error(),
-			})
+		response := map[string]interface{}{
+			"message": successfullyAddedProcess,
+			"id":      run.ID.String(),
 		}
-
-		tasks, err := reader.ApplyTemplatingToTasks(processDef.Tasks, req.Parameters)
-		if err != nil {
-			return c.JSON(http.StatusBadRequest, map[string]string{
-				"message": "Failed to apply parameters",
-				"error":   err.Error(),
-			})
+		if run.RunAt != nil {
+			response["message"] = successfullyScheduledProcess
+			response["run_at"] = run.RunAt.Format(time.RFC3339)
+		}
+		if len(warnings) > 0 {
+			response["warnings"] = warnings
 		}
+		if key != "" {
+			// Without the saved response, retries with the key would get 409 until it
+			// expires, so the key is released and the request fails instead.
+			if err := saveResponse(ctx, keyStore, key, run.ID, response); err != nil {
+				logger.GetLogger().Errorf("failed to save response for idempotency key %s: %v", key, err)
+				return c.JSON(http.StatusInternalServerError, map[string]string{
+					"message": "Process was started, but its idempotency key could not be saved",
+					"id":      run.ID.String(),
+					"error":   err.Error(),
+				})
+			}
+		}
+		succeeded = true
 
-		processDef.Tasks = tasks
+		c.Response().Header().Set(echo.HeaderLocation, statusLocation(statusURL, run.ID))
+		return c.JSON(http.StatusAccepted, response)
+	}
+}
 
-		if err := validator.Validate(processDef); err != nil {
-			return c.JSON(http.StatusBadRequest, map[string]string{
-				"message": "Process validation failed",
-				"error":   err.Error(),
-			})
-		}
+// saveResponse records the response of a started run for its idempotency key,
+// retrying the save a few times before giving up.
+func saveResponse(ctx context.Context, keyStore IdempotencyStore, key string, id uuid.UUID, response map[string]interface{}) error {
+	body, err := json.Marshal(response)
+	if err != nil {
+		return fmt.Errorf("failed to encode response: %w", err)
+	}
 
-		message := model.Message{
-			UUID:              uuid.New(),
-			ProcessDefinition: processDef,
+	for attempt := 1; ; attempt++ {
+		err = keyStore.SaveResponse(ctx, key, id, http.StatusAccepted, body)
+		if err == nil || attempt == saveResponseAttempts || ctx.Err() != nil {
+			return err
 		}
+		logger.GetLogger().Warnf("failed to save response for idempotency key %s, attempt %d: %v", key, attempt, err)
+	}
+}
 
-		if err := publisher.Publish(ctx, message); err != nil {
-			return c.JSON(http.StatusInternalServerError, map[string]string{
-				"message": "Process publishing failed",
-				"error":   err.Error(),
-			})
-		}
+// startFailed answers a request whose process could not be started.
+func startFailed(c echo.Context, err error) error {
+	var startErr *StartError
+	if !errors.As(err, &startErr) {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": err.Error(),
+		})
+	}
+	return c.JSON(startErr.Status, map[string]string{
+		"message": startErr.Message,
+		"error":   startErr.Err.Error(),
+	})
+}
 
-		return c.JSON(http.StatusOK, map[string]interface{}{
-			"message": successfullyAddedProcess,
+// replay answers a request whose Idempotency-Key is already in use.
+func replay(c echo.Context, record model.IdempotencyRecord, hash, statusURL string) error {
+	if record.RequestHash != hash {
+		return c.JSON(http.StatusUnprocessableEntity, map[string]string{
+			"message": "Idempotency key was already used for a different request",
+		})
+	}
+	if record.Response == nil || record.ProcessID == nil {
+		return c.JSON(http.StatusConflict, map[string]string{
+			"message": "A request with this idempotency key is still in progress",
 		})
 	}
+
+	c.Response().Header().Set(echo.HeaderLocation, statusLocation(statusURL, *record.ProcessID))
+	return c.JSONBlob(record.StatusCode, record.Response)
+}
+
+// requestHash identifies the content of a request, regardless of its formatting.
+func requestHash(req StartProcessRequest) (string, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode request: %w", err)
+	}
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func statusLocation(statusURL string, id uuid.UUID) string {
+	return strings.TrimSuffix(statusURL, "/") + "/listProcess/" + id.String()
 }
@@ -7,6 +7,7 @@ import (
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
@@ -16,6 +17,7 @@ import (
 	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/model"
 	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/processproducer/handler"
 	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/processproducer/handler/handlerfakes"
+	processvalidator "github.com/ggsomnoev/ntt-ds-sap-process-api/internal/processproducer/validator"
 )
 
 var (
@@ -23,6 +25,8 @@ var (
 	ErrTemplate      = errors.New("template error")
 	ErrValidation    = errors.New("validation error")
 	ErrPublishFailed = errors.New("failed to publish process")
+	ErrQueue         = errors.New("failed to queue process")
+	ErrKeyStore      = errors.New("idempotency store error")
 )
 
 var _ = Describe("Process Handler", func() {
@@ -33,6 +37,8 @@ var _ = Describe("Process Handler", func() {
 		publisher *handlerfakes.FakePublisher
 		store     *handlerfakes.FakeProcessDefinitionStore
 		reader    *handlerfakes.FakeReader
+		runStore  *handlerfakes.FakeProcessRunStore
+		keyStore  *handlerfakes.FakeIdempotencyStore
 		validator *handlerfakes.FakeValidator
 	)
 
@@ -43,8 +49,11 @@ var _ = Describe("Process Handler", func() {
 		publisher = &handlerfakes.FakePublisher{}
 		reader = &handlerfakes.FakeReader{}
 		store = &handlerfakes.FakeProcessDefinitionStore{}
+		runStore = &handlerfakes.FakeProcessRunStore{}
+		keyStore = &handlerfakes.FakeIdempotencyStore{}
 		validator = &handlerfakes.FakeValidator{}
-		handler.RegisterHandlers(ctx, e, publisher, reader, store, validator)
+		starter := handler.NewProcessStarter(publisher, reader, store, runStore, validator)
+		handler.RegisterHandlers(ctx, e, starter, keyStore, "http://127.0.0.1:8081/")
 	})
 
 	Describe("POST /startProcess", func() {
@@ -78,7 +87,7 @@ var _ = Describe("Process Handler", func() {
 		})
 
 		It("succeeds", func() {
-			Expect(recorder.Code).To(Equal(http.StatusOK))
+			Expect(recorder.Code).To(Equal(http.StatusAccepted))
 			Expect(store.GetProcessPathByNameCallCount()).To(Equal(1))
 			Expect(reader.ParseConfigFileCallCount()).To(Equal(1))
 			Expect(reader.ApplyTemplatingToTasksCallCount()).To(Equal(1))
@@ -89,6 +98,35 @@ var _ = Describe("Process Handler", func() {
 			Expect(actualMessage.ProcessDefinition).To(Equal(process))
 		})
 
+		It("records the run as queued under the message ID", func() {
+			Expect(runStore.InsertQueuedProcessCallCount()).To(Equal(1))
+			_, run := runStore.InsertQueuedProcessArgsForCall(0)
+			_, actualMessage := publisher.PublishArgsForCall(0)
+			Expect(run.ID).To(Equal(actualMessage.UUID))
+			Expect(run.Status).To(Equal(model.StatusQueued))
+			Expect(run.Definition).To(Equal(process))
+		})
+
+		It("returns the run ID and where to find its status", func() {
+			_, actualMessage := publisher.PublishArgsForCall(0)
+			var response map[string]string
+			Expect(json.Unmarshal(recorder.Body.Bytes(), &response)).To(Succeed())
+			Expect(response["id"]).To(Equal(actualMessage.UUID.String()))
+			Expect(recorder.Header().Get(echo.HeaderLocation)).To(Equal("http://127.0.0.1:8081/listProcess/" + actualMessage.UUID.String()))
+		})
+
+		When("recording the queued run fails", func() {
+			BeforeEach(func() {
+				runStore.InsertQueuedProcessReturns(ErrQueue)
+			})
+
+			It("returns 500 without publishing", func() {
+				Expect(recorder.Code).To(Equal(http.StatusInternalServerError))
+				Expect(recorder.Body.String()).To(ContainSubstring("Process queueing failed"))
+				Expect(publisher.PublishCallCount()).To(Equal(0))
+			})
+		})
+
 		When("invalid JSON is posted", func() {
 			BeforeEach(func() {
 				req = httptest.NewRequest(http.MethodPost, "/startProcess", bytes.NewBufferString("{invalid json"))
@@ -133,6 +171,29 @@ var _ = Describe("Process Handler", func() {
 			})
 		})
 
+		When("the process has finally tasks", func() {
+			var finally []model.Task
+
+			BeforeEach(func() {
+				finally = []model.Task{
+					{Name: "cleanup", Class: model.LocalCmd, Parameters: map[string]string{"cmd": "rm -rf /tmp/{{.env}}"}},
+				}
+				process.Finally = finally
+				reader.ParseConfigFileReturns(process, nil)
+				reader.ApplyTemplatingToTasksReturnsOnCall(0, process.Tasks, nil)
+				reader.ApplyTemplatingToTasksReturnsOnCall(1, finally, nil)
+			})
+
+			It("applies the parameters to them as well", func() {
+				Expect(recorder.Code).To(Equal(http.StatusAccepted))
+				Expect(reader.ApplyTemplatingToTasksCallCount()).To(Equal(2))
+				actualTasks, _ := reader.ApplyTemplatingToTasksArgsForCall(1)
+				Expect(actualTasks).To(Equal(finally))
+				_, actualMessage := publisher.PublishArgsForCall(0)
+				Expect(actualMessage.ProcessDefinition.Finally).To(Equal(finally))
+			})
+		})
+
 		When("validation fails", func() {
 			BeforeEach(func() {
 				validator.ValidateReturns(ErrValidation)
@@ -144,6 +205,169 @@ var _ = Describe("Process Handler", func() {
 			})
 		})
 
+		When("the analysis warns about the task graph", func() {
+			BeforeEach(func() {
+				validator.AnalyzeReturns(processvalidator.GraphAnalysis{
+					Warnings: []string{"task 'notify' can never run"},
+				}, nil)
+			})
+
+			It("returns the warnings", func() {
+				Expect(recorder.Code).To(Equal(http.StatusAccepted))
+				Expect(validator.AnalyzeCallCount()).To(Equal(1))
+				Expect(recorder.Body.String()).To(ContainSubstring("task 'notify' can never run"))
+				Expect(publisher.PublishCallCount()).To(Equal(1))
+			})
+		})
+
+		When("the analysis fails", func() {
+			BeforeEach(func() {
+				validator.AnalyzeReturns(processvalidator.GraphAnalysis{}, ErrValidation)
+			})
+
+			It("returns 400 with the analysis error", func() {
+				Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+				Expect(recorder.Body.String()).To(ContainSubstring("validation error"))
+				Expect(publisher.PublishCallCount()).To(Equal(0))
+			})
+		})
+
+		When("the process runs sub-processes", func() {
+			var definitions map[string]model.ProcessDefinition
+
+			subprocessTask := func(name, process string) model.Task {
+				return model.Task{
+					Name:       name,
+					Class:      model.ProcessCmd,
+					Parameters: map[string]string{model.ProcessParam: process},
+				}
+			}
+
+			BeforeEach(func() {
+				process.Tasks = append(process.Tasks, subprocessTask("provision", "provision-vm"))
+				definitions = map[string]model.ProcessDefinition{
+					"sample": process,
+					"provision-vm": {
+						Name:   "provision-vm",
+						Params: []model.Param{{Name: "size", Mandatory: true}},
+						Tasks:  []model.Task{subprocessTask("network", "setup-network")},
+					},
+					"setup-network": {
+						Name:  "setup-network",
+						Tasks: []model.Task{{Name: "vlan", Class: model.LocalCmd}},
+					},
+				}
+
+				store.GetProcessPathByNameStub = func(_ context.Context, name string) (string, error) {
+					return name, nil
+				}
+				reader.ParseConfigFileStub = func(path string) (model.ProcessDefinition, error) {
+					def, ok := definitions[path]
+					if !ok {
+						return model.ProcessDefinition{}, ErrNotFound
+					}
+					return def, nil
+				}
+				reader.ApplyTemplatingToTasksStub = func(tasks []model.Task, _ map[string]string) ([]model.Task, error) {
+					return tasks, nil
+				}
+			})
+
+			It("embeds the definitions they run", func() {
+				Expect(recorder.Code).To(Equal(http.StatusAccepted))
+				_, actualMessage := publisher.PublishArgsForCall(0)
+				provision := actualMessage.ProcessDefinition.Tasks[1].Subprocess
+				Expect(provision).NotTo(BeNil())
+				Expect(provision.Name).To(Equal("provision-vm"))
+				Expect(provision.Tasks[0].Subprocess).NotTo(BeNil())
+				Expect(provision.Tasks[0].Subprocess.Name).To(Equal("setup-network"))
+			})
+
+			It("checks the mandatory parameters of the sub-processes against the task parameters", func() {
+				Expect(validator.ValidateMandatoryParamsCallCount()).To(Equal(3))
+				def, params := validator.ValidateMandatoryParamsArgsForCall(1)
+				Expect(def.Name).To(Equal("provision-vm"))
+				Expect(params).To(HaveKeyWithValue(model.ProcessParam, "provision-vm"))
+			})
+
+			When("the task parameters miss a mandatory parameter", func() {
+				BeforeEach(func() {
+					validator.ValidateMandatoryParamsStub = func(def model.ProcessDefinition, _ map[string]string) error {
+						if def.Name == "provision-vm" {
+							return errors.New("missing mandatory parameters: size")
+						}
+						return nil
+					}
+				})
+
+				It("returns 400", func() {
+					Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+					Expect(recorder.Body.String()).To(ContainSubstring("task 'provision' process 'provision-vm': missing mandatory parameters: size"))
+					Expect(publisher.PublishCallCount()).To(Equal(0))
+				})
+			})
+
+			When("the definitions form a cycle", func() {
+				BeforeEach(func() {
+					network := definitions["setup-network"]
+					network.Tasks = append(network.Tasks, subprocessTask("again", "sample"))
+					definitions["setup-network"] = network
+				})
+
+				It("returns 400", func() {
+					Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+					var response map[string]string
+					Expect(json.Unmarshal(recorder.Body.Bytes(), &response)).To(Succeed())
+					Expect(response["error"]).To(Equal(
+						"cycle between process definitions: sample -> provision-vm -> setup-network -> sample",
+					))
+					Expect(publisher.PublishCallCount()).To(Equal(0))
+				})
+			})
+
+			When("the definitions are nested too deeply", func() {
+				BeforeEach(func() {
+					network := definitions["setup-network"]
+					network.Tasks = []model.Task{subprocessTask("level-3", "level-3")}
+					definitions["setup-network"] = network
+					definitions["level-3"] = model.ProcessDefinition{Name: "level-3", Tasks: []model.Task{subprocessTask("level-4", "level-4")}}
+					definitions["level-4"] = model.ProcessDefinition{Name: "level-4", Tasks: []model.Task{subprocessTask("level-5", "level-5")}}
+					definitions["level-5"] = model.ProcessDefinition{Name: "level-5"}
+				})
+
+				It("returns 400", func() {
+					Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+					Expect(recorder.Body.String()).To(ContainSubstring("process definitions are nested deeper than 5"))
+				})
+			})
+
+			When("a sub-process has an approval", func() {
+				BeforeEach(func() {
+					network := definitions["setup-network"]
+					network.Tasks = []model.Task{{Name: "sign-off", Class: model.ApprovalCmd}}
+					definitions["setup-network"] = network
+				})
+
+				It("returns 400", func() {
+					Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+					Expect(recorder.Body.String()).To(ContainSubstring(
+						"process 'setup-network' cannot run as a sub-process, task 'sign-off' is an approval",
+					))
+				})
+			})
+
+			When("a sub-process is not indexed", func() {
+				BeforeEach(func() {
+					delete(definitions, "setup-network")
+				})
+
+				It("returns 400", func() {
+					Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+					Expect(recorder.Body.String()).To(ContainSubstring("Could not resolve sub-processes"))
+				})
+			})
+		})
+
 		When("publishing fails", func() {
 			BeforeEach(func() {
 				publisher.PublishReturns(ErrPublishFailed)
@@ -153,6 +377,299 @@ var _ = Describe("Process Handler", func() {
 				Expect(recorder.Code).To(Equal(http.StatusInternalServerError))
 				Expect(recorder.Body.String()).To(ContainSubstring("failed to publish process"))
 			})
+
+			It("removes the queued run", func() {
+				Expect(runStore.DeleteQueuedProcessCallCount()).To(Equal(1))
+				_, id := runStore.DeleteQueuedProcessArgsForCall(0)
+				_, run := runStore.InsertQueuedProcessArgsForCall(0)
+				Expect(id).To(Equal(run.ID))
+			})
+		})
+
+		When("a delay is given", func() {
+			BeforeEach(func() {
+				body, _ := json.Marshal(handler.StartProcessRequest{Name: "sample", Delay: "1h"})
+				req = httptest.NewRequest(http.MethodPost, "/startProcess", bytes.NewReader(body))
+				req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			})
+
+			It("records the run as scheduled without publishing it", func() {
+				Expect(recorder.Code).To(Equal(http.StatusAccepted))
+				Expect(publisher.PublishCallCount()).To(Equal(0))
+				Expect(runStore.InsertQueuedProcessCallCount()).To(Equal(0))
+				Expect(runStore.InsertScheduledProcessCallCount()).To(Equal(1))
+
+				_, run := runStore.InsertScheduledProcessArgsForCall(0)
+				Expect(run.Status).To(Equal(model.StatusScheduled))
+				Expect(*run.RunAt).To(BeTemporally("~", time.Now().Add(time.Hour), time.Second))
+			})
+
+			It("returns when the run is due", func() {
+				var response map[string]string
+				Expect(json.Unmarshal(recorder.Body.Bytes(), &response)).To(Succeed())
+				Expect(response["message"]).To(Equal("successfully scheduled process"))
+				Expect(response["run_at"]).NotTo(BeEmpty())
+			})
+		})
+
+		When("runAt is given", func() {
+			var runAt time.Time
+
+			BeforeEach(func() {
+				runAt = time.Now().Add(24 * time.Hour).Truncate(time.Second)
+				body, _ := json.Marshal(handler.StartProcessRequest{Name: "sample", RunAt: &runAt})
+				req = httptest.NewRequest(http.MethodPost, "/startProcess", bytes.NewReader(body))
+				req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			})
+
+			It("records the run as scheduled at that time", func() {
+				Expect(recorder.Code).To(Equal(http.StatusAccepted))
+				_, run := runStore.InsertScheduledProcessArgsForCall(0)
+				Expect(*run.RunAt).To(BeTemporally("==", runAt))
+			})
+
+			When("it has passed already", func() {
+				BeforeEach(func() {
+					runAt = time.Now().Add(-time.Hour)
+					body, _ := json.Marshal(handler.StartProcessRequest{Name: "sample", RunAt: &runAt})
+					req = httptest.NewRequest(http.MethodPost, "/startProcess", bytes.NewReader(body))
+					req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+				})
+
+				It("starts the run right away", func() {
+					Expect(recorder.Code).To(Equal(http.StatusAccepted))
+					Expect(runStore.InsertScheduledProcessCallCount()).To(Equal(0))
+					Expect(publisher.PublishCallCount()).To(Equal(1))
+				})
+			})
+		})
+
+		When("both runAt and delay are given", func() {
+			BeforeEach(func() {
+				runAt := time.Now().Add(time.Hour)
+				body, _ := json.Marshal(handler.StartProcessRequest{Name: "sample", RunAt: &runAt, Delay: "1h"})
+				req = httptest.NewRequest(http.MethodPost, "/startProcess", bytes.NewReader(body))
+				req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			})
+
+			It("returns 400", func() {
+				Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+				Expect(recorder.Body.String()).To(ContainSubstring("only one of runAt and delay can be set"))
+				Expect(runStore.InsertScheduledProcessCallCount()).To(Equal(0))
+			})
+		})
+
+		When("the delay is invalid", func() {
+			BeforeEach(func() {
+				body, _ := json.Marshal(handler.StartProcessRequest{Name: "sample", Delay: "-5m"})
+				req = httptest.NewRequest(http.MethodPost, "/startProcess", bytes.NewReader(body))
+				req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			})
+
+			It("returns 400", func() {
+				Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+				Expect(recorder.Body.String()).To(ContainSubstring("Invalid start time"))
+			})
+		})
+
+		When("scheduling the run fails", func() {
+			BeforeEach(func() {
+				body, _ := json.Marshal(handler.StartProcessRequest{Name: "sample", Delay: "1h"})
+				req = httptest.NewRequest(http.MethodPost, "/startProcess", bytes.NewReader(body))
+				req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+				runStore.InsertScheduledProcessReturns(ErrQueue)
+			})
+
+			It("returns 500", func() {
+				Expect(recorder.Code).To(Equal(http.StatusInternalServerError))
+				Expect(recorder.Body.String()).To(ContainSubstring("Process scheduling failed"))
+			})
+		})
+
+		It("uses the priority of the definition", func() {
+			_, run := runStore.InsertQueuedProcessArgsForCall(0)
+			_, actualMessage := publisher.PublishArgsForCall(0)
+			Expect(run.Priority).To(Equal(0))
+			Expect(actualMessage.Priority).To(Equal(0))
+		})
+
+		When("the definition has a priority", func() {
+			BeforeEach(func() {
+				process.Priority = 3
+				reader.ParseConfigFileReturns(process, nil)
+			})
+
+			It("publishes the run with it", func() {
+				_, actualMessage := publisher.PublishArgsForCall(0)
+				Expect(actualMessage.Priority).To(Equal(3))
+			})
+		})
+
+		When("a priority is requested", func() {
+			withPriority := func(priority int) *http.Request {
+				body, _ := json.Marshal(handler.StartProcessRequest{Name: "sample", Priority: &priority})
+				r := httptest.NewRequest(http.MethodPost, "/startProcess", bytes.NewReader(body))
+				r.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+				return r
+			}
+
+			BeforeEach(func() {
+				req = withPriority(8)
+			})
+
+			It("overrides the priority of the definition", func() {
+				Expect(recorder.Code).To(Equal(http.StatusAccepted))
+				_, run := runStore.InsertQueuedProcessArgsForCall(0)
+				_, actualMessage := publisher.PublishArgsForCall(0)
+				Expect(run.Priority).To(Equal(8))
+				Expect(actualMessage.Priority).To(Equal(8))
+			})
+
+			When("it is out of range", func() {
+				BeforeEach(func() {
+					req = withPriority(10)
+				})
+
+				It("returns 400", func() {
+					Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+					Expect(recorder.Body.String()).To(ContainSubstring("priority must be between 0 and 9, got 10"))
+					Expect(publisher.PublishCallCount()).To(Equal(0))
+				})
+			})
+		})
+
+		It("does not use idempotency keys without the header", func() {
+			Expect(keyStore.ReserveKeyCallCount()).To(Equal(0))
+			Expect(keyStore.SaveResponseCallCount()).To(Equal(0))
+		})
+
+		When("an idempotency key is given", func() {
+			BeforeEach(func() {
+				req.Header.Set(handler.IdempotencyKeyHeader, "ci-job-42")
+				keyStore.ReserveKeyStub = func(_ context.Context, key, hash string) (model.IdempotencyRecord, bool, error) {
+					return model.IdempotencyRecord{Key: key, RequestHash: hash}, true, nil
+				}
+			})
+
+			It("reserves the key with the hash of the request", func() {
+				Expect(keyStore.ReserveKeyCallCount()).To(Equal(1))
+				_, key, hash := keyStore.ReserveKeyArgsForCall(0)
+				Expect(key).To(Equal("ci-job-42"))
+				Expect(hash).To(HaveLen(64))
+			})
+
+			It("saves the response for the key", func() {
+				Expect(recorder.Code).To(Equal(http.StatusAccepted))
+				Expect(keyStore.SaveResponseCallCount()).To(Equal(1))
+				_, key, id, statusCode, body := keyStore.SaveResponseArgsForCall(0)
+				_, actualMessage := publisher.PublishArgsForCall(0)
+				Expect(key).To(Equal("ci-job-42"))
+				Expect(id).To(Equal(actualMessage.UUID))
+				Expect(statusCode).To(Equal(http.StatusAccepted))
+				Expect(body).To(MatchJSON(recorder.Body.Bytes()))
+				Expect(keyStore.ReleaseKeyCallCount()).To(Equal(0))
+			})
+
+			When("the key was used for the same request before", func() {
+				var originalID uuid.UUID
+
+				BeforeEach(func() {
+					originalID = uuid.New()
+					keyStore.ReserveKeyStub = func(_ context.Context, key, hash string) (model.IdempotencyRecord, bool, error) {
+						return model.IdempotencyRecord{
+							Key:         key,
+							RequestHash: hash,
+							ProcessID:   &originalID,
+							StatusCode:  http.StatusAccepted,
+							Response:    json.RawMessage(`{"id":"` + originalID.String() + `","message":"successfully added process"}`),
+						}, false, nil
+					}
+				})
+
+				It("returns the original response without starting another run", func() {
+					Expect(recorder.Code).To(Equal(http.StatusAccepted))
+					Expect(recorder.Body.String()).To(MatchJSON(`{"id":"` + originalID.String() + `","message":"successfully added process"}`))
+					Expect(recorder.Header().Get(echo.HeaderLocation)).To(Equal("http://127.0.0.1:8081/listProcess/" + originalID.String()))
+					Expect(runStore.InsertQueuedProcessCallCount()).To(Equal(0))
+					Expect(publisher.PublishCallCount()).To(Equal(0))
+					Expect(keyStore.ReleaseKeyCallCount()).To(Equal(0))
+				})
+			})
+
+			When("the key was used for a different request", func() {
+				BeforeEach(func() {
+					keyStore.ReserveKeyReturns(model.IdempotencyRecord{Key: "ci-job-42", RequestHash: "other"}, false, nil)
+				})
+
+				It("returns 422", func() {
+					Expect(recorder.Code).To(Equal(http.StatusUnprocessableEntity))
+					Expect(publisher.PublishCallCount()).To(Equal(0))
+				})
+			})
+
+			When("the request with the key is still in progress", func() {
+				BeforeEach(func() {
+					keyStore.ReserveKeyStub = func(_ context.Context, key, hash string) (model.IdempotencyRecord, bool, error) {
+						return model.IdempotencyRecord{Key: key, RequestHash: hash}, false, nil
+					}
+				})
+
+				It("returns 409", func() {
+					Expect(recorder.Code).To(Equal(http.StatusConflict))
+					Expect(publisher.PublishCallCount()).To(Equal(0))
+				})
+			})
+
+			When("reserving the key fails", func() {
+				BeforeEach(func() {
+					keyStore.ReserveKeyReturns(model.IdempotencyRecord{}, false, ErrKeyStore)
+				})
+
+				It("returns 500 without publishing", func() {
+					Expect(recorder.Code).To(Equal(http.StatusInternalServerError))
+					Expect(publisher.PublishCallCount()).To(Equal(0))
+				})
+			})
+
+			When("the request fails", func() {
+				BeforeEach(func() {
+					publisher.PublishReturns(ErrPublishFailed)
+				})
+
+				It("releases the key so the request can be retried", func() {
+					Expect(recorder.Code).To(Equal(http.StatusInternalServerError))
+					Expect(keyStore.ReleaseKeyCallCount()).To(Equal(1))
+					_, key := keyStore.ReleaseKeyArgsForCall(0)
+					Expect(key).To(Equal("ci-job-42"))
+					Expect(keyStore.SaveResponseCallCount()).To(Equal(0))
+				})
+			})
+
+			When("saving the response fails once", func() {
+				BeforeEach(func() {
+					keyStore.SaveResponseReturnsOnCall(0, ErrKeyStore)
+				})
+
+				It("retries the save", func() {
+					Expect(recorder.Code).To(Equal(http.StatusAccepted))
+					Expect(keyStore.SaveResponseCallCount()).To(Equal(2))
+					Expect(keyStore.ReleaseKeyCallCount()).To(Equal(0))
+				})
+			})
+
+			When("saving the response keeps failing", func() {
+				BeforeEach(func() {
+					keyStore.SaveResponseReturns(ErrKeyStore)
+				})
+
+				It("releases the key and returns 500 with the run ID", func() {
+					Expect(recorder.Code).To(Equal(http.StatusInternalServerError))
+					_, actualMessage := publisher.PublishArgsForCall(0)
+					Expect(recorder.Body.String()).To(ContainSubstring(actualMessage.UUID.String()))
+					Expect(keyStore.SaveResponseCallCount()).To(Equal(3))
+					Expect(keyStore.ReleaseKeyCallCount()).To(Equal(1))
+				})
+			})
 		})
 	})
 })
@@ -0,0 +1,291 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package handlerfakes
+
+import (
+	"context"
+	"sync"
+
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/model"
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/processproducer/handler"
+	"github.com/google/uuid"
+)
+
+type FakeIdempotencyStore struct {
+	ReleaseKeyStub        func(context.Context, string) error
+	releaseKeyMutex       sync.RWMutex
+	releaseKeyArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	releaseKeyReturns struct {
+		result1 error
+	}
+	releaseKeyReturnsOnCall map[int]struct {
+		result1 error
+	}
+	ReserveKeyStub        func(context.Context, string, string) (model.IdempotencyRecord, bool, error)
+	reserveKeyMutex       sync.RWMutex
+	reserveKeyArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+	}
+	reserveKeyReturns struct {
+		result1 model.IdempotencyRecord
+		result2 bool
+		result3 error
+	}
+	reserveKeyReturnsOnCall map[int]struct {
+		result1 model.IdempotencyRecord
+		result2 bool
+		result3 error
+	}
+	SaveResponseStub        func(context.Context, string, uuid.UUID, int, []byte) error
+	saveResponseMutex       sync.RWMutex
+	saveResponseArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 uuid.UUID
+		arg4 int
+		arg5 []byte
+	}
+	saveResponseReturns struct {
+		result1 error
+	}
+	saveResponseReturnsOnCall map[int]struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeIdempotencyStore) ReleaseKey(arg1 context.Context, arg2 string) error {
+	fake.releaseKeyMutex.Lock()
+	ret, specificReturn := fake.releaseKeyReturnsOnCall[len(fake.releaseKeyArgsForCall)]
+	fake.releaseKeyArgsForCall = append(fake.releaseKeyArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.ReleaseKeyStub
+	fakeReturns := fake.releaseKeyReturns
+	fake.recordInvocation("ReleaseKey", []interface{}{arg1, arg2})
+	fake.releaseKeyMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeIdempotencyStore) ReleaseKeyCallCount() int {
+	fake.releaseKeyMutex.RLock()
+	defer fake.releaseKeyMutex.RUnlock()
+	return len(fake.releaseKeyArgsForCall)
+}
+
+func (fake *FakeIdempotencyStore) ReleaseKeyCalls(stub func(context.Context, string) error) {
+	fake.releaseKeyMutex.Lock()
+	defer fake.releaseKeyMutex.Unlock()
+	fake.ReleaseKeyStub = stub
+}
+
+func (fake *FakeIdempotencyStore) ReleaseKeyArgsForCall(i int) (context.Context, string) {
+	fake.releaseKeyMutex.RLock()
+	defer fake.releaseKeyMutex.RUnlock()
+	argsForCall := fake.releaseKeyArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeIdempotencyStore) ReleaseKeyReturns(result1 error) {
+	fake.releaseKeyMutex.Lock()
+	defer fake.releaseKeyMutex.Unlock()
+	fake.ReleaseKeyStub = nil
+	fake.releaseKeyReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeIdempotencyStore) ReleaseKeyReturnsOnCall(i int, result1 error) {
+	fake.releaseKeyMutex.Lock()
+	defer fake.releaseKeyMutex.Unlock()
+	fake.ReleaseKeyStub = nil
+	if fake.releaseKeyReturnsOnCall == nil {
+		fake.releaseKeyReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.releaseKeyReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeIdempotencyStore) ReserveKey(arg1 context.Context, arg2 string, arg3 string) (model.IdempotencyRecord, bool, error) {
+	fake.reserveKeyMutex.Lock()
+	ret, specificReturn := fake.reserveKeyReturnsOnCall[len(fake.reserveKeyArgsForCall)]
+	fake.reserveKeyArgsForCall = append(fake.reserveKeyArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+	}{arg1, arg2, arg3})
+	stub := fake.ReserveKeyStub
+	fakeReturns := fake.reserveKeyReturns
+	fake.recordInvocation("ReserveKey", []interface{}{arg1, arg2, arg3})
+	fake.reserveKeyMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
+}
+
+func (fake *FakeIdempotencyStore) ReserveKeyCallCount() int {
+	fake.reserveKeyMutex.RLock()
+	defer fake.reserveKeyMutex.RUnlock()
+	return len(fake.reserveKeyArgsForCall)
+}
+
+func (fake *FakeIdempotencyStore) ReserveKeyCalls(stub func(context.Context, string, string) (model.IdempotencyRecord, bool, error)) {
+	fake.reserveKeyMutex.Lock()
+	defer fake.reserveKeyMutex.Unlock()
+	fake.ReserveKeyStub = stub
+}
+
+func (fake *FakeIdempotencyStore) ReserveKeyArgsForCall(i int) (context.Context, string, string) {
+	fake.reserveKeyMutex.RLock()
+	defer fake.reserveKeyMutex.RUnlock()
+	argsForCall := fake.reserveKeyArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeIdempotencyStore) ReserveKeyReturns(result1 model.IdempotencyRecord, result2 bool, result3 error) {
+	fake.reserveKeyMutex.Lock()
+	defer fake.reserveKeyMutex.Unlock()
+	fake.ReserveKeyStub = nil
+	fake.reserveKeyReturns = struct {
+		result1 model.IdempotencyRecord
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeIdempotencyStore) ReserveKeyReturnsOnCall(i int, result1 model.IdempotencyRecord, result2 bool, result3 error) {
+	fake.reserveKeyMutex.Lock()
+	defer fake.reserveKeyMutex.Unlock()
+	fake.ReserveKeyStub = nil
+	if fake.reserveKeyReturnsOnCall == nil {
+		fake.reserveKeyReturnsOnCall = make(map[int]struct {
+			result1 model.IdempotencyRecord
+			result2 bool
+			result3 error
+		})
+	}
+	fake.reserveKeyReturnsOnCall[i] = struct {
+		result1 model.IdempotencyRecord
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeIdempotencyStore) SaveResponse(arg1 context.Context, arg2 string, arg3 uuid.UUID, arg4 int, arg5 []byte) error {
+	var arg5Copy []byte
+	if arg5 != nil {
+		arg5Copy = make([]byte, len(arg5))
+		copy(arg5Copy, arg5)
+	}
+	fake.saveResponseMutex.Lock()
+	ret, specificReturn := fake.saveResponseReturnsOnCall[len(fake.saveResponseArgsForCall)]
+	fake.saveResponseArgsForCall = append(fake.saveResponseArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 uuid.UUID
+		arg4 int
+		arg5 []byte
+	}{arg1, arg2, arg3, arg4, arg5Copy})
+	stub := fake.SaveResponseStub
+	fakeReturns := fake.saveResponseReturns
+	fake.recordInvocation("SaveResponse", []interface{}{arg1, arg2, arg3, arg4, arg5Copy})
+	fake.saveResponseMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4, arg5)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeIdempotencyStore) SaveResponseCallCount() int {
+	fake.saveResponseMutex.RLock()
+	defer fake.saveResponseMutex.RUnlock()
+	return len(fake.saveResponseArgsForCall)
+}
+
+func (fake *FakeIdempotencyStore) SaveResponseCalls(stub func(context.Context, string, uuid.UUID, int, []byte) error) {
+	fake.saveResponseMutex.Lock()
+	defer fake.saveResponseMutex.Unlock()
+	fake.SaveResponseStub = stub
+}
+
+func (fake *FakeIdempotencyStore) SaveResponseArgsForCall(i int) (context.Context, string, uuid.UUID, int, []byte) {
+	fake.saveResponseMutex.RLock()
+	defer fake.saveResponseMutex.RUnlock()
+	argsForCall := fake.saveResponseArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5
+}
+
+func (fake *FakeIdempotencyStore) SaveResponseReturns(result1 error) {
+	fake.saveResponseMutex.Lock()
+	defer fake.saveResponseMutex.Unlock()
+	fake.SaveResponseStub = nil
+	fake.saveResponseReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeIdempotencyStore) SaveResponseReturnsOnCall(i int, result1 error) {
+	fake.saveResponseMutex.Lock()
+	defer fake.saveResponseMutex.Unlock()
+	fake.SaveResponseStub = nil
+	if fake.saveResponseReturnsOnCall == nil {
+		fake.saveResponseReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.saveResponseReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeIdempotencyStore) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.releaseKeyMutex.RLock()
+	defer fake.releaseKeyMutex.RUnlock()
+	fake.reserveKeyMutex.RLock()
+	defer fake.reserveKeyMutex.RUnlock()
+	fake.saveResponseMutex.RLock()
+	defer fake.saveResponseMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeIdempotencyStore) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ handler.IdempotencyStore = new(FakeIdempotencyStore)
@@ -0,0 +1,268 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package handlerfakes
+
+import (
+	"context"
+	"sync"
+
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/model"
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/processproducer/handler"
+	"github.com/google/uuid"
+)
+
+type FakeProcessRunStore struct {
+	DeleteQueuedProcessStub        func(context.Context, uuid.UUID) error
+	deleteQueuedProcessMutex       sync.RWMutex
+	deleteQueuedProcessArgsForCall []struct {
+		arg1 context.Context
+		arg2 uuid.UUID
+	}
+	deleteQueuedProcessReturns struct {
+		result1 error
+	}
+	deleteQueuedProcessReturnsOnCall map[int]struct {
+		result1 error
+	}
+	InsertQueuedProcessStub        func(context.Context, model.ProcessRun) error
+	insertQueuedProcessMutex       sync.RWMutex
+	insertQueuedProcessArgsForCall []struct {
+		arg1 context.Context
+		arg2 model.ProcessRun
+	}
+	insertQueuedProcessReturns struct {
+		result1 error
+	}
+	insertQueuedProcessReturnsOnCall map[int]struct {
+		result1 error
+	}
+	InsertScheduledProcessStub        func(context.Context, model.ProcessRun) error
+	insertScheduledProcessMutex       sync.RWMutex
+	insertScheduledProcessArgsForCall []struct {
+		arg1 context.Context
+		arg2 model.ProcessRun
+	}
+	insertScheduledProcessReturns struct {
+		result1 error
+	}
+	insertScheduledProcessReturnsOnCall map[int]struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeProcessRunStore) DeleteQueuedProcess(arg1 context.Context, arg2 uuid.UUID) error {
+	fake.deleteQueuedProcessMutex.Lock()
+	ret, specificReturn := fake.deleteQueuedProcessReturnsOnCall[len(fake.deleteQueuedProcessArgsForCall)]
+	fake.deleteQueuedProcessArgsForCall = append(fake.deleteQueuedProcessArgsForCall, struct {
+		arg1 context.Context
+		arg2 uuid.UUID
+	}{arg1, arg2})
+	stub := fake.DeleteQueuedProcessStub
+	fakeReturns := fake.deleteQueuedProcessReturns
+	fake.recordInvocation("DeleteQueuedProcess", []interface{}{arg1, arg2})
+	fake.deleteQueuedProcessMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeProcessRunStore) DeleteQueuedProcessCallCount() int {
+	fake.deleteQueuedProcessMutex.RLock()
+	defer fake.deleteQueuedProcessMutex.RUnlock()
+	return len(fake.deleteQueuedProcessArgsForCall)
+}
+
+func (fake *FakeProcessRunStore) DeleteQueuedProcessCalls(stub func(context.Context, uuid.UUID) error) {
+	fake.deleteQueuedProcessMutex.Lock()
+	defer fake.deleteQueuedProcessMutex.Unlock()
+	fake.DeleteQueuedProcessStub = stub
+}
+
+func (fake *FakeProcessRunStore) DeleteQueuedProcessArgsForCall(i int) (context.Context, uuid.UUID) {
+	fake.deleteQueuedProcessMutex.RLock()
+	defer fake.deleteQueuedProcessMutex.RUnlock()
+	argsForCall := fake.deleteQueuedProcessArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeProcessRunStore) DeleteQueuedProcessReturns(result1 error) {
+	fake.deleteQueuedProcessMutex.Lock()
+	defer fake.deleteQueuedProcessMutex.Unlock()
+	fake.DeleteQueuedProcessStub = nil
+	fake.deleteQueuedProcessReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeProcessRunStore) DeleteQueuedProcessReturnsOnCall(i int, result1 error) {
+	fake.deleteQueuedProcessMutex.Lock()
+	defer fake.deleteQueuedProcessMutex.Unlock()
+	fake.DeleteQueuedProcessStub = nil
+	if fake.deleteQueuedProcessReturnsOnCall == nil {
+		fake.deleteQueuedProcessReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.deleteQueuedProcessReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeProcessRunStore) InsertQueuedProcess(arg1 context.Context, arg2 model.ProcessRun) error {
+	fake.insertQueuedProcessMutex.Lock()
+	ret, specificReturn := fake.insertQueuedProcessReturnsOnCall[len(fake.insertQueuedProcessArgsForCall)]
+	fake.insertQueuedProcessArgsForCall = append(fake.insertQueuedProcessArgsForCall, struct {
+		arg1 context.Context
+		arg2 model.ProcessRun
+	}{arg1, arg2})
+	stub := fake.InsertQueuedProcessStub
+	fakeReturns := fake.insertQueuedProcessReturns
+	fake.recordInvocation("InsertQueuedProcess", []interface{}{arg1, arg2})
+	fake.insertQueuedProcessMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeProcessRunStore) InsertQueuedProcessCallCount() int {
+	fake.insertQueuedProcessMutex.RLock()
+	defer fake.insertQueuedProcessMutex.RUnlock()
+	return len(fake.insertQueuedProcessArgsForCall)
+}
+
+func (fake *FakeProcessRunStore) InsertQueuedProcessCalls(stub func(context.Context, model.ProcessRun) error) {
+	fake.insertQueuedProcessMutex.Lock()
+	defer fake.insertQueuedProcessMutex.Unlock()
+	fake.InsertQueuedProcessStub = stub
+}
+
+func (fake *FakeProcessRunStore) InsertQueuedProcessArgsForCall(i int) (context.Context, model.ProcessRun) {
+	fake.insertQueuedProcessMutex.RLock()
+	defer fake.insertQueuedProcessMutex.RUnlock()
+	argsForCall := fake.insertQueuedProcessArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeProcessRunStore) InsertQueuedProcessReturns(result1 error) {
+	fake.insertQueuedProcessMutex.Lock()
+	defer fake.insertQueuedProcessMutex.Unlock()
+	fake.InsertQueuedProcessStub = nil
+	fake.insertQueuedProcessReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeProcessRunStore) InsertQueuedProcessReturnsOnCall(i int, result1 error) {
+	fake.insertQueuedProcessMutex.Lock()
+	defer fake.insertQueuedProcessMutex.Unlock()
+	fake.InsertQueuedProcessStub = nil
+	if fake.insertQueuedProcessReturnsOnCall == nil {
+		fake.insertQueuedProcessReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.insertQueuedProcessReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeProcessRunStore) InsertScheduledProcess(arg1 context.Context, arg2 model.ProcessRun) error {
+	fake.insertScheduledProcessMutex.Lock()
+	ret, specificReturn := fake.insertScheduledProcessReturnsOnCall[len(fake.insertScheduledProcessArgsForCall)]
+	fake.insertScheduledProcessArgsForCall = append(fake.insertScheduledProcessArgsForCall, struct {
+		arg1 context.Context
+		arg2 model.ProcessRun
+	}{arg1, arg2})
+	stub := fake.InsertScheduledProcessStub
+	fakeReturns := fake.insertScheduledProcessReturns
+	fake.recordInvocation("InsertScheduledProcess", []interface{}{arg1, arg2})
+	fake.insertScheduledProcessMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeProcessRunStore) InsertScheduledProcessCallCount() int {
+	fake.insertScheduledProcessMutex.RLock()
+	defer fake.insertScheduledProcessMutex.RUnlock()
+	return len(fake.insertScheduledProcessArgsForCall)
+}
+
+func (fake *FakeProcessRunStore) InsertScheduledProcessCalls(stub func(context.Context, model.ProcessRun) error) {
+	fake.insertScheduledProcessMutex.Lock()
+	defer fake.insertScheduledProcessMutex.Unlock()
+	fake.InsertScheduledProcessStub = stub
+}
+
+func (fake *FakeProcessRunStore) InsertScheduledProcessArgsForCall(i int) (context.Context, model.ProcessRun) {
+	fake.insertScheduledProcessMutex.RLock()
+	defer fake.insertScheduledProcessMutex.RUnlock()
+	argsForCall := fake.insertScheduledProcessArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeProcessRunStore) InsertScheduledProcessReturns(result1 error) {
+	fake.insertScheduledProcessMutex.Lock()
+	defer fake.insertScheduledProcessMutex.Unlock()
+	fake.InsertScheduledProcessStub = nil
+	fake.insertScheduledProcessReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeProcessRunStore) InsertScheduledProcessReturnsOnCall(i int, result1 error) {
+	fake.insertScheduledProcessMutex.Lock()
+	defer fake.insertScheduledProcessMutex.Unlock()
+	fake.InsertScheduledProcessStub = nil
+	if fake.insertScheduledProcessReturnsOnCall == nil {
+		fake.insertScheduledProcessReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.insertScheduledProcessReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeProcessRunStore) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.deleteQueuedProcessMutex.RLock()
+	defer fake.deleteQueuedProcessMutex.RUnlock()
+	fake.insertQueuedProcessMutex.RLock()
+	defer fake.insertQueuedProcessMutex.RUnlock()
+	fake.insertScheduledProcessMutex.RLock()
+	defer fake.insertScheduledProcessMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeProcessRunStore) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ handler.ProcessRunStore = new(FakeProcessRunStore)
@@ -0,0 +1,367 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package handlerfakes
+
+import (
+	"context"
+	"sync"
+
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/model"
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/processproducer/handler"
+)
+
+type FakeScheduler struct {
+	ListStub        func(context.Context) ([]model.ScheduleState, error)
+	listMutex       sync.RWMutex
+	listArgsForCall []struct {
+		arg1 context.Context
+	}
+	listReturns struct {
+		result1 []model.ScheduleState
+		result2 error
+	}
+	listReturnsOnCall map[int]struct {
+		result1 []model.ScheduleState
+		result2 error
+	}
+	PauseStub        func(context.Context, string, string) error
+	pauseMutex       sync.RWMutex
+	pauseArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+	}
+	pauseReturns struct {
+		result1 error
+	}
+	pauseReturnsOnCall map[int]struct {
+		result1 error
+	}
+	ResumeStub        func(context.Context, string, string) (model.ScheduleState, error)
+	resumeMutex       sync.RWMutex
+	resumeArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+	}
+	resumeReturns struct {
+		result1 model.ScheduleState
+		result2 error
+	}
+	resumeReturnsOnCall map[int]struct {
+		result1 model.ScheduleState
+		result2 error
+	}
+	TriggerStub        func(context.Context, string, string) (model.ProcessRun, []string, error)
+	triggerMutex       sync.RWMutex
+	triggerArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+	}
+	triggerReturns struct {
+		result1 model.ProcessRun
+		result2 []string
+		result3 error
+	}
+	triggerReturnsOnCall map[int]struct {
+		result1 model.ProcessRun
+		result2 []string
+		result3 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeScheduler) List(arg1 context.Context) ([]model.ScheduleState, error) {
+	fake.listMutex.Lock()
+	ret, specificReturn := fake.listReturnsOnCall[len(fake.listArgsForCall)]
+	fake.listArgsForCall = append(fake.listArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	stub := fake.ListStub
+	fakeReturns := fake.listReturns
+	fake.recordInvocation("List", []interface{}{arg1})
+	fake.listMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeScheduler) ListCallCount() int {
+	fake.listMutex.RLock()
+	defer fake.listMutex.RUnlock()
+	return len(fake.listArgsForCall)
+}
+
+func (fake *FakeScheduler) ListCalls(stub func(context.Context) ([]model.ScheduleState, error)) {
+	fake.listMutex.Lock()
+	defer fake.listMutex.Unlock()
+	fake.ListStub = stub
+}
+
+func (fake *FakeScheduler) ListArgsForCall(i int) context.Context {
+	fake.listMutex.RLock()
+	defer fake.listMutex.RUnlock()
+	argsForCall := fake.listArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeScheduler) ListReturns(result1 []model.ScheduleState, result2 error) {
+	fake.listMutex.Lock()
+	defer fake.listMutex.Unlock()
+	fake.ListStub = nil
+	fake.listReturns = struct {
+		result1 []model.ScheduleState
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeScheduler) ListReturnsOnCall(i int, result1 []model.ScheduleState, result2 error) {
+	fake.listMutex.Lock()
+	defer fake.listMutex.Unlock()
+	fake.ListStub = nil
+	if fake.listReturnsOnCall == nil {
+		fake.listReturnsOnCall = make(map[int]struct {
+			result1 []model.ScheduleState
+			result2 error
+		})
+	}
+	fake.listReturnsOnCall[i] = struct {
+		result1 []model.ScheduleState
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeScheduler) Pause(arg1 context.Context, arg2 string, arg3 string) error {
+	fake.pauseMutex.Lock()
+	ret, specificReturn := fake.pauseReturnsOnCall[len(fake.pauseArgsForCall)]
+	fake.pauseArgsForCall = append(fake.pauseArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+	}{arg1, arg2, arg3})
+	stub := fake.PauseStub
+	fakeReturns := fake.pauseReturns
+	fake.recordInvocation("Pause", []interface{}{arg1, arg2, arg3})
+	fake.pauseMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeScheduler) PauseCallCount() int {
+	fake.pauseMutex.RLock()
+	defer fake.pauseMutex.RUnlock()
+	return len(fake.pauseArgsForCall)
+}
+
+func (fake *FakeScheduler) PauseCalls(stub func(context.Context, string, string) error) {
+	fake.pauseMutex.Lock()
+	defer fake.pauseMutex.Unlock()
+	fake.PauseStub = stub
+}
+
+func (fake *FakeScheduler) PauseArgsForCall(i int) (context.Context, string, string) {
+	fake.pauseMutex.RLock()
+	defer fake.pauseMutex.RUnlock()
+	argsForCall := fake.pauseArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeScheduler) PauseReturns(result1 error) {
+	fake.pauseMutex.Lock()
+	defer fake.pauseMutex.Unlock()
+	fake.PauseStub = nil
+	fake.pauseReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeScheduler) PauseReturnsOnCall(i int, result1 error) {
+	fake.pauseMutex.Lock()
+	defer fake.pauseMutex.Unlock()
+	fake.PauseStub = nil
+	if fake.pauseReturnsOnCall == nil {
+		fake.pauseReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.pauseReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeScheduler) Resume(arg1 context.Context, arg2 string, arg3 string) (model.ScheduleState, error) {
+	fake.resumeMutex.Lock()
+	ret, specificReturn := fake.resumeReturnsOnCall[len(fake.resumeArgsForCall)]
+	fake.resumeArgsForCall = append(fake.resumeArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+	}{arg1, arg2, arg3})
+	stub := fake.ResumeStub
+	fakeReturns := fake.resumeReturns
+	fake.recordInvocation("Resume", []interface{}{arg1, arg2, arg3})
+	fake.resumeMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeScheduler) ResumeCallCount() int {
+	fake.resumeMutex.RLock()
+	defer fake.resumeMutex.RUnlock()
+	return len(fake.resumeArgsForCall)
+}
+
+func (fake *FakeScheduler) ResumeCalls(stub func(context.Context, string, string) (model.ScheduleState, error)) {
+	fake.resumeMutex.Lock()
+	defer fake.resumeMutex.Unlock()
+	fake.ResumeStub = stub
+}
+
+func (fake *FakeScheduler) ResumeArgsForCall(i int) (context.Context, string, string) {
+	fake.resumeMutex.RLock()
+	defer fake.resumeMutex.RUnlock()
+	argsForCall := fake.resumeArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeScheduler) ResumeReturns(result1 model.ScheduleState, result2 error) {
+	fake.resumeMutex.Lock()
+	defer fake.resumeMutex.Unlock()
+	fake.ResumeStub = nil
+	fake.resumeReturns = struct {
+		result1 model.ScheduleState
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeScheduler) ResumeReturnsOnCall(i int, result1 model.ScheduleState, result2 error) {
+	fake.resumeMutex.Lock()
+	defer fake.resumeMutex.Unlock()
+	fake.ResumeStub = nil
+	if fake.resumeReturnsOnCall == nil {
+		fake.resumeReturnsOnCall = make(map[int]struct {
+			result1 model.ScheduleState
+			result2 error
+		})
+	}
+	fake.resumeReturnsOnCall[i] = struct {
+		result1 model.ScheduleState
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeScheduler) Trigger(arg1 context.Context, arg2 string, arg3 string) (model.ProcessRun, []string, error) {
+	fake.triggerMutex.Lock()
+	ret, specificReturn := fake.triggerReturnsOnCall[len(fake.triggerArgsForCall)]
+	fake.triggerArgsForCall = append(fake.triggerArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+	}{arg1, arg2, arg3})
+	stub := fake.TriggerStub
+	fakeReturns := fake.triggerReturns
+	fake.recordInvocation("Trigger", []interface{}{arg1, arg2, arg3})
+	fake.triggerMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
+}
+
+func (fake *FakeScheduler) TriggerCallCount() int {
+	fake.triggerMutex.RLock()
+	defer fake.triggerMutex.RUnlock()
+	return len(fake.triggerArgsForCall)
+}
+
+func (fake *FakeScheduler) TriggerCalls(stub func(context.Context, string, string) (model.ProcessRun, []string, error)) {
+	fake.triggerMutex.Lock()
+	defer fake.triggerMutex.Unlock()
+	fake.TriggerStub = stub
+}
+
+func (fake *FakeScheduler) TriggerArgsForCall(i int) (context.Context, string, string) {
+	fake.triggerMutex.RLock()
+	defer fake.triggerMutex.RUnlock()
+	argsForCall := fake.triggerArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeScheduler) TriggerReturns(result1 model.ProcessRun, result2 []string, result3 error) {
+	fake.triggerMutex.Lock()
+	defer fake.triggerMutex.Unlock()
+	fake.TriggerStub = nil
+	fake.triggerReturns = struct {
+		result1 model.ProcessRun
+		result2 []string
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeScheduler) TriggerReturnsOnCall(i int, result1 model.ProcessRun, result2 []string, result3 error) {
+	fake.triggerMutex.Lock()
+	defer fake.triggerMutex.Unlock()
+	fake.TriggerStub = nil
+	if fake.triggerReturnsOnCall == nil {
+		fake.triggerReturnsOnCall = make(map[int]struct {
+			result1 model.ProcessRun
+			result2 []string
+			result3 error
+		})
+	}
+	fake.triggerReturnsOnCall[i] = struct {
+		result1 model.ProcessRun
+		result2 []string
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeScheduler) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.listMutex.RLock()
+	defer fake.listMutex.RUnlock()
+	fake.pauseMutex.RLock()
+	defer fake.pauseMutex.RUnlock()
+	fake.resumeMutex.RLock()
+	defer fake.resumeMutex.RUnlock()
+	fake.triggerMutex.RLock()
+	defer fake.triggerMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeScheduler) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ handler.Scheduler = new(FakeScheduler)
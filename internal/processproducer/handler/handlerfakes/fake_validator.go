@@ -6,9 +6,23 @@ import (
 
 	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/model"
 	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/processproducer/handler"
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/processproducer/validator"
 )
 
 type FakeValidator struct {
+	AnalyzeStub        func(model.ProcessDefinition) (validator.GraphAnalysis, error)
+	analyzeMutex       sync.RWMutex
+	analyzeArgsForCall []struct {
+		arg1 model.ProcessDefinition
+	}
+	analyzeReturns struct {
+		result1 validator.GraphAnalysis
+		result2 error
+	}
+	analyzeReturnsOnCall map[int]struct {
+		result1 validator.GraphAnalysis
+		result2 error
+	}
 	ValidateStub        func(model.ProcessDefinition) error
 	validateMutex       sync.RWMutex
 	validateArgsForCall []struct {
@@ -36,6 +50,70 @@ type FakeValidator struct {
 	invocationsMutex sync.RWMutex
 }
 
+func (fake *FakeValidator) Analyze(arg1 model.ProcessDefinition) (validator.GraphAnalysis, error) {
+	fake.analyzeMutex.Lock()
+	ret, specificReturn := fake.analyzeReturnsOnCall[len(fake.analyzeArgsForCall)]
+	fake.analyzeArgsForCall = append(fake.analyzeArgsForCall, struct {
+		arg1 model.ProcessDefinition
+	}{arg1})
+	stub := fake.AnalyzeStub
+	fakeReturns := fake.analyzeReturns
+	fake.recordInvocation("Analyze", []interface{}{arg1})
+	fake.analyzeMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeValidator) AnalyzeCallCount() int {
+	fake.analyzeMutex.RLock()
+	defer fake.analyzeMutex.RUnlock()
+	return len(fake.analyzeArgsForCall)
+}
+
+func (fake *FakeValidator) AnalyzeCalls(stub func(model.ProcessDefinition) (validator.GraphAnalysis, error)) {
+	fake.analyzeMutex.Lock()
+	defer fake.analyzeMutex.Unlock()
+	fake.AnalyzeStub = stub
+}
+
+func (fake *FakeValidator) AnalyzeArgsForCall(i int) model.ProcessDefinition {
+	fake.analyzeMutex.RLock()
+	defer fake.analyzeMutex.RUnlock()
+	argsForCall := fake.analyzeArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeValidator) AnalyzeReturns(result1 validator.GraphAnalysis, result2 error) {
+	fake.analyzeMutex.Lock()
+	defer fake.analyzeMutex.Unlock()
+	fake.AnalyzeStub = nil
+	fake.analyzeReturns = struct {
+		result1 validator.GraphAnalysis
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeValidator) AnalyzeReturnsOnCall(i int, result1 validator.GraphAnalysis, result2 error) {
+	fake.analyzeMutex.Lock()
+	defer fake.analyzeMutex.Unlock()
+	fake.AnalyzeStub = nil
+	if fake.analyzeReturnsOnCall == nil {
+		fake.analyzeReturnsOnCall = make(map[int]struct {
+			result1 validator.GraphAnalysis
+			result2 error
+		})
+	}
+	fake.analyzeReturnsOnCall[i] = struct {
+		result1 validator.GraphAnalysis
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeValidator) Validate(arg1 model.ProcessDefinition) error {
 	fake.validateMutex.Lock()
 	ret, specificReturn := fake.validateReturnsOnCall[len(fake.validateArgsForCall)]
@@ -162,6 +240,8 @@ func (fake *FakeValidator) ValidateMandatoryParamsReturnsOnCall(i int, result1 e
 func (fake *FakeValidator) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.analyzeMutex.RLock()
+	defer fake.analyzeMutex.RUnlock()
 	fake.validateMutex.RLock()
 	defer fake.validateMutex.RUnlock()
 	fake.validateMandatoryParamsMutex.RLock()
@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/logger"
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/model"
+	"github.com/labstack/echo/v4"
+)
+
+//counterfeiter:generate . Scheduler
+type Scheduler interface {
+	List(context.Context) ([]model.ScheduleState, error)
+	Pause(context.Context, string, string) error
+	Resume(context.Context, string, string) (model.ScheduleState, error)
+	Trigger(context.Context, string, string) (model.ProcessRun, []string, error)
+}
+
+// RegisterScheduleHandlers registers the routes to list, pause, resume and trigger the
+// schedules of the process definitions.
+func RegisterScheduleHandlers(ctx context.Context, srv *echo.Echo, scheduler Scheduler, statusURL string) {
+	if srv != nil {
+		srv.GET("/schedules", handleListSchedules(ctx, scheduler))
+		srv.POST("/schedules/:process/:schedule/pause", handlePauseSchedule(ctx, scheduler))
+		srv.POST("/schedules/:process/:schedule/resume", handleResumeSchedule(ctx, scheduler))
+		srv.POST("/schedules/:process/:schedule/trigger", handleTriggerSchedule(ctx, scheduler, statusURL))
+	} else {
+		logger.GetLogger().Warn("Running routes without a webapi server, did NOT register routes.")
+	}
+}
+
+func handleListSchedules(ctx context.Context, scheduler Scheduler) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		schedules, err := scheduler.List(ctx)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to list schedules")
+		}
+		if schedules == nil {
+			schedules = []model.ScheduleState{}
+		}
+		return c.JSON(http.StatusOK, schedules)
+	}
+}
+
+func handlePauseSchedule(ctx context.Context, scheduler Scheduler) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		process, schedule := c.Param("process"), c.Param("schedule")
+
+		if err := scheduler.Pause(ctx, process, schedule); err != nil {
+			if errors.Is(err, model.ErrScheduleNotFound) {
+				return echo.NewHTTPError(http.StatusNotFound, "Schedule not found")
+			}
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to pause schedule")
+		}
+		return c.JSON(http.StatusOK, map[string]interface{}{
+			"message": fmt.Sprintf("schedule %s of process %s paused", schedule, process),
+		})
+	}
+}
+
+func handleResumeSchedule(ctx context.Context, scheduler Scheduler) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		state, err := scheduler.Resume(ctx, c.Param("process"), c.Param("schedule"))
+		if err != nil {
+			if errors.Is(err, model.ErrScheduleNotFound) {
+				return echo.NewHTTPError(http.StatusNotFound, "Schedule not found")
+			}
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to resume schedule")
+		}
+		return c.JSON(http.StatusOK, state)
+	}
+}
+
+func handleTriggerSchedule(ctx context.Context, scheduler Scheduler, statusURL string) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		run, warnings, err := scheduler.Trigger(ctx, c.Param("process"), c.Param("schedule"))
+		if err != nil {
+			if errors.Is(err, model.ErrScheduleNotFound) {
+				return echo.NewHTTPError(http.StatusNotFound, "Schedule not found")
+			}
+			return startFailed(c, err)
+		}
+
+		response := map[string]interface{}{
+			"message": successfullyAddedProcess,
+			"id":      run.ID.String(),
+		}
+		if len(warnings) > 0 {
+			response["warnings"] = warnings
+		}
+		c.Response().Header().Set(echo.HeaderLocation, statusLocation(statusURL, run.ID))
+		return c.JSON(http.StatusAccepted, response)
+	}
+}
@@ -0,0 +1,128 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/model"
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/processproducer/handler"
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/processproducer/handler/handlerfakes"
+)
+
+var _ = Describe("Schedule Handlers", func() {
+	var (
+		e         *echo.Echo
+		ctx       context.Context
+		recorder  *httptest.ResponseRecorder
+		scheduler *handlerfakes.FakeScheduler
+		schedule  model.ScheduleState
+	)
+
+	BeforeEach(func() {
+		e = echo.New()
+		ctx = context.Background()
+		recorder = httptest.NewRecorder()
+		scheduler = &handlerfakes.FakeScheduler{}
+		handler.RegisterScheduleHandlers(ctx, e, scheduler, "http://127.0.0.1:8081")
+
+		schedule = model.ScheduleState{
+			ProcessName: "backup",
+			Schedule:    model.Schedule{Name: "nightly", Cron: "0 2 * * *"},
+			NextFireAt:  time.Date(2024, 3, 16, 2, 0, 0, 0, time.UTC),
+		}
+	})
+
+	serve := func(method, path string) {
+		e.ServeHTTP(recorder, httptest.NewRequest(method, path, nil))
+	}
+
+	Describe("GET /schedules", func() {
+		It("lists the schedules with their next fire time", func() {
+			scheduler.ListReturns([]model.ScheduleState{schedule}, nil)
+			serve(http.MethodGet, "/schedules")
+
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+			var response []map[string]interface{}
+			Expect(json.Unmarshal(recorder.Body.Bytes(), &response)).To(Succeed())
+			Expect(response).To(HaveLen(1))
+			Expect(response[0]).To(HaveKeyWithValue("process_name", "backup"))
+			Expect(response[0]).To(HaveKeyWithValue("name", "nightly"))
+			Expect(response[0]).To(HaveKeyWithValue("next_fire_at", "2024-03-16T02:00:00Z"))
+		})
+
+		It("fails when the schedules cannot be listed", func() {
+			scheduler.ListReturns(nil, ErrKeyStore)
+			serve(http.MethodGet, "/schedules")
+
+			Expect(recorder.Code).To(Equal(http.StatusInternalServerError))
+		})
+	})
+
+	Describe("POST /schedules/:process/:schedule/pause", func() {
+		It("pauses the schedule", func() {
+			serve(http.MethodPost, "/schedules/backup/nightly/pause")
+
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+			_, process, name := scheduler.PauseArgsForCall(0)
+			Expect(process).To(Equal("backup"))
+			Expect(name).To(Equal("nightly"))
+		})
+
+		It("fails when the schedule does not exist", func() {
+			scheduler.PauseReturns(fmt.Errorf("wrapped: %w", model.ErrScheduleNotFound))
+			serve(http.MethodPost, "/schedules/backup/nightly/pause")
+
+			Expect(recorder.Code).To(Equal(http.StatusNotFound))
+		})
+	})
+
+	Describe("POST /schedules/:process/:schedule/resume", func() {
+		It("returns the resumed schedule", func() {
+			scheduler.ResumeReturns(schedule, nil)
+			serve(http.MethodPost, "/schedules/backup/nightly/resume")
+
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+			Expect(recorder.Body.String()).To(ContainSubstring(`"paused":false`))
+		})
+
+		It("fails when the schedule does not exist", func() {
+			scheduler.ResumeReturns(model.ScheduleState{}, model.ErrScheduleNotFound)
+			serve(http.MethodPost, "/schedules/backup/nightly/resume")
+
+			Expect(recorder.Code).To(Equal(http.StatusNotFound))
+		})
+	})
+
+	Describe("POST /schedules/:process/:schedule/trigger", func() {
+		It("returns the started run", func() {
+			id := uuid.New()
+			scheduler.TriggerReturns(model.ProcessRun{ID: id}, nil, nil)
+			serve(http.MethodPost, "/schedules/backup/nightly/trigger")
+
+			Expect(recorder.Code).To(Equal(http.StatusAccepted))
+			Expect(recorder.Body.String()).To(ContainSubstring(id.String()))
+			Expect(recorder.Header().Get(echo.HeaderLocation)).To(Equal("http://127.0.0.1:8081/listProcess/" + id.String()))
+		})
+
+		It("fails like /startProcess when the run cannot be started", func() {
+			scheduler.TriggerReturns(model.ProcessRun{}, nil, &handler.StartError{
+				Status:  http.StatusBadRequest,
+				Message: "Missing mandatory parameters",
+				Err:     ErrValidation,
+			})
+			serve(http.MethodPost, "/schedules/backup/nightly/trigger")
+
+			Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+			Expect(recorder.Body.String()).To(ContainSubstring("Missing mandatory parameters"))
+		})
+	})
+})
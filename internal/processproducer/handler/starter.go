@@ -0,0 +1,160 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/logger"
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/model"
+	"github.com/google/uuid"
+)
+
+// StartError is returned by ProcessStarter.Start. Status is the HTTP status code
+// the failure maps to.
+type StartError struct {
+	Status  int
+	Message string
+	Err     error
+}
+
+func (e *StartError) Error() string {
+	return e.Message + ": " + e.Err.Error()
+}
+
+func (e *StartError) Unwrap() error {
+	return e.Err
+}
+
+// StartOptions control when and how urgently a run is started.
+type StartOptions struct {
+	// RunAt holds the run back until the given time. The zero time starts it right away.
+	RunAt time.Time
+	// Priority overrides the priority of the definition.
+	Priority *int
+}
+
+// ProcessStarter queues runs of the indexed process definitions.
+type ProcessStarter struct {
+	publisher Publisher
+	reader    Reader
+	store     ProcessDefinitionStore
+	runStore  ProcessRunStore
+	validator Validator
+}
+
+func NewProcessStarter(
+	publisher Publisher,
+	reader Reader,
+	store ProcessDefinitionStore,
+	runStore ProcessRunStore,
+	validator Validator,
+) *ProcessStarter {
+	return &ProcessStarter{
+		publisher: publisher,
+		reader:    reader,
+		store:     store,
+		runStore:  runStore,
+		validator: validator,
+	}
+}
+
+// Start records a queued run of the process with the given parameters and publishes
+// it. The definitions run by its processCmd tasks are resolved from the index up
+// front. If opts.RunAt is in the future, the run is recorded as scheduled instead and
+// only published once it is due. It returns the run and the warnings of the analysis
+// of its task graph.
+func (s *ProcessStarter) Start(ctx context.Context, name string, params map[string]string, opts StartOptions) (model.ProcessRun, []string, error) {
+	processPath, err := s.store.GetProcessPathByName(ctx, name)
+	if err != nil {
+		return model.ProcessRun{}, nil, &StartError{http.StatusBadRequest, "Process definition template not found", err}
+	}
+
+	processDef, err := s.reader.ParseConfigFile(processPath)
+	if err != nil {
+		return model.ProcessRun{}, nil, &StartError{http.StatusBadRequest, "Could not parse process definition template", err}
+	}
+
+	if err := s.validator.ValidateMandatoryParams(processDef, params); err != nil {
+		return model.ProcessRun{}, nil, &StartError{http.StatusBadRequest, "Missing mandatory parameters", err}
+	}
+
+	tasks, err := s.reader.ApplyTemplatingToTasks(processDef.Tasks, params)
+	if err != nil {
+		return model.ProcessRun{}, nil, &StartError{http.StatusBadRequest, "Failed to apply parameters", err}
+	}
+
+	processDef.Tasks = tasks
+
+	if len(processDef.Finally) > 0 {
+		finally, err := s.reader.ApplyTemplatingToTasks(processDef.Finally, params)
+		if err != nil {
+			return model.ProcessRun{}, nil, &StartError{http.StatusBadRequest, "Failed to apply parameters", err}
+		}
+		processDef.Finally = finally
+	}
+
+	if err := s.validator.Validate(processDef); err != nil {
+		return model.ProcessRun{}, nil, &StartError{http.StatusBadRequest, "Process validation failed", err}
+	}
+
+	if err := s.resolveSubprocesses(ctx, &processDef, []string{processDef.Name}); err != nil {
+		return model.ProcessRun{}, nil, &StartError{http.StatusBadRequest, "Could not resolve sub-processes", err}
+	}
+
+	analysis, err := s.validator.Analyze(processDef)
+	if err != nil {
+		return model.ProcessRun{}, nil, &StartError{http.StatusBadRequest, "Process validation failed", err}
+	}
+	for _, warning := range analysis.Warnings {
+		logger.GetLogger().Warnf("Process %s: %s", processDef.Name, warning)
+	}
+
+	priority := processDef.Priority
+	if opts.Priority != nil {
+		priority = *opts.Priority
+	}
+	if priority < 0 || priority > model.MaxPriority {
+		err := fmt.Errorf("priority must be between 0 and %d, got %d", model.MaxPriority, priority)
+		return model.ProcessRun{}, nil, &StartError{http.StatusBadRequest, "Invalid priority", err}
+	}
+
+	message := model.Message{
+		UUID:              uuid.New(),
+		ProcessDefinition: processDef,
+		Priority:          priority,
+	}
+
+	// The run is recorded under the ID of the message, so it is visible as queued
+	// until a consumer picks the message up.
+	run := model.ProcessRun{
+		ID:         message.UUID,
+		Definition: processDef,
+		Status:     model.StatusQueued,
+		Priority:   priority,
+		StartedAt:  time.Now(),
+	}
+
+	if opts.RunAt.After(run.StartedAt) {
+		run.Status = model.StatusScheduled
+		run.RunAt = &opts.RunAt
+		if err := s.runStore.InsertScheduledProcess(ctx, run); err != nil {
+			return model.ProcessRun{}, nil, &StartError{http.StatusInternalServerError, "Process scheduling failed", err}
+		}
+		return run, analysis.Warnings, nil
+	}
+
+	if err := s.runStore.InsertQueuedProcess(ctx, run); err != nil {
+		return model.ProcessRun{}, nil, &StartError{http.StatusInternalServerError, "Process queueing failed", err}
+	}
+
+	if err := s.publisher.Publish(ctx, message); err != nil {
+		if err := s.runStore.DeleteQueuedProcess(ctx, run.ID); err != nil {
+			logger.GetLogger().Errorf("failed to delete queued process %s: %v", run.ID, err)
+		}
+		return model.ProcessRun{}, nil, &StartError{http.StatusInternalServerError, "Process publishing failed", err}
+	}
+
+	return run, analysis.Warnings, nil
+}
@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/model"
+)
+
+// resolveSubprocesses looks up the definitions run by the processCmd tasks of def and
+// embeds them into the tasks, along with the definitions they run in turn. lineage
+// holds the names of the definitions def is nested in, def included.
+func (s *ProcessStarter) resolveSubprocesses(ctx context.Context, def *model.ProcessDefinition, lineage []string) error {
+	for i := range def.Tasks {
+		if err := s.resolveSubprocess(ctx, &def.Tasks[i], lineage); err != nil {
+			return err
+		}
+		if def.Tasks[i].Compensate != nil {
+			if err := s.resolveSubprocess(ctx, def.Tasks[i].Compensate, lineage); err != nil {
+				return err
+			}
+		}
+	}
+	for i := range def.Finally {
+		if err := s.resolveSubprocess(ctx, &def.Finally[i], lineage); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *ProcessStarter) resolveSubprocess(ctx context.Context, task *model.Task, lineage []string) error {
+	if task.Class != model.ProcessCmd {
+		return nil
+	}
+
+	name := task.Parameters[model.ProcessParam]
+	chain := append(slices.Clone(lineage), name)
+	if slices.Contains(lineage, name) {
+		return fmt.Errorf("cycle between process definitions: %s", strings.Join(chain, " -> "))
+	}
+	if len(chain) > model.MaxProcessDepth {
+		return fmt.Errorf("process definitions are nested deeper than %d: %s", model.MaxProcessDepth, strings.Join(chain, " -> "))
+	}
+
+	path, err := s.store.GetProcessPathByName(ctx, name)
+	if err != nil {
+		return fmt.Errorf("task '%s': %w", task.Name, err)
+	}
+	child, err := s.reader.ParseConfigFile(path)
+	if err != nil {
+		return fmt.Errorf("task '%s': %w", task.Name, err)
+	}
+
+	// The values may reference task outputs and are only known once the task runs,
+	// but the parameters have to be mapped.
+	if err := s.validator.ValidateMandatoryParams(child, task.Parameters); err != nil {
+		return fmt.Errorf("task '%s' process '%s': %w", task.Name, name, err)
+	}
+	// A child run is executed by the task that started it, so it cannot be parked.
+	for _, childTask := range slices.Concat(child.Tasks, child.Finally) {
+		if childTask.Class == model.ApprovalCmd {
+			return fmt.Errorf("process '%s' cannot run as a sub-process, task '%s' is an approval", name, childTask.Name)
+		}
+	}
+
+	if err := s.resolveSubprocesses(ctx, &child, chain); err != nil {
+		return err
+	}
+	task.Subprocess = &child
+	return nil
+}
@@ -3,26 +3,85 @@ package producer
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/lifecycle"
 	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/logger"
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/processproducer/dispatcher"
 	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/processproducer/handler"
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/processproducer/scheduler"
+	processstore "github.com/ggsomnoev/ntt-ds-sap-process-api/internal/processproducer/store"
 	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/processproducer/validator"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/labstack/echo/v4"
 )
 
+const (
+	idempotencyKeyCleanupInterval = time.Hour
+	// scheduleInterval is how often the scheduler checks for due schedules.
+	scheduleInterval = 10 * time.Second
+	// dispatchInterval is how often delayed runs are checked for being due.
+	dispatchInterval = 5 * time.Second
+)
+
+// DefinitionStore is the index of the process definitions kept by the process loader.
+type DefinitionStore interface {
+	handler.ProcessDefinitionStore
+	scheduler.DefinitionStore
+}
+
 func Process(
 	procSpawnFn lifecycle.ProcessSpawnFunc,
 	ctx context.Context,
 	srv *echo.Echo,
 	publisher handler.Publisher,
 	reader handler.Reader,
-	store handler.ProcessDefinitionStore,
+	store DefinitionStore,
+	pool *pgxpool.Pool,
+	statusURL string,
+	idempotencyKeyTTL time.Duration,
 ) {
+	keyStore := processstore.NewIdempotencyStore(pool, idempotencyKeyTTL)
+	validatorSvc := validator.NewProcessValidator()
+	runStore := processstore.NewProcessRunStore(pool)
+	starter := handler.NewProcessStarter(publisher, reader, store, runStore, validatorSvc)
+	schedulerSvc := scheduler.NewScheduler(store, reader, processstore.NewScheduleStore(pool), starter)
+
 	procSpawnFn(func(ctx context.Context) error {
-		validatorSvc := validator.NewProcessValidator()
+		schedulerSvc.Run(ctx, scheduleInterval)
+		return nil
+	}, "Scheduler")
 
-		handler.RegisterHandlers(ctx, srv, publisher, reader, store, validatorSvc)
+	procSpawnFn(func(ctx context.Context) error {
+		dispatcher.NewDispatcher(runStore, publisher).Run(ctx, dispatchInterval)
+		return nil
+	}, "Delayed Run Dispatcher")
+
+	procSpawnFn(func(ctx context.Context) error {
+		ticker := time.NewTicker(idempotencyKeyCleanupInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-ticker.C:
+			}
+
+			deleted, err := keyStore.DeleteExpiredKeys(ctx)
+			if err != nil {
+				logger.GetLogger().Errorf("failed to clean up idempotency keys: %v", err)
+				continue
+			}
+			if deleted > 0 {
+				logger.GetLogger().Infof("Deleted %d expired idempotency keys", deleted)
+			}
+		}
+	}, "Idempotency Key Cleanup")
+
+	procSpawnFn(func(ctx context.Context) error {
+		handler.RegisterHandlers(ctx, srv, starter, keyStore, statusURL)
+		handler.RegisterScheduleHandlers(ctx, srv, schedulerSvc, statusURL)
 
 		<-ctx.Done()
 		logger.GetLogger().Info("closing the RabbitMQ connection due to app exit")
@@ -0,0 +1,4 @@
+// Package scheduler starts the runs declared in the schedules of process definitions.
+package scheduler
+
+//go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 -generate
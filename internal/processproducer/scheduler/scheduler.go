@@ -0,0 +1,253 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/cron"
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/logger"
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/model"
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/processproducer/handler"
+	"github.com/google/uuid"
+)
+
+// syncInterval is how often the schedules are read from the process definitions again.
+const syncInterval = time.Minute
+
+//counterfeiter:generate . DefinitionStore
+type DefinitionStore interface {
+	ListProcessPaths(context.Context) (map[string]string, error)
+}
+
+//counterfeiter:generate . Reader
+type Reader interface {
+	ParseConfigFile(string) (model.ProcessDefinition, error)
+}
+
+//counterfeiter:generate . ScheduleStore
+type ScheduleStore interface {
+	SyncSchedules(context.Context, string, []model.ScheduleState) error
+	DeleteOtherSchedules(context.Context, []string) (int64, error)
+	ListSchedules(context.Context) ([]model.ScheduleState, error)
+	DueSchedules(context.Context, time.Time) ([]model.ScheduleState, error)
+	GetSchedule(context.Context, string, string) (model.ScheduleState, error)
+	ClaimFire(context.Context, string, string, time.Time, time.Time) (bool, error)
+	RecordRun(context.Context, string, string, uuid.UUID) error
+	PauseSchedule(context.Context, string, string) error
+	ResumeSchedule(context.Context, string, string, time.Time) error
+}
+
+//counterfeiter:generate . Starter
+type Starter interface {
+	Start(context.Context, string, map[string]string, handler.StartOptions) (model.ProcessRun, []string, error)
+}
+
+// Scheduler keeps the schedules of the indexed process definitions in the database and
+// starts their runs once they are due. Every replica of the producer runs a scheduler,
+// and a schedule is only fired by the one that moves its next fire time first.
+type Scheduler struct {
+	definitions DefinitionStore
+	reader      Reader
+	store       ScheduleStore
+	starter     Starter
+}
+
+func NewScheduler(definitions DefinitionStore, reader Reader, store ScheduleStore, starter Starter) *Scheduler {
+	return &Scheduler{
+		definitions: definitions,
+		reader:      reader,
+		store:       store,
+		starter:     starter,
+	}
+}
+
+// Run fires the due schedules every interval until the context is done.
+func (s *Scheduler) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var lastSync time.Time
+	for {
+		if time.Since(lastSync) >= syncInterval {
+			if err := s.Sync(ctx); err != nil && ctx.Err() == nil {
+				logger.GetLogger().Errorf("failed to sync schedules: %v", err)
+			}
+			lastSync = time.Now()
+		}
+		if err := s.FireDue(ctx); err != nil && ctx.Err() == nil {
+			logger.GetLogger().Errorf("failed to fire schedules: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Sync stores the schedules declared in the indexed process definitions. Schedules that
+// were removed from a definition, or whose definition is no longer indexed, are deleted.
+func (s *Scheduler) Sync(ctx context.Context) error {
+	paths, err := s.definitions.ListProcessPaths(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list process definitions: %w", err)
+	}
+
+	var errs []error
+	names := slices.Collect(maps.Keys(paths))
+	deleted, err := s.store.DeleteOtherSchedules(ctx, names)
+	if err != nil {
+		errs = append(errs, err)
+	} else if deleted > 0 {
+		logger.GetLogger().Infof("Deleted %d schedules of processes that are no longer indexed", deleted)
+	}
+
+	now := time.Now()
+	for name, path := range paths {
+		def, err := s.reader.ParseConfigFile(path)
+		if err != nil {
+			// Keep the stored schedules, the definition may be fixed soon.
+			logger.GetLogger().Warnf("Skipping schedules of process %s: %v", name, err)
+			continue
+		}
+
+		states := make([]model.ScheduleState, 0, len(def.Schedules))
+		for _, schedule := range def.Schedules {
+			next, err := nextFire(schedule, now)
+			if err != nil {
+				logger.GetLogger().Warnf("Skipping schedule %s of process %s: %v", schedule.Name, name, err)
+				continue
+			}
+			states = append(states, model.ScheduleState{
+				ProcessName: name,
+				Schedule:    schedule,
+				NextFireAt:  next,
+			})
+		}
+
+		if err := s.store.SyncSchedules(ctx, name, states); err != nil {
+			errs = append(errs, fmt.Errorf("process %s: %w", name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// FireDue starts a run of every schedule that is due. Fire times that were missed,
+// e.g. while no producer was running, are skipped except for the last one.
+func (s *Scheduler) FireDue(ctx context.Context) error {
+	now := time.Now()
+	due, err := s.store.DueSchedules(ctx, now)
+	if err != nil {
+		return fmt.Errorf("failed to get due schedules: %w", err)
+	}
+
+	var errs []error
+	for _, schedule := range due {
+		if err := s.fire(ctx, schedule, now); err != nil {
+			errs = append(errs, fmt.Errorf("schedule %s of process %s: %w", schedule.Name, schedule.ProcessName, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (s *Scheduler) fire(ctx context.Context, schedule model.ScheduleState, now time.Time) error {
+	next, err := nextFire(schedule.Schedule, now)
+	if err != nil {
+		return err
+	}
+
+	claimed, err := s.store.ClaimFire(ctx, schedule.ProcessName, schedule.Name, schedule.NextFireAt, next)
+	if err != nil {
+		return err
+	}
+	if !claimed {
+		// Another replica fired it, or it was paused in the meantime.
+		return nil
+	}
+
+	run, _, err := s.start(ctx, schedule)
+	if err != nil {
+		return err
+	}
+
+	logger.GetLogger().Infof("Started run %s of process %s by schedule %s, next at %s",
+		run.ID, schedule.ProcessName, schedule.Name, next.Format(time.RFC3339))
+	return nil
+}
+
+// List returns all schedules.
+func (s *Scheduler) List(ctx context.Context) ([]model.ScheduleState, error) {
+	return s.store.ListSchedules(ctx)
+}
+
+// Pause stops a schedule from firing until it is resumed.
+func (s *Scheduler) Pause(ctx context.Context, processName, scheduleName string) error {
+	return s.store.PauseSchedule(ctx, processName, scheduleName)
+}
+
+// Resume lets a paused schedule fire again. Fire times that passed while it was paused
+// are skipped.
+func (s *Scheduler) Resume(ctx context.Context, processName, scheduleName string) (model.ScheduleState, error) {
+	schedule, err := s.store.GetSchedule(ctx, processName, scheduleName)
+	if err != nil {
+		return model.ScheduleState{}, err
+	}
+
+	next, err := nextFire(schedule.Schedule, time.Now())
+	if err != nil {
+		return model.ScheduleState{}, err
+	}
+	if err := s.store.ResumeSchedule(ctx, processName, scheduleName, next); err != nil {
+		return model.ScheduleState{}, err
+	}
+
+	schedule.Paused = false
+	schedule.NextFireAt = next
+	return schedule, nil
+}
+
+// Trigger starts a run of a schedule right away, whether it is paused or not. It does
+// not change when the schedule fires next.
+func (s *Scheduler) Trigger(ctx context.Context, processName, scheduleName string) (model.ProcessRun, []string, error) {
+	schedule, err := s.store.GetSchedule(ctx, processName, scheduleName)
+	if err != nil {
+		return model.ProcessRun{}, nil, err
+	}
+
+	return s.start(ctx, schedule)
+}
+
+func (s *Scheduler) start(ctx context.Context, schedule model.ScheduleState) (model.ProcessRun, []string, error) {
+	run, warnings, err := s.starter.Start(ctx, schedule.ProcessName, schedule.Parameters, handler.StartOptions{})
+	if err != nil {
+		return model.ProcessRun{}, nil, fmt.Errorf("failed to start run: %w", err)
+	}
+	if err := s.store.RecordRun(ctx, schedule.ProcessName, schedule.Name, run.ID); err != nil {
+		logger.GetLogger().Errorf("failed to record run %s of schedule %s: %v", run.ID, schedule.Name, err)
+	}
+	return run, warnings, nil
+}
+
+// nextFire returns the first fire time of the schedule after t, in the timezone of the
+// schedule.
+func nextFire(schedule model.Schedule, t time.Time) (time.Time, error) {
+	expr, err := cron.Parse(schedule.Cron)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid cron expression: %w", err)
+	}
+	loc, err := schedule.Location()
+	if err != nil {
+		return time.Time{}, fmt.Errorf("unknown timezone '%s': %w", schedule.Timezone, err)
+	}
+
+	next := expr.Next(t.In(loc))
+	if next.IsZero() {
+		return time.Time{}, fmt.Errorf("cron expression '%s' never fires", schedule.Cron)
+	}
+	return next, nil
+}
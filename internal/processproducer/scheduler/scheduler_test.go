@@ -0,0 +1,252 @@
+package scheduler_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/model"
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/processproducer/scheduler"
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/processproducer/scheduler/schedulerfakes"
+)
+
+var (
+	ErrDb    = errors.New("db error")
+	ErrParse = errors.New("parse error")
+	ErrStart = errors.New("start error")
+)
+
+var _ = Describe("Scheduler", func() {
+	var (
+		ctx         context.Context
+		definitions *schedulerfakes.FakeDefinitionStore
+		reader      *schedulerfakes.FakeReader
+		store       *schedulerfakes.FakeScheduleStore
+		starter     *schedulerfakes.FakeStarter
+		s           *scheduler.Scheduler
+		schedule    model.ScheduleState
+		run         model.ProcessRun
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		definitions = &schedulerfakes.FakeDefinitionStore{}
+		reader = &schedulerfakes.FakeReader{}
+		store = &schedulerfakes.FakeScheduleStore{}
+		starter = &schedulerfakes.FakeStarter{}
+		s = scheduler.NewScheduler(definitions, reader, store, starter)
+
+		schedule = model.ScheduleState{
+			ProcessName: "backup",
+			Schedule: model.Schedule{
+				Name:       "nightly",
+				Cron:       "0 2 * * *",
+				Timezone:   "Europe/Berlin",
+				Parameters: map[string]string{"target": "db"},
+			},
+			NextFireAt: time.Now().Add(-time.Second).Truncate(time.Microsecond),
+		}
+		run = model.ProcessRun{ID: uuid.New()}
+		starter.StartReturns(run, nil, nil)
+	})
+
+	Describe("Sync", func() {
+		var errAction error
+
+		BeforeEach(func() {
+			definitions.ListProcessPathsReturns(map[string]string{"backup": "/configs/backup.yaml"}, nil)
+			reader.ParseConfigFileReturns(model.ProcessDefinition{
+				Name:      "backup",
+				Schedules: []model.Schedule{schedule.Schedule},
+			}, nil)
+		})
+
+		JustBeforeEach(func() {
+			errAction = s.Sync(ctx)
+		})
+
+		It("stores the schedules of the definitions with their next fire time", func() {
+			Expect(errAction).NotTo(HaveOccurred())
+			Expect(reader.ParseConfigFileArgsForCall(0)).To(Equal("/configs/backup.yaml"))
+
+			Expect(store.SyncSchedulesCallCount()).To(Equal(1))
+			_, name, states := store.SyncSchedulesArgsForCall(0)
+			Expect(name).To(Equal("backup"))
+			Expect(states).To(HaveLen(1))
+			Expect(states[0].Schedule).To(Equal(schedule.Schedule))
+			Expect(states[0].NextFireAt).To(BeTemporally(">", time.Now()))
+			Expect(states[0].NextFireAt.Location().String()).To(Equal("Europe/Berlin"))
+			Expect(states[0].NextFireAt.Hour()).To(Equal(2))
+		})
+
+		It("deletes the schedules of the processes that are no longer indexed", func() {
+			Expect(store.DeleteOtherSchedulesCallCount()).To(Equal(1))
+			_, names := store.DeleteOtherSchedulesArgsForCall(0)
+			Expect(names).To(ConsistOf("backup"))
+		})
+
+		When("deleting the schedules of removed processes fails", func() {
+			BeforeEach(func() {
+				store.DeleteOtherSchedulesReturns(0, ErrDb)
+			})
+
+			It("still stores the schedules of the definitions", func() {
+				Expect(errAction).To(MatchError(ErrDb))
+				Expect(store.SyncSchedulesCallCount()).To(Equal(1))
+			})
+		})
+
+		When("a definition cannot be parsed", func() {
+			BeforeEach(func() {
+				reader.ParseConfigFileReturns(model.ProcessDefinition{}, ErrParse)
+			})
+
+			It("keeps its stored schedules", func() {
+				Expect(errAction).NotTo(HaveOccurred())
+				Expect(store.SyncSchedulesCallCount()).To(Equal(0))
+			})
+		})
+
+		When("listing the definitions fails", func() {
+			BeforeEach(func() {
+				definitions.ListProcessPathsReturns(nil, ErrDb)
+			})
+
+			It("returns an error", func() {
+				Expect(errAction).To(MatchError(ErrDb))
+			})
+		})
+
+		When("storing the schedules fails", func() {
+			BeforeEach(func() {
+				store.SyncSchedulesReturns(ErrDb)
+			})
+
+			It("returns an error", func() {
+				Expect(errAction).To(MatchError(ErrDb))
+			})
+		})
+	})
+
+	Describe("FireDue", func() {
+		var errAction error
+
+		BeforeEach(func() {
+			store.DueSchedulesReturns([]model.ScheduleState{schedule}, nil)
+			store.ClaimFireReturns(true, nil)
+		})
+
+		JustBeforeEach(func() {
+			errAction = s.FireDue(ctx)
+		})
+
+		It("moves the next fire time of the schedule", func() {
+			Expect(errAction).NotTo(HaveOccurred())
+			Expect(store.ClaimFireCallCount()).To(Equal(1))
+			_, process, name, due, next := store.ClaimFireArgsForCall(0)
+			Expect(process).To(Equal("backup"))
+			Expect(name).To(Equal("nightly"))
+			Expect(due).To(Equal(schedule.NextFireAt))
+			Expect(next).To(BeTemporally(">", time.Now()))
+		})
+
+		It("starts a run with the parameters of the schedule", func() {
+			Expect(starter.StartCallCount()).To(Equal(1))
+			_, process, params, _ := starter.StartArgsForCall(0)
+			Expect(process).To(Equal("backup"))
+			Expect(params).To(Equal(schedule.Parameters))
+		})
+
+		It("records the run", func() {
+			Expect(store.RecordRunCallCount()).To(Equal(1))
+			_, _, _, id := store.RecordRunArgsForCall(0)
+			Expect(id).To(Equal(run.ID))
+		})
+
+		When("the schedule was fired by another replica", func() {
+			BeforeEach(func() {
+				store.ClaimFireReturns(false, nil)
+			})
+
+			It("does not start a run", func() {
+				Expect(errAction).NotTo(HaveOccurred())
+				Expect(starter.StartCallCount()).To(Equal(0))
+			})
+		})
+
+		When("claiming the fire fails", func() {
+			BeforeEach(func() {
+				store.ClaimFireReturns(false, ErrDb)
+			})
+
+			It("returns an error", func() {
+				Expect(errAction).To(MatchError(ErrDb))
+				Expect(starter.StartCallCount()).To(Equal(0))
+			})
+		})
+
+		When("starting the run fails", func() {
+			BeforeEach(func() {
+				starter.StartReturns(model.ProcessRun{}, nil, ErrStart)
+			})
+
+			It("returns an error", func() {
+				Expect(errAction).To(MatchError(ErrStart))
+				Expect(store.RecordRunCallCount()).To(Equal(0))
+			})
+		})
+	})
+
+	Describe("Resume", func() {
+		var (
+			resumed   model.ScheduleState
+			errAction error
+		)
+
+		BeforeEach(func() {
+			schedule.Paused = true
+			store.GetScheduleReturns(schedule, nil)
+		})
+
+		JustBeforeEach(func() {
+			resumed, errAction = s.Resume(ctx, "backup", "nightly")
+		})
+
+		It("resumes the schedule from now on", func() {
+			Expect(errAction).NotTo(HaveOccurred())
+			Expect(store.ResumeScheduleCallCount()).To(Equal(1))
+			_, _, _, next := store.ResumeScheduleArgsForCall(0)
+			Expect(next).To(BeTemporally(">", time.Now()))
+			Expect(resumed.Paused).To(BeFalse())
+			Expect(resumed.NextFireAt).To(Equal(next))
+		})
+
+		When("the schedule does not exist", func() {
+			BeforeEach(func() {
+				store.GetScheduleReturns(model.ScheduleState{}, model.ErrScheduleNotFound)
+			})
+
+			It("returns an error", func() {
+				Expect(errAction).To(MatchError(model.ErrScheduleNotFound))
+				Expect(store.ResumeScheduleCallCount()).To(Equal(0))
+			})
+		})
+	})
+
+	Describe("Trigger", func() {
+		It("starts a run without moving the next fire time", func() {
+			schedule.Paused = true
+			store.GetScheduleReturns(schedule, nil)
+
+			started, _, err := s.Trigger(ctx, "backup", "nightly")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(started).To(Equal(run))
+			Expect(store.ClaimFireCallCount()).To(Equal(0))
+			Expect(store.RecordRunCallCount()).To(Equal(1))
+		})
+	})
+})
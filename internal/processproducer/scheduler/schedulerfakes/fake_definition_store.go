@@ -0,0 +1,117 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package schedulerfakes
+
+import (
+	"context"
+	"sync"
+
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/processproducer/scheduler"
+)
+
+type FakeDefinitionStore struct {
+	ListProcessPathsStub        func(context.Context) (map[string]string, error)
+	listProcessPathsMutex       sync.RWMutex
+	listProcessPathsArgsForCall []struct {
+		arg1 context.Context
+	}
+	listProcessPathsReturns struct {
+		result1 map[string]string
+		result2 error
+	}
+	listProcessPathsReturnsOnCall map[int]struct {
+		result1 map[string]string
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeDefinitionStore) ListProcessPaths(arg1 context.Context) (map[string]string, error) {
+	fake.listProcessPathsMutex.Lock()
+	ret, specificReturn := fake.listProcessPathsReturnsOnCall[len(fake.listProcessPathsArgsForCall)]
+	fake.listProcessPathsArgsForCall = append(fake.listProcessPathsArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	stub := fake.ListProcessPathsStub
+	fakeReturns := fake.listProcessPathsReturns
+	fake.recordInvocation("ListProcessPaths", []interface{}{arg1})
+	fake.listProcessPathsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeDefinitionStore) ListProcessPathsCallCount() int {
+	fake.listProcessPathsMutex.RLock()
+	defer fake.listProcessPathsMutex.RUnlock()
+	return len(fake.listProcessPathsArgsForCall)
+}
+
+func (fake *FakeDefinitionStore) ListProcessPathsCalls(stub func(context.Context) (map[string]string, error)) {
+	fake.listProcessPathsMutex.Lock()
+	defer fake.listProcessPathsMutex.Unlock()
+	fake.ListProcessPathsStub = stub
+}
+
+func (fake *FakeDefinitionStore) ListProcessPathsArgsForCall(i int) context.Context {
+	fake.listProcessPathsMutex.RLock()
+	defer fake.listProcessPathsMutex.RUnlock()
+	argsForCall := fake.listProcessPathsArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeDefinitionStore) ListProcessPathsReturns(result1 map[string]string, result2 error) {
+	fake.listProcessPathsMutex.Lock()
+	defer fake.listProcessPathsMutex.Unlock()
+	fake.ListProcessPathsStub = nil
+	fake.listProcessPathsReturns = struct {
+		result1 map[string]string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeDefinitionStore) ListProcessPathsReturnsOnCall(i int, result1 map[string]string, result2 error) {
+	fake.listProcessPathsMutex.Lock()
+	defer fake.listProcessPathsMutex.Unlock()
+	fake.ListProcessPathsStub = nil
+	if fake.listProcessPathsReturnsOnCall == nil {
+		fake.listProcessPathsReturnsOnCall = make(map[int]struct {
+			result1 map[string]string
+			result2 error
+		})
+	}
+	fake.listProcessPathsReturnsOnCall[i] = struct {
+		result1 map[string]string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeDefinitionStore) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.listProcessPathsMutex.RLock()
+	defer fake.listProcessPathsMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeDefinitionStore) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ scheduler.DefinitionStore = new(FakeDefinitionStore)
@@ -0,0 +1,117 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package schedulerfakes
+
+import (
+	"sync"
+
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/model"
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/processproducer/scheduler"
+)
+
+type FakeReader struct {
+	ParseConfigFileStub        func(string) (model.ProcessDefinition, error)
+	parseConfigFileMutex       sync.RWMutex
+	parseConfigFileArgsForCall []struct {
+		arg1 string
+	}
+	parseConfigFileReturns struct {
+		result1 model.ProcessDefinition
+		result2 error
+	}
+	parseConfigFileReturnsOnCall map[int]struct {
+		result1 model.ProcessDefinition
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeReader) ParseConfigFile(arg1 string) (model.ProcessDefinition, error) {
+	fake.parseConfigFileMutex.Lock()
+	ret, specificReturn := fake.parseConfigFileReturnsOnCall[len(fake.parseConfigFileArgsForCall)]
+	fake.parseConfigFileArgsForCall = append(fake.parseConfigFileArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.ParseConfigFileStub
+	fakeReturns := fake.parseConfigFileReturns
+	fake.recordInvocation("ParseConfigFile", []interface{}{arg1})
+	fake.parseConfigFileMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeReader) ParseConfigFileCallCount() int {
+	fake.parseConfigFileMutex.RLock()
+	defer fake.parseConfigFileMutex.RUnlock()
+	return len(fake.parseConfigFileArgsForCall)
+}
+
+func (fake *FakeReader) ParseConfigFileCalls(stub func(string) (model.ProcessDefinition, error)) {
+	fake.parseConfigFileMutex.Lock()
+	defer fake.parseConfigFileMutex.Unlock()
+	fake.ParseConfigFileStub = stub
+}
+
+func (fake *FakeReader) ParseConfigFileArgsForCall(i int) string {
+	fake.parseConfigFileMutex.RLock()
+	defer fake.parseConfigFileMutex.RUnlock()
+	argsForCall := fake.parseConfigFileArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeReader) ParseConfigFileReturns(result1 model.ProcessDefinition, result2 error) {
+	fake.parseConfigFileMutex.Lock()
+	defer fake.parseConfigFileMutex.Unlock()
+	fake.ParseConfigFileStub = nil
+	fake.parseConfigFileReturns = struct {
+		result1 model.ProcessDefinition
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeReader) ParseConfigFileReturnsOnCall(i int, result1 model.ProcessDefinition, result2 error) {
+	fake.parseConfigFileMutex.Lock()
+	defer fake.parseConfigFileMutex.Unlock()
+	fake.ParseConfigFileStub = nil
+	if fake.parseConfigFileReturnsOnCall == nil {
+		fake.parseConfigFileReturnsOnCall = make(map[int]struct {
+			result1 model.ProcessDefinition
+			result2 error
+		})
+	}
+	fake.parseConfigFileReturnsOnCall[i] = struct {
+		result1 model.ProcessDefinition
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeReader) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.parseConfigFileMutex.RLock()
+	defer fake.parseConfigFileMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeReader) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ scheduler.Reader = new(FakeReader)
@@ -0,0 +1,778 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package schedulerfakes
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/model"
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/processproducer/scheduler"
+	"github.com/google/uuid"
+)
+
+type FakeScheduleStore struct {
+	ClaimFireStub        func(context.Context, string, string, time.Time, time.Time) (bool, error)
+	claimFireMutex       sync.RWMutex
+	claimFireArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 time.Time
+		arg5 time.Time
+	}
+	claimFireReturns struct {
+		result1 bool
+		result2 error
+	}
+	claimFireReturnsOnCall map[int]struct {
+		result1 bool
+		result2 error
+	}
+	DeleteOtherSchedulesStub        func(context.Context, []string) (int64, error)
+	deleteOtherSchedulesMutex       sync.RWMutex
+	deleteOtherSchedulesArgsForCall []struct {
+		arg1 context.Context
+		arg2 []string
+	}
+	deleteOtherSchedulesReturns struct {
+		result1 int64
+		result2 error
+	}
+	deleteOtherSchedulesReturnsOnCall map[int]struct {
+		result1 int64
+		result2 error
+	}
+	DueSchedulesStub        func(context.Context, time.Time) ([]model.ScheduleState, error)
+	dueSchedulesMutex       sync.RWMutex
+	dueSchedulesArgsForCall []struct {
+		arg1 context.Context
+		arg2 time.Time
+	}
+	dueSchedulesReturns struct {
+		result1 []model.ScheduleState
+		result2 error
+	}
+	dueSchedulesReturnsOnCall map[int]struct {
+		result1 []model.ScheduleState
+		result2 error
+	}
+	GetScheduleStub        func(context.Context, string, string) (model.ScheduleState, error)
+	getScheduleMutex       sync.RWMutex
+	getScheduleArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+	}
+	getScheduleReturns struct {
+		result1 model.ScheduleState
+		result2 error
+	}
+	getScheduleReturnsOnCall map[int]struct {
+		result1 model.ScheduleState
+		result2 error
+	}
+	ListSchedulesStub        func(context.Context) ([]model.ScheduleState, error)
+	listSchedulesMutex       sync.RWMutex
+	listSchedulesArgsForCall []struct {
+		arg1 context.Context
+	}
+	listSchedulesReturns struct {
+		result1 []model.ScheduleState
+		result2 error
+	}
+	listSchedulesReturnsOnCall map[int]struct {
+		result1 []model.ScheduleState
+		result2 error
+	}
+	PauseScheduleStub        func(context.Context, string, string) error
+	pauseScheduleMutex       sync.RWMutex
+	pauseScheduleArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+	}
+	pauseScheduleReturns struct {
+		result1 error
+	}
+	pauseScheduleReturnsOnCall map[int]struct {
+		result1 error
+	}
+	RecordRunStub        func(context.Context, string, string, uuid.UUID) error
+	recordRunMutex       sync.RWMutex
+	recordRunArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 uuid.UUID
+	}
+	recordRunReturns struct {
+		result1 error
+	}
+	recordRunReturnsOnCall map[int]struct {
+		result1 error
+	}
+	ResumeScheduleStub        func(context.Context, string, string, time.Time) error
+	resumeScheduleMutex       sync.RWMutex
+	resumeScheduleArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 time.Time
+	}
+	resumeScheduleReturns struct {
+		result1 error
+	}
+	resumeScheduleReturnsOnCall map[int]struct {
+		result1 error
+	}
+	SyncSchedulesStub        func(context.Context, string, []model.ScheduleState) error
+	syncSchedulesMutex       sync.RWMutex
+	syncSchedulesArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 []model.ScheduleState
+	}
+	syncSchedulesReturns struct {
+		result1 error
+	}
+	syncSchedulesReturnsOnCall map[int]struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeScheduleStore) ClaimFire(arg1 context.Context, arg2 string, arg3 string, arg4 time.Time, arg5 time.Time) (bool, error) {
+	fake.claimFireMutex.Lock()
+	ret, specificReturn := fake.claimFireReturnsOnCall[len(fake.claimFireArgsForCall)]
+	fake.claimFireArgsForCall = append(fake.claimFireArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 time.Time
+		arg5 time.Time
+	}{arg1, arg2, arg3, arg4, arg5})
+	stub := fake.ClaimFireStub
+	fakeReturns := fake.claimFireReturns
+	fake.recordInvocation("ClaimFire", []interface{}{arg1, arg2, arg3, arg4, arg5})
+	fake.claimFireMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4, arg5)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeScheduleStore) ClaimFireCallCount() int {
+	fake.claimFireMutex.RLock()
+	defer fake.claimFireMutex.RUnlock()
+	return len(fake.claimFireArgsForCall)
+}
+
+func (fake *FakeScheduleStore) ClaimFireCalls(stub func(context.Context, string, string, time.Time, time.Time) (bool, error)) {
+	fake.claimFireMutex.Lock()
+	defer fake.claimFireMutex.Unlock()
+	fake.ClaimFireStub = stub
+}
+
+func (fake *FakeScheduleStore) ClaimFireArgsForCall(i int) (context.Context, string, string, time.Time, time.Time) {
+	fake.claimFireMutex.RLock()
+	defer fake.claimFireMutex.RUnlock()
+	argsForCall := fake.claimFireArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5
+}
+
+func (fake *FakeScheduleStore) ClaimFireReturns(result1 bool, result2 error) {
+	fake.claimFireMutex.Lock()
+	defer fake.claimFireMutex.Unlock()
+	fake.ClaimFireStub = nil
+	fake.claimFireReturns = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeScheduleStore) ClaimFireReturnsOnCall(i int, result1 bool, result2 error) {
+	fake.claimFireMutex.Lock()
+	defer fake.claimFireMutex.Unlock()
+	fake.ClaimFireStub = nil
+	if fake.claimFireReturnsOnCall == nil {
+		fake.claimFireReturnsOnCall = make(map[int]struct {
+			result1 bool
+			result2 error
+		})
+	}
+	fake.claimFireReturnsOnCall[i] = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeScheduleStore) DeleteOtherSchedules(arg1 context.Context, arg2 []string) (int64, error) {
+	var arg2Copy []string
+	if arg2 != nil {
+		arg2Copy = make([]string, len(arg2))
+		copy(arg2Copy, arg2)
+	}
+	fake.deleteOtherSchedulesMutex.Lock()
+	ret, specificReturn := fake.deleteOtherSchedulesReturnsOnCall[len(fake.deleteOtherSchedulesArgsForCall)]
+	fake.deleteOtherSchedulesArgsForCall = append(fake.deleteOtherSchedulesArgsForCall, struct {
+		arg1 context.Context
+		arg2 []string
+	}{arg1, arg2Copy})
+	stub := fake.DeleteOtherSchedulesStub
+	fakeReturns := fake.deleteOtherSchedulesReturns
+	fake.recordInvocation("DeleteOtherSchedules", []interface{}{arg1, arg2Copy})
+	fake.deleteOtherSchedulesMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeScheduleStore) DeleteOtherSchedulesCallCount() int {
+	fake.deleteOtherSchedulesMutex.RLock()
+	defer fake.deleteOtherSchedulesMutex.RUnlock()
+	return len(fake.deleteOtherSchedulesArgsForCall)
+}
+
+func (fake *FakeScheduleStore) DeleteOtherSchedulesCalls(stub func(context.Context, []string) (int64, error)) {
+	fake.deleteOtherSchedulesMutex.Lock()
+	defer fake.deleteOtherSchedulesMutex.Unlock()
+	fake.DeleteOtherSchedulesStub = stub
+}
+
+func (fake *FakeScheduleStore) DeleteOtherSchedulesArgsForCall(i int) (context.Context, []string) {
+	fake.deleteOtherSchedulesMutex.RLock()
+	defer fake.deleteOtherSchedulesMutex.RUnlock()
+	argsForCall := fake.deleteOtherSchedulesArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeScheduleStore) DeleteOtherSchedulesReturns(result1 int64, result2 error) {
+	fake.deleteOtherSchedulesMutex.Lock()
+	defer fake.deleteOtherSchedulesMutex.Unlock()
+	fake.DeleteOtherSchedulesStub = nil
+	fake.deleteOtherSchedulesReturns = struct {
+		result1 int64
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeScheduleStore) DeleteOtherSchedulesReturnsOnCall(i int, result1 int64, result2 error) {
+	fake.deleteOtherSchedulesMutex.Lock()
+	defer fake.deleteOtherSchedulesMutex.Unlock()
+	fake.DeleteOtherSchedulesStub = nil
+	if fake.deleteOtherSchedulesReturnsOnCall == nil {
+		fake.deleteOtherSchedulesReturnsOnCall = make(map[int]struct {
+			result1 int64
+			result2 error
+		})
+	}
+	fake.deleteOtherSchedulesReturnsOnCall[i] = struct {
+		result1 int64
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeScheduleStore) DueSchedules(arg1 context.Context, arg2 time.Time) ([]model.ScheduleState, error) {
+	fake.dueSchedulesMutex.Lock()
+	ret, specificReturn := fake.dueSchedulesReturnsOnCall[len(fake.dueSchedulesArgsForCall)]
+	fake.dueSchedulesArgsForCall = append(fake.dueSchedulesArgsForCall, struct {
+		arg1 context.Context
+		arg2 time.Time
+	}{arg1, arg2})
+	stub := fake.DueSchedulesStub
+	fakeReturns := fake.dueSchedulesReturns
+	fake.recordInvocation("DueSchedules", []interface{}{arg1, arg2})
+	fake.dueSchedulesMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeScheduleStore) DueSchedulesCallCount() int {
+	fake.dueSchedulesMutex.RLock()
+	defer fake.dueSchedulesMutex.RUnlock()
+	return len(fake.dueSchedulesArgsForCall)
+}
+
+func (fake *FakeScheduleStore) DueSchedulesCalls(stub func(context.Context, time.Time) ([]model.ScheduleState, error)) {
+	fake.dueSchedulesMutex.Lock()
+	defer fake.dueSchedulesMutex.Unlock()
+	fake.DueSchedulesStub = stub
+}
+
+func (fake *FakeScheduleStore) DueSchedulesArgsForCall(i int) (context.Context, time.Time) {
+	fake.dueSchedulesMutex.RLock()
+	defer fake.dueSchedulesMutex.RUnlock()
+	argsForCall := fake.dueSchedulesArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeScheduleStore) DueSchedulesReturns(result1 []model.ScheduleState, result2 error) {
+	fake.dueSchedulesMutex.Lock()
+	defer fake.dueSchedulesMutex.Unlock()
+	fake.DueSchedulesStub = nil
+	fake.dueSchedulesReturns = struct {
+		result1 []model.ScheduleState
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeScheduleStore) DueSchedulesReturnsOnCall(i int, result1 []model.ScheduleState, result2 error) {
+	fake.dueSchedulesMutex.Lock()
+	defer fake.dueSchedulesMutex.Unlock()
+	fake.DueSchedulesStub = nil
+	if fake.dueSchedulesReturnsOnCall == nil {
+		fake.dueSchedulesReturnsOnCall = make(map[int]struct {
+			result1 []model.ScheduleState
+			result2 error
+		})
+	}
+	fake.dueSchedulesReturnsOnCall[i] = struct {
+		result1 []model.ScheduleState
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeScheduleStore) GetSchedule(arg1 context.Context, arg2 string, arg3 string) (model.ScheduleState, error) {
+	fake.getScheduleMutex.Lock()
+	ret, specificReturn := fake.getScheduleReturnsOnCall[len(fake.getScheduleArgsForCall)]
+	fake.getScheduleArgsForCall = append(fake.getScheduleArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+	}{arg1, arg2, arg3})
+	stub := fake.GetScheduleStub
+	fakeReturns := fake.getScheduleReturns
+	fake.recordInvocation("GetSchedule", []interface{}{arg1, arg2, arg3})
+	fake.getScheduleMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeScheduleStore) GetScheduleCallCount() int {
+	fake.getScheduleMutex.RLock()
+	defer fake.getScheduleMutex.RUnlock()
+	return len(fake.getScheduleArgsForCall)
+}
+
+func (fake *FakeScheduleStore) GetScheduleCalls(stub func(context.Context, string, string) (model.ScheduleState, error)) {
+	fake.getScheduleMutex.Lock()
+	defer fake.getScheduleMutex.Unlock()
+	fake.GetScheduleStub = stub
+}
+
+func (fake *FakeScheduleStore) GetScheduleArgsForCall(i int) (context.Context, string, string) {
+	fake.getScheduleMutex.RLock()
+	defer fake.getScheduleMutex.RUnlock()
+	argsForCall := fake.getScheduleArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeScheduleStore) GetScheduleReturns(result1 model.ScheduleState, result2 error) {
+	fake.getScheduleMutex.Lock()
+	defer fake.getScheduleMutex.Unlock()
+	fake.GetScheduleStub = nil
+	fake.getScheduleReturns = struct {
+		result1 model.ScheduleState
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeScheduleStore) GetScheduleReturnsOnCall(i int, result1 model.ScheduleState, result2 error) {
+	fake.getScheduleMutex.Lock()
+	defer fake.getScheduleMutex.Unlock()
+	fake.GetScheduleStub = nil
+	if fake.getScheduleReturnsOnCall == nil {
+		fake.getScheduleReturnsOnCall = make(map[int]struct {
+			result1 model.ScheduleState
+			result2 error
+		})
+	}
+	fake.getScheduleReturnsOnCall[i] = struct {
+		result1 model.ScheduleState
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeScheduleStore) ListSchedules(arg1 context.Context) ([]model.ScheduleState, error) {
+	fake.listSchedulesMutex.Lock()
+	ret, specificReturn := fake.listSchedulesReturnsOnCall[len(fake.listSchedulesArgsForCall)]
+	fake.listSchedulesArgsForCall = append(fake.listSchedulesArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	stub := fake.ListSchedulesStub
+	fakeReturns := fake.listSchedulesReturns
+	fake.recordInvocation("ListSchedules", []interface{}{arg1})
+	fake.listSchedulesMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeScheduleStore) ListSchedulesCallCount() int {
+	fake.listSchedulesMutex.RLock()
+	defer fake.listSchedulesMutex.RUnlock()
+	return len(fake.listSchedulesArgsForCall)
+}
+
+func (fake *FakeScheduleStore) ListSchedulesCalls(stub func(context.Context) ([]model.ScheduleState, error)) {
+	fake.listSchedulesMutex.Lock()
+	defer fake.listSchedulesMutex.Unlock()
+	fake.ListSchedulesStub = stub
+}
+
+func (fake *FakeScheduleStore) ListSchedulesArgsForCall(i int) context.Context {
+	fake.listSchedulesMutex.RLock()
+	defer fake.listSchedulesMutex.RUnlock()
+	argsForCall := fake.listSchedulesArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeScheduleStore) ListSchedulesReturns(result1 []model.ScheduleState, result2 error) {
+	fake.listSchedulesMutex.Lock()
+	defer fake.listSchedulesMutex.Unlock()
+	fake.ListSchedulesStub = nil
+	fake.listSchedulesReturns = struct {
+		result1 []model.ScheduleState
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeScheduleStore) ListSchedulesReturnsOnCall(i int, result1 []model.ScheduleState, result2 error) {
+	fake.listSchedulesMutex.Lock()
+	defer fake.listSchedulesMutex.Unlock()
+	fake.ListSchedulesStub = nil
+	if fake.listSchedulesReturnsOnCall == nil {
+		fake.listSchedulesReturnsOnCall = make(map[int]struct {
+			result1 []model.ScheduleState
+			result2 error
+		})
+	}
+	fake.listSchedulesReturnsOnCall[i] = struct {
+		result1 []model.ScheduleState
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeScheduleStore) PauseSchedule(arg1 context.Context, arg2 string, arg3 string) error {
+	fake.pauseScheduleMutex.Lock()
+	ret, specificReturn := fake.pauseScheduleReturnsOnCall[len(fake.pauseScheduleArgsForCall)]
+	fake.pauseScheduleArgsForCall = append(fake.pauseScheduleArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+	}{arg1, arg2, arg3})
+	stub := fake.PauseScheduleStub
+	fakeReturns := fake.pauseScheduleReturns
+	fake.recordInvocation("PauseSchedule", []interface{}{arg1, arg2, arg3})
+	fake.pauseScheduleMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeScheduleStore) PauseScheduleCallCount() int {
+	fake.pauseScheduleMutex.RLock()
+	defer fake.pauseScheduleMutex.RUnlock()
+	return len(fake.pauseScheduleArgsForCall)
+}
+
+func (fake *FakeScheduleStore) PauseScheduleCalls(stub func(context.Context, string, string) error) {
+	fake.pauseScheduleMutex.Lock()
+	defer fake.pauseScheduleMutex.Unlock()
+	fake.PauseScheduleStub = stub
+}
+
+func (fake *FakeScheduleStore) PauseScheduleArgsForCall(i int) (context.Context, string, string) {
+	fake.pauseScheduleMutex.RLock()
+	defer fake.pauseScheduleMutex.RUnlock()
+	argsForCall := fake.pauseScheduleArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeScheduleStore) PauseScheduleReturns(result1 error) {
+	fake.pauseScheduleMutex.Lock()
+	defer fake.pauseScheduleMutex.Unlock()
+	fake.PauseScheduleStub = nil
+	fake.pauseScheduleReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeScheduleStore) PauseScheduleReturnsOnCall(i int, result1 error) {
+	fake.pauseScheduleMutex.Lock()
+	defer fake.pauseScheduleMutex.Unlock()
+	fake.PauseScheduleStub = nil
+	if fake.pauseScheduleReturnsOnCall == nil {
+		fake.pauseScheduleReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.pauseScheduleReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeScheduleStore) RecordRun(arg1 context.Context, arg2 string, arg3 string, arg4 uuid.UUID) error {
+	fake.recordRunMutex.Lock()
+	ret, specificReturn := fake.recordRunReturnsOnCall[len(fake.recordRunArgsForCall)]
+	fake.recordRunArgsForCall = append(fake.recordRunArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 uuid.UUID
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.RecordRunStub
+	fakeReturns := fake.recordRunReturns
+	fake.recordInvocation("RecordRun", []interface{}{arg1, arg2, arg3, arg4})
+	fake.recordRunMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeScheduleStore) RecordRunCallCount() int {
+	fake.recordRunMutex.RLock()
+	defer fake.recordRunMutex.RUnlock()
+	return len(fake.recordRunArgsForCall)
+}
+
+func (fake *FakeScheduleStore) RecordRunCalls(stub func(context.Context, string, string, uuid.UUID) error) {
+	fake.recordRunMutex.Lock()
+	defer fake.recordRunMutex.Unlock()
+	fake.RecordRunStub = stub
+}
+
+func (fake *FakeScheduleStore) RecordRunArgsForCall(i int) (context.Context, string, string, uuid.UUID) {
+	fake.recordRunMutex.RLock()
+	defer fake.recordRunMutex.RUnlock()
+	argsForCall := fake.recordRunArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeScheduleStore) RecordRunReturns(result1 error) {
+	fake.recordRunMutex.Lock()
+	defer fake.recordRunMutex.Unlock()
+	fake.RecordRunStub = nil
+	fake.recordRunReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeScheduleStore) RecordRunReturnsOnCall(i int, result1 error) {
+	fake.recordRunMutex.Lock()
+	defer fake.recordRunMutex.Unlock()
+	fake.RecordRunStub = nil
+	if fake.recordRunReturnsOnCall == nil {
+		fake.recordRunReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.recordRunReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeScheduleStore) ResumeSchedule(arg1 context.Context, arg2 string, arg3 string, arg4 time.Time) error {
+	fake.resumeScheduleMutex.Lock()
+	ret, specificReturn := fake.resumeScheduleReturnsOnCall[len(fake.resumeScheduleArgsForCall)]
+	fake.resumeScheduleArgsForCall = append(fake.resumeScheduleArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 time.Time
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.ResumeScheduleStub
+	fakeReturns := fake.resumeScheduleReturns
+	fake.recordInvocation("ResumeSchedule", []interface{}{arg1, arg2, arg3, arg4})
+	fake.resumeScheduleMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeScheduleStore) ResumeScheduleCallCount() int {
+	fake.resumeScheduleMutex.RLock()
+	defer fake.resumeScheduleMutex.RUnlock()
+	return len(fake.resumeScheduleArgsForCall)
+}
+
+func (fake *FakeScheduleStore) ResumeScheduleCalls(stub func(context.Context, string, string, time.Time) error) {
+	fake.resumeScheduleMutex.Lock()
+	defer fake.resumeScheduleMutex.Unlock()
+	fake.ResumeScheduleStub = stub
+}
+
+func (fake *FakeScheduleStore) ResumeScheduleArgsForCall(i int) (context.Context, string, string, time.Time) {
+	fake.resumeScheduleMutex.RLock()
+	defer fake.resumeScheduleMutex.RUnlock()
+	argsForCall := fake.resumeScheduleArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeScheduleStore) ResumeScheduleReturns(result1 error) {
+	fake.resumeScheduleMutex.Lock()
+	defer fake.resumeScheduleMutex.Unlock()
+	fake.ResumeScheduleStub = nil
+	fake.resumeScheduleReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeScheduleStore) ResumeScheduleReturnsOnCall(i int, result1 error) {
+	fake.resumeScheduleMutex.Lock()
+	defer fake.resumeScheduleMutex.Unlock()
+	fake.ResumeScheduleStub = nil
+	if fake.resumeScheduleReturnsOnCall == nil {
+		fake.resumeScheduleReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.resumeScheduleReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeScheduleStore) SyncSchedules(arg1 context.Context, arg2 string, arg3 []model.ScheduleState) error {
+	var arg3Copy []model.ScheduleState
+	if arg3 != nil {
+		arg3Copy = make([]model.ScheduleState, len(arg3))
+		copy(arg3Copy, arg3)
+	}
+	fake.syncSchedulesMutex.Lock()
+	ret, specificReturn := fake.syncSchedulesReturnsOnCall[len(fake.syncSchedulesArgsForCall)]
+	fake.syncSchedulesArgsForCall = append(fake.syncSchedulesArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 []model.ScheduleState
+	}{arg1, arg2, arg3Copy})
+	stub := fake.SyncSchedulesStub
+	fakeReturns := fake.syncSchedulesReturns
+	fake.recordInvocation("SyncSchedules", []interface{}{arg1, arg2, arg3Copy})
+	fake.syncSchedulesMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeScheduleStore) SyncSchedulesCallCount() int {
+	fake.syncSchedulesMutex.RLock()
+	defer fake.syncSchedulesMutex.RUnlock()
+	return len(fake.syncSchedulesArgsForCall)
+}
+
+func (fake *FakeScheduleStore) SyncSchedulesCalls(stub func(context.Context, string, []model.ScheduleState) error) {
+	fake.syncSchedulesMutex.Lock()
+	defer fake.syncSchedulesMutex.Unlock()
+	fake.SyncSchedulesStub = stub
+}
+
+func (fake *FakeScheduleStore) SyncSchedulesArgsForCall(i int) (context.Context, string, []model.ScheduleState) {
+	fake.syncSchedulesMutex.RLock()
+	defer fake.syncSchedulesMutex.RUnlock()
+	argsForCall := fake.syncSchedulesArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeScheduleStore) SyncSchedulesReturns(result1 error) {
+	fake.syncSchedulesMutex.Lock()
+	defer fake.syncSchedulesMutex.Unlock()
+	fake.SyncSchedulesStub = nil
+	fake.syncSchedulesReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeScheduleStore) SyncSchedulesReturnsOnCall(i int, result1 error) {
+	fake.syncSchedulesMutex.Lock()
+	defer fake.syncSchedulesMutex.Unlock()
+	fake.SyncSchedulesStub = nil
+	if fake.syncSchedulesReturnsOnCall == nil {
+		fake.syncSchedulesReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.syncSchedulesReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeScheduleStore) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.claimFireMutex.RLock()
+	defer fake.claimFireMutex.RUnlock()
+	fake.deleteOtherSchedulesMutex.RLock()
+	defer fake.deleteOtherSchedulesMutex.RUnlock()
+	fake.dueSchedulesMutex.RLock()
+	defer fake.dueSchedulesMutex.RUnlock()
+	fake.getScheduleMutex.RLock()
+	defer fake.getScheduleMutex.RUnlock()
+	fake.listSchedulesMutex.RLock()
+	defer fake.listSchedulesMutex.RUnlock()
+	fake.pauseScheduleMutex.RLock()
+	defer fake.pauseScheduleMutex.RUnlock()
+	fake.recordRunMutex.RLock()
+	defer fake.recordRunMutex.RUnlock()
+	fake.resumeScheduleMutex.RLock()
+	defer fake.resumeScheduleMutex.RUnlock()
+	fake.syncSchedulesMutex.RLock()
+	defer fake.syncSchedulesMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeScheduleStore) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ scheduler.ScheduleStore = new(FakeScheduleStore)
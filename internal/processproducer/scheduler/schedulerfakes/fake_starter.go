@@ -0,0 +1,130 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package schedulerfakes
+
+import (
+	"context"
+	"sync"
+
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/model"
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/processproducer/handler"
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/processproducer/scheduler"
+)
+
+type FakeStarter struct {
+	StartStub        func(context.Context, string, map[string]string, handler.StartOptions) (model.ProcessRun, []string, error)
+	startMutex       sync.RWMutex
+	startArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 map[string]string
+		arg4 handler.StartOptions
+	}
+	startReturns struct {
+		result1 model.ProcessRun
+		result2 []string
+		result3 error
+	}
+	startReturnsOnCall map[int]struct {
+		result1 model.ProcessRun
+		result2 []string
+		result3 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeStarter) Start(arg1 context.Context, arg2 string, arg3 map[string]string, arg4 handler.StartOptions) (model.ProcessRun, []string, error) {
+	fake.startMutex.Lock()
+	ret, specificReturn := fake.startReturnsOnCall[len(fake.startArgsForCall)]
+	fake.startArgsForCall = append(fake.startArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 map[string]string
+		arg4 handler.StartOptions
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.StartStub
+	fakeReturns := fake.startReturns
+	fake.recordInvocation("Start", []interface{}{arg1, arg2, arg3, arg4})
+	fake.startMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
+}
+
+func (fake *FakeStarter) StartCallCount() int {
+	fake.startMutex.RLock()
+	defer fake.startMutex.RUnlock()
+	return len(fake.startArgsForCall)
+}
+
+func (fake *FakeStarter) StartCalls(stub func(context.Context, string, map[string]string, handler.StartOptions) (model.ProcessRun, []string, error)) {
+	fake.startMutex.Lock()
+	defer fake.startMutex.Unlock()
+	fake.StartStub = stub
+}
+
+func (fake *FakeStarter) StartArgsForCall(i int) (context.Context, string, map[string]string, handler.StartOptions) {
+	fake.startMutex.RLock()
+	defer fake.startMutex.RUnlock()
+	argsForCall := fake.startArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeStarter) StartReturns(result1 model.ProcessRun, result2 []string, result3 error) {
+	fake.startMutex.Lock()
+	defer fake.startMutex.Unlock()
+	fake.StartStub = nil
+	fake.startReturns = struct {
+		result1 model.ProcessRun
+		result2 []string
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeStarter) StartReturnsOnCall(i int, result1 model.ProcessRun, result2 []string, result3 error) {
+	fake.startMutex.Lock()
+	defer fake.startMutex.Unlock()
+	fake.StartStub = nil
+	if fake.startReturnsOnCall == nil {
+		fake.startReturnsOnCall = make(map[int]struct {
+			result1 model.ProcessRun
+			result2 []string
+			result3 error
+		})
+	}
+	fake.startReturnsOnCall[i] = struct {
+		result1 model.ProcessRun
+		result2 []string
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeStarter) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.startMutex.RLock()
+	defer fake.startMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeStarter) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ scheduler.Starter = new(FakeStarter)
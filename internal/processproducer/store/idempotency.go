@@ -0,0 +1,95 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/model"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var IdempotencyKeysTable = "idempotency_keys"
+
+// IdempotencyStore keeps the Idempotency-Keys of requests for ttl.
+type IdempotencyStore struct {
+	pool *pgxpool.Pool
+	ttl  time.Duration
+}
+
+func NewIdempotencyStore(pool *pgxpool.Pool, ttl time.Duration) *IdempotencyStore {
+	return &IdempotencyStore{pool: pool, ttl: ttl}
+}
+
+// ReserveKey records the key for a request with the given hash. If the key is already
+// in use, it reports false and returns what is recorded for it. Expired keys are reused.
+func (s *IdempotencyStore) ReserveKey(ctx context.Context, key, requestHash string) (model.IdempotencyRecord, bool, error) {
+	query := fmt.Sprintf(`
+		INSERT INTO %[1]s (key, request_hash, created_at, expires_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (key) DO UPDATE
+		SET request_hash = EXCLUDED.request_hash, process_id = NULL, status_code = NULL, response = NULL,
+			created_at = EXCLUDED.created_at, expires_at = EXCLUDED.expires_at
+		WHERE %[1]s.expires_at < EXCLUDED.created_at
+	`, IdempotencyKeysTable)
+
+	now := time.Now()
+	tag, err := s.pool.Exec(ctx, query, key, requestHash, now, now.Add(s.ttl))
+	if err != nil {
+		return model.IdempotencyRecord{}, false, fmt.Errorf("failed to reserve idempotency key: %w", err)
+	}
+	if tag.RowsAffected() == 1 {
+		return model.IdempotencyRecord{Key: key, RequestHash: requestHash}, true, nil
+	}
+
+	query = fmt.Sprintf(`
+		SELECT key, request_hash, process_id, COALESCE(status_code, 0), response FROM %s WHERE key = $1
+	`, IdempotencyKeysTable)
+
+	var record model.IdempotencyRecord
+	err = s.pool.QueryRow(ctx, query, key).Scan(
+		&record.Key, &record.RequestHash, &record.ProcessID, &record.StatusCode, &record.Response,
+	)
+	if err != nil {
+		return model.IdempotencyRecord{}, false, fmt.Errorf("failed to get idempotency key: %w", err)
+	}
+	return record, false, nil
+}
+
+// SaveResponse records the outcome of the request made with the key.
+func (s *IdempotencyStore) SaveResponse(ctx context.Context, key string, processID uuid.UUID, statusCode int, response []byte) error {
+	query := fmt.Sprintf(`
+		UPDATE %s SET process_id = $1, status_code = $2, response = $3 WHERE key = $4
+	`, IdempotencyKeysTable)
+
+	if _, err := s.pool.Exec(ctx, query, processID, statusCode, response, key); err != nil {
+		return fmt.Errorf("failed to save idempotent response: %w", err)
+	}
+	return nil
+}
+
+// ReleaseKey removes a key whose request did not succeed, so that it can be retried.
+func (s *IdempotencyStore) ReleaseKey(ctx context.Context, key string) error {
+	query := fmt.Sprintf(`
+		DELETE FROM %s WHERE key = $1 AND response IS NULL
+	`, IdempotencyKeysTable)
+
+	if _, err := s.pool.Exec(ctx, query, key); err != nil {
+		return fmt.Errorf("failed to release idempotency key: %w", err)
+	}
+	return nil
+}
+
+// DeleteExpiredKeys removes the keys whose TTL passed.
+func (s *IdempotencyStore) DeleteExpiredKeys(ctx context.Context) (int64, error) {
+	query := fmt.Sprintf(`
+		DELETE FROM %s WHERE expires_at < $1
+	`, IdempotencyKeysTable)
+
+	tag, err := s.pool.Exec(ctx, query, time.Now())
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired idempotency keys: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,109 @@
+package store_test
+
+import (
+	"time"
+
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/model"
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/processproducer/store"
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("IdempotencyStore", func() {
+	var (
+		s        *store.IdempotencyStore
+		record   model.IdempotencyRecord
+		reserved bool
+		err      error
+	)
+
+	BeforeEach(func() {
+		s = store.NewIdempotencyStore(pool, time.Hour)
+
+		_, _ = pool.Exec(ctx, "DELETE FROM idempotency_keys")
+	})
+
+	JustBeforeEach(func() {
+		record, reserved, err = s.ReserveKey(ctx, "ci-job-42", "hash")
+	})
+
+	It("reserves a new key", func() {
+		Expect(err).ToNot(HaveOccurred())
+		Expect(reserved).To(BeTrue())
+	})
+
+	When("the key is reserved", func() {
+		BeforeEach(func() {
+			_, _, err := s.ReserveKey(ctx, "ci-job-42", "other")
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("returns what is recorded for it", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(reserved).To(BeFalse())
+			Expect(record.RequestHash).To(Equal("other"))
+			Expect(record.Response).To(BeNil())
+		})
+	})
+
+	When("the request with the key succeeded", func() {
+		var processID uuid.UUID
+
+		BeforeEach(func() {
+			processID = uuid.New()
+			_, _, err := s.ReserveKey(ctx, "ci-job-42", "hash")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(s.SaveResponse(ctx, "ci-job-42", processID, 202, []byte(`{"id":"`+processID.String()+`"}`))).To(Succeed())
+		})
+
+		It("returns the saved response", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(reserved).To(BeFalse())
+			Expect(record.ProcessID).To(Equal(&processID))
+			Expect(record.StatusCode).To(Equal(202))
+			Expect(record.Response).To(MatchJSON(`{"id":"` + processID.String() + `"}`))
+		})
+
+		It("is not released", func() {
+			Expect(s.ReleaseKey(ctx, "ci-job-42")).To(Succeed())
+			_, reserved, err := s.ReserveKey(ctx, "ci-job-42", "hash")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(reserved).To(BeFalse())
+		})
+	})
+
+	When("the key was released", func() {
+		BeforeEach(func() {
+			_, _, err := s.ReserveKey(ctx, "ci-job-42", "hash")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(s.ReleaseKey(ctx, "ci-job-42")).To(Succeed())
+		})
+
+		It("reserves it again", func() {
+			Expect(reserved).To(BeTrue())
+		})
+	})
+
+	When("the key expired", func() {
+		BeforeEach(func() {
+			expired := store.NewIdempotencyStore(pool, -time.Minute)
+			_, _, err := expired.ReserveKey(ctx, "ci-job-42", "other")
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("reuses it", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(reserved).To(BeTrue())
+		})
+
+		It("is deleted by the cleanup", func() {
+			_, err := pool.Exec(ctx, "UPDATE idempotency_keys SET expires_at = NOW() - INTERVAL '1 minute'")
+			Expect(err).ToNot(HaveOccurred())
+
+			deleted, err := s.DeleteExpiredKeys(ctx)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(deleted).To(Equal(int64(1)))
+		})
+	})
+})
@@ -0,0 +1,190 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/model"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var ProcessSchedulesTable = "process_schedules"
+
+const scheduleColumns = `process_name, schedule_name, cron, timezone, parameters, paused, next_fire_at, last_fired_at, last_run_id`
+
+type ScheduleStore struct {
+	pool *pgxpool.Pool
+}
+
+func NewScheduleStore(pool *pgxpool.Pool) *ScheduleStore {
+	return &ScheduleStore{pool: pool}
+}
+
+// SyncSchedules makes the stored schedules of the process match the given ones. The
+// next fire time of a schedule is only replaced if its cron expression or timezone
+// changed, and whether it is paused is kept.
+func (s *ScheduleStore) SyncSchedules(ctx context.Context, processName string, schedules []model.ScheduleState) error {
+	return pgx.BeginFunc(ctx, s.pool, func(tx pgx.Tx) error {
+		query := fmt.Sprintf(`
+			INSERT INTO %[1]s (process_name, schedule_name, cron, timezone, parameters, next_fire_at)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			ON CONFLICT (process_name, schedule_name) DO UPDATE
+			SET cron = EXCLUDED.cron, timezone = EXCLUDED.timezone, parameters = EXCLUDED.parameters,
+				next_fire_at = CASE
+					WHEN %[1]s.cron <> EXCLUDED.cron OR %[1]s.timezone <> EXCLUDED.timezone THEN EXCLUDED.next_fire_at
+					ELSE %[1]s.next_fire_at
+				END
+		`, ProcessSchedulesTable)
+
+		names := make([]string, 0, len(schedules))
+		for _, schedule := range schedules {
+			_, err := tx.Exec(ctx, query,
+				processName, schedule.Name, schedule.Cron, schedule.Timezone, schedule.Parameters, schedule.NextFireAt,
+			)
+			if err != nil {
+				return fmt.Errorf("failed to save schedule %s: %w", schedule.Name, err)
+			}
+			names = append(names, schedule.Name)
+		}
+
+		query = fmt.Sprintf(`
+			DELETE FROM %s WHERE process_name = $1 AND NOT (schedule_name = ANY($2))
+		`, ProcessSchedulesTable)
+		if _, err := tx.Exec(ctx, query, processName, names); err != nil {
+			return fmt.Errorf("failed to delete removed schedules: %w", err)
+		}
+		return nil
+	})
+}
+
+// DeleteOtherSchedules deletes the schedules of all processes but the given ones. It
+// returns the number of deleted schedules.
+func (s *ScheduleStore) DeleteOtherSchedules(ctx context.Context, processNames []string) (int64, error) {
+	query := fmt.Sprintf(`
+		DELETE FROM %s WHERE NOT (process_name = ANY($1))
+	`, ProcessSchedulesTable)
+
+	tag, err := s.pool.Exec(ctx, query, processNames)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete schedules of removed processes: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+// ListSchedules returns all schedules, the ones that fire next first.
+func (s *ScheduleStore) ListSchedules(ctx context.Context) ([]model.ScheduleState, error) {
+	query := fmt.Sprintf(`
+		SELECT %s FROM %s ORDER BY next_fire_at ASC, process_name ASC, schedule_name ASC
+	`, scheduleColumns, ProcessSchedulesTable)
+
+	return s.querySchedules(ctx, query)
+}
+
+// DueSchedules returns the schedules that are not paused and should have fired at now.
+func (s *ScheduleStore) DueSchedules(ctx context.Context, now time.Time) ([]model.ScheduleState, error) {
+	query := fmt.Sprintf(`
+		SELECT %s FROM %s WHERE NOT paused AND next_fire_at <= $1 ORDER BY next_fire_at ASC
+	`, scheduleColumns, ProcessSchedulesTable)
+
+	return s.querySchedules(ctx, query, now)
+}
+
+// GetSchedule returns a schedule of a process.
+func (s *ScheduleStore) GetSchedule(ctx context.Context, processName, scheduleName string) (model.ScheduleState, error) {
+	query := fmt.Sprintf(`
+		SELECT %s FROM %s WHERE process_name = $1 AND schedule_name = $2
+	`, scheduleColumns, ProcessSchedulesTable)
+
+	schedules, err := s.querySchedules(ctx, query, processName, scheduleName)
+	if err != nil {
+		return model.ScheduleState{}, err
+	}
+	if len(schedules) == 0 {
+		return model.ScheduleState{}, model.ErrScheduleNotFound
+	}
+	return schedules[0], nil
+}
+
+// ClaimFire moves the next fire time of a schedule from due to next. It reports false if
+// the schedule was fired by someone else in the meantime, or paused.
+func (s *ScheduleStore) ClaimFire(ctx context.Context, processName, scheduleName string, due, next time.Time) (bool, error) {
+	query := fmt.Sprintf(`
+		UPDATE %s SET next_fire_at = $1, last_fired_at = $2
+		WHERE process_name = $3 AND schedule_name = $4 AND next_fire_at = $5 AND NOT paused
+	`, ProcessSchedulesTable)
+
+	tag, err := s.pool.Exec(ctx, query, next, time.Now(), processName, scheduleName, due)
+	if err != nil {
+		return false, fmt.Errorf("failed to claim schedule fire: %w", err)
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+// RecordRun records the run last started by a schedule.
+func (s *ScheduleStore) RecordRun(ctx context.Context, processName, scheduleName string, runID uuid.UUID) error {
+	query := fmt.Sprintf(`
+		UPDATE %s SET last_run_id = $1 WHERE process_name = $2 AND schedule_name = $3
+	`, ProcessSchedulesTable)
+
+	if _, err := s.pool.Exec(ctx, query, runID, processName, scheduleName); err != nil {
+		return fmt.Errorf("failed to record scheduled run: %w", err)
+	}
+	return nil
+}
+
+// PauseSchedule stops a schedule from firing.
+func (s *ScheduleStore) PauseSchedule(ctx context.Context, processName, scheduleName string) error {
+	query := fmt.Sprintf(`
+		UPDATE %s SET paused = TRUE WHERE process_name = $1 AND schedule_name = $2
+	`, ProcessSchedulesTable)
+
+	return s.updateSchedule(ctx, query, processName, scheduleName)
+}
+
+// ResumeSchedule lets a paused schedule fire again, next at the given time.
+func (s *ScheduleStore) ResumeSchedule(ctx context.Context, processName, scheduleName string, next time.Time) error {
+	query := fmt.Sprintf(`
+		UPDATE %s SET paused = FALSE, next_fire_at = $3 WHERE process_name = $1 AND schedule_name = $2
+	`, ProcessSchedulesTable)
+
+	return s.updateSchedule(ctx, query, processName, scheduleName, next)
+}
+
+func (s *ScheduleStore) updateSchedule(ctx context.Context, query, processName, scheduleName string, args ...any) error {
+	tag, err := s.pool.Exec(ctx, query, append([]any{processName, scheduleName}, args...)...)
+	if err != nil {
+		return fmt.Errorf("failed to update schedule: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return model.ErrScheduleNotFound
+	}
+	return nil
+}
+
+func (s *ScheduleStore) querySchedules(ctx context.Context, query string, args ...any) ([]model.ScheduleState, error) {
+	rows, err := s.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query schedules: %w", err)
+	}
+	defer rows.Close()
+
+	var schedules []model.ScheduleState
+	for rows.Next() {
+		var st model.ScheduleState
+		err := rows.Scan(
+			&st.ProcessName, &st.Name, &st.Cron, &st.Timezone, &st.Parameters, &st.Paused,
+			&st.NextFireAt, &st.LastFiredAt, &st.LastRunID,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan schedule: %w", err)
+		}
+		schedules = append(schedules, st)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read schedules: %w", err)
+	}
+	return schedules, nil
+}
@@ -0,0 +1,161 @@
+package store_test
+
+import (
+	"time"
+
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/model"
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/processproducer/store"
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ScheduleStore", func() {
+	var (
+		s        *store.ScheduleStore
+		schedule model.ScheduleState
+		due      time.Time
+	)
+
+	BeforeEach(func() {
+		s = store.NewScheduleStore(pool)
+
+		_, _ = pool.Exec(ctx, "DELETE FROM process_schedules")
+
+		due = time.Now().Add(-time.Minute).Truncate(time.Second)
+		schedule = model.ScheduleState{
+			ProcessName: "backup",
+			Schedule: model.Schedule{
+				Name:       "nightly",
+				Cron:       "0 2 * * *",
+				Parameters: map[string]string{"target": "db"},
+			},
+			NextFireAt: due,
+		}
+		Expect(s.SyncSchedules(ctx, "backup", []model.ScheduleState{schedule})).To(Succeed())
+	})
+
+	It("lists the synced schedules", func() {
+		schedules, err := s.ListSchedules(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(schedules).To(HaveLen(1))
+		Expect(schedules[0].ProcessName).To(Equal("backup"))
+		Expect(schedules[0].Schedule).To(Equal(schedule.Schedule))
+		Expect(schedules[0].NextFireAt).To(BeTemporally("==", due))
+		Expect(schedules[0].Paused).To(BeFalse())
+	})
+
+	It("keeps the next fire time if the cron expression did not change", func() {
+		schedule.NextFireAt = due.Add(time.Hour)
+		Expect(s.SyncSchedules(ctx, "backup", []model.ScheduleState{schedule})).To(Succeed())
+
+		stored, err := s.GetSchedule(ctx, "backup", "nightly")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(stored.NextFireAt).To(BeTemporally("==", due))
+	})
+
+	It("replaces the next fire time if the cron expression changed", func() {
+		schedule.Cron = "0 3 * * *"
+		schedule.NextFireAt = due.Add(time.Hour)
+		Expect(s.SyncSchedules(ctx, "backup", []model.ScheduleState{schedule})).To(Succeed())
+
+		stored, err := s.GetSchedule(ctx, "backup", "nightly")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(stored.NextFireAt).To(BeTemporally("==", due.Add(time.Hour)))
+	})
+
+	It("deletes schedules that were removed", func() {
+		Expect(s.SyncSchedules(ctx, "backup", nil)).To(Succeed())
+
+		_, err := s.GetSchedule(ctx, "backup", "nightly")
+		Expect(err).To(MatchError(model.ErrScheduleNotFound))
+	})
+
+	It("deletes the schedules of other processes", func() {
+		cleanup := schedule
+		cleanup.ProcessName = "cleanup"
+		Expect(s.SyncSchedules(ctx, "cleanup", []model.ScheduleState{cleanup})).To(Succeed())
+
+		deleted, err := s.DeleteOtherSchedules(ctx, []string{"cleanup"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(deleted).To(Equal(int64(1)))
+
+		schedules, err := s.ListSchedules(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(schedules).To(ConsistOf(HaveField("ProcessName", "cleanup")))
+	})
+
+	It("returns due schedules", func() {
+		schedules, err := s.DueSchedules(ctx, time.Now())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(schedules).To(HaveLen(1))
+
+		schedules, err = s.DueSchedules(ctx, due.Add(-time.Second))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(schedules).To(BeEmpty())
+	})
+
+	It("lets only one caller claim a fire", func() {
+		next := due.Add(24 * time.Hour)
+
+		claimed, err := s.ClaimFire(ctx, "backup", "nightly", due, next)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(claimed).To(BeTrue())
+
+		claimed, err = s.ClaimFire(ctx, "backup", "nightly", due, next)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(claimed).To(BeFalse())
+
+		stored, err := s.GetSchedule(ctx, "backup", "nightly")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(stored.NextFireAt).To(BeTemporally("==", next))
+		Expect(stored.LastFiredAt).NotTo(BeNil())
+	})
+
+	It("records the last run", func() {
+		runID := uuid.New()
+		Expect(s.RecordRun(ctx, "backup", "nightly", runID)).To(Succeed())
+
+		stored, err := s.GetSchedule(ctx, "backup", "nightly")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(stored.LastRunID).To(Equal(&runID))
+	})
+
+	When("the schedule is paused", func() {
+		BeforeEach(func() {
+			Expect(s.PauseSchedule(ctx, "backup", "nightly")).To(Succeed())
+		})
+
+		It("is not due and cannot be fired", func() {
+			schedules, err := s.DueSchedules(ctx, time.Now())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(schedules).To(BeEmpty())
+
+			claimed, err := s.ClaimFire(ctx, "backup", "nightly", due, due.Add(time.Hour))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(claimed).To(BeFalse())
+		})
+
+		It("stays paused when it is synced", func() {
+			Expect(s.SyncSchedules(ctx, "backup", []model.ScheduleState{schedule})).To(Succeed())
+
+			stored, err := s.GetSchedule(ctx, "backup", "nightly")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(stored.Paused).To(BeTrue())
+		})
+
+		It("can be resumed", func() {
+			next := time.Now().Add(time.Hour).Truncate(time.Second)
+			Expect(s.ResumeSchedule(ctx, "backup", "nightly", next)).To(Succeed())
+
+			stored, err := s.GetSchedule(ctx, "backup", "nightly")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(stored.Paused).To(BeFalse())
+			Expect(stored.NextFireAt).To(BeTemporally("==", next))
+		})
+	})
+
+	It("fails to pause an unknown schedule", func() {
+		Expect(s.PauseSchedule(ctx, "backup", "weekly")).To(MatchError(model.ErrScheduleNotFound))
+	})
+})
@@ -0,0 +1,125 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/model"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var ProcessRunsTable = "process_runs"
+
+type ProcessRunStore struct {
+	pool *pgxpool.Pool
+}
+
+func NewProcessRunStore(pool *pgxpool.Pool) *ProcessRunStore {
+	return &ProcessRunStore{pool: pool}
+}
+
+// InsertQueuedProcess records a run that waits for a consumer to pick it up. Its start
+// time is the time it was queued until the consumer starts it.
+func (s *ProcessRunStore) InsertQueuedProcess(ctx context.Context, run model.ProcessRun) error {
+	query := fmt.Sprintf(`
+		INSERT INTO %s (id, definition, status, priority, started_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`, ProcessRunsTable)
+
+	if _, err := s.pool.Exec(ctx, query, run.ID, run.Definition, model.StatusQueued, run.Priority, run.StartedAt); err != nil {
+		return fmt.Errorf("failed to insert queued process: %w", err)
+	}
+	return nil
+}
+
+// DeleteQueuedProcess removes a run that is still queued, e.g. because its message could not be published.
+func (s *ProcessRunStore) DeleteQueuedProcess(ctx context.Context, id uuid.UUID) error {
+	query := fmt.Sprintf(`
+		DELETE FROM %s WHERE id = $1 AND status = $2
+	`, ProcessRunsTable)
+
+	if _, err := s.pool.Exec(ctx, query, id, model.StatusQueued); err != nil {
+		return fmt.Errorf("failed to delete queued process: %w", err)
+	}
+	return nil
+}
+
+// InsertScheduledProcess records a run that is held until its RunAt time.
+func (s *ProcessRunStore) InsertScheduledProcess(ctx context.Context, run model.ProcessRun) error {
+	query := fmt.Sprintf(`
+		INSERT INTO %s (id, definition, status, priority, started_at, run_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`, ProcessRunsTable)
+
+	if _, err := s.pool.Exec(ctx, query, run.ID, run.Definition, model.StatusScheduled, run.Priority, run.StartedAt, run.RunAt); err != nil {
+		return fmt.Errorf("failed to insert scheduled process: %w", err)
+	}
+	return nil
+}
+
+// QueueDueProcesses calls publish for up to limit scheduled runs that are due at now and
+// marks them as queued. The runs are locked while they are published, so that they are
+// neither published by another producer nor cancelled in the meantime. It stops at the
+// first run that fails to publish and returns how many runs were queued.
+func (s *ProcessRunStore) QueueDueProcesses(
+	ctx context.Context,
+	now time.Time,
+	limit int,
+	publish func(context.Context, model.ProcessRun) error,
+) (int, error) {
+	var (
+		queued     int
+		publishErr error
+	)
+	err := pgx.BeginFunc(ctx, s.pool, func(tx pgx.Tx) error {
+		query := fmt.Sprintf(`
+			SELECT id, definition, priority, run_at FROM %s
+			WHERE status = $1 AND run_at <= $2
+			ORDER BY run_at ASC
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		`, ProcessRunsTable)
+
+		rows, err := tx.Query(ctx, query, model.StatusScheduled, now, limit)
+		if err != nil {
+			return fmt.Errorf("failed to get due processes: %w", err)
+		}
+		var runs []model.ProcessRun
+		for rows.Next() {
+			run := model.ProcessRun{Status: model.StatusScheduled}
+			if err := rows.Scan(&run.ID, &run.Definition, &run.Priority, &run.RunAt); err != nil {
+				rows.Close()
+				return fmt.Errorf("failed to scan due process: %w", err)
+			}
+			runs = append(runs, run)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("failed to read due processes: %w", err)
+		}
+
+		// The start time of a queued run is the time it was queued.
+		query = fmt.Sprintf(`
+			UPDATE %s SET status = $1, started_at = $2 WHERE id = $3
+		`, ProcessRunsTable)
+		for _, run := range runs {
+			if err := publish(ctx, run); err != nil {
+				// The runs published so far are committed as queued.
+				publishErr = fmt.Errorf("failed to publish process %s: %w", run.ID, err)
+				return nil
+			}
+			if _, err := tx.Exec(ctx, query, model.StatusQueued, time.Now(), run.ID); err != nil {
+				return fmt.Errorf("failed to queue process %s: %w", run.ID, err)
+			}
+			queued++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return queued, publishErr
+}
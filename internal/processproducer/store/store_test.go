@@ -0,0 +1,150 @@
+package store_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/model"
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/processproducer/store"
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ProcessRunStore", func() {
+	var (
+		s   *store.ProcessRunStore
+		run model.ProcessRun
+	)
+
+	statusOf := func(id uuid.UUID) (model.ProcessStatus, error) {
+		var status model.ProcessStatus
+		err := pool.QueryRow(ctx, "SELECT status FROM process_runs WHERE id = $1", id).Scan(&status)
+		return status, err
+	}
+
+	BeforeEach(func() {
+		s = store.NewProcessRunStore(pool)
+
+		_, _ = pool.Exec(ctx, "DELETE FROM process_runs")
+
+		run = model.ProcessRun{
+			ID:         uuid.New(),
+			Definition: model.ProcessDefinition{Name: "my-process"},
+			StartedAt:  time.Now(),
+		}
+	})
+
+	Describe("InsertQueuedProcess", func() {
+		It("records the run as queued", func() {
+			Expect(s.InsertQueuedProcess(ctx, run)).To(Succeed())
+
+			status, err := statusOf(run.ID)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(status).To(Equal(model.StatusQueued))
+		})
+
+		It("records the priority of the run", func() {
+			run.Priority = 7
+			Expect(s.InsertQueuedProcess(ctx, run)).To(Succeed())
+
+			var priority int
+			Expect(pool.QueryRow(ctx, "SELECT priority FROM process_runs WHERE id = $1", run.ID).Scan(&priority)).To(Succeed())
+			Expect(priority).To(Equal(7))
+		})
+	})
+
+	Describe("DeleteQueuedProcess", func() {
+		BeforeEach(func() {
+			Expect(s.InsertQueuedProcess(ctx, run)).To(Succeed())
+		})
+
+		It("removes the queued run", func() {
+			Expect(s.DeleteQueuedProcess(ctx, run.ID)).To(Succeed())
+
+			_, err := statusOf(run.ID)
+			Expect(err).To(HaveOccurred())
+		})
+
+		When("the run was started already", func() {
+			BeforeEach(func() {
+				_, err := pool.Exec(ctx, "UPDATE process_runs SET status = $1 WHERE id = $2", model.StatusRunning, run.ID)
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("keeps it", func() {
+				Expect(s.DeleteQueuedProcess(ctx, run.ID)).To(Succeed())
+
+				status, err := statusOf(run.ID)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(status).To(Equal(model.StatusRunning))
+			})
+		})
+	})
+
+	Describe("QueueDueProcesses", func() {
+		var (
+			published []model.ProcessRun
+			publish   func(context.Context, model.ProcessRun) error
+		)
+
+		BeforeEach(func() {
+			published = nil
+			publish = func(_ context.Context, run model.ProcessRun) error {
+				published = append(published, run)
+				return nil
+			}
+
+			runAt := time.Now().Add(-time.Minute)
+			run.RunAt = &runAt
+			run.Priority = 4
+			Expect(s.InsertScheduledProcess(ctx, run)).To(Succeed())
+		})
+
+		It("records the run as scheduled", func() {
+			status, err := statusOf(run.ID)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(status).To(Equal(model.StatusScheduled))
+		})
+
+		It("publishes and queues the due runs", func() {
+			queued, err := s.QueueDueProcesses(ctx, time.Now(), 10, publish)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(queued).To(Equal(1))
+			Expect(published).To(HaveLen(1))
+			Expect(published[0].ID).To(Equal(run.ID))
+			Expect(published[0].Definition).To(Equal(run.Definition))
+			Expect(published[0].Priority).To(Equal(4))
+
+			status, err := statusOf(run.ID)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(status).To(Equal(model.StatusQueued))
+		})
+
+		It("keeps the runs that are not due yet", func() {
+			queued, err := s.QueueDueProcesses(ctx, run.RunAt.Add(-time.Second), 10, publish)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(queued).To(Equal(0))
+			Expect(published).To(BeEmpty())
+		})
+
+		When("publishing fails", func() {
+			BeforeEach(func() {
+				publish = func(context.Context, model.ProcessRun) error {
+					return errors.New("publish error")
+				}
+			})
+
+			It("keeps the run scheduled", func() {
+				queued, err := s.QueueDueProcesses(ctx, time.Now(), 10, publish)
+				Expect(err).To(HaveOccurred())
+				Expect(queued).To(Equal(0))
+
+				status, err := statusOf(run.ID)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(status).To(Equal(model.StatusScheduled))
+			})
+		})
+	})
+})
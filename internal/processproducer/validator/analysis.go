@@ -0,0 +1,243 @@
+package validator
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/condition"
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/model"
+)
+
+// GraphAnalysis describes the dependency graph of the tasks of a process.
+type GraphAnalysis struct {
+	// CriticalPath is the longest chain of tasks that have to run one after another.
+	CriticalPath []string
+	// MaxWidth is the highest number of tasks on the same level of the graph, i.e. that
+	// can run at the same time once the tasks before them are done.
+	MaxWidth int
+	// Warnings point at tasks that can never run because of their dependencies.
+	Warnings []string
+}
+
+// Analyze examines the task graph of the process. It fails on unknown dependencies and cycles.
+func (pv *ProcessValidator) Analyze(proc model.ProcessDefinition) (GraphAnalysis, error) {
+	if err := validateDependencies(proc.Tasks); err != nil {
+		return GraphAnalysis{}, err
+	}
+	if err := validateDependencies(proc.Finally); err != nil {
+		return GraphAnalysis{}, fmt.Errorf("finally: %w", err)
+	}
+
+	byName := make(map[string]model.Task, len(proc.Tasks))
+	for _, task := range proc.Tasks {
+		byName[task.Name] = task
+	}
+	order := topologicalOrder(proc.Tasks)
+
+	// depth is the length of the longest chain of tasks ending with the task.
+	depth := make(map[string]int, len(order))
+	previous := make(map[string]string, len(order))
+	widths := make(map[int]int)
+	var analysis GraphAnalysis
+	last := ""
+	for _, name := range order {
+		depth[name] = 1
+		for _, dep := range byName[name].WaitFor {
+			if depth[dep]+1 > depth[name] {
+				depth[name] = depth[dep] + 1
+				previous[name] = dep
+			}
+		}
+
+		widths[depth[name]]++
+		analysis.MaxWidth = max(analysis.MaxWidth, widths[depth[name]])
+		if last == "" || depth[name] > depth[last] {
+			last = name
+		}
+	}
+	for name := last; name != ""; name = previous[name] {
+		analysis.CriticalPath = append(analysis.CriticalPath, name)
+	}
+	slices.Reverse(analysis.CriticalPath)
+
+	analysis.Warnings = unreachableTasks(byName, order)
+	return analysis, nil
+}
+
+// unreachableTasks reports tasks without a condition that need a task to succeed
+// which only runs if one of the tasks they also need to succeed did not.
+func unreachableTasks(byName map[string]model.Task, order []string) []string {
+	// requires holds the tasks that must succeed for a task without a condition to run.
+	requires := make(map[string]map[string]bool, len(order))
+	// onFailureOf holds, for tasks whose condition does not hold if everything succeeded,
+	// the tasks of which one must not have succeeded.
+	onFailureOf := make(map[string][]string)
+	unreachable := make(map[string]bool)
+
+	var warnings []string
+	for _, name := range order {
+		task := byName[name]
+		if task.When != "" {
+			if tasks, ok := runsOnFailureOnly(task); ok {
+				onFailureOf[name] = tasks
+			}
+			continue
+		}
+
+		required := make(map[string]bool)
+		for _, dep := range task.WaitFor {
+			required[dep] = true
+			for r := range requires[dep] {
+				required[r] = true
+			}
+		}
+		requires[name] = required
+
+		if i := slices.IndexFunc(task.WaitFor, func(dep string) bool { return unreachable[dep] }); i >= 0 {
+			unreachable[name] = true
+			warnings = append(warnings, fmt.Sprintf("task '%s' can never run: it waits for task '%s', which can never run", name, task.WaitFor[i]))
+			continue
+		}
+
+		for _, r := range order {
+			failing, ok := onFailureOf[r]
+			if !required[r] || !ok || slices.ContainsFunc(failing, func(f string) bool { return !required[f] }) {
+				continue
+			}
+			unreachable[name] = true
+			warnings = append(warnings, fmt.Sprintf(
+				"task '%s' can never run: it needs task '%s' to succeed, which only runs if %s did not succeed, but it needs that as well",
+				name, r, quoteAll(failing),
+			))
+			break
+		}
+	}
+	return warnings
+}
+
+// runsOnFailureOnly reports whether the condition of the task does not hold if all
+// tasks succeeded, along with the tasks the condition observes.
+func runsOnFailureOnly(task model.Task) ([]string, bool) {
+	expr, err := condition.Parse(task.When)
+	if err != nil {
+		return nil, false
+	}
+	// Conditions that cannot be decided without outputs fail to evaluate and are assumed to hold.
+	ok, err := expr.Eval(allSucceeded{task: task})
+	if err != nil || ok {
+		return nil, false
+	}
+
+	observed := slices.Clone(task.WaitFor)
+	for _, ref := range expr.References() {
+		if !slices.Contains(observed, ref.Task) {
+			observed = append(observed, ref.Task)
+		}
+	}
+	return observed, len(observed) > 0
+}
+
+// allSucceeded is a condition environment in which every task succeeded.
+type allSucceeded struct {
+	task model.Task
+}
+
+func (e allSucceeded) Dependencies() []model.TaskStatus {
+	statuses := make([]model.TaskStatus, len(e.task.WaitFor))
+	for i := range statuses {
+		statuses[i] = model.TaskStatusSucceeded
+	}
+	return statuses
+}
+
+func (allSucceeded) Status(string) (model.TaskStatus, bool) {
+	return model.TaskStatusSucceeded, true
+}
+
+func (allSucceeded) Output(string, string) (string, bool) {
+	return "", false
+}
+
+// findCycle returns a dependency cycle between the tasks, e.g. [a b a] if a waits for b
+// and b waits for a, or nil if there is none. All dependencies must be known.
+func findCycle(tasks []model.Task) []string {
+	byName := make(map[string]model.Task, len(tasks))
+	for _, task := range tasks {
+		byName[task.Name] = task
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	state := make(map[string]int, len(tasks))
+	var path []string
+
+	var visit func(name string) []string
+	visit = func(name string) []string {
+		state[name] = visiting
+		path = append(path, name)
+		for _, dep := range byName[name].WaitFor {
+			switch state[dep] {
+			case visiting:
+				start := slices.Index(path, dep)
+				return append(slices.Clone(path[start:]), dep)
+			case unvisited:
+				if cycle := visit(dep); cycle != nil {
+					return cycle
+				}
+			}
+		}
+		path = path[:len(path)-1]
+		state[name] = done
+		return nil
+	}
+
+	for _, task := range tasks {
+		if state[task.Name] == unvisited {
+			if cycle := visit(task.Name); cycle != nil {
+				return cycle
+			}
+		}
+	}
+	return nil
+}
+
+// topologicalOrder orders acyclic tasks so that every task comes after the tasks it waits for.
+func topologicalOrder(tasks []model.Task) []string {
+	byName := make(map[string]model.Task, len(tasks))
+	for _, task := range tasks {
+		byName[task.Name] = task
+	}
+
+	order := make([]string, 0, len(tasks))
+	visited := make(map[string]bool, len(tasks))
+	var visit func(name string)
+	visit = func(name string) {
+		if visited[name] {
+			return
+		}
+		visited[name] = true
+		for _, dep := range byName[name].WaitFor {
+			visit(dep)
+		}
+		order = append(order, name)
+	}
+	for _, task := range tasks {
+		visit(task.Name)
+	}
+	return order
+}
+
+func quoteAll(names []string) string {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = "'" + name + "'"
+	}
+	if len(quoted) == 1 {
+		return quoted[0]
+	}
+	return "one of " + strings.Join(quoted, ", ")
+}
@@ -0,0 +1,107 @@
+package validator_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/model"
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/processproducer/validator"
+)
+
+var _ = Describe("Analyze", func() {
+	var (
+		validatorSvc *validator.ProcessValidator
+		proc         model.ProcessDefinition
+		analysis     validator.GraphAnalysis
+		err          error
+	)
+
+	BeforeEach(func() {
+		validatorSvc = validator.NewProcessValidator()
+		proc = model.ProcessDefinition{
+			Name: "deployment",
+			Tasks: []model.Task{
+				{Name: "build", Class: "localCmd"},
+				{Name: "test", Class: "localCmd", WaitFor: []string{"build"}},
+				{Name: "lint", Class: "localCmd", WaitFor: []string{"build"}},
+				{Name: "package", Class: "localCmd", WaitFor: []string{"test", "lint"}},
+				{Name: "docs", Class: "localCmd"},
+			},
+		}
+	})
+
+	JustBeforeEach(func() {
+		analysis, err = validatorSvc.Analyze(proc)
+	})
+
+	It("reports the critical path", func() {
+		Expect(err).NotTo(HaveOccurred())
+		Expect(analysis.CriticalPath).To(Equal([]string{"build", "test", "package"}))
+	})
+
+	It("reports the maximum width", func() {
+		Expect(analysis.MaxWidth).To(Equal(2))
+	})
+
+	It("has no warnings", func() {
+		Expect(analysis.Warnings).To(BeEmpty())
+	})
+
+	When("a task needs a task to succeed that only runs on failure", func() {
+		BeforeEach(func() {
+			proc.Tasks = []model.Task{
+				{Name: "deploy", Class: "sshCmd"},
+				{Name: "rollback", Class: "sshCmd", WaitFor: []string{"deploy"}, When: "failure()"},
+				{Name: "notify", Class: "sshCmd", WaitFor: []string{"deploy", "rollback"}},
+				{Name: "report", Class: "sshCmd", WaitFor: []string{"notify"}},
+				{Name: "audit", Class: "sshCmd", WaitFor: []string{"notify"}, When: "always()"},
+			}
+		})
+
+		It("warns about it and the tasks waiting for it", func() {
+			Expect(err).NotTo(HaveOccurred())
+			Expect(analysis.Warnings).To(Equal([]string{
+				"task 'notify' can never run: it needs task 'rollback' to succeed, which only runs if 'deploy' did not succeed, but it needs that as well",
+				"task 'report' can never run: it waits for task 'notify', which can never run",
+			}))
+		})
+	})
+
+	When("a task only needs the task that runs on failure", func() {
+		BeforeEach(func() {
+			proc.Tasks = []model.Task{
+				{Name: "deploy", Class: "sshCmd"},
+				{Name: "rollback", Class: "sshCmd", WaitFor: []string{"deploy"}, When: "status('deploy') == 'failed'"},
+				{Name: "notify", Class: "sshCmd", WaitFor: []string{"rollback"}},
+			}
+		})
+
+		It("has no warnings", func() {
+			Expect(err).NotTo(HaveOccurred())
+			Expect(analysis.Warnings).To(BeEmpty())
+		})
+	})
+
+	When("the tasks form a cycle", func() {
+		BeforeEach(func() {
+			proc.Tasks[0].WaitFor = []string{"package"}
+		})
+
+		It("returns an error with the cycle", func() {
+			Expect(err).To(MatchError("dependency cycle between tasks: build -> package -> test -> build"))
+		})
+	})
+
+	When("the finally tasks form a cycle", func() {
+		BeforeEach(func() {
+			proc.Finally = []model.Task{
+				{Name: "unlock", Class: "sshCmd", WaitFor: []string{"cleanup"}},
+				{Name: "cleanup", Class: "sshCmd", WaitFor: []string{"unlock"}},
+			}
+		})
+
+		It("returns an error with the cycle", func() {
+			Expect(err).To(MatchError("finally: dependency cycle between tasks: unlock -> cleanup -> unlock"))
+		})
+	})
+})
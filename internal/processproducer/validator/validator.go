@@ -3,9 +3,13 @@ package validator
 import (
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
 
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/condition"
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/cron"
 	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/model"
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/taskoutput"
 )
 
 type ProcessValidator struct{}
@@ -18,9 +22,43 @@ func (pv *ProcessValidator) Validate(proc model.ProcessDefinition) error {
 	if strings.TrimSpace(proc.Name) == "" {
 		return errors.New("process name must not be empty")
 	}
+	if proc.Timeout < 0 {
+		return errors.New("process timeout must not be negative")
+	}
+	if proc.MaxParallel < 0 {
+		return errors.New("process maxParallel must not be negative")
+	}
+	switch proc.OnWorkerLoss {
+	case "", model.WorkerLossFail, model.WorkerLossRetry:
+	default:
+		return fmt.Errorf("process onWorkerLoss must be '%s' or '%s', got '%s'", model.WorkerLossFail, model.WorkerLossRetry, proc.OnWorkerLoss)
+	}
+	if proc.Priority < 0 || proc.Priority > model.MaxPriority {
+		return fmt.Errorf("process priority must be between 0 and %d, got %d", model.MaxPriority, proc.Priority)
+	}
 
-	taskNames := make(map[string]struct{})
+	tasks := slices.Clone(proc.Tasks)
 	for _, task := range proc.Tasks {
+		if compensation, ok := task.Compensation(); ok {
+			if err := validateCompensation(task, compensation); err != nil {
+				return err
+			}
+			tasks = append(tasks, compensation)
+		}
+	}
+	for _, task := range proc.Finally {
+		if task.Compensate != nil {
+			return fmt.Errorf("finally task '%s' cannot declare a compensation", task.Name)
+		}
+		// Runs cannot be parked once they are cleaning up.
+		if task.Class == model.ApprovalCmd {
+			return fmt.Errorf("finally task '%s' cannot be an approval", task.Name)
+		}
+	}
+
+	// Task names are unique across the tasks, their compensations and the finally tasks.
+	taskNames := make(map[string]struct{})
+	for _, task := range slices.Concat(tasks, proc.Finally) {
 		if strings.TrimSpace(task.Name) == "" {
 			return errors.New("task name must not be empty")
 		}
@@ -37,6 +75,29 @@ func (pv *ProcessValidator) Validate(proc model.ProcessDefinition) error {
 				return fmt.Errorf("task '%s' cannot wait for itself", task.Name)
 			}
 		}
+
+		if task.Timeout < 0 {
+			return fmt.Errorf("task '%s' timeout must not be negative", task.Name)
+		}
+		if proc.Timeout > 0 && task.Timeout > proc.Timeout {
+			return fmt.Errorf("task '%s' timeout must not exceed the process timeout", task.Name)
+		}
+
+		if err := validateRetryPolicy(task); err != nil {
+			return err
+		}
+
+		if err := validateRollingPolicy(task); err != nil {
+			return err
+		}
+
+		if err := validateOutputs(task); err != nil {
+			return err
+		}
+
+		if err := validateSubprocess(task); err != nil {
+			return err
+		}
 	}
 
 	paramNames := make(map[string]struct{})
@@ -50,14 +111,253 @@ func (pv *ProcessValidator) Validate(proc model.ProcessDefinition) error {
 		paramNames[param.Name] = struct{}{}
 	}
 
-	for _, task := range proc.Tasks {
+	if err := validateDependencies(proc.Tasks); err != nil {
+		return err
+	}
+	if err := validateDependencies(proc.Finally); err != nil {
+		return fmt.Errorf("finally: %w", err)
+	}
+
+	if err := validateReferences(proc.Tasks, nil); err != nil {
+		return err
+	}
+	if err := validateReferences(proc.Finally, proc.Tasks); err != nil {
+		return fmt.Errorf("finally: %w", err)
+	}
+
+	return pv.validateSchedules(proc)
+}
+
+func (pv *ProcessValidator) validateSchedules(proc model.ProcessDefinition) error {
+	names := make(map[string]struct{}, len(proc.Schedules))
+	for _, schedule := range proc.Schedules {
+		if strings.TrimSpace(schedule.Name) == "" {
+			return errors.New("schedule name must not be empty")
+		}
+		if _, exists := names[schedule.Name]; exists {
+			return fmt.Errorf("duplicate schedule name found: %s", schedule.Name)
+		}
+		names[schedule.Name] = struct{}{}
+
+		if _, err := cron.Parse(schedule.Cron); err != nil {
+			return fmt.Errorf("schedule '%s' has an invalid cron expression: %w", schedule.Name, err)
+		}
+		if _, err := schedule.Location(); err != nil {
+			return fmt.Errorf("schedule '%s' has an unknown timezone '%s'", schedule.Name, schedule.Timezone)
+		}
+		if err := pv.ValidateMandatoryParams(proc, schedule.Parameters); err != nil {
+			return fmt.Errorf("schedule '%s': %w", schedule.Name, err)
+		}
+	}
+	return nil
+}
+
+// validateDependencies checks that tasks only wait for tasks of the same list and
+// that they do not form a cycle.
+func validateDependencies(tasks []model.Task) error {
+	names := make(map[string]struct{}, len(tasks))
+	for _, task := range tasks {
+		names[task.Name] = struct{}{}
+	}
+
+	for _, task := range tasks {
 		for _, dep := range task.WaitFor {
-			if _, ok := taskNames[dep]; !ok {
+			if _, ok := names[dep]; !ok {
 				return fmt.Errorf("task '%s' waits for unknown task '%s'", task.Name, dep)
 			}
 		}
 	}
 
+	if cycle := findCycle(tasks); cycle != nil {
+		return fmt.Errorf("dependency cycle between tasks: %s", strings.Join(cycle, " -> "))
+	}
+	return nil
+}
+
+// validateCompensation checks that the compensation of a task does not declare what
+// only regular tasks can, as compensations run one by one once the run failed.
+func validateCompensation(task, compensation model.Task) error {
+	switch {
+	case len(compensation.WaitFor) > 0:
+		return fmt.Errorf("compensation of task '%s' cannot wait for other tasks", task.Name)
+	case compensation.When != "":
+		return fmt.Errorf("compensation of task '%s' cannot have a condition", task.Name)
+	case compensation.Compensate != nil:
+		return fmt.Errorf("compensation of task '%s' cannot declare a compensation", task.Name)
+	case compensation.Class == model.ApprovalCmd:
+		return fmt.Errorf("compensation of task '%s' cannot be an approval", task.Name)
+	case compensation.ForEach != "":
+		return fmt.Errorf("compensation of task '%s' cannot declare forEach", task.Name)
+	}
+	return nil
+}
+
+// validateSubprocess checks that a processCmd task names the definition it runs. The name
+// is looked up when the run is started, so it cannot be templated.
+func validateSubprocess(task model.Task) error {
+	if task.Class != model.ProcessCmd {
+		return nil
+	}
+	name := task.Parameters[model.ProcessParam]
+	if strings.TrimSpace(name) == "" {
+		return fmt.Errorf("task '%s' must name the process to run in parameter '%s'", task.Name, model.ProcessParam)
+	}
+	if strings.Contains(name, "{{") {
+		return fmt.Errorf("task '%s' process must be the name of a process definition, not a template", task.Name)
+	}
+	return nil
+}
+
+func validateOutputs(task model.Task) error {
+	names := make(map[string]struct{})
+	for _, output := range task.Outputs {
+		if strings.TrimSpace(output.Name) == "" {
+			return fmt.Errorf("task '%s' output name must not be empty", task.Name)
+		}
+		if _, exists := names[output.Name]; exists {
+			return fmt.Errorf("task '%s' has duplicate output '%s'", task.Name, output.Name)
+		}
+		names[output.Name] = struct{}{}
+
+		if err := taskoutput.Validate(output); err != nil {
+			return fmt.Errorf("task '%s' output '%s': %w", task.Name, output.Name, err)
+		}
+	}
+	return nil
+}
+
+// validateReferences checks the task conditions and that every referenced task output
+// is declared by a task the referencing task (transitively) waits for, so it is known before it runs.
+// The finished tasks are done before any of the tasks start and can be referenced freely.
+func validateReferences(tasks []model.Task, finished []model.Task) error {
+	byName := make(map[string]model.Task, len(tasks))
+	for _, task := range tasks {
+		byName[task.Name] = task
+	}
+	finishedByName := make(map[string]model.Task, len(finished))
+	for _, task := range finished {
+		finishedByName[task.Name] = task
+	}
+
+	checkReference := func(task model.Task, where, refTask, refOutput string) error {
+		source, ok := byName[refTask]
+		if !ok {
+			source, ok = finishedByName[refTask]
+		}
+		if !ok {
+			return fmt.Errorf("task '%s' %s references unknown task '%s'", task.Name, where, refTask)
+		}
+		if refOutput != "" && !slices.ContainsFunc(source.Outputs, func(o model.OutputCapture) bool { return o.Name == refOutput }) {
+			return fmt.Errorf("task '%s' %s references undeclared output '%s' of task '%s'", task.Name, where, refOutput, refTask)
+		}
+		if _, done := finishedByName[refTask]; done {
+			return nil
+		}
+		if !dependsOn(byName, task, refTask, map[string]bool{}) {
+			return fmt.Errorf("task '%s' %s references task '%s' it does not wait for", task.Name, where, refTask)
+		}
+		return nil
+	}
+
+	checkTask := func(task model.Task) error {
+		for key, param := range task.Parameters {
+			for _, ref := range taskoutput.References(param) {
+				if err := checkReference(task, fmt.Sprintf("param '%s'", key), ref.Task, ref.Output); err != nil {
+					return err
+				}
+			}
+		}
+
+		if task.When == "" {
+			return nil
+		}
+		expr, err := condition.Parse(task.When)
+		if err != nil {
+			return fmt.Errorf("task '%s' has invalid condition '%s': %w", task.Name, task.When, err)
+		}
+		for _, ref := range expr.References() {
+			if err := checkReference(task, "condition", ref.Task, ref.Output); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	for _, task := range tasks {
+		if err := checkTask(task); err != nil {
+			return err
+		}
+
+		// A compensation only runs after its task succeeded, so it can use what the task used.
+		if compensation, ok := task.Compensation(); ok {
+			compensation.WaitFor = []string{task.Name}
+			if err := checkTask(compensation); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func dependsOn(tasks map[string]model.Task, task model.Task, target string, visited map[string]bool) bool {
+	for _, dep := range task.WaitFor {
+		if dep == target {
+			return true
+		}
+		if visited[dep] {
+			continue
+		}
+		visited[dep] = true
+		if dependsOn(tasks, tasks[dep], target, visited) {
+			return true
+		}
+	}
+	return false
+}
+
+func validateRetryPolicy(task model.Task) error {
+	policy := task.Retry
+	if policy == nil {
+		return nil
+	}
+
+	if policy.Attempts < 0 {
+		return fmt.Errorf("task '%s' retry attempts must not be negative", task.Name)
+	}
+	switch policy.Backoff {
+	case "", model.BackoffFixed, model.BackoffExponential:
+	default:
+		return fmt.Errorf("task '%s' has unsupported retry backoff '%s'", task.Name, policy.Backoff)
+	}
+	if policy.InitialDelay < 0 || policy.MaxDelay < 0 {
+		return fmt.Errorf("task '%s' retry delays must not be negative", task.Name)
+	}
+	if policy.MaxDelay > 0 && policy.MaxDelay < policy.InitialDelay {
+		return fmt.Errorf("task '%s' retry maxDelay must not be lower than initialDelay", task.Name)
+	}
+	return nil
+}
+
+// validateRollingPolicy checks the rolling policy of a task. It only applies to tasks with a
+// forEach, which are validated once they are expanded into their instances.
+func validateRollingPolicy(task model.Task) error {
+	policy := task.Rolling
+	if policy == nil {
+		return nil
+	}
+
+	if task.ForEach == "" && task.Group == "" {
+		return fmt.Errorf("task '%s' rolling requires forEach", task.Name)
+	}
+	if policy.BatchSize < 1 {
+		return fmt.Errorf("task '%s' rolling batchSize must be at least 1", task.Name)
+	}
+	if policy.MaxFailures < 0 {
+		return fmt.Errorf("task '%s' rolling maxFailures must not be negative", task.Name)
+	}
+	if policy.PauseBetweenBatches < 0 {
+		return fmt.Errorf("task '%s' rolling pauseBetweenBatches must not be negative", task.Name)
+	}
 	return nil
 }
 
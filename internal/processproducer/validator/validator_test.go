@@ -1,6 +1,8 @@
 package validator_test
 
 import (
+	"time"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
@@ -91,6 +93,337 @@ var _ = Describe("ProcessValidator", func() {
 			})
 		})
 
+		When("a task has a valid retry policy", func() {
+			BeforeEach(func() {
+				proc.Tasks[0].Retry = &model.RetryPolicy{
+					Attempts:     3,
+					Backoff:      model.BackoffExponential,
+					InitialDelay: model.Duration(time.Second),
+					MaxDelay:     model.Duration(10 * time.Second),
+				}
+			})
+
+			It("succeeds", func() {
+				Expect(err).NotTo(HaveOccurred())
+			})
+		})
+
+		When("a task has negative retry attempts", func() {
+			BeforeEach(func() {
+				proc.Tasks[0].Retry = &model.RetryPolicy{Attempts: -1}
+			})
+
+			It("returns an error", func() {
+				Expect(err).To(MatchError("task 'task1' retry attempts must not be negative"))
+			})
+		})
+
+		When("a task has an unsupported retry backoff", func() {
+			BeforeEach(func() {
+				proc.Tasks[0].Retry = &model.RetryPolicy{Attempts: 2, Backoff: "random"}
+			})
+
+			It("returns an error", func() {
+				Expect(err).To(MatchError("task 'task1' has unsupported retry backoff 'random'"))
+			})
+		})
+
+		When("a task retry maxDelay is lower than initialDelay", func() {
+			BeforeEach(func() {
+				proc.Tasks[0].Retry = &model.RetryPolicy{
+					Attempts:     2,
+					InitialDelay: model.Duration(time.Minute),
+					MaxDelay:     model.Duration(time.Second),
+				}
+			})
+
+			It("returns an error", func() {
+				Expect(err).To(MatchError("task 'task1' retry maxDelay must not be lower than initialDelay"))
+			})
+		})
+
+		When("an instance of a task with a forEach has a rolling policy", func() {
+			BeforeEach(func() {
+				proc.Tasks[0].Group = "task"
+				proc.Tasks[0].Rolling = &model.RollingPolicy{
+					BatchSize:           2,
+					MaxFailures:         1,
+					PauseBetweenBatches: model.Duration(30 * time.Second),
+				}
+			})
+
+			It("succeeds", func() {
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			When("the batch size is not set", func() {
+				BeforeEach(func() {
+					proc.Tasks[0].Rolling.BatchSize = 0
+				})
+
+				It("returns an error", func() {
+					Expect(err).To(MatchError("task 'task1' rolling batchSize must be at least 1"))
+				})
+			})
+
+			When("maxFailures is negative", func() {
+				BeforeEach(func() {
+					proc.Tasks[0].Rolling.MaxFailures = -1
+				})
+
+				It("returns an error", func() {
+					Expect(err).To(MatchError("task 'task1' rolling maxFailures must not be negative"))
+				})
+			})
+
+			When("the pause between batches is negative", func() {
+				BeforeEach(func() {
+					proc.Tasks[0].Rolling.PauseBetweenBatches = model.Duration(-time.Second)
+				})
+
+				It("returns an error", func() {
+					Expect(err).To(MatchError("task 'task1' rolling pauseBetweenBatches must not be negative"))
+				})
+			})
+		})
+
+		When("a task without a forEach has a rolling policy", func() {
+			BeforeEach(func() {
+				proc.Tasks[0].Rolling = &model.RollingPolicy{BatchSize: 1}
+			})
+
+			It("returns an error", func() {
+				Expect(err).To(MatchError("task 'task1' rolling requires forEach"))
+			})
+		})
+
+		When("a task references an output of a task it waits for", func() {
+			BeforeEach(func() {
+				proc.Tasks[0].Outputs = []model.OutputCapture{{Name: "buildId", LastLine: true}}
+				proc.Tasks[1].Parameters = map[string]string{"command": "deploy {{.tasks.task1.outputs.buildId}}"}
+			})
+
+			It("succeeds", func() {
+				Expect(err).NotTo(HaveOccurred())
+			})
+		})
+
+		When("a task output declares more than one source", func() {
+			BeforeEach(func() {
+				proc.Tasks[0].Outputs = []model.OutputCapture{{Name: "buildId", Regex: "id=(\\d+)", LastLine: true}}
+			})
+
+			It("returns an error", func() {
+				Expect(err).To(MatchError("task 'task1' output 'buildId': exactly one of regex, jsonPath and lastLine must be set"))
+			})
+		})
+
+		When("a task references an undeclared output", func() {
+			BeforeEach(func() {
+				proc.Tasks[1].Parameters = map[string]string{"command": "deploy {{.tasks.task1.outputs.buildId}}"}
+			})
+
+			It("returns an error", func() {
+				Expect(err).To(MatchError("task 'task2' param 'command' references undeclared output 'buildId' of task 'task1'"))
+			})
+		})
+
+		When("a task references an output of a task it does not wait for", func() {
+			BeforeEach(func() {
+				proc.Tasks[1].Outputs = []model.OutputCapture{{Name: "version", LastLine: true}}
+				proc.Tasks[0].Parameters = map[string]string{"command": "echo {{.tasks.task2.outputs.version}}"}
+			})
+
+			It("returns an error", func() {
+				Expect(err).To(MatchError("task 'task1' param 'command' references task 'task2' it does not wait for"))
+			})
+		})
+
+		When("a task has a valid condition", func() {
+			BeforeEach(func() {
+				proc.Tasks[1].When = "failure() && status(task1) == 'timed_out'"
+			})
+
+			It("succeeds", func() {
+				Expect(err).NotTo(HaveOccurred())
+			})
+		})
+
+		When("a task has a malformed condition", func() {
+			BeforeEach(func() {
+				proc.Tasks[1].When = "failure() &&"
+			})
+
+			It("returns an error", func() {
+				Expect(err).To(MatchError("task 'task2' has invalid condition 'failure() &&': unexpected end of condition"))
+			})
+		})
+
+		When("a task condition references a task it does not wait for", func() {
+			BeforeEach(func() {
+				proc.Tasks[0].When = "status('task2') == 'failed'"
+			})
+
+			It("returns an error", func() {
+				Expect(err).To(MatchError("task 'task1' condition references task 'task2' it does not wait for"))
+			})
+		})
+
+		When("maxParallel is negative", func() {
+			BeforeEach(func() {
+				proc.MaxParallel = -1
+			})
+
+			It("returns an error", func() {
+				Expect(err).To(MatchError("process maxParallel must not be negative"))
+			})
+		})
+
+		When("the process has schedules", func() {
+			BeforeEach(func() {
+				proc.Params = []model.Param{{Name: "env", Mandatory: true}}
+				proc.Schedules = []model.Schedule{
+					{Name: "nightly", Cron: "0 2 * * *", Timezone: "Europe/Berlin", Parameters: map[string]string{"env": "prod"}},
+				}
+			})
+
+			It("succeeds", func() {
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			When("a cron expression is invalid", func() {
+				BeforeEach(func() {
+					proc.Schedules[0].Cron = "0 25 * * *"
+				})
+
+				It("returns an error", func() {
+					Expect(err).To(MatchError("schedule 'nightly' has an invalid cron expression: hour 25 out of range 0-23"))
+				})
+			})
+
+			When("a timezone is unknown", func() {
+				BeforeEach(func() {
+					proc.Schedules[0].Timezone = "Mars/Olympus"
+				})
+
+				It("returns an error", func() {
+					Expect(err).To(MatchError("schedule 'nightly' has an unknown timezone 'Mars/Olympus'"))
+				})
+			})
+
+			When("a schedule misses mandatory parameters", func() {
+				BeforeEach(func() {
+					proc.Schedules[0].Parameters = nil
+				})
+
+				It("returns an error", func() {
+					Expect(err).To(MatchError("schedule 'nightly': missing mandatory parameters: env"))
+				})
+			})
+
+			When("schedule names are duplicated", func() {
+				BeforeEach(func() {
+					proc.Schedules = append(proc.Schedules, proc.Schedules[0])
+				})
+
+				It("returns an error", func() {
+					Expect(err).To(MatchError("duplicate schedule name found: nightly"))
+				})
+			})
+		})
+
+		When("onWorkerLoss is unknown", func() {
+			BeforeEach(func() {
+				proc.OnWorkerLoss = "ignore"
+			})
+
+			It("returns an error", func() {
+				Expect(err).To(MatchError("process onWorkerLoss must be 'fail' or 'retry', got 'ignore'"))
+			})
+		})
+
+		When("onWorkerLoss is retry", func() {
+			BeforeEach(func() {
+				proc.OnWorkerLoss = model.WorkerLossRetry
+			})
+
+			It("succeeds", func() {
+				Expect(err).NotTo(HaveOccurred())
+			})
+		})
+
+		When("the priority is out of range", func() {
+			BeforeEach(func() {
+				proc.Priority = 10
+			})
+
+			It("returns an error", func() {
+				Expect(err).To(MatchError("process priority must be between 0 and 9, got 10"))
+			})
+		})
+
+		When("the process timeout is negative", func() {
+			BeforeEach(func() {
+				proc.Timeout = model.Duration(-time.Second)
+			})
+
+			It("returns an error", func() {
+				Expect(err).To(MatchError("process timeout must not be negative"))
+			})
+		})
+
+		When("a task timeout is negative", func() {
+			BeforeEach(func() {
+				proc.Tasks[0].Timeout = model.Duration(-time.Second)
+			})
+
+			It("returns an error", func() {
+				Expect(err).To(MatchError("task 'task1' timeout must not be negative"))
+			})
+		})
+
+		When("a task timeout exceeds the process timeout", func() {
+			BeforeEach(func() {
+				proc.Timeout = model.Duration(time.Minute)
+				proc.Tasks[0].Timeout = model.Duration(time.Hour)
+			})
+
+			It("returns an error", func() {
+				Expect(err).To(MatchError("task 'task1' timeout must not exceed the process timeout"))
+			})
+		})
+
+		When("a task runs a sub-process", func() {
+			BeforeEach(func() {
+				proc.Tasks[1].Class = model.ProcessCmd
+				proc.Tasks[1].Parameters = map[string]string{model.ProcessParam: "provision-vm", "size": "large"}
+			})
+
+			It("succeeds", func() {
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			When("it does not name the process", func() {
+				BeforeEach(func() {
+					delete(proc.Tasks[1].Parameters, model.ProcessParam)
+				})
+
+				It("returns an error", func() {
+					Expect(err).To(MatchError("task 'task2' must name the process to run in parameter 'process'"))
+				})
+			})
+
+			When("the process name is a template", func() {
+				BeforeEach(func() {
+					proc.Tasks[1].Parameters[model.ProcessParam] = "provision-{{.env}}"
+				})
+
+				It("returns an error", func() {
+					Expect(err).To(MatchError("task 'task2' process must be the name of a process definition, not a template"))
+				})
+			})
+		})
+
 		When("a param name is empty", func() {
 			BeforeEach(func() {
 				proc.Params[0].Name = " "
@@ -120,6 +453,161 @@ var _ = Describe("ProcessValidator", func() {
 				Expect(err).To(MatchError("task 'task2' waits for unknown task 'nonexistent'"))
 			})
 		})
+
+		When("tasks wait for each other", func() {
+			BeforeEach(func() {
+				proc.Tasks[0].WaitFor = []string{"task2"}
+			})
+
+			It("returns an error with the cycle", func() {
+				Expect(err).To(MatchError("dependency cycle between tasks: task1 -> task2 -> task1"))
+			})
+		})
+
+		When("a task declares a compensation", func() {
+			BeforeEach(func() {
+				proc.Tasks[1].Outputs = []model.OutputCapture{{Name: "release", LastLine: true}}
+				proc.Tasks[1].Compensate = &model.Task{
+					Class:      "other.class",
+					Parameters: map[string]string{"command": "rollback {{.tasks.task2.outputs.release}}"},
+				}
+			})
+
+			It("succeeds", func() {
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			When("the compensation waits for a task", func() {
+				BeforeEach(func() {
+					proc.Tasks[1].Compensate.WaitFor = []string{"task1"}
+				})
+
+				It("returns an error", func() {
+					Expect(err).To(MatchError("compensation of task 'task2' cannot wait for other tasks"))
+				})
+			})
+
+			When("the compensation is an approval", func() {
+				BeforeEach(func() {
+					proc.Tasks[1].Compensate.Class = model.ApprovalCmd
+				})
+
+				It("returns an error", func() {
+					Expect(err).To(MatchError("compensation of task 'task2' cannot be an approval"))
+				})
+			})
+
+			When("the compensation declares forEach", func() {
+				BeforeEach(func() {
+					proc.Tasks[1].Compensate.ForEach = "{{.hosts}}"
+				})
+
+				It("returns an error", func() {
+					Expect(err).To(MatchError("compensation of task 'task2' cannot declare forEach"))
+				})
+			})
+
+			When("the compensation has no class", func() {
+				BeforeEach(func() {
+					proc.Tasks[1].Compensate.Class = ""
+				})
+
+				It("returns an error", func() {
+					Expect(err).To(MatchError("task 'compensate-task2' class must not be empty"))
+				})
+			})
+
+			When("the compensation has the name of a task", func() {
+				BeforeEach(func() {
+					proc.Tasks[1].Compensate.Name = "task1"
+				})
+
+				It("returns an error", func() {
+					Expect(err).To(MatchError("duplicate task name found: task1"))
+				})
+			})
+
+			When("the compensation references a task that may not have run", func() {
+				BeforeEach(func() {
+					proc.Tasks[0].Outputs = []model.OutputCapture{{Name: "dir", LastLine: true}}
+					proc.Tasks[0].Compensate = &model.Task{
+						Class:      "some.class",
+						Parameters: map[string]string{"command": "rm -rf {{.tasks.task2.outputs.release}}"},
+					}
+				})
+
+				It("returns an error", func() {
+					Expect(err).To(MatchError("task 'compensate-task1' param 'command' references task 'task2' it does not wait for"))
+				})
+			})
+		})
+
+		When("the process has finally tasks", func() {
+			BeforeEach(func() {
+				proc.Tasks[0].Outputs = []model.OutputCapture{{Name: "dir", LastLine: true}}
+				proc.Finally = []model.Task{
+					{Name: "unlock", Class: "some.class", When: "status('task2') != 'succeeded'"},
+					{Name: "cleanup", Class: "some.class", WaitFor: []string{"unlock"}, Parameters: map[string]string{
+						"command": "rm -rf {{.tasks.task1.outputs.dir}}",
+					}},
+				}
+			})
+
+			It("succeeds", func() {
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			When("a finally task has the name of a task", func() {
+				BeforeEach(func() {
+					proc.Finally[0].Name = "task1"
+					proc.Finally[1].WaitFor = []string{"task1"}
+				})
+
+				It("returns an error", func() {
+					Expect(err).To(MatchError("duplicate task name found: task1"))
+				})
+			})
+
+			When("a finally task waits for a task", func() {
+				BeforeEach(func() {
+					proc.Finally[0].WaitFor = []string{"task2"}
+				})
+
+				It("returns an error", func() {
+					Expect(err).To(MatchError("finally: task 'unlock' waits for unknown task 'task2'"))
+				})
+			})
+
+			When("a finally task declares a compensation", func() {
+				BeforeEach(func() {
+					proc.Finally[0].Compensate = &model.Task{Class: "some.class"}
+				})
+
+				It("returns an error", func() {
+					Expect(err).To(MatchError("finally task 'unlock' cannot declare a compensation"))
+				})
+			})
+
+			When("a finally task is an approval", func() {
+				BeforeEach(func() {
+					proc.Finally[0].Class = model.ApprovalCmd
+				})
+
+				It("returns an error", func() {
+					Expect(err).To(MatchError("finally task 'unlock' cannot be an approval"))
+				})
+			})
+
+			When("a task references a finally task", func() {
+				BeforeEach(func() {
+					proc.Tasks[1].When = "status('unlock') == 'succeeded'"
+				})
+
+				It("returns an error", func() {
+					Expect(err).To(MatchError("task 'task2' condition references unknown task 'unlock'"))
+				})
+			})
+		})
 	})
 
 	Describe("ValidateMandatoryParams", func() {
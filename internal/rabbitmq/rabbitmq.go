@@ -86,10 +86,7 @@ func (c *Client) connect() error {
 
 	dlq := fmt.Sprintf("%s.dlq", c.queue)
 
-	_, err = ch.QueueDeclare(c.queue, true, false, false, false, amqp.Table{
-		"x-dead-letter-exchange":    "",
-		"x-dead-letter-routing-key": dlq,
-	})
+	ch, err = c.declareQueue(conn, ch, dlq)
 	if err != nil {
 		closeErr := conn.Close()
 		if closeErr != nil {
@@ -113,6 +110,37 @@ func (c *Client) connect() error {
 	return nil
 }
 
+// declareQueue declares the queue with priorities. A queue that was declared without
+// them by an earlier version cannot be redeclared, so it is used as it is until it is
+// migrated, and messages are consumed in FIFO order. It returns the channel to use, as
+// the failed declaration closes the given one.
+func (c *Client) declareQueue(conn *amqp.Connection, ch *amqp.Channel, dlq string) (*amqp.Channel, error) {
+	args := amqp.Table{
+		"x-dead-letter-exchange":    "",
+		"x-dead-letter-routing-key": dlq,
+	}
+
+	priorityArgs := amqp.Table{"x-max-priority": int32(model.MaxPriority)}
+	for k, v := range args {
+		priorityArgs[k] = v
+	}
+	_, err := ch.QueueDeclare(c.queue, true, false, false, false, priorityArgs)
+	var amqpErr *amqp.Error
+	if !errors.As(err, &amqpErr) || amqpErr.Code != amqp.PreconditionFailed {
+		return ch, err
+	}
+
+	ch, err = conn.Channel()
+	if err != nil {
+		return nil, fmt.Errorf("failed to reopen channel: %w", err)
+	}
+	if _, err := ch.QueueDeclare(c.queue, true, false, false, false, args); err != nil {
+		return nil, err
+	}
+	logger.GetLogger().Warnf("Queue %s was declared without priorities, runs are queued in FIFO order until it is migrated", c.queue)
+	return ch, nil
+}
+
 func (c *Client) setupTLSConfig() (*tls.Config, error) {
 	caCert, err := os.ReadFile(c.tlsConfig.CAFile)
 	if err != nil {
@@ -160,6 +188,7 @@ func (c *Client) Publish(ctx context.Context, message model.Message) error {
 		false,   // immediate
 		amqp.Publishing{
 			ContentType: "application/json",
+			Priority:    uint8(message.Priority),
 			Body:        msg,
 		},
 	)
@@ -170,8 +199,19 @@ func (c *Client) Publish(ctx context.Context, message model.Message) error {
 	return nil
 }
 
-func (c *Client) Consume(ctx context.Context, cb func(ctx context.Context, n model.Message) error) error {
+// Consume delivers messages to cb using the given number of workers. The channel
+// prefetch is set to the same number, so every worker holds at most one unacked message.
+func (c *Client) Consume(ctx context.Context, workers int, cb func(ctx context.Context, n model.Message) error) error {
+	if workers < 1 {
+		workers = 1
+	}
+
 	c.mutex.Lock()
+	if err := c.channel.Qos(workers, 0, false); err != nil {
+		c.mutex.Unlock()
+		return fmt.Errorf("failed to set QoS: %w", err)
+	}
+
 	msgs, err := c.channel.Consume(
 		c.queue,
 		"",
@@ -188,51 +228,75 @@ func (c *Client) Consume(ctx context.Context, cb func(ctx context.Context, n mod
 		return fmt.Errorf("failed to start consumer: %w", err)
 	}
 
+	errs := make(chan error, workers)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- c.work(ctx, msgs, cb)
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	logger.GetLogger().Info("stopping RabbitMQ consumer")
+	return nil
+}
+
+func (c *Client) work(ctx context.Context, msgs <-chan amqp.Delivery, cb func(ctx context.Context, n model.Message) error) error {
 	for {
 		select {
 		case <-ctx.Done():
-			logger.GetLogger().Info("stopping RabbitMQ consumer")
 			return nil
 		case msg, ok := <-msgs:
 			if !ok {
 				return fmt.Errorf("channel closed")
 			}
+			c.handleDelivery(ctx, msg, cb)
+		}
+	}
+}
 
-			var m model.Message
-			if err := json.Unmarshal(msg.Body, &m); err != nil {
-				logger.GetLogger().Errorf("invalid message: %v", err)
-				// Malformed messages are discarded to DLQ.
+func (c *Client) handleDelivery(ctx context.Context, msg amqp.Delivery, cb func(ctx context.Context, n model.Message) error) {
+	var m model.Message
+	if err := json.Unmarshal(msg.Body, &m); err != nil {
+		logger.GetLogger().Errorf("invalid message: %v", err)
+		// Malformed messages are discarded to DLQ.
+		if err := msg.Nack(false, false); err != nil {
+			logger.GetLogger().Errorf("failed to ack message: %v", err)
+		}
+		return
+	}
+
+	retries := 0
+	for retries < maxMessageRetries {
+		if err := cb(ctx, m); err != nil {
+			retries++
+			logger.GetLogger().Errorf("cb failed (attempt %d/%d): %v", retries, maxMessageRetries, err)
+			if retries == maxMessageRetries {
+				logger.GetLogger().Errorf("max retries reached, discarding message")
+				// Discarded to DLQ.
 				if err := msg.Nack(false, false); err != nil {
-					logger.GetLogger().Errorf("failed to ack message: %v", err)
+					logger.GetLogger().Errorf("failed to nack message: %v", err)
 				}
-				continue
+				break
 			}
 
-			retries := 0
-			for retries < maxMessageRetries {
-				if err := cb(ctx, m); err != nil {
-					retries++
-					logger.GetLogger().Errorf("cb failed (attempt %d/%d): %v", retries, maxMessageRetries, err)
-					if retries == maxMessageRetries {
-						logger.GetLogger().Errorf("max retries reached, discarding message")
-						// Discarded to DLQ.
-						if err := msg.Nack(false, false); err != nil {
-							logger.GetLogger().Errorf("failed to nack message: %v", err)
-						}
-						break
-					}
-
-					delay := time.Duration(retries) * 2 * time.Second
-					time.Sleep(delay)
-					continue
-				}
+			delay := time.Duration(retries) * 2 * time.Second
+			time.Sleep(delay)
+			continue
+		}
 
-				if err := msg.Ack(false); err != nil {
-					logger.GetLogger().Errorf("failed to ack message: %v", err)
-				}
-				break
-			}
+		if err := msg.Ack(false); err != nil {
+			logger.GetLogger().Errorf("failed to ack message: %v", err)
 		}
+		break
 	}
 }
 
@@ -0,0 +1,250 @@
+// Package taskoutput captures named values from the stdout of a task and makes them
+// available to downstream tasks as {{.tasks.<name>.outputs.<key>}}.
+package taskoutput
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/model"
+)
+
+// deferredOpen and deferredClose enclose the actions Defer leaves for Render. They are
+// private use characters, so text from request inputs is not mistaken for an action.
+const (
+	deferredOpen  = "\uE000"
+	deferredClose = "\uE001"
+)
+
+var (
+	// actionPattern matches template actions that reference task outputs.
+	actionPattern = regexp.MustCompile(`\{\{[^}]*\.tasks\.[^}]*\}\}`)
+	// referencePattern matches a single task output reference inside an action.
+	referencePattern = regexp.MustCompile(`\.tasks\.(\w+)\.outputs\.(\w+)`)
+	// deferredPattern matches an action left for Render.
+	deferredPattern = regexp.MustCompile(deferredOpen + `([^` + deferredClose + `]*)` + deferredClose)
+)
+
+// Reference points to an output of another task.
+type Reference struct {
+	Task   string
+	Output string
+}
+
+// References returns every task output referenced in the given template.
+func References(tmpl string) []Reference {
+	var refs []Reference
+	for _, match := range referencePattern.FindAllStringSubmatch(tmpl, -1) {
+		refs = append(refs, Reference{Task: match[1], Output: match[2]})
+	}
+	return refs
+}
+
+// Defer turns every action referencing task outputs into a literal, so the template
+// survives rendering with request parameters and its actions can be rendered by Render
+// once the outputs are known.
+func Defer(tmpl string) string {
+	return actionPattern.ReplaceAllStringFunc(tmpl, func(action string) string {
+		return "{{" + strconv.Quote(Deferred(action)) + "}}"
+	})
+}
+
+// Deferred returns what a template deferred by Defer renders the action to.
+func Deferred(action string) string {
+	return deferredOpen + action + deferredClose
+}
+
+// HasDeferred reports whether s holds actions left for Render.
+func HasDeferred(s string) bool {
+	return deferredPattern.MatchString(s)
+}
+
+// Render renders the actions Defer left in s. outputs maps task names to their outputs.
+// The rest of s is kept as is, even if it looks like a template.
+func Render(s string, outputs map[string]map[string]string) (string, error) {
+	if !HasDeferred(s) {
+		return s, nil
+	}
+
+	tasks := make(map[string]any, len(outputs))
+	for name, values := range outputs {
+		tasks[name] = map[string]any{"outputs": values}
+	}
+	data := map[string]any{"tasks": tasks}
+
+	var renderErr error
+	rendered := deferredPattern.ReplaceAllStringFunc(s, func(match string) string {
+		if renderErr != nil {
+			return ""
+		}
+		action := deferredPattern.FindStringSubmatch(match)[1]
+		t, err := template.New("task").Option("missingkey=error").Parse(action)
+		if err != nil {
+			renderErr = fmt.Errorf("failed to parse template: %w", err)
+			return ""
+		}
+		var buf bytes.Buffer
+		if err := t.Execute(&buf, data); err != nil {
+			renderErr = fmt.Errorf("failed to render template: %w", err)
+			return ""
+		}
+		return buf.String()
+	})
+	if renderErr != nil {
+		return "", renderErr
+	}
+	return rendered, nil
+}
+
+// Validate checks that the capture declares exactly one source and that it is well-formed.
+func Validate(capture model.OutputCapture) error {
+	sources := 0
+	if capture.Regex != "" {
+		sources++
+		if _, err := regexp.Compile(capture.Regex); err != nil {
+			return fmt.Errorf("invalid regex: %w", err)
+		}
+	}
+	if capture.JSONPath != "" {
+		sources++
+		if _, err := parseJSONPath(capture.JSONPath); err != nil {
+			return err
+		}
+	}
+	if capture.LastLine {
+		sources++
+	}
+
+	if sources != 1 {
+		return errors.New("exactly one of regex, jsonPath and lastLine must be set")
+	}
+	return nil
+}
+
+// CaptureAll extracts all declared outputs from stdout.
+func CaptureAll(captures []model.OutputCapture, stdout string) (map[string]string, error) {
+	if len(captures) == 0 {
+		return nil, nil
+	}
+
+	outputs := make(map[string]string, len(captures))
+	for _, capture := range captures {
+		value, err := Capture(capture, stdout)
+		if err != nil {
+			return nil, fmt.Errorf("output '%s': %w", capture.Name, err)
+		}
+		outputs[capture.Name] = value
+	}
+	return outputs, nil
+}
+
+// Capture extracts a single output from stdout.
+func Capture(capture model.OutputCapture, stdout string) (string, error) {
+	switch {
+	case capture.Regex != "":
+		re, err := regexp.Compile(capture.Regex)
+		if err != nil {
+			return "", fmt.Errorf("invalid regex: %w", err)
+		}
+		match := re.FindStringSubmatch(stdout)
+		if match == nil {
+			return "", fmt.Errorf("regex %q did not match", capture.Regex)
+		}
+		if len(match) > 1 {
+			return match[1], nil
+		}
+		return match[0], nil
+	case capture.JSONPath != "":
+		return captureJSONPath(capture.JSONPath, stdout)
+	case capture.LastLine:
+		lines := strings.Split(strings.TrimRight(stdout, "\r\n"), "\n")
+		return strings.TrimSuffix(lines[len(lines)-1], "\r"), nil
+	default:
+		return "", errors.New("no output source declared")
+	}
+}
+
+func captureJSONPath(path, stdout string) (string, error) {
+	segments, err := parseJSONPath(path)
+	if err != nil {
+		return "", err
+	}
+
+	var value any
+	if err := json.Unmarshal([]byte(stdout), &value); err != nil {
+		return "", fmt.Errorf("stdout is not valid JSON: %w", err)
+	}
+
+	for _, segment := range segments {
+		switch node := value.(type) {
+		case map[string]any:
+			child, ok := node[segment]
+			if !ok {
+				return "", fmt.Errorf("path %q not found", path)
+			}
+			value = child
+		case []any:
+			idx, err := strconv.Atoi(segment)
+			if err != nil || idx < 0 || idx >= len(node) {
+				return "", fmt.Errorf("path %q not found", path)
+			}
+			value = node[idx]
+		default:
+			return "", fmt.Errorf("path %q not found", path)
+		}
+	}
+
+	if s, ok := value.(string); ok {
+		return s, nil
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode value at %q: %w", path, err)
+	}
+	return string(encoded), nil
+}
+
+// parseJSONPath supports the dotted subset of JSONPath, e.g. "$.build.id" or "$.items[0].name".
+func parseJSONPath(path string) ([]string, error) {
+	if !strings.HasPrefix(path, "$") {
+		return nil, fmt.Errorf("jsonPath %q must start with '$'", path)
+	}
+
+	var segments []string
+	rest := path[1:]
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			if end == 0 {
+				return nil, fmt.Errorf("jsonPath %q has an empty segment", path)
+			}
+			segments = append(segments, rest[:end])
+			rest = rest[end:]
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("jsonPath %q has an unterminated index", path)
+			}
+			segment := strings.Trim(rest[1:end], `'"`)
+			if segment == "" {
+				return nil, fmt.Errorf("jsonPath %q has an empty index", path)
+			}
+			segments = append(segments, segment)
+			rest = rest[end+1:]
+		default:
+			return nil, fmt.Errorf("jsonPath %q is not supported", path)
+		}
+	}
+	return segments, nil
+}
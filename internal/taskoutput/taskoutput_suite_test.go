@@ -0,0 +1,13 @@
+package taskoutput_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestTaskOutput(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "TaskOutput Suite")
+}
@@ -0,0 +1,129 @@
+package taskoutput_test
+
+import (
+	"bytes"
+	"strconv"
+	"text/template"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/model"
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/taskoutput"
+)
+
+var _ = Describe("TaskOutput", func() {
+	Describe("Capture", func() {
+		const stdout = "starting\nBUILD_ID=b42\ndone\n"
+
+		It("captures the first regex group", func() {
+			value, err := taskoutput.Capture(model.OutputCapture{Regex: `BUILD_ID=(\w+)`}, stdout)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(value).To(Equal("b42"))
+		})
+
+		It("captures the whole regex match without groups", func() {
+			value, err := taskoutput.Capture(model.OutputCapture{Regex: `b\d+`}, stdout)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(value).To(Equal("b42"))
+		})
+
+		It("returns an error when the regex does not match", func() {
+			_, err := taskoutput.Capture(model.OutputCapture{Regex: `VERSION=(\w+)`}, stdout)
+			Expect(err).To(MatchError(ContainSubstring("did not match")))
+		})
+
+		It("captures the last line", func() {
+			value, err := taskoutput.Capture(model.OutputCapture{LastLine: true}, stdout)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(value).To(Equal("done"))
+		})
+
+		It("captures a JSONPath value", func() {
+			json := `{"build": {"id": "b42", "artifacts": [{"size": 12}]}}`
+
+			value, err := taskoutput.Capture(model.OutputCapture{JSONPath: "$.build.id"}, json)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(value).To(Equal("b42"))
+
+			value, err = taskoutput.Capture(model.OutputCapture{JSONPath: "$.build.artifacts[0].size"}, json)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(value).To(Equal("12"))
+		})
+
+		It("returns an error when the JSONPath does not exist", func() {
+			_, err := taskoutput.Capture(model.OutputCapture{JSONPath: "$.missing"}, `{"build": {}}`)
+			Expect(err).To(MatchError(`path "$.missing" not found`))
+		})
+	})
+
+	Describe("Validate", func() {
+		It("accepts a single source", func() {
+			Expect(taskoutput.Validate(model.OutputCapture{Name: "id", JSONPath: "$.id"})).To(Succeed())
+		})
+
+		It("rejects a capture without a source", func() {
+			Expect(taskoutput.Validate(model.OutputCapture{Name: "id"})).To(MatchError(ContainSubstring("exactly one")))
+		})
+
+		It("rejects an invalid regex", func() {
+			Expect(taskoutput.Validate(model.OutputCapture{Name: "id", Regex: "("})).To(MatchError(ContainSubstring("invalid regex")))
+		})
+
+		It("rejects an invalid JSONPath", func() {
+			Expect(taskoutput.Validate(model.OutputCapture{Name: "id", JSONPath: "build.id"})).To(MatchError(ContainSubstring("must start with '$'")))
+		})
+	})
+
+	Describe("Defer and Render", func() {
+		// requestTemplating renders tmpl with request inputs the way the process loader does.
+		requestTemplating := func(tmpl string, inputs map[string]string) string {
+			t, err := template.New("param").Parse(taskoutput.Defer(tmpl))
+			Expect(err).NotTo(HaveOccurred())
+			var buf bytes.Buffer
+			Expect(t.Execute(&buf, inputs)).To(Succeed())
+			return buf.String()
+		}
+
+		It("keeps output references through request templating", func() {
+			Expect(taskoutput.Defer("cd {{ .tasks.mk.outputs.dir }} && {{.cmd}}")).
+				To(Equal(`cd {{` + strconv.Quote(taskoutput.Deferred("{{ .tasks.mk.outputs.dir }}")) + `}} && {{.cmd}}`))
+		})
+
+		It("renders output references", func() {
+			rendered, err := taskoutput.Render(requestTemplating("cd {{.tasks.mk.outputs.dir}}", nil), map[string]map[string]string{
+				"mk": {"dir": "/tmp/x"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(rendered).To(Equal("cd /tmp/x"))
+		})
+
+		It("keeps braces that came from request inputs", func() {
+			deferred := requestTemplating("echo {{.msg}} {{.tasks.mk.outputs.dir}}", map[string]string{"msg": "{{.Secret}}"})
+
+			rendered, err := taskoutput.Render(deferred, map[string]map[string]string{
+				"mk": {"dir": "/tmp/x"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(rendered).To(Equal("echo {{.Secret}} /tmp/x"))
+		})
+
+		It("keeps escaped braces of the template", func() {
+			rendered, err := taskoutput.Render(requestTemplating(`echo '{{"{{"}} x }}'`, nil), nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(rendered).To(Equal("echo '{{ x }}'"))
+		})
+
+		It("returns an error for unknown outputs", func() {
+			_, err := taskoutput.Render(requestTemplating("cd {{.tasks.mk.outputs.dir}}", nil), nil)
+			Expect(err).To(MatchError(ContainSubstring("failed to render template")))
+		})
+
+		It("lists the references", func() {
+			Expect(taskoutput.References("{{.tasks.a.outputs.x}}-{{.tasks.b.outputs.y}}")).To(Equal([]taskoutput.Reference{
+				{Task: "a", Output: "x"},
+				{Task: "b", Output: "y"},
+			}))
+		})
+	})
+})
@@ -0,0 +1,208 @@
+// Package templating renders the tasks of process definitions with the inputs of a run.
+// The producer renders the definitions of requested runs with it and the consumer the
+// definitions of processCmd tasks.
+package templating
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/model"
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/taskoutput"
+)
+
+// Apply renders the task parameters, conditions and compensations with the request inputs
+// and expands the tasks with a forEach into their instances. References to outputs of
+// other tasks are deferred with taskoutput.Defer and rendered by the consumer before execution.
+func Apply(tasks []model.Task, inputs map[string]string) ([]model.Task, error) {
+	var rendered []model.Task
+	// groups maps the tasks with a forEach to the names of their instances.
+	groups := make(map[string][]string)
+	for _, t := range tasks {
+		if t.ForEach == "" {
+			task, err := renderTask(t, inputs)
+			if err != nil {
+				return nil, err
+			}
+			rendered = append(rendered, task)
+			continue
+		}
+
+		items, err := forEachItems(t, inputs)
+		if err != nil {
+			return nil, err
+		}
+		instances := make([]string, 0, len(items))
+		for i, item := range items {
+			task, err := renderTask(instance(t, i, item), itemInputs(inputs, item, i))
+			if err != nil {
+				return nil, err
+			}
+			rendered = append(rendered, task)
+			instances = append(instances, task.Name)
+		}
+		groups[t.Name] = instances
+	}
+
+	if len(groups) > 0 {
+		for i := range rendered {
+			rendered[i].WaitFor = expandGroups(rendered[i].WaitFor, groups)
+		}
+	}
+	return rendered, nil
+}
+
+func renderTask(t model.Task, inputs map[string]string) (model.Task, error) {
+	params := make(map[string]string)
+	for key, tmplStr := range t.Parameters {
+		value, err := renderTemplate(key, tmplStr, inputs)
+		if err != nil {
+			return model.Task{}, fmt.Errorf("task %s param %s: %w", t.Name, key, err)
+		}
+		params[key] = value
+	}
+	t.Parameters = params
+
+	if t.When != "" {
+		when, err := renderTemplate("when", t.When, inputs)
+		if err != nil {
+			return model.Task{}, fmt.Errorf("task %s condition: %w", t.Name, err)
+		}
+		t.When = when
+	}
+
+	if t.Compensate != nil {
+		compensation, err := renderTask(*t.Compensate, inputs)
+		if err != nil {
+			return model.Task{}, fmt.Errorf("task %s compensation: %w", t.Name, err)
+		}
+		t.Compensate = &compensation
+	}
+	return t, nil
+}
+
+// forEachItems renders the forEach of the task and splits it into its items.
+func forEachItems(t model.Task, inputs map[string]string) ([]string, error) {
+	list, err := renderTemplate("forEach", t.ForEach, inputs)
+	if err != nil {
+		return nil, fmt.Errorf("task %s forEach: %w", t.Name, err)
+	}
+	// Task outputs are only known by the consumer, long after the tasks were expanded.
+	if taskoutput.HasDeferred(list) {
+		return nil, fmt.Errorf("task %s forEach cannot reference task outputs", t.Name)
+	}
+
+	items, err := parseList(list)
+	if err != nil {
+		return nil, fmt.Errorf("task %s forEach: %w", t.Name, err)
+	}
+	// Without instances the tasks waiting for the task would not wait for anything.
+	if len(items) == 0 {
+		return nil, fmt.Errorf("task %s forEach list is empty", t.Name)
+	}
+	return items, nil
+}
+
+// parseList splits a JSON array or a comma separated list into its items. Items of a
+// JSON array that are not strings are kept as JSON.
+func parseList(list string) ([]string, error) {
+	list = strings.TrimSpace(list)
+	if strings.HasPrefix(list, "[") {
+		var values []any
+		if err := json.Unmarshal([]byte(list), &values); err != nil {
+			return nil, fmt.Errorf("invalid JSON array: %w", err)
+		}
+		items := make([]string, 0, len(values))
+		for _, value := range values {
+			if s, ok := value.(string); ok {
+				items = append(items, s)
+				continue
+			}
+			encoded, err := json.Marshal(value)
+			if err != nil {
+				return nil, fmt.Errorf("failed to encode item: %w", err)
+			}
+			items = append(items, string(encoded))
+		}
+		return items, nil
+	}
+
+	var items []string
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items, nil
+}
+
+// instance returns the i-th instance of a task with a forEach. A compensation that
+// declares its name is numbered the same way, the others are named after the instance.
+// The references of the compensation to the outputs of the task point to the instance.
+func instance(t model.Task, i int, item string) model.Task {
+	name := t.Name
+	t.Name = instanceName(name, i)
+	t.ForEach = ""
+	t.Group = name
+	t.Item = item
+	if t.Compensate != nil {
+		compensation := *t.Compensate
+		if compensation.Name != "" {
+			compensation.Name = instanceName(compensation.Name, i)
+		}
+		own := regexp.MustCompile(`\.tasks\.` + regexp.QuoteMeta(name) + `\.outputs\.`)
+		params := make(map[string]string, len(compensation.Parameters))
+		for key, value := range compensation.Parameters {
+			params[key] = own.ReplaceAllLiteralString(value, ".tasks."+t.Name+".outputs.")
+		}
+		compensation.Parameters = params
+		t.Compensate = &compensation
+	}
+	return t
+}
+
+// instanceName names the i-th instance of a task. It can be used in references to task outputs
+// as long as the name of the task can.
+func instanceName(name string, i int) string {
+	return fmt.Sprintf("%s_%d", name, i)
+}
+
+// itemInputs adds the item and index of an instance to the request inputs.
+func itemInputs(inputs map[string]string, item string, i int) map[string]string {
+	data := make(map[string]string, len(inputs)+2)
+	maps.Copy(data, inputs)
+	data["item"] = item
+	data["index"] = strconv.Itoa(i)
+	return data
+}
+
+// expandGroups replaces the tasks with a forEach among deps by their instances.
+func expandGroups(deps []string, groups map[string][]string) []string {
+	var expanded []string
+	for _, dep := range deps {
+		if instances, ok := groups[dep]; ok {
+			expanded = append(expanded, instances...)
+			continue
+		}
+		expanded = append(expanded, dep)
+	}
+	return expanded
+}
+
+func renderTemplate(name, tmplStr string, inputs map[string]string) (string, error) {
+	tmpl, err := template.New(name).Parse(taskoutput.Defer(tmplStr))
+	if err != nil {
+		return "", fmt.Errorf("failed to parse template: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, inputs); err != nil {
+		return "", fmt.Errorf("failed to render template: %w", err)
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,212 @@
+package templating_test
+
+import (
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/model"
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/taskoutput"
+	"github.com/ggsomnoev/ntt-ds-sap-process-api/internal/templating"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Apply", func() {
+	It("applies template values to task parameters", func() {
+		tasks := []model.Task{
+			{
+				Name:       "task1",
+				Class:      "localCmd",
+				Parameters: map[string]string{"command": "echo {{.msg}}"},
+			},
+		}
+
+		rendered, err := templating.Apply(tasks, map[string]string{"msg": "hello"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(rendered).To(HaveLen(1))
+		Expect(rendered[0].Parameters["command"]).To(Equal("echo hello"))
+	})
+
+	It("keeps references to task outputs for the consumer", func() {
+		tasks := []model.Task{
+			{
+				Name:  "deploy",
+				Class: "sshCmd",
+				Parameters: map[string]string{
+					"command": "deploy {{.tasks.build.outputs.buildId}} to {{.env}}",
+				},
+			},
+		}
+
+		rendered, err := templating.Apply(tasks, map[string]string{"env": "prod"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(rendered[0].Parameters["command"]).To(Equal("deploy " + taskoutput.Deferred("{{.tasks.build.outputs.buildId}}") + " to prod"))
+	})
+
+	It("applies template values to task conditions", func() {
+		tasks := []model.Task{
+			{Name: "copy", Class: "scpCmd", When: `"{{.env}}" == "prod"`},
+		}
+
+		rendered, err := templating.Apply(tasks, map[string]string{"env": "prod"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(rendered[0].When).To(Equal(`"prod" == "prod"`))
+	})
+
+	It("applies template values to task compensations", func() {
+		tasks := []model.Task{
+			{
+				Name:  "deploy",
+				Class: "sshCmd",
+				Compensate: &model.Task{
+					Class:      "sshCmd",
+					Parameters: map[string]string{"command": "rollback {{.env}} {{.tasks.deploy.outputs.release}}"},
+				},
+			},
+		}
+
+		rendered, err := templating.Apply(tasks, map[string]string{"env": "prod"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(rendered[0].Compensate.Parameters["command"]).To(Equal("rollback prod " + taskoutput.Deferred("{{.tasks.deploy.outputs.release}}")))
+		Expect(tasks[0].Compensate.Parameters["command"]).To(Equal("rollback {{.env}} {{.tasks.deploy.outputs.release}}"))
+	})
+
+	It("returns an error if the template is invalid", func() {
+		tasks := []model.Task{
+			{
+				Name:  "badTemplateTask",
+				Class: "localCmd",
+				Parameters: map[string]string{
+					"cmd": "{{.msg", // malformed template
+				},
+			},
+		}
+
+		inputs := map[string]string{"msg": "hi"}
+		_, err := templating.Apply(tasks, inputs)
+		Expect(err).To(MatchError(ContainSubstring("failed to parse template")))
+	})
+
+	Describe("forEach", func() {
+		var (
+			tasks    []model.Task
+			inputs   map[string]string
+			rendered []model.Task
+			err      error
+		)
+
+		BeforeEach(func() {
+			tasks = []model.Task{
+				{
+					Name:       "upgrade",
+					Class:      "sshCmd",
+					ForEach:    "{{.hosts}}",
+					Parameters: map[string]string{"host": "{{.item}}", "command": "upgrade --slot {{.index}} {{.version}}"},
+				},
+				{Name: "notify", Class: "localCmd", WaitFor: []string{"upgrade"}},
+			}
+			inputs = map[string]string{"hosts": "web1, web2,web3", "version": "1.2"}
+		})
+
+		JustBeforeEach(func() {
+			rendered, err = templating.Apply(tasks, inputs)
+		})
+
+		It("expands the task into an instance per item", func() {
+			Expect(err).NotTo(HaveOccurred())
+			Expect(rendered).To(HaveLen(4))
+			Expect(rendered[0].Name).To(Equal("upgrade_0"))
+			Expect(rendered[0].ForEach).To(BeEmpty())
+			Expect(rendered[0].Group).To(Equal("upgrade"))
+			Expect(rendered[0].Item).To(Equal("web1"))
+			Expect(rendered[0].Parameters).To(Equal(map[string]string{"host": "web1", "command": "upgrade --slot 0 1.2"}))
+			Expect(rendered[1].Name).To(Equal("upgrade_1"))
+			Expect(rendered[1].Parameters["host"]).To(Equal("web2"))
+			Expect(rendered[2].Name).To(Equal("upgrade_2"))
+			Expect(rendered[2].Parameters["host"]).To(Equal("web3"))
+		})
+
+		It("lets tasks waiting for it wait for all of its instances", func() {
+			Expect(rendered[3].Name).To(Equal("notify"))
+			Expect(rendered[3].WaitFor).To(Equal([]string{"upgrade_0", "upgrade_1", "upgrade_2"}))
+			Expect(tasks[1].WaitFor).To(Equal([]string{"upgrade"}))
+		})
+
+		When("the list is a JSON array", func() {
+			BeforeEach(func() {
+				inputs["hosts"] = `["web1", 8080, {"name": "web3"}]`
+			})
+
+			It("keeps items that are not strings as JSON", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(rendered[0].Parameters["host"]).To(Equal("web1"))
+				Expect(rendered[1].Parameters["host"]).To(Equal("8080"))
+				Expect(rendered[2].Parameters["host"]).To(Equal(`{"name":"web3"}`))
+			})
+		})
+
+		When("the JSON array is malformed", func() {
+			BeforeEach(func() {
+				inputs["hosts"] = `["web1", `
+			})
+
+			It("returns an error", func() {
+				Expect(err).To(MatchError(ContainSubstring("task upgrade forEach: invalid JSON array")))
+			})
+		})
+
+		When("the list is empty", func() {
+			BeforeEach(func() {
+				inputs["hosts"] = " , "
+			})
+
+			It("returns an error", func() {
+				Expect(err).To(MatchError("task upgrade forEach list is empty"))
+			})
+		})
+
+		When("the JSON array is empty", func() {
+			BeforeEach(func() {
+				inputs["hosts"] = "[]"
+			})
+
+			It("returns an error", func() {
+				Expect(err).To(MatchError("task upgrade forEach list is empty"))
+			})
+		})
+
+		When("the list references task outputs", func() {
+			BeforeEach(func() {
+				tasks[0].ForEach = "{{.tasks.discover.outputs.hosts}}"
+			})
+
+			It("returns an error", func() {
+				Expect(err).To(MatchError("task upgrade forEach cannot reference task outputs"))
+			})
+		})
+
+		When("the task has a compensation", func() {
+			BeforeEach(func() {
+				tasks[0].Compensate = &model.Task{
+					Name:       "downgrade",
+					Class:      "sshCmd",
+					Parameters: map[string]string{"host": "{{.item}}"},
+				}
+			})
+
+			It("renders and numbers it with the instance", func() {
+				Expect(rendered[1].Compensate.Name).To(Equal("downgrade_1"))
+				Expect(rendered[1].Compensate.Parameters["host"]).To(Equal("web2"))
+				Expect(tasks[0].Compensate.Name).To(Equal("downgrade"))
+			})
+
+			When("it uses the outputs of the task", func() {
+				BeforeEach(func() {
+					tasks[0].Compensate.Parameters["command"] = "downgrade --to {{.tasks.upgrade.outputs.previous}}"
+				})
+
+				It("uses the outputs of the instance", func() {
+					Expect(rendered[1].Compensate.Parameters["command"]).To(Equal("downgrade --to " + taskoutput.Deferred("{{.tasks.upgrade_1.outputs.previous}}")))
+					Expect(tasks[0].Compensate.Parameters["command"]).To(Equal("downgrade --to {{.tasks.upgrade.outputs.previous}}"))
+				})
+			})
+		})
+	})
+})
@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE process_runs
+    DROP COLUMN IF EXISTS stop_requested_at,
+    DROP COLUMN IF EXISTS stop_reason;
+
+COMMIT;
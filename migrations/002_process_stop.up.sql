@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE process_runs
+    ADD COLUMN IF NOT EXISTS stop_reason TEXT,
+    ADD COLUMN IF NOT EXISTS stop_requested_at TIMESTAMPTZ;
+
+COMMIT;
@@ -0,0 +1,21 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS task_runs (
+    id SERIAL PRIMARY KEY,
+    process_id UUID NOT NULL REFERENCES process_runs(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    class TEXT NOT NULL,
+    status TEXT NOT NULL,
+    attempt INT NOT NULL DEFAULT 1,
+    exit_code INT,
+    stdout TEXT NOT NULL DEFAULT '',
+    stderr TEXT NOT NULL DEFAULT '',
+    error TEXT NOT NULL DEFAULT '',
+    started_at TIMESTAMPTZ,
+    ended_at TIMESTAMPTZ,
+    UNIQUE (process_id, name, attempt)
+);
+
+CREATE INDEX IF NOT EXISTS task_runs_process_id_idx ON task_runs (process_id);
+
+COMMIT;
@@ -0,0 +1,9 @@
+BEGIN;
+
+DROP INDEX IF EXISTS process_logs_process_id_idx;
+
+ALTER TABLE process_logs
+    DROP COLUMN IF EXISTS stream,
+    DROP COLUMN IF EXISTS task_name;
+
+COMMIT;
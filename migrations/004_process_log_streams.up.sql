@@ -0,0 +1,9 @@
+BEGIN;
+
+ALTER TABLE process_logs
+    ADD COLUMN IF NOT EXISTS task_name TEXT,
+    ADD COLUMN IF NOT EXISTS stream TEXT;
+
+CREATE INDEX IF NOT EXISTS process_logs_process_id_idx ON process_logs (process_id, created_at);
+
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE task_runs ADD COLUMN IF NOT EXISTS outputs JSONB;
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+DROP TABLE IF EXISTS process_run_attempts;
+
+ALTER TABLE process_runs DROP COLUMN IF EXISTS attempt;
+
+COMMIT;
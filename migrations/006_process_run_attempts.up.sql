@@ -0,0 +1,14 @@
+BEGIN;
+
+ALTER TABLE process_runs ADD COLUMN IF NOT EXISTS attempt INT NOT NULL DEFAULT 1;
+
+CREATE TABLE IF NOT EXISTS process_run_attempts (
+    process_id UUID NOT NULL REFERENCES process_runs(id) ON DELETE CASCADE,
+    attempt INT NOT NULL,
+    status TEXT NOT NULL,
+    started_at TIMESTAMPTZ NOT NULL,
+    ended_at TIMESTAMPTZ,
+    PRIMARY KEY (process_id, attempt)
+);
+
+COMMIT;
@@ -0,0 +1,8 @@
+BEGIN;
+
+ALTER TABLE process_events
+    DROP COLUMN IF EXISTS lease_expires_at,
+    DROP COLUMN IF EXISTS claimed_by,
+    DROP COLUMN IF EXISTS state;
+
+COMMIT;
@@ -0,0 +1,12 @@
+BEGIN;
+
+-- Events are claimed by a consumer for a limited time that it keeps extending while the
+-- run executes. Events recorded before leases existed are all processed.
+ALTER TABLE process_events
+    ADD COLUMN IF NOT EXISTS state TEXT NOT NULL DEFAULT 'completed',
+    ADD COLUMN IF NOT EXISTS claimed_by TEXT,
+    ADD COLUMN IF NOT EXISTS lease_expires_at TIMESTAMPTZ;
+
+ALTER TABLE process_events ALTER COLUMN state DROP DEFAULT;
+
+COMMIT;
@@ -0,0 +1,18 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS process_schedules (
+    process_name TEXT NOT NULL,
+    schedule_name TEXT NOT NULL,
+    cron TEXT NOT NULL,
+    timezone TEXT NOT NULL DEFAULT '',
+    parameters JSONB,
+    paused BOOLEAN NOT NULL DEFAULT FALSE,
+    next_fire_at TIMESTAMPTZ NOT NULL,
+    last_fired_at TIMESTAMPTZ,
+    last_run_id UUID,
+    PRIMARY KEY (process_name, schedule_name)
+);
+
+CREATE INDEX IF NOT EXISTS process_schedules_next_fire_at_idx ON process_schedules (next_fire_at);
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+DROP INDEX IF EXISTS process_runs_run_at_idx;
+
+ALTER TABLE process_runs DROP COLUMN IF EXISTS run_at;
+
+COMMIT;
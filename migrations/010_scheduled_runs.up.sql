@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE process_runs ADD COLUMN IF NOT EXISTS run_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS process_runs_run_at_idx ON process_runs (run_at) WHERE status = 'scheduled';
+
+COMMIT;
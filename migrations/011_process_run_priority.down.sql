@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE process_runs DROP COLUMN IF EXISTS priority;
+
+COMMIT;
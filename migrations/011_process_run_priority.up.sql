@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE process_runs ADD COLUMN IF NOT EXISTS priority INT NOT NULL DEFAULT 0;
+
+COMMIT;
@@ -0,0 +1,9 @@
+BEGIN;
+
+DROP INDEX IF EXISTS task_runs_approval_expires_at_idx;
+
+ALTER TABLE task_runs DROP COLUMN IF EXISTS decided_at;
+ALTER TABLE task_runs DROP COLUMN IF EXISTS decided_by;
+ALTER TABLE task_runs DROP COLUMN IF EXISTS approval_expires_at;
+
+COMMIT;
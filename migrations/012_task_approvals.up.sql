@@ -0,0 +1,9 @@
+BEGIN;
+
+ALTER TABLE task_runs ADD COLUMN IF NOT EXISTS approval_expires_at TIMESTAMPTZ;
+ALTER TABLE task_runs ADD COLUMN IF NOT EXISTS decided_by TEXT;
+ALTER TABLE task_runs ADD COLUMN IF NOT EXISTS decided_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS task_runs_approval_expires_at_idx ON task_runs (approval_expires_at) WHERE status = 'awaiting_approval';
+
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE process_runs ADD COLUMN IF NOT EXISTS pause_requested_at TIMESTAMPTZ;
+
+COMMIT;
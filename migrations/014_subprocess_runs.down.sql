@@ -0,0 +1,8 @@
+BEGIN;
+
+DROP INDEX IF EXISTS process_runs_parent_id_idx;
+
+ALTER TABLE process_runs DROP COLUMN IF EXISTS parent_task;
+ALTER TABLE process_runs DROP COLUMN IF EXISTS parent_id;
+
+COMMIT;
@@ -0,0 +1,8 @@
+BEGIN;
+
+ALTER TABLE process_runs ADD COLUMN IF NOT EXISTS parent_id UUID REFERENCES process_runs(id) ON DELETE CASCADE;
+ALTER TABLE process_runs ADD COLUMN IF NOT EXISTS parent_task TEXT;
+
+CREATE INDEX IF NOT EXISTS process_runs_parent_id_idx ON process_runs (parent_id);
+
+COMMIT;
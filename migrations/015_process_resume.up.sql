@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE process_runs ADD COLUMN IF NOT EXISTS resume_requested_at TIMESTAMPTZ;
+
+COMMIT;